@@ -0,0 +1,90 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+type CartExpirationPolicy struct {
+	AbandonAfter time.Duration
+	ExpireAfter  time.Duration
+}
+
+type CartExpirationService struct {
+	cartRepository  domain.CartRepository
+	eventDispatcher domain.EventDispatcher
+	clock           domain.Clock
+	policy          CartExpirationPolicy
+}
+
+func NewCartExpirationService(cartRepository domain.CartRepository, eventDispatcher domain.EventDispatcher, clock domain.Clock, policy CartExpirationPolicy) (*CartExpirationService, error) {
+	if cartRepository == nil {
+		return nil, errors.New("cart repository was nil")
+	}
+
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
+	if clock == nil {
+		return nil, errors.New("clock was nil")
+	}
+
+	if policy.AbandonAfter <= 0 {
+		return nil, errors.New("invalid abandon after duration")
+	}
+
+	if policy.ExpireAfter <= policy.AbandonAfter {
+		return nil, errors.New("expire after must be longer than abandon after")
+	}
+
+	return &CartExpirationService{
+		cartRepository:  cartRepository,
+		eventDispatcher: eventDispatcher,
+		clock:           clock,
+		policy:          policy,
+	}, nil
+}
+
+func (s *CartExpirationService) ExpireIdleCarts() (IdleCartsDto, error) {
+	now := s.clock.Now()
+	abandonBefore := now.Add(-s.policy.AbandonAfter)
+	expireBefore := now.Add(-s.policy.ExpireAfter)
+
+	result := IdleCartsDto{
+		Abandoned: []uuid.UUID{},
+		Expired:   []uuid.UUID{},
+	}
+	idleCarts, err := s.cartRepository.FindIdleCarts(abandonBefore)
+	if err != nil {
+		return result, err
+	}
+
+	for _, cart := range idleCarts {
+		switch {
+		case cart.GetLastActivityAt().Before(expireBefore):
+			if err := cart.Expire(); err != nil {
+				return result, err
+			}
+			result.Expired = append(result.Expired, uuid.UUID(cart.GetID()))
+		case cart.IsActive():
+			if err := cart.Abandon(); err != nil {
+				return result, err
+			}
+			result.Abandoned = append(result.Abandoned, uuid.UUID(cart.GetID()))
+		default:
+			continue
+		}
+
+		if err := s.cartRepository.Save(cart); err != nil {
+			return result, err
+		}
+
+		dispatchDomainEvents[domain.CartId](s.eventDispatcher, cart)
+	}
+
+	return result, nil
+}
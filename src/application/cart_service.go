@@ -2,6 +2,10 @@ package application
 
 import (
 	"errors"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
 
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/google/uuid"
@@ -11,9 +15,86 @@ type CartService struct {
 	cartRepository     domain.CartRepository
 	customerRepository domain.CustomerRepository
 	productRepository  domain.ProductRepository
+	eventDispatcher    domain.EventDispatcher
+	exchangeRates      domain.ExchangeRateProvider
+	clock              domain.Clock
+	activeCartPolicy   ActiveCartPolicy
+	pricingPolicy      CartPricingPolicy
+	stockRepository    domain.StockRepository
+	promotions         domain.PromotionCatalog
+	shipping           domain.ShippingCatalog
+	taxCalculator      domain.TaxCalculator
+	conflictRetries    int
 }
 
-func NewCartService(cartRepository domain.CartRepository, customerRepository domain.CustomerRepository, productRepository domain.ProductRepository) (*CartService, error) {
+type ActiveCartPolicy string
+
+const (
+	ReuseActiveCart        ActiveCartPolicy = "reuse"
+	RejectSecondActiveCart ActiveCartPolicy = "reject"
+)
+
+type CartPricingPolicy string
+
+const (
+	KeepPriceSnapshot CartPricingPolicy = "snapshot"
+	RepriceOnRead     CartPricingPolicy = "reprice_on_read"
+	RepriceOnCheckout CartPricingPolicy = "reprice_on_checkout"
+)
+
+type CartServiceOption func(*CartService)
+
+func WithClock(clock domain.Clock) CartServiceOption {
+	return func(s *CartService) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+func WithActiveCartPolicy(policy ActiveCartPolicy) CartServiceOption {
+	return func(s *CartService) {
+		s.activeCartPolicy = policy
+	}
+}
+
+func WithCartPricingPolicy(policy CartPricingPolicy) CartServiceOption {
+	return func(s *CartService) {
+		s.pricingPolicy = policy
+	}
+}
+
+func WithStockReservations(stockRepository domain.StockRepository) CartServiceOption {
+	return func(s *CartService) {
+		s.stockRepository = stockRepository
+	}
+}
+
+func WithPromotions(promotions domain.PromotionCatalog) CartServiceOption {
+	return func(s *CartService) {
+		s.promotions = promotions
+	}
+}
+
+func WithShipping(shipping domain.ShippingCatalog) CartServiceOption {
+	return func(s *CartService) {
+		s.shipping = shipping
+	}
+}
+
+func WithTaxes(calculator domain.TaxCalculator) CartServiceOption {
+	return func(s *CartService) {
+		s.taxCalculator = calculator
+	}
+}
+
+func WithConflictRetries(retries int) CartServiceOption {
+	return func(s *CartService) {
+		s.conflictRetries = retries
+	}
+}
+
+func NewCartService(cartRepository domain.CartRepository, customerRepository domain.CustomerRepository, productRepository domain.ProductRepository, eventDispatcher domain.EventDispatcher, exchangeRates domain.ExchangeRateProvider, options ...CartServiceOption) (*CartService, error) {
 	if cartRepository == nil {
 		return nil, errors.New("cart repository was nil")
 	}
@@ -26,11 +107,45 @@ func NewCartService(cartRepository domain.CartRepository, customerRepository dom
 		return nil, errors.New("product repository was nil")
 	}
 
-	return &CartService{
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
+	if exchangeRates == nil {
+		return nil, errors.New("exchange rate provider was nil")
+	}
+
+	service := &CartService{
 		cartRepository:     cartRepository,
 		customerRepository: customerRepository,
 		productRepository:  productRepository,
-	}, nil
+		eventDispatcher:    eventDispatcher,
+		exchangeRates:      exchangeRates,
+		clock:              domain.SystemClock(),
+		activeCartPolicy:   ReuseActiveCart,
+		pricingPolicy:      KeepPriceSnapshot,
+		conflictRetries:    3,
+	}
+
+	for _, option := range options {
+		option(service)
+	}
+
+	if service.activeCartPolicy != ReuseActiveCart && service.activeCartPolicy != RejectSecondActiveCart {
+		return nil, errors.New("invalid active cart policy")
+	}
+
+	switch service.pricingPolicy {
+	case KeepPriceSnapshot, RepriceOnRead, RepriceOnCheckout:
+	default:
+		return nil, errors.New("invalid cart pricing policy")
+	}
+
+	if service.conflictRetries < 0 {
+		return nil, errors.New("invalid conflict retries")
+	}
+
+	return service, nil
 }
 
 func (s *CartService) CreateNewCart(command CreateCartCommand) (CartDto, error) {
@@ -39,54 +154,835 @@ func (s *CartService) CreateNewCart(command CreateCartCommand) (CartDto, error)
 		return CartDto{}, NewNotFoundError(command.CustomerId.String(), "customer")
 	}
 
-	cart, err := domain.NewCart(customer)
+	if customer == nil {
+		return CartDto{}, errors.New("no customer provided")
+	}
+
+	if !customer.IsActive() {
+		return CartDto{}, NewInvalidArgumentError("customer", "it is deactivated")
+	}
+
+	options := []domain.CartOption{domain.WithCartClock(s.clock)}
+	var currency domain.Currency
+	if command.Currency != "" {
+		if currency, err = domain.NewCurrency(command.Currency); err != nil {
+			return CartDto{}, NewInvalidArgumentError("currency", err.Error())
+		}
+		options = append(options, domain.WithDisplayCurrency(currency))
+	}
+
+	existingCart, err := s.findReusableCart(customer.GetID(), currency)
 	if err != nil {
 		return CartDto{}, err
 	}
 
-	if err = s.cartRepository.Save(cart); err != nil {
+	if existingCart != nil {
+		return s.reuseCart(existingCart, currency)
+	}
+
+	cart, err := domain.NewCart(customer, options...)
+	if err != nil {
 		return CartDto{}, err
 	}
 
-	return mapCartToDto(cart), nil
+	cartDto, err := s.saveCart(cart)
+	if _, conflict := err.(*ConflictError); conflict {
+		activeCart, findErr := s.findActiveCart(customer.GetID())
+		if findErr == nil && activeCart != nil {
+			return s.reuseCart(activeCart, currency)
+		}
+	}
+
+	return cartDto, err
 }
 
 func (s *CartService) AddItemToCart(command AddItemToCartCommand) (CartDto, error) {
-	product, err := s.productRepository.FindByID(domain.ProductId(command.ProductId))
+	for attempt := 0; ; attempt++ {
+		cartDto, err := s.addItemToCart(command)
+		if command.ExpectedVersion != nil || attempt >= s.conflictRetries || !isConcurrencyConflict(err) {
+			return cartDto, err
+		}
+	}
+}
+
+func (s *CartService) addItemToCart(command AddItemToCartCommand) (CartDto, error) {
+	product, err := s.findProductToAdd(command)
+	if err != nil {
+		return CartDto{}, err
+	}
+	productId := uuid.UUID(product.GetID())
+
+	if product.IsArchived() {
+		return CartDto{}, NewProductArchivedError(productId.String())
+	}
+
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil {
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	stockItem := s.findStockItem(product.GetID())
+	if err = ensureStockAvailable(stockItem, productId, command.Quantity); err != nil {
+		return CartDto{}, err
+	}
+
+	if _, err = cart.AddItemWithExchangeRates(product, command.Quantity, s.exchangeRates); err != nil {
+		if errors.Is(err, domain.ErrCurrencyNotConvertible) {
+			return CartDto{}, NewInvalidArgumentError("product", "its price in "+string(product.GetPrice().Currency())+" cannot be converted to "+string(cart.GetCurrency()))
+		}
+		return CartDto{}, mapCartItemError(err, productId)
+	}
+
+	if stockItem != nil {
+		if err = stockItem.Reserve(cart.GetID(), command.Quantity); err != nil {
+			return CartDto{}, err
+		}
+		if err = s.saveStockItem(stockItem); err != nil {
+			return CartDto{}, err
+		}
+	}
+
+	cartDto, err := s.saveCart(cart)
+	if err != nil && stockItem != nil {
+		s.cancelReservation(cart.GetID(), product.GetID(), command.Quantity)
+	}
+
+	return cartDto, err
+}
+
+func (s *CartService) RemoveItemFromCart(command RemoveItemFromCartCommand) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil {
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	if err = cart.RemoveItem(domain.ProductId(command.ProductId)); err != nil {
+		return CartDto{}, mapCartItemError(err, command.ProductId)
+	}
+
+	cartDto, err := s.saveCart(cart)
+	if err != nil {
+		return CartDto{}, err
+	}
+
+	s.releaseStock(cart.GetID(), domain.ProductId(command.ProductId))
+	return cartDto, nil
+}
+
+func (s *CartService) UpdateItemQuantity(command UpdateItemQuantityCommand) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
 	if err != nil {
-		return CartDto{}, NewNotFoundError(command.ProductId.String(), "product")
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
 	}
 
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	productId := domain.ProductId(command.ProductId)
+	previousQuantity := cartItemQuantity(cart, productId)
+	stockItem := s.findStockItem(productId)
+	if err = ensureStockAvailable(stockItem, command.ProductId, command.Quantity-previousQuantity); err != nil {
+		return CartDto{}, err
+	}
+
+	if _, err = cart.UpdateItemQuantity(productId, command.Quantity); err != nil {
+		return CartDto{}, mapCartItemError(err, command.ProductId)
+	}
+
+	reserved := command.Quantity - previousQuantity
+	if stockItem != nil && reserved > 0 {
+		if err = stockItem.Reserve(cart.GetID(), reserved); err != nil {
+			return CartDto{}, err
+		}
+		if err = s.saveStockItem(stockItem); err != nil {
+			return CartDto{}, err
+		}
+	}
+
+	cartDto, err := s.saveCart(cart)
+	if err != nil {
+		if stockItem != nil && reserved > 0 {
+			s.cancelReservation(cart.GetID(), productId, reserved)
+		}
+		return CartDto{}, err
+	}
+
+	if stockItem != nil && reserved < 0 {
+		s.cancelReservation(cart.GetID(), productId, -reserved)
+	}
+
+	return cartDto, nil
+}
+
+func (s *CartService) ClearCart(command ClearCartCommand) (CartDto, error) {
 	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
 	if err != nil {
 		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
 	}
 
-	if _, err = cart.AddItem(product, command.Quantity); err != nil {
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
 		return CartDto{}, err
 	}
 
-	if err = s.cartRepository.Save(cart); err != nil {
+	items := cart.GetItems()
+	if err = cart.Clear(); err != nil {
+		return CartDto{}, mapCartError(err)
+	}
+
+	cartDto, err := s.saveCart(cart)
+	if err != nil {
+		return CartDto{}, err
+	}
+
+	for _, item := range items {
+		s.releaseStock(cart.GetID(), item.GetProductId())
+	}
+
+	return cartDto, nil
+}
+
+func (s *CartService) ApplyCoupon(command ApplyCouponCommand) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil || cart == nil {
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	code, err := domain.NewCouponCode(command.Code)
+	if err != nil {
+		return CartDto{}, NewInvalidArgumentError("code", err.Error())
+	}
+
+	if s.promotions == nil {
+		return CartDto{}, NewNotFoundError(string(code), "coupon")
+	}
+
+	coupon, err := s.promotions.FindCoupon(code)
+	if err != nil {
+		return CartDto{}, NewNotFoundError(string(code), "coupon")
+	}
+
+	if err = cart.ApplyCoupon(coupon); err != nil {
+		return CartDto{}, mapCartError(err)
+	}
+
+	return s.saveCart(cart)
+}
+
+func (s *CartService) RemoveCoupon(command RemoveCouponCommand) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil || cart == nil {
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	code, err := domain.NewCouponCode(command.Code)
+	if err != nil {
+		return CartDto{}, NewInvalidArgumentError("code", err.Error())
+	}
+
+	if err = cart.RemoveCoupon(code); err != nil {
+		if errors.Is(err, domain.ErrCouponNotApplied) {
+			return CartDto{}, NewNotFoundError(string(code), "coupon")
+		}
+		return CartDto{}, mapCartError(err)
+	}
+
+	return s.saveCart(cart)
+}
+
+func (s *CartService) GetShippingOptions(query GetShippingOptionsQuery) ([]ShippingOptionDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(query.CartId))
+	if err != nil || cart == nil {
+		return nil, NewNotFoundError(query.CartId.String(), "cart")
+	}
+
+	optionDtos := []ShippingOptionDto{}
+	if s.shipping == nil {
+		return optionDtos, nil
+	}
+
+	for _, method := range s.shipping.GetShippingMethods() {
+		quote, err := cart.QuoteShipping(method)
+		if err != nil {
+			if errors.Is(err, domain.ErrShippingUnavailable) {
+				continue
+			}
+			return nil, mapCartError(err)
+		}
+
+		optionDtos = append(optionDtos, ShippingOptionDto{
+			Method:   string(quote.GetMethod()),
+			Name:     quote.GetName(),
+			Cost:     PriceDto(quote.GetCost()),
+			Currency: string(quote.GetCost().Currency()),
+			Selected: cart.HasShipping() && cart.GetShipping().GetMethod() == quote.GetMethod(),
+		})
+	}
+
+	return optionDtos, nil
+}
+
+func (s *CartService) SelectShipping(command SelectShippingCommand) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil || cart == nil {
+		return CartDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err = ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return CartDto{}, err
+	}
+
+	code, err := domain.NewShippingMethodCode(command.Method)
+	if err != nil {
+		return CartDto{}, NewInvalidArgumentError("method", err.Error())
+	}
+
+	if s.shipping == nil {
+		return CartDto{}, NewNotFoundError(string(code), "shipping method")
+	}
+
+	method, err := s.shipping.FindShippingMethod(code)
+	if err != nil {
+		return CartDto{}, NewNotFoundError(string(code), "shipping method")
+	}
+
+	if err = cart.SelectShipping(method); err != nil {
+		if errors.Is(err, domain.ErrShippingUnavailable) {
+			return CartDto{}, NewInvalidArgumentError("method", "it is not available for this cart")
+		}
+		return CartDto{}, mapCartError(err)
+	}
+
+	return s.saveCart(cart)
+}
+
+func (s *CartService) GetCart(query GetCartQuery) (CartDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(query.CartId))
+	if err != nil || cart == nil {
+		return CartDto{}, NewNotFoundError(query.CartId.String(), "cart")
+	}
+
+	if err = s.repriceForRead(cart); err != nil {
 		return CartDto{}, err
 	}
 
 	return mapCartToDto(cart), nil
 }
 
+func (s *CartService) GetCustomerCarts(query GetCustomerCartsQuery) ([]CartDto, error) {
+	customer, err := s.customerRepository.FindByID(domain.CustomerId(query.CustomerId))
+	if err != nil || customer == nil {
+		return nil, NewNotFoundError(query.CustomerId.String(), "customer")
+	}
+
+	var status domain.CartStatus
+	if query.Status != "" {
+		if status, err = domain.NewCartStatus(query.Status); err != nil {
+			return nil, NewInvalidArgumentError("status", err.Error())
+		}
+	}
+
+	carts, err := s.cartRepository.GetCustomerCarts(customer.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	cartDtos := []CartDto{}
+	for _, cart := range carts {
+		if status != "" && cart.GetStatus() != status {
+			continue
+		}
+
+		if err = s.repriceForRead(cart); err != nil {
+			return nil, err
+		}
+		cartDtos = append(cartDtos, mapCartToDto(cart))
+	}
+
+	return cartDtos, nil
+}
+
+func (s *CartService) findActiveCart(customerId domain.CustomerId) (*domain.Cart, error) {
+	carts, err := s.cartRepository.GetCustomerCarts(customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cart := range carts {
+		if cart.IsActive() {
+			return cart, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *CartService) findReusableCart(customerId domain.CustomerId, currency domain.Currency) (*domain.Cart, error) {
+	carts, err := s.cartRepository.GetCustomerCarts(customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var abandonedCart *domain.Cart
+	for _, cart := range carts {
+		if cart.IsActive() {
+			return cart, nil
+		}
+
+		if s.activeCartPolicy != ReuseActiveCart || !matchesCurrency(cart, currency) {
+			continue
+		}
+
+		if cart.GetStatus() == domain.CartStatusAbandoned && (abandonedCart == nil || cart.GetLastActivityAt().After(abandonedCart.GetLastActivityAt())) {
+			abandonedCart = cart
+		}
+	}
+
+	return abandonedCart, nil
+}
+
+func matchesCurrency(cart *domain.Cart, currency domain.Currency) bool {
+	return currency == "" || cart.GetCurrency() == currency
+}
+
+func (s *CartService) reuseCart(cart *domain.Cart, currency domain.Currency) (CartDto, error) {
+	if !cart.IsActive() {
+		return s.reactivateCart(cart)
+	}
+
+	if s.activeCartPolicy == RejectSecondActiveCart {
+		return CartDto{}, NewInvalidArgumentError("customer", "it already has an active cart "+cart.GetID().String())
+	}
+
+	if !matchesCurrency(cart, currency) {
+		return CartDto{}, NewInvalidArgumentError("currency", "the customer already has an active cart "+cart.GetID().String()+" in "+string(cart.GetCurrency()))
+	}
+
+	if err := s.repriceForRead(cart); err != nil {
+		return CartDto{}, err
+	}
+
+	cartDto := mapCartToDto(cart)
+	cartDto.Reused = true
+	return cartDto, nil
+}
+
+func (s *CartService) reactivateCart(cart *domain.Cart) (CartDto, error) {
+	if err := cart.Reactivate(); err != nil {
+		return CartDto{}, mapCartError(err)
+	}
+
+	reserved, err := s.reserveCartItems(cart)
+	if err != nil {
+		return CartDto{}, err
+	}
+
+	cartDto, err := s.saveCart(cart)
+	if err != nil {
+		for productId, quantity := range reserved {
+			s.cancelReservation(cart.GetID(), productId, quantity)
+		}
+		return CartDto{}, err
+	}
+
+	cartDto.Reused = true
+	return cartDto, nil
+}
+
+func (s *CartService) reserveCartItems(cart *domain.Cart) (map[domain.ProductId]int, error) {
+	reserved := map[domain.ProductId]int{}
+	for _, cartItem := range cart.GetItems() {
+		productId := cartItem.GetProductId()
+		stockItem := s.findStockItem(productId)
+		if stockItem == nil {
+			continue
+		}
+
+		quantity := cartItem.GetQuantity() - stockItem.GetReservedFor(cart.GetID())
+		if quantity <= 0 {
+			continue
+		}
+
+		err := ensureStockAvailable(stockItem, uuid.UUID(productId), quantity)
+		if err == nil {
+			err = stockItem.Reserve(cart.GetID(), quantity)
+		}
+		if err == nil {
+			err = s.saveStockItem(stockItem)
+		}
+		if err != nil {
+			for reservedProductId, reservedQuantity := range reserved {
+				s.cancelReservation(cart.GetID(), reservedProductId, reservedQuantity)
+			}
+			return nil, err
+		}
+		reserved[productId] = quantity
+	}
+
+	return reserved, nil
+}
+
+func (s *CartService) findProductToAdd(command AddItemToCartCommand) (*domain.Product, error) {
+	if command.SKU == "" {
+		product, err := s.productRepository.FindByID(domain.ProductId(command.ProductId))
+		if err != nil || product == nil {
+			return nil, NewNotFoundError(command.ProductId.String(), "product")
+		}
+		return product, nil
+	}
+
+	sku, err := domain.NewSKU(command.SKU)
+	if err != nil {
+		return nil, NewInvalidArgumentError("sku", err.Error())
+	}
+
+	product, err := s.productRepository.FindBySKU(sku)
+	if err != nil || product == nil {
+		return nil, NewNotFoundError(string(sku), "product")
+	}
+
+	return product, nil
+}
+
+func (s *CartService) findStockItem(productId domain.ProductId) *domain.StockItem {
+	if s.stockRepository == nil {
+		return nil
+	}
+
+	stockItem, err := s.stockRepository.FindByID(productId)
+	if err != nil {
+		return nil
+	}
+
+	return stockItem
+}
+
+func (s *CartService) releaseStock(cartId domain.CartId, productId domain.ProductId) {
+	s.releaseReservation(productId, func(stockItem *domain.StockItem) {
+		stockItem.ReleaseAll(cartId)
+	})
+}
+
+func (s *CartService) cancelReservation(cartId domain.CartId, productId domain.ProductId, quantity int) {
+	s.releaseReservation(productId, func(stockItem *domain.StockItem) {
+		stockItem.Release(cartId, quantity)
+	})
+}
+
+func (s *CartService) releaseReservation(productId domain.ProductId, release func(*domain.StockItem)) {
+	for attempt := 0; ; attempt++ {
+		stockItem := s.findStockItem(productId)
+		if stockItem == nil {
+			return
+		}
+
+		release(stockItem)
+		err := s.saveStockItem(stockItem)
+		if err == nil {
+			return
+		}
+
+		if attempt >= s.conflictRetries || !isConcurrencyConflict(err) {
+			log.Printf("failed to release the stock reserved for product %s: %v", productId, err)
+			return
+		}
+	}
+}
+
+func (s *CartService) saveStockItem(stockItem *domain.StockItem) error {
+	if err := s.stockRepository.Save(stockItem); err != nil {
+		return mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.ProductId](s.eventDispatcher, stockItem)
+	return nil
+}
+
+func ensureStockAvailable(stockItem *domain.StockItem, productId uuid.UUID, quantity int) error {
+	if stockItem == nil || quantity <= stockItem.GetAvailable() {
+		return nil
+	}
+
+	return NewInsufficientStockError(productId.String(), quantity, stockItem.GetAvailable())
+}
+
+func cartItemQuantity(cart *domain.Cart, productId domain.ProductId) int {
+	for _, item := range cart.GetItems() {
+		if item.GetProductId() == productId {
+			return item.GetQuantity()
+		}
+	}
+
+	return 0
+}
+
+func (s *CartService) repriceForRead(cart *domain.Cart) error {
+	repriced, err := s.applyPricingPolicy(cart)
+	if err != nil || !repriced {
+		return err
+	}
+
+	return s.applyAdjustments(cart)
+}
+
+func (s *CartService) applyPricingPolicy(cart *domain.Cart) (bool, error) {
+	if s.pricingPolicy != RepriceOnRead || !cart.IsActive() {
+		return false, nil
+	}
+
+	repriced, err := repriceCart(cart, s.productRepository, s.exchangeRates)
+	if err != nil {
+		return false, err
+	}
+
+	return len(repriced) > 0, nil
+}
+
+func repriceCart(cart *domain.Cart, productRepository domain.ProductRepository, exchangeRates domain.ExchangeRateProvider) ([]domain.ProductId, error) {
+	var repriced []domain.ProductId
+	for _, item := range cart.GetItems() {
+		product, err := productRepository.FindByID(item.GetProductId())
+		if err != nil || product == nil {
+			continue
+		}
+
+		changed, err := cart.RepriceItem(product, exchangeRates)
+		if err != nil {
+			if errors.Is(err, domain.ErrCurrencyNotConvertible) {
+				continue
+			}
+			return nil, mapCartError(err)
+		}
+
+		if changed {
+			repriced = append(repriced, item.GetProductId())
+		}
+	}
+
+	sort.Slice(repriced, func(i, j int) bool {
+		return repriced[i].String() < repriced[j].String()
+	})
+
+	return repriced, nil
+}
+
+func (s *CartService) applyPromotions(cart *domain.Cart) error {
+	if s.promotions == nil || !cart.IsActive() {
+		return nil
+	}
+
+	promotions := s.promotions.GetAutomaticPromotions()
+	for _, code := range cart.GetCoupons() {
+		if coupon, err := s.promotions.FindCoupon(code); err == nil {
+			promotions = append(promotions, coupon.GetPromotion())
+		}
+	}
+
+	return cart.ApplyPromotions(promotions)
+}
+
+func (s *CartService) applyShipping(cart *domain.Cart) error {
+	if s.shipping == nil || !cart.IsActive() || !cart.HasShipping() {
+		return nil
+	}
+
+	method, err := s.shipping.FindShippingMethod(cart.GetShipping().GetMethod())
+	if err != nil {
+		return nil
+	}
+
+	return cart.RebindShipping(method)
+}
+
+func (s *CartService) applyTaxes(cart *domain.Cart) error {
+	if s.taxCalculator == nil || !cart.IsActive() {
+		return nil
+	}
+
+	var region domain.TaxRegion
+	if customer, err := s.customerRepository.FindByID(cart.GetCustomerID()); err == nil && customer != nil {
+		region = customer.GetTaxRegion()
+	}
+
+	return cart.ApplyTaxes(region, s.taxCalculator)
+}
+
+func joinProductIds(productIds []domain.ProductId) string {
+	var ids []string
+	for _, productId := range productIds {
+		ids = append(ids, productId.String())
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+func (s *CartService) saveCart(cart *domain.Cart) (CartDto, error) {
+	if _, err := s.applyPricingPolicy(cart); err != nil {
+		return CartDto{}, err
+	}
+
+	if err := s.applyAdjustments(cart); err != nil {
+		return CartDto{}, err
+	}
+
+	if err := s.cartRepository.Save(cart); err != nil {
+		return CartDto{}, mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.CartId](s.eventDispatcher, cart)
+
+	return mapCartToDto(cart), nil
+}
+
+func (s *CartService) applyAdjustments(cart *domain.Cart) error {
+	if err := s.applyPromotions(cart); err != nil {
+		return mapCartError(err)
+	}
+
+	if err := s.applyShipping(cart); err != nil {
+		return mapCartError(err)
+	}
+
+	if err := s.applyTaxes(cart); err != nil {
+		return mapCartError(err)
+	}
+
+	return nil
+}
+
+func mapCartItemError(err error, productId uuid.UUID) error {
+	if errors.Is(err, domain.ErrItemNotFound) {
+		return NewNotFoundError(productId.String(), "item")
+	}
+
+	return mapCartError(err)
+}
+
+func mapCartError(err error) error {
+	if errors.Is(err, domain.ErrCartCheckedOut) {
+		return NewInvalidArgumentError("cart", "it is already checked out")
+	}
+
+	if errors.Is(err, domain.ErrCartNotActive) {
+		return NewInvalidArgumentError("cart", "it is not active")
+	}
+
+	if errors.Is(err, domain.ErrCartEmpty) {
+		return NewInvalidArgumentError("cart", "it has no items")
+	}
+
+	if errors.Is(err, domain.ErrAmountOutOfRange) {
+		return NewInvalidArgumentError("cart", "a line total is out of range")
+	}
+
+	return err
+}
+
 func mapCartToDto(cart *domain.Cart) CartDto {
+	lineTaxes := map[domain.ProductId]domain.LineTax{}
+	for _, lineTax := range cart.GetLineTaxes() {
+		lineTaxes[lineTax.GetProductId()] = lineTax
+	}
+
 	var itemDtos []ItemDto
 
 	for _, item := range cart.GetItems() {
-		itemDtos = append(itemDtos, ItemDto{
-			ProductId: uuid.UUID(item.GetProductId()),
-			UnitPrice: PriceDto(item.GetUnitPrice()),
-			Quantity:  item.GetQuantity(),
-		})
+		itemDto := ItemDto{
+			ProductId:    uuid.UUID(item.GetProductId()),
+			UnitPrice:    PriceDto(item.GetUnitPrice()),
+			Currency:     string(item.GetUnitPrice().Currency()),
+			Quantity:     item.GetQuantity(),
+			PriceChanged: item.HasPriceChanged(),
+		}
+		if item.HasPriceChanged() {
+			addedUnitPrice := PriceDto(item.GetAddedUnitPrice())
+			itemDto.AddedUnitPrice = &addedUnitPrice
+		}
+		if lineTax, found := lineTaxes[item.GetProductId()]; found {
+			tax := PriceDto(lineTax.GetAmount())
+			itemDto.TaxCategory = string(item.GetTaxCategory())
+			itemDto.TaxRate = formatRate(lineTax.GetRate())
+			itemDto.TaxIncluded = lineTax.IsInclusive()
+			itemDto.Tax = &tax
+		}
+		itemDtos = append(itemDtos, itemDto)
+	}
+
+	var coupons []string
+	for _, code := range cart.GetCoupons() {
+		coupons = append(coupons, string(code))
+	}
+
+	cartDto := CartDto{
+		Id:             uuid.UUID(cart.GetID()),
+		CustomerId:     uuid.UUID(cart.GetCustomerID()),
+		Currency:       string(cart.GetCurrency()),
+		Status:         string(cart.GetStatus()),
+		LastActivityAt: cart.GetLastActivityAt(),
+		Items:          itemDtos,
+		Coupons:        coupons,
+		Subtotal:       PriceDto(cart.GetSubtotal()),
+		LineDiscounts:  mapAdjustmentsToDtos(cart.GetLineAdjustments()),
+		CartDiscounts:  mapAdjustmentsToDtos(cart.GetCartAdjustments()),
+		Total:          PriceDto(cart.GetTotal()),
+		Version:        cart.GetVersion(),
 	}
 
-	return CartDto{
-		Id:         uuid.UUID(cart.GetID()),
-		CustomerId: uuid.UUID(cart.GetCustomerID()),
-		Items:      itemDtos,
+	if cart.HasShipping() {
+		cartDto.Shipping = mapShippingToDto(cart.GetShipping())
 	}
+
+	if cart.IsTaxed() {
+		tax := PriceDto(cart.GetTax())
+		cartDto.TaxRegion = string(cart.GetTaxRegion())
+		cartDto.Tax = &tax
+	}
+
+	return cartDto
+}
+
+func formatRate(rate *big.Rat) string {
+	formatted := strings.TrimRight(rate.FloatString(6), "0")
+	return strings.TrimSuffix(formatted, ".")
+}
+
+func mapShippingToDto(shipping domain.ShippingQuote) *ShippingDto {
+	return &ShippingDto{
+		Method: string(shipping.GetMethod()),
+		Name:   shipping.GetName(),
+		Cost:   PriceDto(shipping.GetCost()),
+	}
+}
+
+func mapAdjustmentsToDtos(adjustments []domain.Adjustment) []DiscountDto {
+	var discountDtos []DiscountDto
+	for _, adjustment := range adjustments {
+		discountDto := DiscountDto{
+			Promotion: adjustment.GetPromotion(),
+			Amount:    PriceDto(adjustment.GetAmount()),
+		}
+		if adjustment.IsLineAdjustment() {
+			productId := uuid.UUID(adjustment.GetProductId())
+			discountDto.ProductId = &productId
+		}
+		discountDtos = append(discountDtos, discountDto)
+	}
+
+	return discountDtos
 }
@@ -6,19 +6,128 @@ import (
 
 type CreateCartCommand struct {
 	CustomerId uuid.UUID `json:"customer_id" validate:"required"`
+	Currency   string    `json:"currency" validate:"omitempty,len=3,alpha"`
 }
 
 type AddItemToCartCommand struct {
-	CartId    uuid.UUID `validate:"required"`
-	ProductId uuid.UUID `json:"product_id" validate:"required"`
-	Quantity  int       `json:"quantity" validate:"required,gt=0"`
+	CartId          uuid.UUID `validate:"required"`
+	ProductId       uuid.UUID `json:"product_id" validate:"required_without=SKU"`
+	SKU             string    `json:"sku" validate:"required_without=ProductId,excluded_with=ProductId"`
+	Quantity        int       `json:"quantity" validate:"required,gt=0"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type RemoveItemFromCartCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	ProductId       uuid.UUID `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type UpdateItemQuantityCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	ProductId       uuid.UUID `validate:"required"`
+	Quantity        int       `json:"quantity" validate:"required,gt=0"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type ClearCartCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type ApplyCouponCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	Code            string    `json:"code" validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type RemoveCouponCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	Code            string    `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type SelectShippingCommand struct {
+	CartId          uuid.UUID `validate:"required"`
+	Method          string    `json:"method" validate:"required"`
+	ExpectedVersion *int      `json:"-"`
 }
 
 type CreateCustomerCommand struct {
-	CustomerName string `json:"customer_name" validate:"required,gte=8"`
+	CustomerName    string      `json:"customer_name" validate:"required,gte=8"`
+	Email           string      `json:"email" validate:"omitempty,email"`
+	Phone           string      `json:"phone"`
+	ShippingAddress *AddressDto `json:"shipping_address"`
+	BillingAddress  *AddressDto `json:"billing_address"`
+}
+
+type UpdateCustomerCommand struct {
+	CustomerId      uuid.UUID   `validate:"required"`
+	CustomerName    *string     `json:"customer_name" validate:"omitempty,gte=8"`
+	Email           *string     `json:"email" validate:"omitempty,email"`
+	Phone           *string     `json:"phone"`
+	ShippingAddress *AddressDto `json:"shipping_address"`
+	BillingAddress  *AddressDto `json:"billing_address"`
+	ExpectedVersion *int        `json:"-"`
+}
+
+type DeactivateCustomerCommand struct {
+	CustomerId      uuid.UUID `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
 }
 
 type CreateProductCommand struct {
-	ProductName string  `json:"product_name" validate:"required,gte=10"`
-	UnitPrice   float64 `json:"unit_price" validate:"required,gt=0"`
+	SKU         string         `json:"sku" validate:"required"`
+	ProductName string         `json:"product_name" validate:"required,gte=10"`
+	UnitPrice   AmountDto      `json:"unit_price" validate:"required,gt=0"`
+	Currency    string         `json:"currency" validate:"omitempty,len=3,alpha"`
+	TaxCategory string         `json:"tax_category"`
+	WeightGrams int            `json:"weight_grams" validate:"gte=0"`
+	Dimensions  *DimensionsDto `json:"dimensions"`
+}
+
+type UpdateProductCommand struct {
+	ProductId       uuid.UUID      `validate:"required"`
+	ProductName     *string        `json:"product_name" validate:"omitempty,gte=10"`
+	UnitPrice       AmountDto      `json:"unit_price" validate:"omitempty,gt=0"`
+	Currency        string         `json:"currency" validate:"omitempty,len=3,alpha"`
+	TaxCategory     string         `json:"tax_category"`
+	WeightGrams     *int           `json:"weight_grams" validate:"omitempty,gte=0"`
+	Dimensions      *DimensionsDto `json:"dimensions"`
+	ExpectedVersion *int           `json:"-"`
+}
+
+type ArchiveProductCommand struct {
+	ProductId       uuid.UUID `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type RestoreProductCommand struct {
+	ProductId       uuid.UUID `validate:"required"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type UpdateStockCommand struct {
+	ProductId       uuid.UUID `validate:"required"`
+	OnHand          *int      `json:"on_hand" validate:"required,gte=0"`
+	ExpectedVersion *int      `json:"-"`
+}
+
+type CheckoutCartCommand struct {
+	CartId          uuid.UUID  `validate:"required"`
+	QuoteId         *uuid.UUID `json:"quote_id"`
+	ExpectedVersion *int       `json:"-"`
+}
+
+type CreateQuoteCommand struct {
+	CartId        uuid.UUID `validate:"required"`
+	ValidForHours int       `json:"valid_for_hours" validate:"gte=0,lte=2160"`
+}
+
+type AcceptQuoteCommand struct {
+	QuoteId uuid.UUID `validate:"required"`
+}
+
+type DeclineQuoteCommand struct {
+	QuoteId uuid.UUID `validate:"required"`
 }
@@ -8,31 +8,195 @@ import (
 )
 
 type CustomerService struct {
-	repository domain.CustomerRepository
+	repository      domain.CustomerRepository
+	eventDispatcher domain.EventDispatcher
 }
 
-func NewCustomerService(repository domain.CustomerRepository) (*CustomerService, error) {
+func NewCustomerService(repository domain.CustomerRepository, eventDispatcher domain.EventDispatcher) (*CustomerService, error) {
 	if repository == nil {
 		return nil, errors.New("customer repository was nil")
 	}
 
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
 	return &CustomerService{
-		repository: repository,
+		repository:      repository,
+		eventDispatcher: eventDispatcher,
 	}, nil
 }
 
 func (s *CustomerService) CreateNewCustomer(command CreateCustomerCommand) (CustomerDto, error) {
-	newCustomer, err := domain.NewCustomer(command.CustomerName)
+	var options []domain.CustomerOption
+	if command.Email != "" {
+		email, err := domain.NewEmail(command.Email)
+		if err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("email", err.Error())
+		}
+		options = append(options, domain.WithEmail(email))
+	}
+
+	if command.Phone != "" {
+		phone, err := domain.NewPhoneNumber(command.Phone)
+		if err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("phone", err.Error())
+		}
+		options = append(options, domain.WithPhoneNumber(phone))
+	}
+
+	if command.ShippingAddress != nil {
+		address, err := parseAddress("shipping_address", *command.ShippingAddress)
+		if err != nil {
+			return CustomerDto{}, err
+		}
+		options = append(options, domain.WithShippingAddress(address))
+	}
+
+	if command.BillingAddress != nil {
+		address, err := parseAddress("billing_address", *command.BillingAddress)
+		if err != nil {
+			return CustomerDto{}, err
+		}
+		options = append(options, domain.WithBillingAddress(address))
+	}
+
+	newCustomer, err := domain.NewCustomer(command.CustomerName, options...)
 	if err != nil {
 		return CustomerDto{}, err
 	}
 
-	if err := s.repository.Save(newCustomer); err != nil {
+	return s.saveCustomer(newCustomer)
+}
+
+func (s *CustomerService) UpdateCustomer(command UpdateCustomerCommand) (CustomerDto, error) {
+	customer, err := s.repository.FindByID(domain.CustomerId(command.CustomerId))
+	if err != nil || customer == nil {
+		return CustomerDto{}, NewNotFoundError(command.CustomerId.String(), "customer")
+	}
+
+	if err = ensureVersion[domain.CustomerId](customer, command.ExpectedVersion, "customer"); err != nil {
+		return CustomerDto{}, err
+	}
+
+	if !customer.IsActive() {
+		return CustomerDto{}, NewInvalidArgumentError("customer", "it is deactivated")
+	}
+
+	if command.CustomerName != nil {
+		if err := customer.Rename(*command.CustomerName); err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("customer_name", err.Error())
+		}
+	}
+
+	if command.Email != nil {
+		email, err := domain.NewEmail(*command.Email)
+		if err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("email", err.Error())
+		}
+		if err := customer.ChangeEmail(email); err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("email", err.Error())
+		}
+	}
+
+	if command.Phone != nil || command.ShippingAddress != nil || command.BillingAddress != nil {
+		phone := customer.GetPhoneNumber()
+		if command.Phone != nil {
+			phone = ""
+			if *command.Phone != "" {
+				if phone, err = domain.NewPhoneNumber(*command.Phone); err != nil {
+					return CustomerDto{}, NewInvalidArgumentError("phone", err.Error())
+				}
+			}
+		}
+
+		shippingAddress := customer.GetShippingAddress()
+		if command.ShippingAddress != nil {
+			if shippingAddress, err = parseAddress("shipping_address", *command.ShippingAddress); err != nil {
+				return CustomerDto{}, err
+			}
+		}
+
+		billingAddress := customer.GetBillingAddress()
+		if command.BillingAddress != nil {
+			if billingAddress, err = parseAddress("billing_address", *command.BillingAddress); err != nil {
+				return CustomerDto{}, err
+			}
+		}
+
+		if err := customer.ChangeContactDetails(phone, shippingAddress, billingAddress); err != nil {
+			return CustomerDto{}, NewInvalidArgumentError("customer", err.Error())
+		}
+	}
+
+	return s.saveCustomer(customer)
+}
+
+func (s *CustomerService) DeactivateCustomer(command DeactivateCustomerCommand) (CustomerDto, error) {
+	customer, err := s.repository.FindByID(domain.CustomerId(command.CustomerId))
+	if err != nil || customer == nil {
+		return CustomerDto{}, NewNotFoundError(command.CustomerId.String(), "customer")
+	}
+
+	if err = ensureVersion[domain.CustomerId](customer, command.ExpectedVersion, "customer"); err != nil {
 		return CustomerDto{}, err
 	}
 
+	customer.Deactivate()
+
+	return s.saveCustomer(customer)
+}
+
+func (s *CustomerService) GetCustomer(query GetCustomerQuery) (CustomerDto, error) {
+	customer, err := s.repository.FindByID(domain.CustomerId(query.CustomerId))
+	if err != nil || customer == nil {
+		return CustomerDto{}, NewNotFoundError(query.CustomerId.String(), "customer")
+	}
+
+	return mapCustomerToDto(customer), nil
+}
+
+func (s *CustomerService) saveCustomer(customer *domain.Customer) (CustomerDto, error) {
+	if err := s.repository.Save(customer); err != nil {
+		return CustomerDto{}, mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.CustomerId](s.eventDispatcher, customer)
+
+	return mapCustomerToDto(customer), nil
+}
+
+func parseAddress(field string, addressDto AddressDto) (domain.Address, error) {
+	address, err := domain.NewAddress(addressDto.Street, addressDto.City, addressDto.PostalCode, addressDto.Country)
+	if err != nil {
+		return domain.Address{}, NewInvalidArgumentError(field, err.Error())
+	}
+
+	return address, nil
+}
+
+func mapAddressToDto(address domain.Address) *AddressDto {
+	if address.IsZero() {
+		return nil
+	}
+
+	return &AddressDto{
+		Street:     address.GetStreet(),
+		City:       address.GetCity(),
+		PostalCode: address.GetPostalCode(),
+		Country:    address.GetCountry(),
+	}
+}
+
+func mapCustomerToDto(customer *domain.Customer) CustomerDto {
 	return CustomerDto{
-		Id:   uuid.UUID(newCustomer.GetID()),
-		Name: newCustomer.GetName(),
-	}, nil
+		Id:              uuid.UUID(customer.GetID()),
+		Name:            customer.GetName(),
+		Email:           string(customer.GetEmail()),
+		Phone:           string(customer.GetPhoneNumber()),
+		ShippingAddress: mapAddressToDto(customer.GetShippingAddress()),
+		BillingAddress:  mapAddressToDto(customer.GetBillingAddress()),
+		Active:          customer.IsActive(),
+		Version:         customer.GetVersion(),
+	}
 }
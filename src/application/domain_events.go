@@ -0,0 +1,19 @@
+package application
+
+import (
+	"log"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+func dispatchDomainEvents[K comparable](dispatcher domain.EventDispatcher, entity domain.Entity[K]) {
+	events := entity.GetDomainEvents()
+	if len(events) == 0 {
+		return
+	}
+
+	entity.ClearDomainEvents()
+	if err := dispatcher.Dispatch(events...); err != nil {
+		log.Printf("failed to dispatch the domain events of %T %v after it was saved: %v", entity, entity.GetID(), err)
+	}
+}
@@ -1,36 +1,435 @@
 package application
 
 import (
-	"strconv"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/google/uuid"
 )
 
-type PriceDto float64
+type PriceDto domain.Money
 
 func (p PriceDto) MarshalJSON() ([]byte, error) {
-	return []byte(strconv.FormatFloat(float64(p), 'f', 2, 64)), nil
+	return []byte(domain.Money(p).Decimal()), nil
+}
+
+type AmountDto string
+
+func (a *AmountDto) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var amount string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &amount); err != nil {
+			return err
+		}
+	} else {
+		amount = string(data)
+	}
+
+	if _, err := domain.ParseDecimal(amount); err != nil {
+		return errors.New("amount must be a number or a numeric string")
+	}
+
+	*a = AmountDto(amount)
+	return nil
+}
+
+func (a AmountDto) Float64() (float64, bool) {
+	value, err := domain.ParseDecimal(string(a))
+	if err != nil {
+		return 0, false
+	}
+
+	float, _ := value.Float64()
+	return float, true
 }
 
 type CartDto struct {
-	Id         uuid.UUID `json:"id"`
-	CustomerId uuid.UUID `json:"customer_id"`
-	Items      []ItemDto `json:"items"`
+	Id             uuid.UUID     `json:"id"`
+	CustomerId     uuid.UUID     `json:"customer_id"`
+	Currency       string        `json:"currency"`
+	Status         string        `json:"status"`
+	LastActivityAt time.Time     `json:"last_activity_at"`
+	Items          []ItemDto     `json:"items"`
+	Coupons        []string      `json:"coupons,omitempty"`
+	Subtotal       PriceDto      `json:"subtotal"`
+	LineDiscounts  []DiscountDto `json:"line_discounts,omitempty"`
+	CartDiscounts  []DiscountDto `json:"cart_discounts,omitempty"`
+	Shipping       *ShippingDto  `json:"shipping,omitempty"`
+	TaxRegion      string        `json:"tax_region,omitempty"`
+	Tax            *PriceDto     `json:"tax,omitempty"`
+	Total          PriceDto      `json:"total"`
+	Version        int           `json:"-"`
+	Reused         bool          `json:"-"`
+}
+
+type DiscountDto struct {
+	Promotion string     `json:"promotion"`
+	ProductId *uuid.UUID `json:"product_id,omitempty"`
+	Amount    PriceDto   `json:"amount"`
+}
+
+type ShippingDto struct {
+	Method string   `json:"method"`
+	Name   string   `json:"name"`
+	Cost   PriceDto `json:"cost"`
+}
+
+type ShippingOptionDto struct {
+	Method   string   `json:"method"`
+	Name     string   `json:"name"`
+	Cost     PriceDto `json:"cost"`
+	Currency string   `json:"currency"`
+	Selected bool     `json:"selected"`
 }
 
 type ItemDto struct {
-	ProductId uuid.UUID `json:"product_id"`
-	UnitPrice PriceDto  `json:"unit_price"`
-	Quantity  int       `json:"quantity"`
+	ProductId      uuid.UUID `json:"product_id"`
+	UnitPrice      PriceDto  `json:"unit_price"`
+	Currency       string    `json:"currency"`
+	Quantity       int       `json:"quantity"`
+	PriceChanged   bool      `json:"price_changed"`
+	AddedUnitPrice *PriceDto `json:"added_unit_price,omitempty"`
+	TaxCategory    string    `json:"tax_category,omitempty"`
+	TaxRate        string    `json:"tax_rate,omitempty"`
+	TaxIncluded    bool      `json:"tax_included,omitempty"`
+	Tax            *PriceDto `json:"tax,omitempty"`
+}
+
+type OrderDto struct {
+	Id            uuid.UUID     `json:"id"`
+	QuoteId       *uuid.UUID    `json:"quote_id,omitempty"`
+	CartId        uuid.UUID     `json:"cart_id"`
+	CustomerId    uuid.UUID     `json:"customer_id"`
+	Currency      string        `json:"currency"`
+	Lines         []LineDto     `json:"lines"`
+	Subtotal      PriceDto      `json:"subtotal"`
+	LineDiscounts []DiscountDto `json:"line_discounts,omitempty"`
+	CartDiscounts []DiscountDto `json:"cart_discounts,omitempty"`
+	Shipping      *ShippingDto  `json:"shipping,omitempty"`
+	Tax           *PriceDto     `json:"tax,omitempty"`
+	Total         PriceDto      `json:"total"`
+	PlacedOn      time.Time     `json:"placed_on"`
+}
+
+type QuoteDto struct {
+	Id            uuid.UUID     `json:"id"`
+	Number        string        `json:"number"`
+	CartId        uuid.UUID     `json:"cart_id"`
+	CustomerId    uuid.UUID     `json:"customer_id"`
+	Currency      string        `json:"currency"`
+	Lines         []LineDto     `json:"lines"`
+	Subtotal      PriceDto      `json:"subtotal"`
+	LineDiscounts []DiscountDto `json:"line_discounts,omitempty"`
+	CartDiscounts []DiscountDto `json:"cart_discounts,omitempty"`
+	Shipping      *ShippingDto  `json:"shipping,omitempty"`
+	Tax           *PriceDto     `json:"tax,omitempty"`
+	Total         PriceDto      `json:"total"`
+	Status        string        `json:"status"`
+	IssuedAt      time.Time     `json:"issued_at"`
+	ExpiresAt     time.Time     `json:"expires_at"`
+}
+
+type LineDto struct {
+	ProductId   uuid.UUID `json:"product_id"`
+	UnitPrice   PriceDto  `json:"unit_price"`
+	Currency    string    `json:"currency"`
+	Quantity    int       `json:"quantity"`
+	Total       PriceDto  `json:"total"`
+	TaxRate     string    `json:"tax_rate,omitempty"`
+	TaxIncluded bool      `json:"tax_included,omitempty"`
+	Tax         *PriceDto `json:"tax,omitempty"`
+}
+
+type ConflictDto struct {
+	Message string `json:"message"`
+	Field   string `json:"field"`
 }
 
 type CustomerDto struct {
-	Id   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	Id              uuid.UUID   `json:"id"`
+	Name            string      `json:"name"`
+	Email           string      `json:"email,omitempty"`
+	Phone           string      `json:"phone,omitempty"`
+	ShippingAddress *AddressDto `json:"shipping_address,omitempty"`
+	BillingAddress  *AddressDto `json:"billing_address,omitempty"`
+	Active          bool        `json:"active"`
+	Version         int         `json:"-"`
+}
+
+type AddressDto struct {
+	Street     string `json:"street" validate:"required"`
+	City       string `json:"city" validate:"required"`
+	PostalCode string `json:"postal_code" validate:"required"`
+	Country    string `json:"country" validate:"required,len=2,alpha"`
 }
 
 type ProductDto struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	UnitPrice PriceDto  `json:"unit_price"`
+	Id          uuid.UUID      `json:"id"`
+	SKU         string         `json:"sku,omitempty"`
+	Name        string         `json:"name"`
+	UnitPrice   PriceDto       `json:"unit_price"`
+	Currency    string         `json:"currency"`
+	TaxCategory string         `json:"tax_category,omitempty"`
+	WeightGrams int            `json:"weight_grams,omitempty"`
+	Dimensions  *DimensionsDto `json:"dimensions,omitempty"`
+	Archived    bool           `json:"archived"`
+	Version     int            `json:"-"`
+}
+
+type DimensionsDto struct {
+	LengthMm int `json:"length_mm" validate:"gt=0"`
+	WidthMm  int `json:"width_mm" validate:"gt=0"`
+	HeightMm int `json:"height_mm" validate:"gt=0"`
+}
+
+type ProductPageDto struct {
+	Items      []ProductDto `json:"items"`
+	NextCursor *string      `json:"next_cursor"`
+}
+
+type IdleCartsDto struct {
+	Abandoned []uuid.UUID `json:"abandoned"`
+	Expired   []uuid.UUID `json:"expired"`
+}
+
+type ExpiredQuotesDto struct {
+	Expired []uuid.UUID `json:"expired"`
+}
+
+type StockDto struct {
+	ProductId uuid.UUID `json:"product_id"`
+	OnHand    int       `json:"on_hand"`
+	Reserved  int       `json:"reserved"`
+	Available int       `json:"available"`
+	Version   int       `json:"-"`
+}
+
+type priceParser struct {
+	err error
+}
+
+func (p *priceParser) price(amount AmountDto, currency string) PriceDto {
+	if p.err != nil {
+		return PriceDto{}
+	}
+
+	money, err := domain.ParseMoney(string(amount), domain.Currency(currency))
+	if err != nil {
+		p.err = err
+		return PriceDto{}
+	}
+
+	return PriceDto(money)
+}
+
+func (p *priceParser) optionalPrice(amount *AmountDto, currency string) *PriceDto {
+	if amount == nil {
+		return nil
+	}
+
+	price := p.price(*amount, currency)
+	return &price
+}
+
+func parseEach[W any, D any](wires []W, parse func(W) D) []D {
+	if wires == nil {
+		return nil
+	}
+
+	dtos := make([]D, 0, len(wires))
+	for _, wire := range wires {
+		dtos = append(dtos, parse(wire))
+	}
+
+	return dtos
+}
+
+type itemFields ItemDto
+
+type itemJSON struct {
+	itemFields
+	UnitPrice      AmountDto  `json:"unit_price"`
+	AddedUnitPrice *AmountDto `json:"added_unit_price"`
+	Tax            *AmountDto `json:"tax"`
+}
+
+type lineFields LineDto
+
+type lineJSON struct {
+	lineFields
+	UnitPrice AmountDto  `json:"unit_price"`
+	Total     AmountDto  `json:"total"`
+	Tax       *AmountDto `json:"tax"`
+}
+
+type discountFields DiscountDto
+
+type discountJSON struct {
+	discountFields
+	Amount AmountDto `json:"amount"`
+}
+
+type shippingFields ShippingDto
+
+type shippingJSON struct {
+	shippingFields
+	Cost AmountDto `json:"cost"`
+}
+
+func parseDiscounts(prices *priceParser, discounts []discountJSON, currency string) []DiscountDto {
+	return parseEach(discounts, func(discount discountJSON) DiscountDto {
+		discountDto := DiscountDto(discount.discountFields)
+		discountDto.Amount = prices.price(discount.Amount, currency)
+		return discountDto
+	})
+}
+
+func parseShipping(prices *priceParser, shipping *shippingJSON, currency string) *ShippingDto {
+	if shipping == nil {
+		return nil
+	}
+
+	shippingDto := ShippingDto(shipping.shippingFields)
+	shippingDto.Cost = prices.price(shipping.Cost, currency)
+	return &shippingDto
+}
+
+func parseLines(prices *priceParser, lines []lineJSON, currency string) []LineDto {
+	return parseEach(lines, func(line lineJSON) LineDto {
+		lineDto := LineDto(line.lineFields)
+		lineDto.UnitPrice = prices.price(line.UnitPrice, lineDto.Currency)
+		lineDto.Total = prices.price(line.Total, currency)
+		lineDto.Tax = prices.optionalPrice(line.Tax, currency)
+		return lineDto
+	})
+}
+
+func (c *CartDto) UnmarshalJSON(data []byte) error {
+	type cartFields CartDto
+	decoded := struct {
+		*cartFields
+		Items         []itemJSON     `json:"items"`
+		Subtotal      AmountDto      `json:"subtotal"`
+		LineDiscounts []discountJSON `json:"line_discounts"`
+		CartDiscounts []discountJSON `json:"cart_discounts"`
+		Shipping      *shippingJSON  `json:"shipping"`
+		Tax           *AmountDto     `json:"tax"`
+		Total         AmountDto      `json:"total"`
+	}{cartFields: (*cartFields)(c)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prices := &priceParser{}
+	c.Items = parseEach(decoded.Items, func(item itemJSON) ItemDto {
+		itemDto := ItemDto(item.itemFields)
+		itemDto.UnitPrice = prices.price(item.UnitPrice, itemDto.Currency)
+		itemDto.AddedUnitPrice = prices.optionalPrice(item.AddedUnitPrice, itemDto.Currency)
+		itemDto.Tax = prices.optionalPrice(item.Tax, c.Currency)
+		return itemDto
+	})
+	c.Subtotal = prices.price(decoded.Subtotal, c.Currency)
+	c.LineDiscounts = parseDiscounts(prices, decoded.LineDiscounts, c.Currency)
+	c.CartDiscounts = parseDiscounts(prices, decoded.CartDiscounts, c.Currency)
+	c.Shipping = parseShipping(prices, decoded.Shipping, c.Currency)
+	c.Tax = prices.optionalPrice(decoded.Tax, c.Currency)
+	c.Total = prices.price(decoded.Total, c.Currency)
+
+	return prices.err
+}
+
+func (o *OrderDto) UnmarshalJSON(data []byte) error {
+	type orderFields OrderDto
+	decoded := struct {
+		*orderFields
+		Lines         []lineJSON     `json:"lines"`
+		Subtotal      AmountDto      `json:"subtotal"`
+		LineDiscounts []discountJSON `json:"line_discounts"`
+		CartDiscounts []discountJSON `json:"cart_discounts"`
+		Shipping      *shippingJSON  `json:"shipping"`
+		Tax           *AmountDto     `json:"tax"`
+		Total         AmountDto      `json:"total"`
+	}{orderFields: (*orderFields)(o)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prices := &priceParser{}
+	o.Lines = parseLines(prices, decoded.Lines, o.Currency)
+	o.Subtotal = prices.price(decoded.Subtotal, o.Currency)
+	o.LineDiscounts = parseDiscounts(prices, decoded.LineDiscounts, o.Currency)
+	o.CartDiscounts = parseDiscounts(prices, decoded.CartDiscounts, o.Currency)
+	o.Shipping = parseShipping(prices, decoded.Shipping, o.Currency)
+	o.Tax = prices.optionalPrice(decoded.Tax, o.Currency)
+	o.Total = prices.price(decoded.Total, o.Currency)
+
+	return prices.err
+}
+
+func (q *QuoteDto) UnmarshalJSON(data []byte) error {
+	type quoteFields QuoteDto
+	decoded := struct {
+		*quoteFields
+		Lines         []lineJSON     `json:"lines"`
+		Subtotal      AmountDto      `json:"subtotal"`
+		LineDiscounts []discountJSON `json:"line_discounts"`
+		CartDiscounts []discountJSON `json:"cart_discounts"`
+		Shipping      *shippingJSON  `json:"shipping"`
+		Tax           *AmountDto     `json:"tax"`
+		Total         AmountDto      `json:"total"`
+	}{quoteFields: (*quoteFields)(q)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prices := &priceParser{}
+	q.Lines = parseLines(prices, decoded.Lines, q.Currency)
+	q.Subtotal = prices.price(decoded.Subtotal, q.Currency)
+	q.LineDiscounts = parseDiscounts(prices, decoded.LineDiscounts, q.Currency)
+	q.CartDiscounts = parseDiscounts(prices, decoded.CartDiscounts, q.Currency)
+	q.Shipping = parseShipping(prices, decoded.Shipping, q.Currency)
+	q.Tax = prices.optionalPrice(decoded.Tax, q.Currency)
+	q.Total = prices.price(decoded.Total, q.Currency)
+
+	return prices.err
+}
+
+func (p *ProductDto) UnmarshalJSON(data []byte) error {
+	type productFields ProductDto
+	decoded := struct {
+		*productFields
+		UnitPrice AmountDto `json:"unit_price"`
+	}{productFields: (*productFields)(p)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prices := &priceParser{}
+	p.UnitPrice = prices.price(decoded.UnitPrice, p.Currency)
+
+	return prices.err
+}
+
+func (s *ShippingOptionDto) UnmarshalJSON(data []byte) error {
+	type shippingOptionFields ShippingOptionDto
+	decoded := struct {
+		*shippingOptionFields
+		Cost AmountDto `json:"cost"`
+	}{shippingOptionFields: (*shippingOptionFields)(s)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prices := &priceParser{}
+	s.Cost = prices.price(decoded.Cost, s.Currency)
+
+	return prices.err
 }
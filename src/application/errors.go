@@ -1,6 +1,12 @@
 package application
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
 
 type NotFoundError struct {
 	entityId   string
@@ -17,3 +23,160 @@ func NewNotFoundError(entityId string, entityType string) error {
 		entityType: entityType,
 	}
 }
+
+type InvalidArgumentError struct {
+	argument string
+	reason   string
+}
+
+func (e InvalidArgumentError) Error() string {
+	return fmt.Sprintf(`invalid %s: %s`, e.argument, e.reason)
+}
+
+func NewInvalidArgumentError(argument string, reason string) error {
+	return &InvalidArgumentError{
+		argument: argument,
+		reason:   reason,
+	}
+}
+
+type InsufficientStockError struct {
+	productId string
+	requested int
+	available int
+}
+
+func (e InsufficientStockError) Error() string {
+	return fmt.Sprintf(`insufficient stock for product %s: requested %d, available %d`, e.productId, e.requested, e.available)
+}
+
+func NewInsufficientStockError(productId string, requested int, available int) error {
+	return &InsufficientStockError{
+		productId: productId,
+		requested: requested,
+		available: available,
+	}
+}
+
+type ProductArchivedError struct {
+	productId string
+}
+
+func (e ProductArchivedError) Error() string {
+	return fmt.Sprintf(`product %s is archived and can no longer be added to carts`, e.productId)
+}
+
+func (e ProductArchivedError) Unwrap() error {
+	return domain.ErrProductArchived
+}
+
+func NewProductArchivedError(productId string) error {
+	return &ProductArchivedError{
+		productId: productId,
+	}
+}
+
+type ConflictError struct {
+	field string
+	value string
+}
+
+func (e ConflictError) Error() string {
+	return fmt.Sprintf(`%s %q is already in use`, e.field, e.value)
+}
+
+func (e ConflictError) Field() string {
+	return e.field
+}
+
+func NewConflictError(field string, value string) error {
+	return &ConflictError{
+		field: field,
+		value: value,
+	}
+}
+
+type PreconditionFailedError struct {
+	entityId        string
+	entityType      string
+	expectedVersion int
+	actualVersion   int
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf(`%s with id %s is at version %d, not %d`, e.entityType, e.entityId, e.actualVersion, e.expectedVersion)
+}
+
+func NewPreconditionFailedError(entityId string, entityType string, expectedVersion int, actualVersion int) error {
+	return &PreconditionFailedError{
+		entityId:        entityId,
+		entityType:      entityType,
+		expectedVersion: expectedVersion,
+		actualVersion:   actualVersion,
+	}
+}
+
+type ConcurrencyConflictError struct {
+	entityId string
+}
+
+func (e ConcurrencyConflictError) Error() string {
+	return fmt.Sprintf(`%s was modified concurrently, reload it and try again`, e.entityId)
+}
+
+func NewConcurrencyConflictError(entityId string) error {
+	return &ConcurrencyConflictError{
+		entityId: entityId,
+	}
+}
+
+type joinedError struct {
+	errs []error
+}
+
+func (e joinedError) Error() string {
+	messages := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e joinedError) Unwrap() []error {
+	return e.errs
+}
+
+func joinErrors(errs ...error) error {
+	var joined []error
+	for _, err := range errs {
+		if err != nil {
+			joined = append(joined, err)
+		}
+	}
+
+	if len(joined) == 0 {
+		return nil
+	}
+
+	return &joinedError{errs: joined}
+}
+
+func isConcurrencyConflict(err error) bool {
+	var concurrencyConflictError *ConcurrencyConflictError
+	return errors.As(err, &concurrencyConflictError)
+}
+
+func mapRepositoryError(err error) error {
+	var uniqueConstraintError *domain.UniqueConstraintError
+	if errors.As(err, &uniqueConstraintError) {
+		return NewConflictError(uniqueConstraintError.Field, uniqueConstraintError.Value)
+	}
+
+	var concurrencyConflictError *domain.ConcurrencyConflictError
+	if errors.As(err, &concurrencyConflictError) {
+		return NewConcurrencyConflictError(concurrencyConflictError.Id)
+	}
+
+	return err
+}
@@ -0,0 +1,224 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+type OrderService struct {
+	cartRepository    domain.CartRepository
+	orderRepository   domain.OrderRepository
+	eventDispatcher   domain.EventDispatcher
+	productRepository domain.ProductRepository
+	exchangeRates     domain.ExchangeRateProvider
+	quoteRepository   domain.QuoteRepository
+	clock             domain.Clock
+}
+
+type OrderServiceOption func(*OrderService)
+
+func WithCheckoutRepricing(productRepository domain.ProductRepository, exchangeRates domain.ExchangeRateProvider) OrderServiceOption {
+	return func(s *OrderService) {
+		s.productRepository = productRepository
+		s.exchangeRates = exchangeRates
+	}
+}
+
+func WithQuotes(quoteRepository domain.QuoteRepository, clock domain.Clock) OrderServiceOption {
+	return func(s *OrderService) {
+		s.quoteRepository = quoteRepository
+		s.clock = clock
+	}
+}
+
+func NewOrderService(cartRepository domain.CartRepository, orderRepository domain.OrderRepository, eventDispatcher domain.EventDispatcher, options ...OrderServiceOption) (*OrderService, error) {
+	if cartRepository == nil {
+		return nil, errors.New("cart repository was nil")
+	}
+
+	if orderRepository == nil {
+		return nil, errors.New("order repository was nil")
+	}
+
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
+	service := &OrderService{
+		cartRepository:  cartRepository,
+		orderRepository: orderRepository,
+		eventDispatcher: eventDispatcher,
+		clock:           domain.SystemClock(),
+	}
+
+	for _, option := range options {
+		option(service)
+	}
+
+	return service, nil
+}
+
+func (s *OrderService) CheckoutCart(command CheckoutCartCommand) (OrderDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil || cart == nil {
+		return OrderDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	if err := ensureVersion[domain.CartId](cart, command.ExpectedVersion, "cart"); err != nil {
+		return OrderDto{}, err
+	}
+
+	var quote *domain.Quote
+	if command.QuoteId != nil {
+		quote, err = s.findQuote(*command.QuoteId)
+		if err != nil {
+			return OrderDto{}, err
+		}
+	} else if err := s.repriceBeforeCheckout(cart); err != nil {
+		return OrderDto{}, err
+	}
+
+	order, err := placeOrder(cart, quote, s.clock.Now().UTC())
+	if err != nil {
+		return OrderDto{}, mapCheckoutError(err, quote)
+	}
+
+	if err := s.orderRepository.Save(order); err != nil {
+		return OrderDto{}, mapRepositoryError(err)
+	}
+
+	if err := s.cartRepository.Save(cart); err != nil {
+		return OrderDto{}, mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.CartId](s.eventDispatcher, cart)
+
+	return mapOrderToDto(order), nil
+}
+
+func (s *OrderService) findQuote(quoteId uuid.UUID) (*domain.Quote, error) {
+	if s.quoteRepository == nil {
+		return nil, NewNotFoundError(quoteId.String(), "quote")
+	}
+
+	quote, err := s.quoteRepository.FindByID(domain.QuoteId(quoteId))
+	if err != nil || quote == nil {
+		return nil, NewNotFoundError(quoteId.String(), "quote")
+	}
+
+	return quote, nil
+}
+
+func placeOrder(cart *domain.Cart, quote *domain.Quote, placedOn time.Time) (*domain.Order, error) {
+	if quote == nil {
+		return domain.PlaceOrder(cart, placedOn)
+	}
+
+	return domain.PlaceOrderFromQuote(cart, quote, placedOn)
+}
+
+func mapCheckoutError(err error, quote *domain.Quote) error {
+	switch {
+	case errors.Is(err, domain.ErrQuoteExpired):
+		return NewInvalidArgumentError("quote", "it expired at "+quote.GetExpiresAt().Format(time.RFC3339))
+	case errors.Is(err, domain.ErrQuoteNotAccepted):
+		return NewInvalidArgumentError("quote", "it is "+string(quote.GetStatus())+" and must be accepted before checkout")
+	case errors.Is(err, domain.ErrQuoteCartMismatch):
+		return NewInvalidArgumentError("quote", "it was issued for another cart")
+	case errors.Is(err, domain.ErrQuoteCartChanged):
+		return NewInvalidArgumentError("quote", "the cart items changed since it was issued")
+	default:
+		return mapCartError(err)
+	}
+}
+
+func (s *OrderService) repriceBeforeCheckout(cart *domain.Cart) error {
+	if s.productRepository == nil || !cart.IsActive() {
+		return nil
+	}
+
+	repriced, err := repriceCart(cart, s.productRepository, s.exchangeRates)
+	if err != nil || len(repriced) == 0 {
+		return err
+	}
+
+	// The repriced cart is saved before rejecting the checkout so the customer can review the
+	// changed lines, and the next checkout places the order at the prices they reviewed.
+	if err := s.cartRepository.Save(cart); err != nil {
+		return mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.CartId](s.eventDispatcher, cart)
+
+	return NewInvalidArgumentError("cart", "prices changed for products "+joinProductIds(repriced)+" since they were added; review the cart and check out again")
+}
+
+func (s *OrderService) GetOrder(query GetOrderQuery) (OrderDto, error) {
+	order, err := s.orderRepository.FindByID(domain.OrderId(query.OrderId))
+	if err != nil || order == nil {
+		return OrderDto{}, NewNotFoundError(query.OrderId.String(), "order")
+	}
+
+	return mapOrderToDto(order), nil
+}
+
+func mapOrderToDto(order *domain.Order) OrderDto {
+	orderDto := OrderDto{
+		Id:            uuid.UUID(order.GetID()),
+		CartId:        uuid.UUID(order.GetCartID()),
+		CustomerId:    uuid.UUID(order.GetCustomerID()),
+		Currency:      string(order.GetCurrency()),
+		Lines:         mapLinesToDto(order.GetLines(), order.GetLineTaxes()),
+		Subtotal:      PriceDto(order.GetSubtotal()),
+		LineDiscounts: mapAdjustmentsToDtos(order.GetLineAdjustments()),
+		CartDiscounts: mapAdjustmentsToDtos(order.GetCartAdjustments()),
+		Total:         PriceDto(order.GetTotal()),
+		PlacedOn:      order.GetPlacedOn(),
+	}
+
+	if order.HasQuote() {
+		quoteId := uuid.UUID(order.GetQuoteID())
+		orderDto.QuoteId = &quoteId
+	}
+
+	if order.HasShipping() {
+		orderDto.Shipping = mapShippingToDto(order.GetShipping())
+	}
+
+	if order.IsTaxed() {
+		tax := PriceDto(order.GetTax())
+		orderDto.Tax = &tax
+	}
+
+	return orderDto
+}
+
+func mapLinesToDto(lines []domain.LineSnapshot, taxes []domain.LineTax) []LineDto {
+	lineTaxes := map[domain.ProductId]domain.LineTax{}
+	for _, lineTax := range taxes {
+		lineTaxes[lineTax.GetProductId()] = lineTax
+	}
+
+	lineDtos := []LineDto{}
+	for _, line := range lines {
+		lineDto := LineDto{
+			ProductId: uuid.UUID(line.GetProductId()),
+			UnitPrice: PriceDto(line.GetUnitPrice()),
+			Currency:  string(line.GetUnitPrice().Currency()),
+			Quantity:  line.GetQuantity(),
+			Total:     PriceDto(line.GetTotal()),
+		}
+		if lineTax, found := lineTaxes[line.GetProductId()]; found {
+			tax := PriceDto(lineTax.GetAmount())
+			lineDto.TaxRate = formatRate(lineTax.GetRate())
+			lineDto.TaxIncluded = lineTax.IsInclusive()
+			lineDto.Tax = &tax
+		}
+		lineDtos = append(lineDtos, lineDto)
+	}
+
+	return lineDtos
+}
@@ -2,38 +2,323 @@ package application
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/google/uuid"
 )
 
 type ProductService struct {
-	repository domain.ProductRepository
+	repository      domain.ProductRepository
+	eventDispatcher domain.EventDispatcher
 }
 
-func NewProductService(repository domain.ProductRepository) (*ProductService, error) {
+func NewProductService(repository domain.ProductRepository, eventDispatcher domain.EventDispatcher) (*ProductService, error) {
 	if repository == nil {
 		return nil, errors.New("repository was nil")
 	}
 
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
 	return &ProductService{
-		repository: repository,
+		repository:      repository,
+		eventDispatcher: eventDispatcher,
 	}, nil
 }
 
 func (s *ProductService) CreateNewProduct(command CreateProductCommand) (ProductDto, error) {
-	newProduct, err := domain.NewProduct(command.ProductName, command.UnitPrice)
+	sku, err := domain.NewSKU(command.SKU)
+	if err != nil {
+		return ProductDto{}, NewInvalidArgumentError("sku", err.Error())
+	}
+
+	currency, err := parseCurrency(command.Currency)
+	if err != nil {
+		return ProductDto{}, err
+	}
+
+	unitPrice, err := domain.ParseMoney(string(command.UnitPrice), currency)
+	if err != nil {
+		return ProductDto{}, NewInvalidArgumentError("unit_price", err.Error())
+	}
+
+	options := []domain.ProductOption{domain.WithSKU(sku)}
+	if command.TaxCategory != "" {
+		taxCategory, err := domain.NewTaxCategory(command.TaxCategory)
+		if err != nil {
+			return ProductDto{}, NewInvalidArgumentError("tax_category", err.Error())
+		}
+		options = append(options, domain.WithTaxCategory(taxCategory))
+	}
+
+	weight, err := domain.NewWeight(command.WeightGrams)
+	if err != nil {
+		return ProductDto{}, NewInvalidArgumentError("weight_grams", err.Error())
+	}
+	options = append(options, domain.WithWeight(weight))
+
+	if command.Dimensions != nil {
+		dimensions, err := parseDimensions(*command.Dimensions)
+		if err != nil {
+			return ProductDto{}, err
+		}
+		options = append(options, domain.WithDimensions(dimensions))
+	}
+
+	newProduct, err := domain.NewProduct(command.ProductName, unitPrice, options...)
 	if err != nil {
 		return ProductDto{}, err
 	}
 
-	if err := s.repository.Save(newProduct); err != nil {
+	return s.saveProduct(newProduct)
+}
+
+func (s *ProductService) UpdateProduct(command UpdateProductCommand) (ProductDto, error) {
+	product, err := s.repository.FindByID(domain.ProductId(command.ProductId))
+	if err != nil || product == nil {
+		return ProductDto{}, NewNotFoundError(command.ProductId.String(), "product")
+	}
+
+	if err = ensureVersion[domain.ProductId](product, command.ExpectedVersion, "product"); err != nil {
 		return ProductDto{}, err
 	}
 
+	if command.ProductName != nil {
+		if err := product.Rename(*command.ProductName); err != nil {
+			return ProductDto{}, NewInvalidArgumentError("product_name", err.Error())
+		}
+	}
+
+	if command.UnitPrice != "" {
+		currency := product.GetPrice().Currency()
+		if command.Currency != "" {
+			if currency, err = parseCurrency(command.Currency); err != nil {
+				return ProductDto{}, err
+			}
+		}
+
+		unitPrice, err := domain.ParseMoney(string(command.UnitPrice), currency)
+		if err != nil {
+			return ProductDto{}, NewInvalidArgumentError("unit_price", err.Error())
+		}
+
+		if err := product.ChangePrice(unitPrice); err != nil {
+			return ProductDto{}, NewInvalidArgumentError("unit_price", err.Error())
+		}
+	} else if command.Currency != "" {
+		return ProductDto{}, NewInvalidArgumentError("currency", "it can only be changed together with unit_price")
+	}
+
+	if command.TaxCategory != "" {
+		taxCategory, err := domain.NewTaxCategory(command.TaxCategory)
+		if err != nil {
+			return ProductDto{}, NewInvalidArgumentError("tax_category", err.Error())
+		}
+
+		if err := product.ChangeTaxCategory(taxCategory); err != nil {
+			return ProductDto{}, NewInvalidArgumentError("tax_category", err.Error())
+		}
+	}
+
+	if command.WeightGrams != nil || command.Dimensions != nil {
+		weight := product.GetWeight()
+		if command.WeightGrams != nil {
+			if weight, err = domain.NewWeight(*command.WeightGrams); err != nil {
+				return ProductDto{}, NewInvalidArgumentError("weight_grams", err.Error())
+			}
+		}
+
+		dimensions := product.GetDimensions()
+		if command.Dimensions != nil {
+			if dimensions, err = parseDimensions(*command.Dimensions); err != nil {
+				return ProductDto{}, err
+			}
+		}
+
+		if err := product.ChangeShippingDetails(weight, dimensions); err != nil {
+			return ProductDto{}, NewInvalidArgumentError("weight_grams", err.Error())
+		}
+	}
+
+	return s.saveProduct(product)
+}
+
+func (s *ProductService) ArchiveProduct(command ArchiveProductCommand) (ProductDto, error) {
+	product, err := s.repository.FindByID(domain.ProductId(command.ProductId))
+	if err != nil || product == nil {
+		return ProductDto{}, NewNotFoundError(command.ProductId.String(), "product")
+	}
+
+	if err = ensureVersion[domain.ProductId](product, command.ExpectedVersion, "product"); err != nil {
+		return ProductDto{}, err
+	}
+
+	product.Archive()
+
+	return s.saveProduct(product)
+}
+
+func (s *ProductService) RestoreProduct(command RestoreProductCommand) (ProductDto, error) {
+	product, err := s.repository.FindByID(domain.ProductId(command.ProductId))
+	if err != nil || product == nil {
+		return ProductDto{}, NewNotFoundError(command.ProductId.String(), "product")
+	}
+
+	if err = ensureVersion[domain.ProductId](product, command.ExpectedVersion, "product"); err != nil {
+		return ProductDto{}, err
+	}
+
+	product.Restore()
+
+	return s.saveProduct(product)
+}
+
+func (s *ProductService) GetProduct(query GetProductQuery) (ProductDto, error) {
+	product, err := s.repository.FindByID(domain.ProductId(query.ProductId))
+	if err != nil || product == nil {
+		return ProductDto{}, NewNotFoundError(query.ProductId.String(), "product")
+	}
+
+	return mapProductToDto(product), nil
+}
+
+func (s *ProductService) GetProductBySKU(query GetProductBySKUQuery) (ProductDto, error) {
+	sku, err := domain.NewSKU(query.SKU)
+	if err != nil {
+		return ProductDto{}, NewInvalidArgumentError("sku", err.Error())
+	}
+
+	product, err := s.repository.FindBySKU(sku)
+	if err != nil || product == nil {
+		return ProductDto{}, NewNotFoundError(string(sku), "product")
+	}
+
+	return mapProductToDto(product), nil
+}
+
+func (s *ProductService) ListProducts(query ListProductsQuery) (ProductPageDto, error) {
+	currency, err := parseCurrency(query.Currency)
+	if err != nil {
+		return ProductPageDto{}, err
+	}
+
+	minPrice, err := parseOptionalAmount(query.MinPrice, currency, "min_price")
+	if err != nil {
+		return ProductPageDto{}, err
+	}
+
+	maxPrice, err := parseOptionalAmount(query.MaxPrice, currency, "max_price")
+	if err != nil {
+		return ProductPageDto{}, err
+	}
+
+	if minPrice != nil && maxPrice != nil {
+		if comparison, _ := maxPrice.Compare(*minPrice); comparison < 0 {
+			return ProductPageDto{}, NewInvalidArgumentError("price range", "max_price is lower than min_price")
+		}
+	}
+
+	page, err := s.repository.List(domain.ProductListQuery{
+		NameContains:    strings.TrimSpace(query.Search),
+		MinPrice:        minPrice,
+		MaxPrice:        maxPrice,
+		SortBy:          domain.ProductSortField(strings.TrimPrefix(query.Sort, "-")),
+		Descending:      strings.HasPrefix(query.Sort, "-"),
+		Cursor:          query.Cursor,
+		Limit:           query.Limit,
+		IncludeArchived: query.IncludeArchived,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			return ProductPageDto{}, NewInvalidArgumentError("cursor", "it does not belong to this listing")
+		}
+		return ProductPageDto{}, err
+	}
+
+	pageDto := ProductPageDto{
+		Items: []ProductDto{},
+	}
+	for _, product := range page.Products {
+		pageDto.Items = append(pageDto.Items, mapProductToDto(product))
+	}
+
+	if page.NextCursor != "" {
+		pageDto.NextCursor = &page.NextCursor
+	}
+
+	return pageDto, nil
+}
+
+func (s *ProductService) saveProduct(product *domain.Product) (ProductDto, error) {
+	if err := s.repository.Save(product); err != nil {
+		return ProductDto{}, mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.ProductId](s.eventDispatcher, product)
+
+	return mapProductToDto(product), nil
+}
+
+func parseCurrency(code string) (domain.Currency, error) {
+	if code == "" {
+		return domain.DefaultCurrency, nil
+	}
+
+	currency, err := domain.NewCurrency(code)
+	if err != nil {
+		return "", NewInvalidArgumentError("currency", err.Error())
+	}
+
+	return currency, nil
+}
+
+func parseOptionalAmount(amount AmountDto, currency domain.Currency, argument string) (*domain.Money, error) {
+	if amount == "" {
+		return nil, nil
+	}
+
+	money, err := domain.ParseMoney(string(amount), currency)
+	if err != nil {
+		return nil, NewInvalidArgumentError(argument, err.Error())
+	}
+
+	return &money, nil
+}
+
+func parseDimensions(dimensionsDto DimensionsDto) (domain.Dimensions, error) {
+	dimensions, err := domain.NewDimensions(dimensionsDto.LengthMm, dimensionsDto.WidthMm, dimensionsDto.HeightMm)
+	if err != nil {
+		return domain.Dimensions{}, NewInvalidArgumentError("dimensions", err.Error())
+	}
+
+	return dimensions, nil
+}
+
+func mapDimensionsToDto(dimensions domain.Dimensions) *DimensionsDto {
+	if dimensions.IsZero() {
+		return nil
+	}
+
+	return &DimensionsDto{
+		LengthMm: dimensions.GetLength(),
+		WidthMm:  dimensions.GetWidth(),
+		HeightMm: dimensions.GetHeight(),
+	}
+}
+
+func mapProductToDto(product *domain.Product) ProductDto {
 	return ProductDto{
-		Id:        uuid.UUID(newProduct.GetID()),
-		Name:      newProduct.GetName(),
-		UnitPrice: PriceDto(newProduct.GetPrice()),
-	}, nil
+		Id:          uuid.UUID(product.GetID()),
+		SKU:         string(product.GetSKU()),
+		Name:        product.GetName(),
+		UnitPrice:   PriceDto(product.GetPrice()),
+		Currency:    string(product.GetPrice().Currency()),
+		TaxCategory: string(product.GetTaxCategory()),
+		WeightGrams: int(product.GetWeight().Grams()),
+		Dimensions:  mapDimensionsToDto(product.GetDimensions()),
+		Archived:    product.IsArchived(),
+		Version:     product.GetVersion(),
+	}
 }
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"github.com/google/uuid"
+)
+
+type GetProductQuery struct {
+	ProductId uuid.UUID `validate:"required"`
+}
+
+type GetProductBySKUQuery struct {
+	SKU string `validate:"required"`
+}
+
+type GetCustomerQuery struct {
+	CustomerId uuid.UUID `validate:"required"`
+}
+
+type GetCartQuery struct {
+	CartId uuid.UUID `validate:"required"`
+}
+
+type GetShippingOptionsQuery struct {
+	CartId uuid.UUID `validate:"required"`
+}
+
+type GetCustomerCartsQuery struct {
+	CustomerId uuid.UUID `validate:"required"`
+	Status     string    `query:"status" validate:"omitempty,oneof=active abandoned checked_out expired"`
+}
+
+type GetOrderQuery struct {
+	OrderId uuid.UUID `validate:"required"`
+}
+
+type GetQuoteQuery struct {
+	QuoteId uuid.UUID `validate:"required"`
+}
+
+type ListProductsQuery struct {
+	Search          string    `query:"q"`
+	MinPrice        AmountDto `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice        AmountDto `query:"max_price" validate:"omitempty,gte=0"`
+	Currency        string    `query:"currency" validate:"omitempty,len=3,alpha"`
+	Sort            string    `query:"sort" validate:"omitempty,oneof=name -name price -price"`
+	Cursor          string    `query:"cursor"`
+	Limit           int       `query:"limit" validate:"gte=0,lte=100"`
+	IncludeArchived bool      `query:"include_archived"`
+}
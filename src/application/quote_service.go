@@ -0,0 +1,204 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+const defaultQuoteValidity = 72 * time.Hour
+
+type QuoteService struct {
+	cartRepository  domain.CartRepository
+	quoteRepository domain.QuoteRepository
+	eventDispatcher domain.EventDispatcher
+	clock           domain.Clock
+	conflictRetries int
+}
+
+func NewQuoteService(cartRepository domain.CartRepository, quoteRepository domain.QuoteRepository, eventDispatcher domain.EventDispatcher, clock domain.Clock) (*QuoteService, error) {
+	if cartRepository == nil {
+		return nil, errors.New("cart repository was nil")
+	}
+
+	if quoteRepository == nil {
+		return nil, errors.New("quote repository was nil")
+	}
+
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
+	if clock == nil {
+		return nil, errors.New("clock was nil")
+	}
+
+	return &QuoteService{
+		cartRepository:  cartRepository,
+		quoteRepository: quoteRepository,
+		eventDispatcher: eventDispatcher,
+		clock:           clock,
+		conflictRetries: 3,
+	}, nil
+}
+
+func (s *QuoteService) CreateQuote(command CreateQuoteCommand) (QuoteDto, error) {
+	for attempt := 0; ; attempt++ {
+		quoteDto, err := s.createQuote(command)
+		if attempt >= s.conflictRetries || !isQuoteNumberConflict(err) {
+			return quoteDto, err
+		}
+	}
+}
+
+func (s *QuoteService) createQuote(command CreateQuoteCommand) (QuoteDto, error) {
+	cart, err := s.cartRepository.FindByID(domain.CartId(command.CartId))
+	if err != nil || cart == nil {
+		return QuoteDto{}, NewNotFoundError(command.CartId.String(), "cart")
+	}
+
+	validFor := defaultQuoteValidity
+	if command.ValidForHours > 0 {
+		validFor = time.Duration(command.ValidForHours) * time.Hour
+	}
+
+	issuedAt := s.clock.Now()
+	number, err := s.quoteRepository.NextQuoteNumber(issuedAt)
+	if err != nil {
+		return QuoteDto{}, err
+	}
+
+	quote, err := domain.IssueQuote(cart, number, issuedAt, validFor)
+	if err != nil {
+		return QuoteDto{}, mapCartError(err)
+	}
+
+	return s.saveQuote(quote)
+}
+
+func (s *QuoteService) GetQuote(query GetQuoteQuery) (QuoteDto, error) {
+	quote, err := s.findQuote(query.QuoteId)
+	if err != nil {
+		return QuoteDto{}, err
+	}
+
+	quoteDto := mapQuoteToDto(quote)
+	quoteDto.Status = string(quote.GetCurrentStatus(s.clock))
+	return quoteDto, nil
+}
+
+func (s *QuoteService) ExpireQuotes() (ExpiredQuotesDto, error) {
+	result := ExpiredQuotesDto{
+		Expired: []uuid.UUID{},
+	}
+	quotes, err := s.quoteRepository.FindExpiredQuotes(s.clock.Now())
+	if err != nil {
+		return result, err
+	}
+
+	for _, quote := range quotes {
+		if !quote.Expire(s.clock) {
+			continue
+		}
+
+		if _, err := s.saveQuote(quote); err != nil {
+			return result, err
+		}
+		result.Expired = append(result.Expired, uuid.UUID(quote.GetID()))
+	}
+
+	return result, nil
+}
+
+func (s *QuoteService) AcceptQuote(command AcceptQuoteCommand) (QuoteDto, error) {
+	quote, err := s.findQuote(command.QuoteId)
+	if err != nil {
+		return QuoteDto{}, err
+	}
+
+	return s.transitionQuote(quote, quote.Accept)
+}
+
+func (s *QuoteService) DeclineQuote(command DeclineQuoteCommand) (QuoteDto, error) {
+	quote, err := s.findQuote(command.QuoteId)
+	if err != nil {
+		return QuoteDto{}, err
+	}
+
+	return s.transitionQuote(quote, quote.Decline)
+}
+
+func (s *QuoteService) findQuote(quoteId uuid.UUID) (*domain.Quote, error) {
+	quote, err := s.quoteRepository.FindByID(domain.QuoteId(quoteId))
+	if err != nil || quote == nil {
+		return nil, NewNotFoundError(quoteId.String(), "quote")
+	}
+
+	return quote, nil
+}
+
+func (s *QuoteService) transitionQuote(quote *domain.Quote, transition func(domain.Clock) error) (QuoteDto, error) {
+	err := transition(s.clock)
+	if errors.Is(err, domain.ErrQuoteExpired) {
+		if _, err := s.saveQuote(quote); err != nil {
+			return QuoteDto{}, err
+		}
+		return QuoteDto{}, NewInvalidArgumentError("quote", "it expired at "+quote.GetExpiresAt().Format(time.RFC3339))
+	}
+
+	if errors.Is(err, domain.ErrQuoteNotPending) {
+		return QuoteDto{}, NewInvalidArgumentError("quote", "it is already "+string(quote.GetStatus()))
+	}
+
+	if err != nil {
+		return QuoteDto{}, err
+	}
+
+	return s.saveQuote(quote)
+}
+
+func (s *QuoteService) saveQuote(quote *domain.Quote) (QuoteDto, error) {
+	if err := s.quoteRepository.Save(quote); err != nil {
+		return QuoteDto{}, mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.QuoteId](s.eventDispatcher, quote)
+
+	return mapQuoteToDto(quote), nil
+}
+
+func isQuoteNumberConflict(err error) bool {
+	var conflictError *ConflictError
+	return errors.As(err, &conflictError) && conflictError.Field() == "number"
+}
+
+func mapQuoteToDto(quote *domain.Quote) QuoteDto {
+	quoteDto := QuoteDto{
+		Id:            uuid.UUID(quote.GetID()),
+		Number:        quote.GetNumber(),
+		CartId:        uuid.UUID(quote.GetCartID()),
+		CustomerId:    uuid.UUID(quote.GetCustomerID()),
+		Currency:      string(quote.GetCurrency()),
+		Lines:         mapLinesToDto(quote.GetLines(), quote.GetLineTaxes()),
+		Subtotal:      PriceDto(quote.GetSubtotal()),
+		LineDiscounts: mapAdjustmentsToDtos(quote.GetLineAdjustments()),
+		CartDiscounts: mapAdjustmentsToDtos(quote.GetCartAdjustments()),
+		Total:         PriceDto(quote.GetTotal()),
+		Status:        string(quote.GetStatus()),
+		IssuedAt:      quote.GetIssuedAt(),
+		ExpiresAt:     quote.GetExpiresAt(),
+	}
+
+	if quote.HasShipping() {
+		quoteDto.Shipping = mapShippingToDto(quote.GetShipping())
+	}
+
+	if quote.IsTaxed() {
+		tax := PriceDto(quote.GetTax())
+		quoteDto.Tax = &tax
+	}
+
+	return quoteDto
+}
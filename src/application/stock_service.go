@@ -0,0 +1,139 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+type StockService struct {
+	productRepository domain.ProductRepository
+	stockRepository   domain.StockRepository
+	eventDispatcher   domain.EventDispatcher
+	conflictRetries   int
+}
+
+func NewStockService(productRepository domain.ProductRepository, stockRepository domain.StockRepository, eventDispatcher domain.EventDispatcher) (*StockService, error) {
+	if productRepository == nil {
+		return nil, errors.New("product repository was nil")
+	}
+
+	if stockRepository == nil {
+		return nil, errors.New("stock repository was nil")
+	}
+
+	if eventDispatcher == nil {
+		return nil, errors.New("event dispatcher was nil")
+	}
+
+	return &StockService{
+		productRepository: productRepository,
+		stockRepository:   stockRepository,
+		eventDispatcher:   eventDispatcher,
+		conflictRetries:   3,
+	}, nil
+}
+
+func (s *StockService) UpdateStock(command UpdateStockCommand) (StockDto, error) {
+	product, err := s.productRepository.FindByID(domain.ProductId(command.ProductId))
+	if err != nil || product == nil {
+		return StockDto{}, NewNotFoundError(command.ProductId.String(), "product")
+	}
+
+	stockItem, findErr := s.stockRepository.FindByID(product.GetID())
+	if findErr != nil {
+		if stockItem, err = domain.NewStockItem(product.GetID(), *command.OnHand); err != nil {
+			return StockDto{}, NewInvalidArgumentError("on_hand", err.Error())
+		}
+	}
+
+	if err = ensureVersion[domain.ProductId](stockItem, command.ExpectedVersion, "stock"); err != nil {
+		return StockDto{}, err
+	}
+
+	if findErr == nil {
+		if err = stockItem.Restock(*command.OnHand); err != nil {
+			if errors.Is(err, domain.ErrOnHandBelowReserved) {
+				return StockDto{}, NewInvalidArgumentError("on_hand", fmt.Sprintf("it is below the %d units reserved in carts", stockItem.GetReserved()))
+			}
+			return StockDto{}, NewInvalidArgumentError("on_hand", err.Error())
+		}
+	}
+
+	if err = s.saveStockItem(stockItem); err != nil {
+		return StockDto{}, err
+	}
+
+	return mapStockItemToDto(stockItem), nil
+}
+
+func (s *StockService) CommitCartReservations(event domain.CartCheckedOut) error {
+	return s.updateCartReservations(event.CartId, func(stockItem *domain.StockItem) {
+		stockItem.Commit(event.CartId)
+	})
+}
+
+func (s *StockService) ReleaseCartReservations(event domain.CartExpired) error {
+	return s.releaseCartReservations(event.CartId)
+}
+
+func (s *StockService) ReleaseAbandonedCartReservations(event domain.CartAbandoned) error {
+	return s.releaseCartReservations(event.CartId)
+}
+
+func (s *StockService) releaseCartReservations(cartId domain.CartId) error {
+	return s.updateCartReservations(cartId, func(stockItem *domain.StockItem) {
+		stockItem.ReleaseAll(cartId)
+	})
+}
+
+func (s *StockService) updateCartReservations(cartId domain.CartId, update func(*domain.StockItem)) error {
+	stockItems, err := s.stockRepository.GetCartReservations(cartId)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, stockItem := range stockItems {
+		if err := s.updateStockItem(stockItem, update); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update the stock reserved for product %s: %w", stockItem.GetID(), err))
+		}
+	}
+
+	return joinErrors(errs...)
+}
+
+func (s *StockService) updateStockItem(stockItem *domain.StockItem, update func(*domain.StockItem)) error {
+	for attempt := 0; ; attempt++ {
+		update(stockItem)
+		err := s.saveStockItem(stockItem)
+		if err == nil || attempt >= s.conflictRetries || !isConcurrencyConflict(err) {
+			return err
+		}
+
+		if stockItem, err = s.stockRepository.FindByID(stockItem.GetID()); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *StockService) saveStockItem(stockItem *domain.StockItem) error {
+	if err := s.stockRepository.Save(stockItem); err != nil {
+		return mapRepositoryError(err)
+	}
+
+	dispatchDomainEvents[domain.ProductId](s.eventDispatcher, stockItem)
+	return nil
+}
+
+func mapStockItemToDto(stockItem *domain.StockItem) StockDto {
+	return StockDto{
+		ProductId: uuid.UUID(stockItem.GetID()),
+		OnHand:    stockItem.GetOnHand(),
+		Reserved:  stockItem.GetReserved(),
+		Available: stockItem.GetAvailable(),
+		Version:   stockItem.GetVersion(),
+	}
+}
@@ -0,0 +1,15 @@
+package application
+
+import (
+	"fmt"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+func ensureVersion[K comparable](entity domain.Entity[K], expectedVersion *int, entityType string) error {
+	if expectedVersion == nil || *expectedVersion == entity.GetVersion() {
+		return nil
+	}
+
+	return NewPreconditionFailedError(fmt.Sprint(entity.GetID()), entityType, *expectedVersion, entity.GetVersion())
+}
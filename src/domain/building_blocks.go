@@ -16,11 +16,14 @@ type Entity[K comparable] interface {
 	addDomainEvent(DomainEvent)
 	GetDomainEvents() []DomainEvent
 	ClearDomainEvents()
+	GetVersion() int
+	SetVersion(version int)
 	EqualsTo(Entity[K]) bool
 }
 
 type baseEntity[K comparable] struct {
 	id           K
+	version      int
 	domainEvents []DomainEvent
 }
 
@@ -43,3 +46,27 @@ func (e baseEntity[K]) GetDomainEvents() []DomainEvent {
 func (e *baseEntity[K]) ClearDomainEvents() {
 	e.domainEvents = []DomainEvent{}
 }
+
+func (e baseEntity[K]) GetVersion() int {
+	return e.version
+}
+
+func (e *baseEntity[K]) SetVersion(version int) {
+	e.version = version
+}
+
+func (e *baseEntity[K]) clone() *baseEntity[K] {
+	return &baseEntity[K]{
+		id:           e.id,
+		version:      e.version,
+		domainEvents: cloneSlice(e.domainEvents),
+	}
+}
+
+func cloneSlice[T any](values []T) []T {
+	if values == nil {
+		return nil
+	}
+
+	return append(make([]T, 0, len(values)), values...)
+}
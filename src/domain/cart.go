@@ -2,41 +2,139 @@ package domain
 
 import (
 	"errors"
+	"math/big"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CartId uuid.UUID
 
+var ErrItemNotFound = errors.New("item not found")
+
+var ErrCartCheckedOut = errors.New("cart is checked out")
+
+var ErrCartEmpty = errors.New("cart is empty")
+
+var ErrCartNotActive = errors.New("cart is not active")
+
+var ErrInvalidCartTransition = errors.New("invalid cart status transition")
+
+type CartStatus string
+
+const (
+	CartStatusActive     CartStatus = "active"
+	CartStatusAbandoned  CartStatus = "abandoned"
+	CartStatusCheckedOut CartStatus = "checked_out"
+	CartStatusExpired    CartStatus = "expired"
+)
+
+var cartTransitions = map[CartStatus][]CartStatus{
+	CartStatusActive:    {CartStatusAbandoned, CartStatusCheckedOut, CartStatusExpired},
+	CartStatusAbandoned: {CartStatusActive, CartStatusExpired},
+}
+
+func NewCartStatus(status string) (CartStatus, error) {
+	switch cartStatus := CartStatus(status); cartStatus {
+	case CartStatusActive, CartStatusAbandoned, CartStatusCheckedOut, CartStatusExpired:
+		return cartStatus, nil
+	}
+
+	return "", errors.New("invalid cart status")
+}
+
+func (id CartId) String() string {
+	return uuid.UUID(id).String()
+}
+
+func (id CartId) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *CartId) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(id).UnmarshalText(data)
+}
+
 type Cart struct {
 	*baseEntity[CartId]
-	customerId CustomerId
-	items      map[ProductId]item
+	customerId     CustomerId
+	currency       Currency
+	items          map[ProductId]item
+	status         CartStatus
+	lastActivityAt time.Time
+	clock          Clock
+	coupons        []CouponCode
+	promotions     []Promotion
+	adjustments    []Adjustment
+	shippingMethod ShippingMethod
+	shipping       ShippingQuote
+	taxRegion      TaxRegion
+	taxCalculator  TaxCalculator
+	taxes          []LineTax
 }
 
 type item struct {
-	productId ProductId
-	price     float64
-	quantity  int
+	productId    ProductId
+	price        Money
+	addedPrice   Money
+	exchangeRate *big.Rat
+	taxCategory  TaxCategory
+	weight       Weight
+	dimensions   Dimensions
+	quantity     int
 }
 
-func NewCart(customer *Customer) (*Cart, error) {
+type CartOption func(*Cart)
+
+func WithDisplayCurrency(currency Currency) CartOption {
+	return func(c *Cart) {
+		c.currency = currency
+	}
+}
+
+func WithCartClock(clock Clock) CartOption {
+	return func(c *Cart) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+func NewCart(customer *Customer, options ...CartOption) (*Cart, error) {
 	if customer == nil {
 		return nil, errors.New("no customer provided")
 	}
 
+	if !customer.IsActive() {
+		return nil, ErrCustomerDeactivated
+	}
+
 	cart := &Cart{
 		baseEntity: &baseEntity[CartId]{
 			id: CartId(uuid.New()),
 		},
 		customerId: customer.GetID(),
+		currency:   DefaultCurrency,
 		items:      map[ProductId]item{},
+		status:     CartStatusActive,
+		clock:      SystemClock(),
+	}
+
+	for _, option := range options {
+		option(cart)
+	}
+	cart.lastActivityAt = cart.clock.Now()
+
+	if _, err := NewCurrency(string(cart.currency)); err != nil {
+		return nil, err
 	}
 
 	cart.addDomainEvent(CartCreated{
 		CartId:     cart.id,
 		CustomerId: cart.customerId,
+		Currency:   cart.currency,
 	})
 
 	return cart, nil
@@ -46,6 +144,24 @@ func (c *Cart) EqualsTo(entity Entity[CartId]) bool {
 	return reflect.TypeOf(c) == reflect.TypeOf(entity) && c.GetID() == entity.GetID()
 }
 
+func (c *Cart) Clone() *Cart {
+	clone := *c
+	clone.baseEntity = c.baseEntity.clone()
+	clone.items = make(map[ProductId]item, len(c.items))
+	for productId, cartItem := range c.items {
+		if cartItem.exchangeRate != nil {
+			cartItem.exchangeRate = new(big.Rat).Set(cartItem.exchangeRate)
+		}
+		clone.items[productId] = cartItem
+	}
+	clone.coupons = cloneSlice(c.coupons)
+	clone.promotions = cloneSlice(c.promotions)
+	clone.adjustments = cloneSlice(c.adjustments)
+	clone.taxes = cloneSlice(c.taxes)
+
+	return &clone
+}
+
 func (c Cart) Size() int {
 	var cartSize int
 	for _, v := range c.items {
@@ -56,25 +172,61 @@ func (c Cart) Size() int {
 }
 
 func (c *Cart) AddItem(product *Product, quantity int) (item, error) {
+	return c.AddItemWithExchangeRates(product, quantity, nil)
+}
+
+func (c *Cart) AddItemWithExchangeRates(product *Product, quantity int, exchangeRates ExchangeRateProvider) (item, error) {
+	if err := c.ensureActive(); err != nil {
+		return item{}, err
+	}
+
 	if product == nil {
 		return item{}, errors.New("invalid product")
 	}
 
+	if product.IsArchived() {
+		return item{}, ErrProductArchived
+	}
+
 	if quantity < 1 {
 		return item{}, errors.New("invalid quantity")
 	}
 
 	productId := product.GetID()
-	if cartItem, found := c.items[productId]; found {
-		c.items[productId] = cartItem.addQuantity(quantity)
+	cartItem, found := c.items[productId]
+	if found {
+		cartItem = cartItem.addQuantity(quantity)
 	} else {
-		c.items[productId] = item{
-			productId: product.GetID(),
-			price:     product.GetPrice(),
-			quantity:  quantity,
+		exchangeRate, err := c.exchangeRateFor(product.GetPrice().Currency(), exchangeRates)
+		if err != nil {
+			return item{}, err
+		}
+
+		cartItem = item{
+			productId:    product.GetID(),
+			price:        product.GetPrice(),
+			addedPrice:   product.GetPrice(),
+			exchangeRate: exchangeRate,
+			taxCategory:  product.GetTaxCategory(),
+			weight:       product.GetWeight(),
+			dimensions:   product.GetDimensions(),
+			quantity:     quantity,
 		}
 	}
 
+	if _, err := cartItem.getTotalIn(c.currency); err != nil {
+		return item{}, err
+	}
+
+	err := c.applyChange(func() {
+		c.items[productId] = cartItem
+		c.touch()
+		c.recalculate()
+	})
+	if err != nil {
+		return item{}, err
+	}
+
 	c.addDomainEvent(ItemAddedToCart{
 		CartId:    c.id,
 		ProductId: productId,
@@ -84,15 +236,645 @@ func (c *Cart) AddItem(product *Product, quantity int) (item, error) {
 	return c.items[productId], nil
 }
 
-func (c Cart) GetTotal() float64 {
-	var total float64
-	for _, item := range c.items {
-		total += item.getTotal()
+func (c *Cart) RemoveItem(productId ProductId) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if _, found := c.items[productId]; !found {
+		return ErrItemNotFound
+	}
+
+	err := c.applyChange(func() {
+		delete(c.items, productId)
+		c.touch()
+		c.recalculate()
+	})
+	if err != nil {
+		return err
+	}
+
+	c.addDomainEvent(ItemRemovedFromCart{
+		CartId:    c.id,
+		ProductId: productId,
+	})
+
+	return nil
+}
+
+func (c *Cart) UpdateItemQuantity(productId ProductId, quantity int) (item, error) {
+	if err := c.ensureActive(); err != nil {
+		return item{}, err
+	}
+
+	if quantity < 1 {
+		return item{}, errors.New("invalid quantity")
+	}
+
+	cartItem, found := c.items[productId]
+	if !found {
+		return item{}, ErrItemNotFound
+	}
+
+	if cartItem.quantity == quantity {
+		return cartItem, nil
+	}
+
+	if _, err := cartItem.withQuantity(quantity).getTotalIn(c.currency); err != nil {
+		return item{}, err
+	}
+
+	err := c.applyChange(func() {
+		c.items[productId] = cartItem.withQuantity(quantity)
+		c.touch()
+		c.recalculate()
+	})
+	if err != nil {
+		return item{}, err
+	}
+
+	c.addDomainEvent(ItemQuantityChanged{
+		CartId:           c.id,
+		ProductId:        productId,
+		PreviousQuantity: cartItem.quantity,
+		Quantity:         quantity,
+	})
+
+	return c.items[productId], nil
+}
+
+func (c *Cart) RepriceItem(product *Product, exchangeRates ExchangeRateProvider) (bool, error) {
+	if err := c.ensureActive(); err != nil {
+		return false, err
+	}
+
+	if product == nil {
+		return false, errors.New("invalid product")
+	}
+
+	productId := product.GetID()
+	cartItem, found := c.items[productId]
+	if !found {
+		return false, ErrItemNotFound
+	}
+
+	price := product.GetPrice()
+	if price.EqualsTo(cartItem.price) {
+		return false, nil
+	}
+
+	exchangeRate, err := c.exchangeRateFor(price.Currency(), exchangeRates)
+	if err != nil {
+		return false, err
+	}
+
+	repricedItem := item{
+		productId:    productId,
+		price:        price,
+		addedPrice:   cartItem.addedPrice,
+		exchangeRate: exchangeRate,
+		taxCategory:  product.GetTaxCategory(),
+		weight:       product.GetWeight(),
+		dimensions:   product.GetDimensions(),
+		quantity:     cartItem.quantity,
+	}
+
+	if _, err := repricedItem.getTotalIn(c.currency); err != nil {
+		return false, err
+	}
+
+	err = c.applyChange(func() {
+		c.items[productId] = repricedItem
+		c.recalculate()
+	})
+	if err != nil {
+		return false, err
+	}
+
+	c.addDomainEvent(ItemRepriced{
+		CartId:            c.id,
+		ProductId:         productId,
+		PreviousUnitPrice: cartItem.price,
+		UnitPrice:         price,
+	})
+
+	return true, nil
+}
+
+func (c *Cart) Clear() error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if len(c.items) == 0 {
+		return nil
+	}
+
+	c.items = map[ProductId]item{}
+	c.touch()
+	c.recalculate()
+
+	c.addDomainEvent(CartCleared{
+		CartId: c.id,
+	})
+
+	return nil
+}
+
+func (c *Cart) ApplyCoupon(coupon Coupon) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if coupon.GetCode() == "" {
+		return errors.New("invalid coupon")
+	}
+
+	for _, code := range c.coupons {
+		if code == coupon.GetCode() {
+			return nil
+		}
+	}
+
+	c.coupons = append(c.coupons, coupon.GetCode())
+	c.touch()
+
+	c.addDomainEvent(CouponApplied{
+		CartId: c.id,
+		Code:   coupon.GetCode(),
+	})
+
+	return nil
+}
+
+func (c *Cart) RemoveCoupon(code CouponCode) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	for position, appliedCode := range c.coupons {
+		if appliedCode == code {
+			c.coupons = append(c.coupons[:position:position], c.coupons[position+1:]...)
+			c.touch()
+
+			c.addDomainEvent(CouponRemoved{
+				CartId: c.id,
+				Code:   code,
+			})
+
+			return nil
+		}
+	}
+
+	return ErrCouponNotApplied
+}
+
+func (c *Cart) ApplyPromotions(promotions []Promotion) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	return c.applyChange(func() {
+		c.promotions = append([]Promotion{}, promotions...)
+		c.recalculate()
+	})
+}
+
+func (c *Cart) QuoteShipping(method ShippingMethod) (ShippingQuote, error) {
+	if method == nil {
+		return ShippingQuote{}, errors.New("invalid shipping method")
+	}
+
+	if len(c.items) == 0 {
+		return ShippingQuote{}, ErrCartEmpty
+	}
+
+	cost, err := method.Quote(c)
+	if err != nil {
+		return ShippingQuote{}, err
+	}
+
+	return NewShippingQuote(method.GetCode(), method.GetName(), cost), nil
+}
+
+func (c *Cart) SelectShipping(method ShippingMethod) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	quote, err := c.QuoteShipping(method)
+	if err != nil {
+		return err
+	}
+
+	if quote.EqualsTo(c.shipping) {
+		c.shippingMethod = method
+		return nil
+	}
+
+	err = c.applyChange(func() {
+		c.shippingMethod = method
+		c.shipping = quote
+		c.touch()
+	})
+	if err != nil {
+		return err
+	}
+
+	c.addDomainEvent(ShippingSelected{
+		CartId: c.id,
+		Method: quote.GetMethod(),
+		Cost:   quote.GetCost(),
+	})
+
+	return nil
+}
+
+func (c *Cart) RebindShipping(method ShippingMethod) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if method == nil || method.GetCode() != c.shipping.method {
+		return errors.New("invalid shipping method")
+	}
+
+	return c.applyChange(func() {
+		c.shippingMethod = method
+		c.evaluateShipping()
+	})
+}
+
+func (c *Cart) ApplyTaxes(region TaxRegion, calculator TaxCalculator) error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if calculator == nil {
+		return errors.New("invalid tax calculator")
+	}
+
+	return c.applyChange(func() {
+		c.taxRegion = region
+		c.taxCalculator = calculator
+		c.evaluateTaxes()
+	})
+}
+
+func (c *Cart) Checkout() error {
+	if err := c.ensureActive(); err != nil {
+		return err
+	}
+
+	if len(c.items) == 0 {
+		return ErrCartEmpty
+	}
+
+	c.status = CartStatusCheckedOut
+	c.touch()
+
+	c.addDomainEvent(CartCheckedOut{
+		CartId:     c.id,
+		CustomerId: c.customerId,
+		Total:      c.GetTotal(),
+	})
+
+	return nil
+}
+
+func (c *Cart) Abandon() error {
+	if err := c.transitionTo(CartStatusAbandoned); err != nil {
+		return err
+	}
+
+	c.addDomainEvent(CartAbandoned{
+		CartId:         c.id,
+		CustomerId:     c.customerId,
+		LastActivityAt: c.lastActivityAt,
+	})
+
+	return nil
+}
+
+func (c *Cart) Expire() error {
+	if err := c.transitionTo(CartStatusExpired); err != nil {
+		return err
+	}
+
+	c.addDomainEvent(CartExpired{
+		CartId:     c.id,
+		CustomerId: c.customerId,
+	})
+
+	return nil
+}
+
+func (c *Cart) Reactivate() error {
+	if err := c.transitionTo(CartStatusActive); err != nil {
+		return err
+	}
+	c.touch()
+
+	c.addDomainEvent(CartReactivated{
+		CartId:     c.id,
+		CustomerId: c.customerId,
+	})
+
+	return nil
+}
+
+func (c Cart) IsCheckedOut() bool {
+	return c.status == CartStatusCheckedOut
+}
+
+func (c Cart) IsActive() bool {
+	return c.status == CartStatusActive
+}
+
+func (c Cart) GetStatus() CartStatus {
+	return c.status
+}
+
+func (c Cart) GetLastActivityAt() time.Time {
+	return c.lastActivityAt
+}
+
+func (c Cart) ensureActive() error {
+	switch c.status {
+	case CartStatusActive:
+		return nil
+	case CartStatusCheckedOut:
+		return ErrCartCheckedOut
+	}
+
+	return ErrCartNotActive
+}
+
+func (c *Cart) transitionTo(status CartStatus) error {
+	for _, allowed := range cartTransitions[c.status] {
+		if allowed == status {
+			c.status = status
+			return nil
+		}
+	}
+
+	return ErrInvalidCartTransition
+}
+
+func (c *Cart) touch() {
+	c.lastActivityAt = c.clock.Now()
+}
+
+func (c *Cart) applyChange(change func()) error {
+	previous := c.Clone()
+	change()
+	if _, err := c.calculateTotal(); err != nil {
+		*c = *previous
+		return err
+	}
+
+	return nil
+}
+
+func (c *Cart) recalculate() {
+	c.evaluatePromotions()
+	c.evaluateShipping()
+	c.evaluateTaxes()
+}
+
+func (c *Cart) evaluatePromotions() {
+	c.adjustments = nil
+	balance := c.GetSubtotal()
+	lineBalances := make(map[ProductId]Money, len(c.items))
+	for productId, cartItem := range c.items {
+		lineBalances[productId], _ = cartItem.getTotalIn(c.currency)
+	}
+
+	for _, promotion := range c.promotions {
+		for _, adjustment := range promotion.Evaluate(c, balance) {
+			limit := balance
+			if adjustment.IsLineAdjustment() {
+				lineBalance, found := lineBalances[adjustment.productId]
+				if !found {
+					continue
+				}
+
+				if comparison, err := lineBalance.Compare(limit); err == nil && comparison < 0 {
+					limit = lineBalance
+				}
+			}
+
+			comparison, err := adjustment.amount.Compare(limit)
+			if err != nil || !adjustment.amount.IsPositive() || !limit.IsPositive() {
+				continue
+			}
+
+			if comparison > 0 {
+				adjustment.amount = limit
+			}
+
+			balance, _ = balance.Subtract(adjustment.amount)
+			if adjustment.IsLineAdjustment() {
+				lineBalances[adjustment.productId], _ = lineBalances[adjustment.productId].Subtract(adjustment.amount)
+			}
+			c.adjustments = append(c.adjustments, adjustment)
+		}
+	}
+}
+
+func (c *Cart) evaluateShipping() {
+	if c.shippingMethod == nil {
+		if len(c.items) == 0 {
+			c.shipping = ShippingQuote{}
+		}
+		return
+	}
+
+	quote, err := c.QuoteShipping(c.shippingMethod)
+	if err != nil {
+		c.shippingMethod = nil
+		c.shipping = ShippingQuote{}
+		return
+	}
+
+	c.shipping = quote
+}
+
+func (c *Cart) evaluateTaxes() {
+	c.taxes = nil
+	if c.taxCalculator == nil {
+		return
+	}
+
+	c.taxes = append([]LineTax{}, c.taxCalculator.Calculate(c.taxRegion, c.taxableLines())...)
+}
+
+func (c Cart) taxableLines() []TaxableLine {
+	items := c.GetItems()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].productId.String() < items[j].productId.String()
+	})
+
+	lines := make([]TaxableLine, len(items))
+	ratios := make([]int64, len(items))
+	for position, cartItem := range items {
+		amount, _ := cartItem.getTotalIn(c.currency)
+		for _, adjustment := range c.GetLineAdjustments() {
+			if adjustment.productId == cartItem.productId {
+				amount, _ = amount.Subtract(adjustment.amount)
+			}
+		}
+
+		lines[position] = TaxableLine{
+			ProductId: cartItem.productId,
+			Category:  cartItem.taxCategory,
+			Amount:    amount,
+		}
+		ratios[position] = amount.MinorUnits()
+	}
+
+	cartDiscount := ZeroMoney(c.currency)
+	for _, adjustment := range c.GetCartAdjustments() {
+		cartDiscount, _ = cartDiscount.Add(adjustment.amount)
 	}
 
+	if shares, err := cartDiscount.Allocate(ratios...); err == nil && cartDiscount.IsPositive() {
+		for position, share := range shares {
+			lines[position].Amount, _ = lines[position].Amount.Subtract(share)
+		}
+	}
+
+	return lines
+}
+
+func (c Cart) GetSubtotal() Money {
+	subtotal, _ := c.calculateSubtotal()
+	return subtotal
+}
+
+func (c Cart) getMerchandiseTotal() Money {
+	total, _ := c.calculateMerchandiseTotal()
 	return total
 }
 
+func (c Cart) GetTotal() Money {
+	total, _ := c.calculateTotal()
+	return total
+}
+
+func (c Cart) calculateSubtotal() (Money, error) {
+	subtotal := ZeroMoney(c.currency)
+	for _, item := range c.items {
+		total, err := item.getTotalIn(c.currency)
+		if err != nil {
+			return Money{}, err
+		}
+
+		if subtotal, err = subtotal.Add(total); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return subtotal, nil
+}
+
+func (c Cart) calculateMerchandiseTotal() (Money, error) {
+	total, err := c.calculateSubtotal()
+	if err != nil {
+		return Money{}, err
+	}
+
+	for _, adjustment := range c.adjustments {
+		if total, err = total.Subtract(adjustment.amount); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+func (c Cart) calculateTotal() (Money, error) {
+	total, err := c.calculateMerchandiseTotal()
+	if err != nil {
+		return Money{}, err
+	}
+
+	if c.HasShipping() {
+		if total, err = total.Add(c.shipping.cost); err != nil {
+			return Money{}, err
+		}
+	}
+
+	for _, tax := range c.taxes {
+		if tax.inclusive {
+			continue
+		}
+
+		if total, err = total.Add(tax.amount); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
+}
+
+func (c Cart) HasShipping() bool {
+	return c.shipping.method != ""
+}
+
+func (c Cart) GetShipping() ShippingQuote {
+	return c.shipping
+}
+
+func (c Cart) GetTax() Money {
+	return sumLineTaxes(c.currency, c.taxes)
+}
+
+func (c Cart) GetLineTaxes() []LineTax {
+	return append([]LineTax{}, c.taxes...)
+}
+
+func (c Cart) IsTaxed() bool {
+	return c.taxes != nil
+}
+
+func (c Cart) GetTaxRegion() TaxRegion {
+	return c.taxRegion
+}
+
+func (c Cart) GetCoupons() []CouponCode {
+	return append([]CouponCode{}, c.coupons...)
+}
+
+func (c Cart) GetLineAdjustments() []Adjustment {
+	return filterAdjustments(c.adjustments, true)
+}
+
+func (c Cart) GetCartAdjustments() []Adjustment {
+	return filterAdjustments(c.adjustments, false)
+}
+
+func (c Cart) GetCurrency() Currency {
+	return c.currency
+}
+
+func (c Cart) exchangeRateFor(currency Currency, exchangeRates ExchangeRateProvider) (*big.Rat, error) {
+	if currency == c.currency {
+		return big.NewRat(1, 1), nil
+	}
+
+	if exchangeRates == nil {
+		return nil, ErrCurrencyNotConvertible
+	}
+
+	rate, err := exchangeRates.GetRate(currency, c.currency)
+	if err != nil || rate == nil || rate.Sign() <= 0 {
+		return nil, ErrCurrencyNotConvertible
+	}
+
+	return new(big.Rat).Set(rate), nil
+}
+
 func (c Cart) GetCustomerID() CustomerId {
 	return c.customerId
 }
@@ -110,23 +892,61 @@ func (i item) GetProductId() ProductId {
 	return i.productId
 }
 
-func (i item) GetUnitPrice() float64 {
+func (i item) GetUnitPrice() Money {
 	return i.price
 }
 
+func (i item) GetAddedUnitPrice() Money {
+	return i.addedPrice
+}
+
+func (i item) HasPriceChanged() bool {
+	return !i.price.EqualsTo(i.addedPrice)
+}
+
 func (i item) GetQuantity() int {
 	return i.quantity
 }
 
-func (i item) getTotal() float64 {
-	return i.price * float64(i.quantity)
+func (i item) GetTaxCategory() TaxCategory {
+	return i.taxCategory
+}
+
+func (i item) GetWeight() Weight {
+	return i.weight
+}
+
+func (i item) GetExchangeRate() *big.Rat {
+	return new(big.Rat).Set(i.exchangeRate)
+}
+
+func (i item) getTotalIn(currency Currency) (Money, error) {
+	return i.totalFor(i.quantity, currency)
+}
+
+func (i item) totalFor(quantity int, currency Currency) (Money, error) {
+	total, err := i.price.Multiply(int64(quantity))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return total.Convert(currency, i.exchangeRate)
 }
 
 func (i item) addQuantity(quantityToAdd int) item {
+	return i.withQuantity(i.quantity + quantityToAdd)
+}
+
+func (i item) withQuantity(quantity int) item {
 	return item{
-		productId: i.productId,
-		price:     i.price,
-		quantity:  i.quantity + quantityToAdd,
+		productId:    i.productId,
+		price:        i.price,
+		addedPrice:   i.addedPrice,
+		exchangeRate: i.exchangeRate,
+		taxCategory:  i.taxCategory,
+		weight:       i.weight,
+		dimensions:   i.dimensions,
+		quantity:     quantity,
 	}
 }
 
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type Email string
+
+func NewEmail(address string) (Email, error) {
+	trimmedAddress := strings.TrimSpace(address)
+	parsed, err := mail.ParseAddress(trimmedAddress)
+	if err != nil || parsed.Address != trimmedAddress {
+		return "", errors.New("invalid email")
+	}
+
+	return Email(strings.ToLower(trimmedAddress)), nil
+}
+
+type PhoneNumber string
+
+var phoneNumberPattern = regexp.MustCompile(`^\+?[0-9]{7,15}$`)
+
+var phoneNumberSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+func NewPhoneNumber(number string) (PhoneNumber, error) {
+	normalizedNumber := phoneNumberSeparators.Replace(strings.TrimSpace(number))
+	if !phoneNumberPattern.MatchString(normalizedNumber) {
+		return "", errors.New("invalid phone number")
+	}
+
+	return PhoneNumber(normalizedNumber), nil
+}
+
+type Address struct {
+	street     string
+	city       string
+	postalCode string
+	country    string
+}
+
+func NewAddress(street string, city string, postalCode string, country string) (Address, error) {
+	address := Address{
+		street:     strings.TrimSpace(street),
+		city:       strings.TrimSpace(city),
+		postalCode: strings.TrimSpace(postalCode),
+		country:    strings.ToUpper(strings.TrimSpace(country)),
+	}
+
+	if address.street == "" || address.city == "" || address.postalCode == "" || !isCountryCode(address.country) {
+		return Address{}, errors.New("invalid address")
+	}
+
+	return address, nil
+}
+
+func (a Address) GetStreet() string {
+	return a.street
+}
+
+func (a Address) GetCity() string {
+	return a.city
+}
+
+func (a Address) GetPostalCode() string {
+	return a.postalCode
+}
+
+func (a Address) GetCountry() string {
+	return a.country
+}
+
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+func (a Address) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(a, other)
+}
+
+type addressJSON struct {
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+func (a Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(addressJSON{
+		Street:     a.street,
+		City:       a.city,
+		PostalCode: a.postalCode,
+		Country:    a.country,
+	})
+}
+
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var decoded addressJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded == (addressJSON{}) {
+		*a = Address{}
+		return nil
+	}
+
+	address, err := NewAddress(decoded.Street, decoded.City, decoded.PostalCode, decoded.Country)
+	if err != nil {
+		return err
+	}
+
+	*a = address
+	return nil
+}
+
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+
+	for _, letter := range code {
+		if letter < 'A' || letter > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var ErrCouponNotApplied = errors.New("coupon is not applied")
+
+type CouponCode string
+
+var couponCodePattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+func NewCouponCode(code string) (CouponCode, error) {
+	normalizedCode := strings.ToUpper(strings.TrimSpace(code))
+	if len(normalizedCode) < 3 || len(normalizedCode) > 32 || !couponCodePattern.MatchString(normalizedCode) {
+		return "", errors.New("invalid coupon code")
+	}
+
+	return CouponCode(normalizedCode), nil
+}
+
+type Coupon struct {
+	code      CouponCode
+	promotion Promotion
+}
+
+func NewCoupon(code CouponCode, promotion Promotion) (Coupon, error) {
+	if code == "" || promotion == nil {
+		return Coupon{}, errors.New("invalid coupon")
+	}
+
+	return Coupon{
+		code:      code,
+		promotion: promotion,
+	}, nil
+}
+
+func (c Coupon) GetCode() CouponCode {
+	return c.code
+}
+
+func (c Coupon) GetPromotion() Promotion {
+	return c.promotion
+}
@@ -10,18 +10,91 @@ import (
 
 type CustomerId uuid.UUID
 
+func (id CustomerId) String() string {
+	return uuid.UUID(id).String()
+}
+
+func (id CustomerId) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *CustomerId) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(id).UnmarshalText(data)
+}
+
+var ErrCustomerDeactivated = errors.New("customer is deactivated")
+
 type Customer struct {
 	*baseEntity[CustomerId]
-	name string
+	name            string
+	email           Email
+	phone           PhoneNumber
+	shippingAddress Address
+	billingAddress  Address
+	deactivated     bool
+}
+
+type CustomerOption func(*Customer)
+
+func WithEmail(email Email) CustomerOption {
+	return func(c *Customer) {
+		c.email = email
+	}
+}
+
+func WithPhoneNumber(phone PhoneNumber) CustomerOption {
+	return func(c *Customer) {
+		c.phone = phone
+	}
+}
+
+func WithShippingAddress(address Address) CustomerOption {
+	return func(c *Customer) {
+		c.shippingAddress = address
+	}
+}
+
+func WithBillingAddress(address Address) CustomerOption {
+	return func(c *Customer) {
+		c.billingAddress = address
+	}
 }
 
 func (c Customer) GetName() string {
 	return c.name
 }
 
-func NewCustomer(name string) (*Customer, error) {
+func (c Customer) GetEmail() Email {
+	return c.email
+}
+
+func (c Customer) GetPhoneNumber() PhoneNumber {
+	return c.phone
+}
+
+func (c Customer) GetShippingAddress() Address {
+	return c.shippingAddress
+}
+
+func (c Customer) GetBillingAddress() Address {
+	return c.billingAddress
+}
+
+func (c Customer) GetTaxRegion() TaxRegion {
+	if !c.shippingAddress.IsZero() {
+		return TaxRegion(c.shippingAddress.GetCountry())
+	}
+
+	return TaxRegion(c.billingAddress.GetCountry())
+}
+
+func (c Customer) IsActive() bool {
+	return !c.deactivated
+}
+
+func NewCustomer(name string, options ...CustomerOption) (*Customer, error) {
 	trimmedName := strings.TrimSpace(name)
-	if len(trimmedName) < 8 {
+	if !isValidCustomerName(trimmedName) {
 		return nil, errors.New("invalid name")
 	}
 
@@ -32,15 +105,113 @@ func NewCustomer(name string) (*Customer, error) {
 		name: trimmedName,
 	}
 
+	for _, option := range options {
+		option(customer)
+	}
+
 	customer.addDomainEvent(CustomerCreated{
 		CustomerId:   customer.id,
 		CustomerName: customer.name,
+		Email:        customer.email,
 	})
 
 	return customer, nil
 }
 
+func (c *Customer) Rename(name string) error {
+	if c.deactivated {
+		return ErrCustomerDeactivated
+	}
+
+	trimmedName := strings.TrimSpace(name)
+	if !isValidCustomerName(trimmedName) {
+		return errors.New("invalid name")
+	}
+
+	if trimmedName == c.name {
+		return nil
+	}
+
+	previousName := c.name
+	c.name = trimmedName
+
+	c.addDomainEvent(CustomerRenamed{
+		CustomerId:   c.id,
+		PreviousName: previousName,
+		CustomerName: trimmedName,
+	})
+
+	return nil
+}
+
+func (c *Customer) ChangeEmail(email Email) error {
+	if c.deactivated {
+		return ErrCustomerDeactivated
+	}
+
+	if email == c.email {
+		return nil
+	}
+
+	previousEmail := c.email
+	c.email = email
+
+	c.addDomainEvent(CustomerEmailChanged{
+		CustomerId:    c.id,
+		PreviousEmail: previousEmail,
+		Email:         email,
+	})
+
+	return nil
+}
+
+func (c *Customer) ChangeContactDetails(phone PhoneNumber, shippingAddress Address, billingAddress Address) error {
+	if c.deactivated {
+		return ErrCustomerDeactivated
+	}
+
+	if phone == c.phone && shippingAddress == c.shippingAddress && billingAddress == c.billingAddress {
+		return nil
+	}
+
+	c.phone = phone
+	c.shippingAddress = shippingAddress
+	c.billingAddress = billingAddress
+
+	c.addDomainEvent(CustomerContactDetailsChanged{
+		CustomerId:      c.id,
+		Phone:           phone,
+		ShippingAddress: shippingAddress,
+		BillingAddress:  billingAddress,
+	})
+
+	return nil
+}
+
+func (c *Customer) Deactivate() {
+	if c.deactivated {
+		return
+	}
+
+	c.deactivated = true
+
+	c.addDomainEvent(CustomerDeactivated{
+		CustomerId: c.id,
+	})
+}
+
 func (c *Customer) EqualsTo(entity Entity[CustomerId]) bool {
 	return reflect.TypeOf(c) == reflect.TypeOf(entity) &&
 		c.GetID() == entity.GetID()
 }
+
+func (c *Customer) Clone() *Customer {
+	clone := *c
+	clone.baseEntity = c.baseEntity.clone()
+
+	return &clone
+}
+
+func isValidCustomerName(name string) bool {
+	return len(name) >= 8
+}
@@ -1,9 +1,14 @@
 package domain
 
+import (
+	"time"
+)
+
 type CartCreated struct {
 	DomainEvent
 	CartId     CartId
 	CustomerId CustomerId
+	Currency   Currency
 }
 
 type ItemAddedToCart struct {
@@ -13,13 +18,190 @@ type ItemAddedToCart struct {
 	Quantity  int
 }
 
+type ItemRemovedFromCart struct {
+	CartId    CartId
+	ProductId ProductId
+}
+
+type ItemQuantityChanged struct {
+	CartId           CartId
+	ProductId        ProductId
+	PreviousQuantity int
+	Quantity         int
+}
+
+type ItemRepriced struct {
+	CartId            CartId
+	ProductId         ProductId
+	PreviousUnitPrice Money
+	UnitPrice         Money
+}
+
+type CouponApplied struct {
+	CartId CartId
+	Code   CouponCode
+}
+
+type ShippingSelected struct {
+	CartId CartId
+	Method ShippingMethodCode
+	Cost   Money
+}
+
+type CouponRemoved struct {
+	CartId CartId
+	Code   CouponCode
+}
+
+type CartCleared struct {
+	CartId CartId
+}
+
+type CartCheckedOut struct {
+	CartId     CartId
+	CustomerId CustomerId
+	Total      Money
+}
+
+type CartAbandoned struct {
+	CartId         CartId
+	CustomerId     CustomerId
+	LastActivityAt time.Time
+}
+
+type CartExpired struct {
+	CartId     CartId
+	CustomerId CustomerId
+}
+
+type CartReactivated struct {
+	CartId     CartId
+	CustomerId CustomerId
+}
+
+type OrderPlaced struct {
+	OrderId    OrderId
+	CartId     CartId
+	CustomerId CustomerId
+	Total      Money
+	PlacedOn   time.Time
+}
+
+type QuoteIssued struct {
+	QuoteId    QuoteId
+	Number     string
+	CartId     CartId
+	CustomerId CustomerId
+	Total      Money
+	ExpiresAt  time.Time
+}
+
+type QuoteAccepted struct {
+	QuoteId QuoteId
+	Number  string
+	Total   Money
+}
+
+type QuoteDeclined struct {
+	QuoteId QuoteId
+	Number  string
+}
+
+type QuoteExpired struct {
+	QuoteId QuoteId
+	Number  string
+}
+
 type CustomerCreated struct {
 	CustomerId   CustomerId
 	CustomerName string
+	Email        Email
+}
+
+type CustomerRenamed struct {
+	CustomerId   CustomerId
+	PreviousName string
+	CustomerName string
+}
+
+type CustomerEmailChanged struct {
+	CustomerId    CustomerId
+	PreviousEmail Email
+	Email         Email
+}
+
+type CustomerContactDetailsChanged struct {
+	CustomerId      CustomerId
+	Phone           PhoneNumber
+	ShippingAddress Address
+	BillingAddress  Address
+}
+
+type CustomerDeactivated struct {
+	CustomerId CustomerId
 }
 
 type ProductCreated struct {
 	ProductId        ProductId
+	SKU              SKU
 	ProductName      string
-	ProductUnitPrice float64
+	ProductUnitPrice Money
+	TaxCategory      TaxCategory
+	Weight           Weight
+	Dimensions       Dimensions
+}
+
+type ProductRenamed struct {
+	ProductId    ProductId
+	PreviousName string
+	ProductName  string
+}
+
+type ProductPriceChanged struct {
+	ProductId        ProductId
+	PreviousPrice    Money
+	ProductUnitPrice Money
+}
+
+type ProductTaxCategoryChanged struct {
+	ProductId           ProductId
+	PreviousTaxCategory TaxCategory
+	TaxCategory         TaxCategory
+}
+
+type ProductShippingDetailsChanged struct {
+	ProductId  ProductId
+	Weight     Weight
+	Dimensions Dimensions
+}
+
+type ProductArchived struct {
+	ProductId ProductId
+}
+
+type ProductRestored struct {
+	ProductId ProductId
+}
+
+type StockRestocked struct {
+	ProductId ProductId
+	OnHand    int
+}
+
+type StockReserved struct {
+	ProductId ProductId
+	CartId    CartId
+	Quantity  int
+}
+
+type StockReleased struct {
+	ProductId ProductId
+	CartId    CartId
+	Quantity  int
+}
+
+type StockCommitted struct {
+	ProductId ProductId
+	CartId    CartId
+	Quantity  int
 }
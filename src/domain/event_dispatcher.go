@@ -0,0 +1,35 @@
+package domain
+
+import "reflect"
+
+type DomainEventHandler func(DomainEvent) error
+
+type EventDispatcher interface {
+	Register(eventName string, handler DomainEventHandler)
+	Dispatch(events ...DomainEvent) error
+}
+
+func RegisterEventHandler[E DomainEvent](dispatcher EventDispatcher, handler func(E) error) {
+	var event E
+	dispatcher.Register(EventName(event), func(domainEvent DomainEvent) error {
+		typedEvent, ok := domainEvent.(E)
+		if !ok {
+			return nil
+		}
+
+		return handler(typedEvent)
+	})
+}
+
+func EventName(event DomainEvent) string {
+	eventType := reflect.TypeOf(event)
+	if eventType == nil {
+		return ""
+	}
+
+	for eventType.Kind() == reflect.Pointer {
+		eventType = eventType.Elem()
+	}
+
+	return eventType.Name()
+}
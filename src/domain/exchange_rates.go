@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+)
+
+var ErrCurrencyNotConvertible = errors.New("currency cannot be converted")
+
+type ExchangeRateProvider interface {
+	GetRate(from Currency, to Currency) (*big.Rat, error)
+}
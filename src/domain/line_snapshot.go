@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+)
+
+type LineSnapshot struct {
+	productId ProductId
+	unitPrice Money
+	quantity  int
+	total     Money
+}
+
+func snapshotCartLines(cart *Cart) []LineSnapshot {
+	var lines []LineSnapshot
+	for _, cartItem := range cart.GetItems() {
+		total, _ := cartItem.getTotalIn(cart.GetCurrency())
+		lines = append(lines, LineSnapshot{
+			productId: cartItem.GetProductId(),
+			unitPrice: cartItem.GetUnitPrice(),
+			quantity:  cartItem.GetQuantity(),
+			total:     total,
+		})
+	}
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].productId.String() < lines[j].productId.String()
+	})
+
+	return lines
+}
+
+func sameQuantities(lines []LineSnapshot, others []LineSnapshot) bool {
+	if len(lines) != len(others) {
+		return false
+	}
+
+	for position, line := range lines {
+		if line.productId != others[position].productId || line.quantity != others[position].quantity {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l LineSnapshot) GetProductId() ProductId {
+	return l.productId
+}
+
+func (l LineSnapshot) GetUnitPrice() Money {
+	return l.unitPrice
+}
+
+func (l LineSnapshot) GetQuantity() int {
+	return l.quantity
+}
+
+func (l LineSnapshot) GetTotal() Money {
+	return l.total
+}
+
+func (l LineSnapshot) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(l, other)
+}
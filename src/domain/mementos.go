@@ -0,0 +1,649 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+type ProductMemento struct {
+	Id          ProductId   `json:"id"`
+	Version     int         `json:"version"`
+	SKU         SKU         `json:"sku,omitempty"`
+	Name        string      `json:"name"`
+	UnitPrice   Money       `json:"unit_price"`
+	TaxCategory TaxCategory `json:"tax_category"`
+	Weight      Weight      `json:"weight_grams,omitempty"`
+	Dimensions  Dimensions  `json:"dimensions"`
+	Archived    bool        `json:"archived,omitempty"`
+}
+
+func (p *Product) ToMemento() ProductMemento {
+	return ProductMemento{
+		Id:          p.id,
+		Version:     p.version,
+		SKU:         p.sku,
+		Name:        p.name,
+		UnitPrice:   p.unitPrice,
+		TaxCategory: p.taxCategory,
+		Weight:      p.weight,
+		Dimensions:  p.dimensions,
+		Archived:    p.archived,
+	}
+}
+
+func RestoreProduct(memento ProductMemento) (*Product, error) {
+	if memento.Id == (ProductId{}) || memento.Version < 0 || !isValidProductName(memento.Name) || !memento.UnitPrice.IsPositive() || memento.Weight < 0 {
+		return nil, errors.New("invalid product memento")
+	}
+
+	if memento.SKU != "" {
+		if _, err := NewSKU(string(memento.SKU)); err != nil {
+			return nil, err
+		}
+	}
+
+	taxCategory := StandardTaxCategory
+	if memento.TaxCategory != "" {
+		var err error
+		if taxCategory, err = NewTaxCategory(string(memento.TaxCategory)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Product{
+		baseEntity: &baseEntity[ProductId]{
+			id:      memento.Id,
+			version: memento.Version,
+		},
+		sku:         memento.SKU,
+		name:        memento.Name,
+		unitPrice:   memento.UnitPrice,
+		taxCategory: taxCategory,
+		weight:      memento.Weight,
+		dimensions:  memento.Dimensions,
+		archived:    memento.Archived,
+	}, nil
+}
+
+type CustomerMemento struct {
+	Id              CustomerId  `json:"id"`
+	Version         int         `json:"version"`
+	Name            string      `json:"name"`
+	Email           Email       `json:"email,omitempty"`
+	Phone           PhoneNumber `json:"phone,omitempty"`
+	ShippingAddress Address     `json:"shipping_address"`
+	BillingAddress  Address     `json:"billing_address"`
+	Deactivated     bool        `json:"deactivated,omitempty"`
+}
+
+func (c *Customer) ToMemento() CustomerMemento {
+	return CustomerMemento{
+		Id:              c.id,
+		Version:         c.version,
+		Name:            c.name,
+		Email:           c.email,
+		Phone:           c.phone,
+		ShippingAddress: c.shippingAddress,
+		BillingAddress:  c.billingAddress,
+		Deactivated:     c.deactivated,
+	}
+}
+
+func RestoreCustomer(memento CustomerMemento) (*Customer, error) {
+	if memento.Id == (CustomerId{}) || memento.Version < 0 || !isValidCustomerName(memento.Name) {
+		return nil, errors.New("invalid customer memento")
+	}
+
+	if memento.Email != "" {
+		if _, err := NewEmail(string(memento.Email)); err != nil {
+			return nil, err
+		}
+	}
+
+	if memento.Phone != "" {
+		if _, err := NewPhoneNumber(string(memento.Phone)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Customer{
+		baseEntity: &baseEntity[CustomerId]{
+			id:      memento.Id,
+			version: memento.Version,
+		},
+		name:            memento.Name,
+		email:           memento.Email,
+		phone:           memento.Phone,
+		shippingAddress: memento.ShippingAddress,
+		billingAddress:  memento.BillingAddress,
+		deactivated:     memento.Deactivated,
+	}, nil
+}
+
+type CartMemento struct {
+	Id             CartId              `json:"id"`
+	Version        int                 `json:"version"`
+	CustomerId     CustomerId          `json:"customer_id"`
+	Currency       Currency            `json:"currency"`
+	Status         CartStatus          `json:"status"`
+	LastActivityAt time.Time           `json:"last_activity_at"`
+	Items          []CartItemMemento   `json:"items"`
+	Coupons        []CouponCode        `json:"coupons,omitempty"`
+	Adjustments    []AdjustmentMemento `json:"adjustments,omitempty"`
+	Shipping       *ShippingMemento    `json:"shipping,omitempty"`
+	Taxed          bool                `json:"taxed,omitempty"`
+	TaxRegion      TaxRegion           `json:"tax_region,omitempty"`
+	Taxes          []LineTaxMemento    `json:"taxes,omitempty"`
+}
+
+type CartItemMemento struct {
+	ProductId      ProductId   `json:"product_id"`
+	UnitPrice      Money       `json:"unit_price"`
+	AddedUnitPrice Money       `json:"added_unit_price"`
+	ExchangeRate   *big.Rat    `json:"exchange_rate"`
+	TaxCategory    TaxCategory `json:"tax_category"`
+	Weight         Weight      `json:"weight_grams,omitempty"`
+	Dimensions     Dimensions  `json:"dimensions"`
+	Quantity       int         `json:"quantity"`
+}
+
+type AdjustmentMemento struct {
+	Promotion string     `json:"promotion"`
+	ProductId *ProductId `json:"product_id,omitempty"`
+	Amount    Money      `json:"amount"`
+}
+
+type ShippingMemento struct {
+	Method ShippingMethodCode `json:"method"`
+	Name   string             `json:"name"`
+	Cost   Money              `json:"cost"`
+}
+
+type LineTaxMemento struct {
+	ProductId ProductId `json:"product_id"`
+	Rate      *big.Rat  `json:"rate"`
+	Inclusive bool      `json:"inclusive,omitempty"`
+	Amount    Money     `json:"amount"`
+}
+
+func (c *Cart) ToMemento() CartMemento {
+	memento := CartMemento{
+		Id:             c.id,
+		Version:        c.version,
+		CustomerId:     c.customerId,
+		Currency:       c.currency,
+		Status:         c.status,
+		LastActivityAt: c.lastActivityAt,
+		Items:          []CartItemMemento{},
+		Coupons:        c.GetCoupons(),
+		Taxed:          c.IsTaxed(),
+		TaxRegion:      c.taxRegion,
+	}
+
+	items := c.GetItems()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].productId.String() < items[j].productId.String()
+	})
+	for _, cartItem := range items {
+		memento.Items = append(memento.Items, CartItemMemento{
+			ProductId:      cartItem.productId,
+			UnitPrice:      cartItem.price,
+			AddedUnitPrice: cartItem.addedPrice,
+			ExchangeRate:   cartItem.GetExchangeRate(),
+			TaxCategory:    cartItem.taxCategory,
+			Weight:         cartItem.weight,
+			Dimensions:     cartItem.dimensions,
+			Quantity:       cartItem.quantity,
+		})
+	}
+
+	memento.Adjustments = toAdjustmentMementos(c.adjustments)
+	memento.Shipping = toShippingMemento(c.shipping)
+	memento.Taxes = toLineTaxMementos(c.taxes)
+
+	return memento
+}
+
+func RestoreCart(memento CartMemento) (*Cart, error) {
+	if memento.Id == (CartId{}) || memento.Version < 0 || memento.CustomerId == (CustomerId{}) || memento.LastActivityAt.IsZero() {
+		return nil, errors.New("invalid cart memento")
+	}
+
+	if _, err := NewCurrency(string(memento.Currency)); err != nil {
+		return nil, err
+	}
+
+	if _, err := NewCartStatus(string(memento.Status)); err != nil {
+		return nil, err
+	}
+
+	cart := &Cart{
+		baseEntity: &baseEntity[CartId]{
+			id:      memento.Id,
+			version: memento.Version,
+		},
+		customerId:     memento.CustomerId,
+		currency:       memento.Currency,
+		items:          map[ProductId]item{},
+		status:         memento.Status,
+		lastActivityAt: memento.LastActivityAt,
+		clock:          SystemClock(),
+		coupons:        append([]CouponCode(nil), memento.Coupons...),
+		taxRegion:      memento.TaxRegion,
+	}
+
+	for _, itemMemento := range memento.Items {
+		if itemMemento.ProductId == (ProductId{}) || itemMemento.Quantity < 1 || itemMemento.ExchangeRate == nil || itemMemento.ExchangeRate.Sign() <= 0 {
+			return nil, errors.New("invalid cart item memento")
+		}
+
+		if _, found := cart.items[itemMemento.ProductId]; found {
+			return nil, errors.New("duplicated cart item memento for product " + itemMemento.ProductId.String())
+		}
+
+		taxCategory := itemMemento.TaxCategory
+		if taxCategory == "" {
+			taxCategory = StandardTaxCategory
+		}
+
+		cart.items[itemMemento.ProductId] = item{
+			productId:    itemMemento.ProductId,
+			price:        itemMemento.UnitPrice,
+			addedPrice:   itemMemento.AddedUnitPrice,
+			exchangeRate: new(big.Rat).Set(itemMemento.ExchangeRate),
+			taxCategory:  taxCategory,
+			weight:       itemMemento.Weight,
+			dimensions:   itemMemento.Dimensions,
+			quantity:     itemMemento.Quantity,
+		}
+	}
+
+	cart.adjustments = restoreAdjustments(memento.Adjustments)
+
+	var err error
+	if cart.shipping, err = restoreShipping(memento.Shipping); err != nil {
+		return nil, err
+	}
+
+	if cart.taxes, err = restoreLineTaxes(memento.Taxed, memento.Taxes); err != nil {
+		return nil, err
+	}
+
+	if _, err = cart.calculateTotal(); err != nil {
+		return nil, err
+	}
+
+	return cart, nil
+}
+
+type LineMemento struct {
+	ProductId ProductId `json:"product_id"`
+	UnitPrice Money     `json:"unit_price"`
+	Quantity  int       `json:"quantity"`
+	Total     Money     `json:"total"`
+}
+
+type OrderMemento struct {
+	Id          OrderId             `json:"id"`
+	Version     int                 `json:"version"`
+	QuoteId     *QuoteId            `json:"quote_id,omitempty"`
+	CartId      CartId              `json:"cart_id"`
+	CustomerId  CustomerId          `json:"customer_id"`
+	Currency    Currency            `json:"currency"`
+	Lines       []LineMemento       `json:"lines"`
+	Subtotal    Money               `json:"subtotal"`
+	Adjustments []AdjustmentMemento `json:"adjustments,omitempty"`
+	Shipping    *ShippingMemento    `json:"shipping,omitempty"`
+	Taxed       bool                `json:"taxed,omitempty"`
+	Taxes       []LineTaxMemento    `json:"taxes,omitempty"`
+	Total       Money               `json:"total"`
+	PlacedOn    time.Time           `json:"placed_on"`
+}
+
+func (o *Order) ToMemento() OrderMemento {
+	memento := OrderMemento{
+		Id:          o.id,
+		Version:     o.version,
+		CartId:      o.cartId,
+		CustomerId:  o.customerId,
+		Currency:    o.currency,
+		Lines:       toLineMementos(o.lines),
+		Subtotal:    o.subtotal,
+		Adjustments: toAdjustmentMementos(o.adjustments),
+		Shipping:    toShippingMemento(o.shipping),
+		Taxed:       o.IsTaxed(),
+		Taxes:       toLineTaxMementos(o.taxes),
+		Total:       o.total,
+		PlacedOn:    o.placedOn,
+	}
+
+	if o.HasQuote() {
+		quoteId := o.quoteId
+		memento.QuoteId = &quoteId
+	}
+
+	return memento
+}
+
+func RestoreOrder(memento OrderMemento) (*Order, error) {
+	if memento.Id == (OrderId{}) || memento.Version < 0 || memento.CartId == (CartId{}) || memento.CustomerId == (CustomerId{}) || memento.PlacedOn.IsZero() {
+		return nil, errors.New("invalid order memento")
+	}
+
+	if _, err := NewCurrency(string(memento.Currency)); err != nil {
+		return nil, err
+	}
+
+	lines, err := restoreLines(memento.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	shipping, err := restoreShipping(memento.Shipping)
+	if err != nil {
+		return nil, err
+	}
+
+	taxes, err := restoreLineTaxes(memento.Taxed, memento.Taxes)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &Order{
+		baseEntity: &baseEntity[OrderId]{
+			id:      memento.Id,
+			version: memento.Version,
+		},
+		cartId:      memento.CartId,
+		customerId:  memento.CustomerId,
+		currency:    memento.Currency,
+		lines:       lines,
+		subtotal:    memento.Subtotal,
+		adjustments: restoreAdjustments(memento.Adjustments),
+		shipping:    shipping,
+		taxes:       taxes,
+		total:       memento.Total,
+		placedOn:    memento.PlacedOn,
+	}
+
+	if memento.QuoteId != nil {
+		order.quoteId = *memento.QuoteId
+	}
+
+	return order, nil
+}
+
+type QuoteMemento struct {
+	Id          QuoteId             `json:"id"`
+	Version     int                 `json:"version"`
+	Number      string              `json:"number"`
+	CartId      CartId              `json:"cart_id"`
+	CustomerId  CustomerId          `json:"customer_id"`
+	Currency    Currency            `json:"currency"`
+	Lines       []LineMemento       `json:"lines"`
+	Subtotal    Money               `json:"subtotal"`
+	Adjustments []AdjustmentMemento `json:"adjustments,omitempty"`
+	Shipping    *ShippingMemento    `json:"shipping,omitempty"`
+	Taxed       bool                `json:"taxed,omitempty"`
+	Taxes       []LineTaxMemento    `json:"taxes,omitempty"`
+	Total       Money               `json:"total"`
+	IssuedAt    time.Time           `json:"issued_at"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+	Status      QuoteStatus         `json:"status"`
+}
+
+func (q *Quote) ToMemento() QuoteMemento {
+	return QuoteMemento{
+		Id:          q.id,
+		Version:     q.version,
+		Number:      q.number,
+		CartId:      q.cartId,
+		CustomerId:  q.customerId,
+		Currency:    q.currency,
+		Lines:       toLineMementos(q.lines),
+		Subtotal:    q.subtotal,
+		Adjustments: toAdjustmentMementos(q.adjustments),
+		Shipping:    toShippingMemento(q.shipping),
+		Taxed:       q.IsTaxed(),
+		Taxes:       toLineTaxMementos(q.taxes),
+		Total:       q.total,
+		IssuedAt:    q.issuedAt,
+		ExpiresAt:   q.expiresAt,
+		Status:      q.status,
+	}
+}
+
+func RestoreQuote(memento QuoteMemento) (*Quote, error) {
+	if memento.Id == (QuoteId{}) || memento.Version < 0 || memento.Number == "" || memento.CartId == (CartId{}) || memento.CustomerId == (CustomerId{}) || memento.IssuedAt.IsZero() || !memento.ExpiresAt.After(memento.IssuedAt) {
+		return nil, errors.New("invalid quote memento")
+	}
+
+	if _, err := NewCurrency(string(memento.Currency)); err != nil {
+		return nil, err
+	}
+
+	switch memento.Status {
+	case QuoteStatusPending, QuoteStatusAccepted, QuoteStatusDeclined, QuoteStatusExpired:
+	default:
+		return nil, errors.New("invalid quote status")
+	}
+
+	lines, err := restoreLines(memento.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	shipping, err := restoreShipping(memento.Shipping)
+	if err != nil {
+		return nil, err
+	}
+
+	taxes, err := restoreLineTaxes(memento.Taxed, memento.Taxes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Quote{
+		baseEntity: &baseEntity[QuoteId]{
+			id:      memento.Id,
+			version: memento.Version,
+		},
+		number:      memento.Number,
+		cartId:      memento.CartId,
+		customerId:  memento.CustomerId,
+		currency:    memento.Currency,
+		lines:       lines,
+		subtotal:    memento.Subtotal,
+		adjustments: restoreAdjustments(memento.Adjustments),
+		shipping:    shipping,
+		taxes:       taxes,
+		total:       memento.Total,
+		issuedAt:    memento.IssuedAt,
+		expiresAt:   memento.ExpiresAt,
+		status:      memento.Status,
+	}, nil
+}
+
+type StockItemMemento struct {
+	ProductId    ProductId                 `json:"product_id"`
+	Version      int                       `json:"version"`
+	OnHand       int                       `json:"on_hand"`
+	Reservations []StockReservationMemento `json:"reservations,omitempty"`
+}
+
+type StockReservationMemento struct {
+	CartId   CartId `json:"cart_id"`
+	Quantity int    `json:"quantity"`
+}
+
+func (s *StockItem) ToMemento() StockItemMemento {
+	memento := StockItemMemento{
+		ProductId: s.id,
+		Version:   s.version,
+		OnHand:    s.onHand,
+	}
+
+	for cartId, quantity := range s.reservations {
+		memento.Reservations = append(memento.Reservations, StockReservationMemento{
+			CartId:   cartId,
+			Quantity: quantity,
+		})
+	}
+
+	sort.Slice(memento.Reservations, func(i, j int) bool {
+		return memento.Reservations[i].CartId.String() < memento.Reservations[j].CartId.String()
+	})
+
+	return memento
+}
+
+func RestoreStockItem(memento StockItemMemento) (*StockItem, error) {
+	if memento.ProductId == (ProductId{}) || memento.Version < 0 || memento.OnHand < 0 {
+		return nil, errors.New("invalid stock item memento")
+	}
+
+	stockItem := &StockItem{
+		baseEntity: &baseEntity[ProductId]{
+			id:      memento.ProductId,
+			version: memento.Version,
+		},
+		onHand:       memento.OnHand,
+		reservations: map[CartId]int{},
+	}
+
+	for _, reservation := range memento.Reservations {
+		if reservation.CartId == (CartId{}) || reservation.Quantity < 1 {
+			return nil, errors.New("invalid stock reservation memento")
+		}
+
+		if _, found := stockItem.reservations[reservation.CartId]; found {
+			return nil, errors.New("duplicated stock reservation memento for cart " + reservation.CartId.String())
+		}
+
+		stockItem.reservations[reservation.CartId] = reservation.Quantity
+	}
+
+	if stockItem.GetAvailable() < 0 {
+		return nil, ErrOnHandBelowReserved
+	}
+
+	return stockItem, nil
+}
+
+func toLineMementos(lines []LineSnapshot) []LineMemento {
+	mementos := []LineMemento{}
+	for _, line := range lines {
+		mementos = append(mementos, LineMemento{
+			ProductId: line.productId,
+			UnitPrice: line.unitPrice,
+			Quantity:  line.quantity,
+			Total:     line.total,
+		})
+	}
+
+	return mementos
+}
+
+func restoreLines(mementos []LineMemento) ([]LineSnapshot, error) {
+	var lines []LineSnapshot
+	for _, memento := range mementos {
+		if memento.ProductId == (ProductId{}) || memento.Quantity < 1 {
+			return nil, errors.New("invalid line memento")
+		}
+
+		lines = append(lines, LineSnapshot{
+			productId: memento.ProductId,
+			unitPrice: memento.UnitPrice,
+			quantity:  memento.Quantity,
+			total:     memento.Total,
+		})
+	}
+
+	return lines, nil
+}
+
+func toAdjustmentMementos(adjustments []Adjustment) []AdjustmentMemento {
+	var mementos []AdjustmentMemento
+	for _, adjustment := range adjustments {
+		memento := AdjustmentMemento{
+			Promotion: adjustment.promotion,
+			Amount:    adjustment.amount,
+		}
+		if adjustment.IsLineAdjustment() {
+			productId := adjustment.productId
+			memento.ProductId = &productId
+		}
+		mementos = append(mementos, memento)
+	}
+
+	return mementos
+}
+
+func restoreAdjustments(mementos []AdjustmentMemento) []Adjustment {
+	var adjustments []Adjustment
+	for _, memento := range mementos {
+		adjustment := NewCartAdjustment(memento.Promotion, memento.Amount)
+		if memento.ProductId != nil {
+			adjustment = NewLineAdjustment(memento.Promotion, *memento.ProductId, memento.Amount)
+		}
+		adjustments = append(adjustments, adjustment)
+	}
+
+	return adjustments
+}
+
+func toShippingMemento(shipping ShippingQuote) *ShippingMemento {
+	if shipping.method == "" {
+		return nil
+	}
+
+	return &ShippingMemento{
+		Method: shipping.method,
+		Name:   shipping.name,
+		Cost:   shipping.cost,
+	}
+}
+
+func restoreShipping(memento *ShippingMemento) (ShippingQuote, error) {
+	if memento == nil {
+		return ShippingQuote{}, nil
+	}
+
+	if memento.Method == "" {
+		return ShippingQuote{}, errors.New("invalid shipping memento")
+	}
+
+	return NewShippingQuote(memento.Method, memento.Name, memento.Cost), nil
+}
+
+func toLineTaxMementos(taxes []LineTax) []LineTaxMemento {
+	var mementos []LineTaxMemento
+	for _, lineTax := range taxes {
+		mementos = append(mementos, LineTaxMemento{
+			ProductId: lineTax.productId,
+			Rate:      lineTax.GetRate(),
+			Inclusive: lineTax.inclusive,
+			Amount:    lineTax.amount,
+		})
+	}
+
+	return mementos
+}
+
+func restoreLineTaxes(taxed bool, mementos []LineTaxMemento) ([]LineTax, error) {
+	if !taxed {
+		return nil, nil
+	}
+
+	taxes := []LineTax{}
+	for _, memento := range mementos {
+		if memento.Rate == nil {
+			return nil, errors.New("invalid line tax memento")
+		}
+		taxes = append(taxes, NewLineTax(memento.ProductId, memento.Rate, memento.Inclusive, memento.Amount))
+	}
+
+	return taxes, nil
+}
@@ -0,0 +1,326 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const DefaultCurrency Currency = "USD"
+
+const (
+	maxDecimalLength   = 64
+	maxDecimalExponent = 32
+)
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+var ErrAmountOutOfRange = errors.New("amount out of range")
+
+type Currency string
+
+var currencyExponents = map[Currency]int{
+	"BHD": 3,
+	"CLP": 0,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"PYG": 0,
+	"TND": 3,
+	"UYI": 0,
+	"VND": 0,
+}
+
+func NewCurrency(code string) (Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", errors.New("invalid currency")
+	}
+
+	for _, letter := range code {
+		if letter < 'A' || letter > 'Z' {
+			return "", errors.New("invalid currency")
+		}
+	}
+
+	return Currency(code), nil
+}
+
+func (c Currency) exponent() int {
+	if exponent, found := currencyExponents[c]; found {
+		return exponent
+	}
+
+	return 2
+}
+
+func (c Currency) minorUnitsPerMajor() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(c.exponent())), nil)
+}
+
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+func NewMoney(minorUnits int64, currency Currency) Money {
+	return Money{
+		amount:   minorUnits,
+		currency: currency,
+	}
+}
+
+func ZeroMoney(currency Currency) Money {
+	return NewMoney(0, currency)
+}
+
+func ParseMoney(amount string, currency Currency) (Money, error) {
+	currency, err := NewCurrency(string(currency))
+	if err != nil {
+		return Money{}, err
+	}
+
+	decimal, err := ParseDecimal(strings.TrimSpace(amount))
+	if err != nil {
+		return Money{}, err
+	}
+
+	minorUnits, err := roundHalfEven(decimal.Mul(decimal, new(big.Rat).SetInt(currency.minorUnitsPerMajor())))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(minorUnits, currency), nil
+}
+
+func ParseDecimal(value string) (*big.Rat, error) {
+	if len(value) > maxDecimalLength {
+		return nil, errors.New("amount is too long")
+	}
+
+	if position := strings.IndexAny(value, "eE"); position >= 0 {
+		exponent, err := strconv.Atoi(value[position+1:])
+		if err != nil || exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return nil, fmt.Errorf("invalid amount %q", value)
+		}
+	}
+
+	decimal, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return decimal, nil
+}
+
+func (m Money) MinorUnits() int64 {
+	return m.amount
+}
+
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	sum := m.amount + other.amount
+	if (other.amount > 0 && sum < m.amount) || (other.amount < 0 && sum > m.amount) {
+		return Money{}, ErrAmountOutOfRange
+	}
+
+	return NewMoney(sum, m.currency), nil
+}
+
+func (m Money) Subtract(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	difference := m.amount - other.amount
+	if (other.amount > 0 && difference > m.amount) || (other.amount < 0 && difference < m.amount) {
+		return Money{}, ErrAmountOutOfRange
+	}
+
+	return NewMoney(difference, m.currency), nil
+}
+
+func (m Money) Negate() Money {
+	return NewMoney(-m.amount, m.currency)
+}
+
+func (m Money) Multiply(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(quantity))
+	if !product.IsInt64() {
+		return Money{}, ErrAmountOutOfRange
+	}
+
+	return NewMoney(product.Int64(), m.currency), nil
+}
+
+func (m Money) MultiplyByRate(rate *big.Rat) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), rate)
+	minorUnits, err := roundHalfEven(product)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(minorUnits, m.currency), nil
+}
+
+func (m Money) Convert(to Currency, rate *big.Rat) (Money, error) {
+	if m.currency == to {
+		return m, nil
+	}
+
+	converted := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), rate)
+	converted.Mul(converted, new(big.Rat).SetFrac(to.minorUnitsPerMajor(), m.currency.minorUnitsPerMajor()))
+	minorUnits, err := roundHalfEven(converted)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(minorUnits, to), nil
+}
+
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	totalRatio := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, errors.New("invalid ratio")
+		}
+		totalRatio.Add(totalRatio, big.NewInt(ratio))
+	}
+
+	if totalRatio.Sign() == 0 {
+		return nil, errors.New("invalid ratio")
+	}
+
+	if m.amount == math.MinInt64 {
+		return nil, ErrAmountOutOfRange
+	}
+
+	sign := int64(1)
+	remainder := m.amount
+	if remainder < 0 {
+		sign = -1
+		remainder = -remainder
+	}
+
+	amount := big.NewInt(remainder)
+	shares := make([]Money, len(ratios))
+	for position, ratio := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(ratio))
+		share.Quo(share, totalRatio)
+		shares[position] = NewMoney(share.Int64(), m.currency)
+		remainder -= share.Int64()
+	}
+
+	for position := 0; remainder > 0; position = (position + 1) % len(shares) {
+		if ratios[position] == 0 {
+			continue
+		}
+		shares[position].amount++
+		remainder--
+	}
+
+	for position := range shares {
+		shares[position].amount *= sign
+	}
+
+	return shares, nil
+}
+
+func (m Money) Compare(other Money) (int, error) {
+	if m.currency != other.currency {
+		return 0, ErrCurrencyMismatch
+	}
+
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) Decimal() string {
+	exponent := m.currency.exponent()
+	return new(big.Rat).SetFrac(big.NewInt(m.amount), m.currency.minorUnitsPerMajor()).FloatString(exponent)
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.currency)
+}
+
+func (m Money) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(m, other)
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{
+		Amount:   m.Decimal(),
+		Currency: m.currency,
+	})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var decoded moneyJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	money, err := ParseMoney(decoded.Amount, decoded.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = money
+	return nil
+}
+
+func roundHalfEven(value *big.Rat) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	doubledRemainder := new(big.Int).Abs(remainder)
+	doubledRemainder.Lsh(doubledRemainder, 1)
+	comparison := doubledRemainder.Cmp(value.Denom())
+	if comparison > 0 || (comparison == 0 && quotient.Bit(0) == 1) {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrAmountOutOfRange
+	}
+
+	return quotient.Int64(), nil
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderId uuid.UUID
+
+func (id OrderId) String() string {
+	return uuid.UUID(id).String()
+}
+
+func (id OrderId) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *OrderId) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(id).UnmarshalText(data)
+}
+
+type Order struct {
+	*baseEntity[OrderId]
+	quoteId     QuoteId
+	cartId      CartId
+	customerId  CustomerId
+	currency    Currency
+	lines       []LineSnapshot
+	subtotal    Money
+	adjustments []Adjustment
+	shipping    ShippingQuote
+	taxes       []LineTax
+	total       Money
+	placedOn    time.Time
+}
+
+func PlaceOrder(cart *Cart, placedOn time.Time) (*Order, error) {
+	if cart == nil {
+		return nil, errors.New("no cart provided")
+	}
+
+	if err := cart.Checkout(); err != nil {
+		return nil, err
+	}
+
+	return placeOrder(cart, &Order{
+		cartId:      cart.GetID(),
+		customerId:  cart.GetCustomerID(),
+		currency:    cart.GetCurrency(),
+		lines:       snapshotCartLines(cart),
+		subtotal:    cart.GetSubtotal(),
+		adjustments: cloneSlice(cart.adjustments),
+		shipping:    cart.GetShipping(),
+		taxes:       cloneSlice(cart.taxes),
+		total:       cart.GetTotal(),
+		placedOn:    placedOn,
+	}), nil
+}
+
+func PlaceOrderFromQuote(cart *Cart, quote *Quote, placedOn time.Time) (*Order, error) {
+	if cart == nil {
+		return nil, errors.New("no cart provided")
+	}
+
+	if quote == nil {
+		return nil, errors.New("no quote provided")
+	}
+
+	if err := quote.ensureOrderable(cart, placedOn); err != nil {
+		return nil, err
+	}
+
+	if err := cart.Checkout(); err != nil {
+		return nil, err
+	}
+
+	return placeOrder(cart, &Order{
+		quoteId:     quote.GetID(),
+		cartId:      cart.GetID(),
+		customerId:  cart.GetCustomerID(),
+		currency:    quote.currency,
+		lines:       quote.GetLines(),
+		subtotal:    quote.subtotal,
+		adjustments: cloneSlice(quote.adjustments),
+		shipping:    quote.shipping,
+		taxes:       cloneSlice(quote.taxes),
+		total:       quote.total,
+		placedOn:    placedOn,
+	}), nil
+}
+
+func placeOrder(cart *Cart, order *Order) *Order {
+	order.baseEntity = &baseEntity[OrderId]{
+		id: OrderId(uuid.New()),
+	}
+
+	cart.addDomainEvent(OrderPlaced{
+		OrderId:    order.id,
+		CartId:     order.cartId,
+		CustomerId: order.customerId,
+		Total:      order.total,
+		PlacedOn:   order.placedOn,
+	})
+
+	return order
+}
+
+func (o *Order) EqualsTo(entity Entity[OrderId]) bool {
+	return reflect.TypeOf(o) == reflect.TypeOf(entity) && o.GetID() == entity.GetID()
+}
+
+func (o *Order) Clone() *Order {
+	clone := *o
+	clone.baseEntity = o.baseEntity.clone()
+	clone.lines = cloneSlice(o.lines)
+	clone.adjustments = cloneSlice(o.adjustments)
+	clone.taxes = cloneSlice(o.taxes)
+
+	return &clone
+}
+
+func (o Order) HasQuote() bool {
+	return o.quoteId != QuoteId{}
+}
+
+func (o Order) GetQuoteID() QuoteId {
+	return o.quoteId
+}
+
+func (o Order) GetCartID() CartId {
+	return o.cartId
+}
+
+func (o Order) GetCustomerID() CustomerId {
+	return o.customerId
+}
+
+func (o Order) GetCurrency() Currency {
+	return o.currency
+}
+
+func (o Order) GetLines() []LineSnapshot {
+	return append([]LineSnapshot{}, o.lines...)
+}
+
+func (o Order) GetSubtotal() Money {
+	return o.subtotal
+}
+
+func (o Order) GetLineAdjustments() []Adjustment {
+	return filterAdjustments(o.adjustments, true)
+}
+
+func (o Order) GetCartAdjustments() []Adjustment {
+	return filterAdjustments(o.adjustments, false)
+}
+
+func (o Order) HasShipping() bool {
+	return o.shipping.method != ""
+}
+
+func (o Order) GetShipping() ShippingQuote {
+	return o.shipping
+}
+
+func (o Order) GetTax() Money {
+	return sumLineTaxes(o.currency, o.taxes)
+}
+
+func (o Order) GetLineTaxes() []LineTax {
+	return append([]LineTax{}, o.taxes...)
+}
+
+func (o Order) IsTaxed() bool {
+	return o.taxes != nil
+}
+
+func (o Order) GetTotal() Money {
+	return o.total
+}
+
+func (o Order) GetPlacedOn() time.Time {
+	return o.placedOn
+}
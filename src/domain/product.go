@@ -10,15 +10,62 @@ import (
 
 type ProductId uuid.UUID
 
+var ErrProductArchived = errors.New("product is archived")
+
+func (id ProductId) String() string {
+	return uuid.UUID(id).String()
+}
+
+func (id ProductId) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *ProductId) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(id).UnmarshalText(data)
+}
+
 type Product struct {
 	*baseEntity[ProductId]
-	name      string
-	unitPrice float64
+	sku         SKU
+	name        string
+	unitPrice   Money
+	taxCategory TaxCategory
+	weight      Weight
+	dimensions  Dimensions
+	archived    bool
+}
+
+type ProductOption func(*Product)
+
+func WithSKU(sku SKU) ProductOption {
+	return func(product *Product) {
+		product.sku = sku
+	}
 }
 
-func NewProduct(name string, price float64) (*Product, error) {
+func WithTaxCategory(category TaxCategory) ProductOption {
+	return func(product *Product) {
+		if category != "" {
+			product.taxCategory = category
+		}
+	}
+}
+
+func WithWeight(weight Weight) ProductOption {
+	return func(product *Product) {
+		product.weight = weight
+	}
+}
+
+func WithDimensions(dimensions Dimensions) ProductOption {
+	return func(product *Product) {
+		product.dimensions = dimensions
+	}
+}
+
+func NewProduct(name string, price Money, options ...ProductOption) (*Product, error) {
 	trimmedName := strings.TrimSpace(name)
-	if len(trimmedName) < 10 || price <= 0.00 {
+	if !isValidProductName(trimmedName) || !price.IsPositive() {
 		return nil, errors.New("invalid arguments")
 	}
 
@@ -26,28 +73,177 @@ func NewProduct(name string, price float64) (*Product, error) {
 		baseEntity: &baseEntity[ProductId]{
 			id: ProductId(uuid.New()),
 		},
-		name:      trimmedName,
-		unitPrice: price,
+		name:        trimmedName,
+		unitPrice:   price,
+		taxCategory: StandardTaxCategory,
+	}
+
+	for _, option := range options {
+		option(product)
 	}
 
 	product.addDomainEvent(ProductCreated{
 		ProductId:        product.id,
+		SKU:              product.sku,
 		ProductName:      product.name,
 		ProductUnitPrice: product.unitPrice,
+		TaxCategory:      product.taxCategory,
+		Weight:           product.weight,
+		Dimensions:       product.dimensions,
 	})
 
 	return product, nil
 }
 
+func (p *Product) Rename(name string) error {
+	trimmedName := strings.TrimSpace(name)
+	if !isValidProductName(trimmedName) {
+		return errors.New("invalid product name")
+	}
+
+	if trimmedName == p.name {
+		return nil
+	}
+
+	previousName := p.name
+	p.name = trimmedName
+
+	p.addDomainEvent(ProductRenamed{
+		ProductId:    p.id,
+		PreviousName: previousName,
+		ProductName:  trimmedName,
+	})
+
+	return nil
+}
+
+func (p *Product) ChangePrice(price Money) error {
+	if !price.IsPositive() {
+		return errors.New("invalid product price")
+	}
+
+	if price.EqualsTo(p.unitPrice) {
+		return nil
+	}
+
+	previousPrice := p.unitPrice
+	p.unitPrice = price
+
+	p.addDomainEvent(ProductPriceChanged{
+		ProductId:        p.id,
+		PreviousPrice:    previousPrice,
+		ProductUnitPrice: price,
+	})
+
+	return nil
+}
+
+func (p *Product) ChangeTaxCategory(category TaxCategory) error {
+	if category == "" {
+		return errors.New("invalid tax category")
+	}
+
+	if category == p.taxCategory {
+		return nil
+	}
+
+	previousCategory := p.taxCategory
+	p.taxCategory = category
+
+	p.addDomainEvent(ProductTaxCategoryChanged{
+		ProductId:           p.id,
+		PreviousTaxCategory: previousCategory,
+		TaxCategory:         category,
+	})
+
+	return nil
+}
+
+func (p *Product) ChangeShippingDetails(weight Weight, dimensions Dimensions) error {
+	if weight < 0 {
+		return errors.New("invalid weight")
+	}
+
+	if weight == p.weight && dimensions == p.dimensions {
+		return nil
+	}
+
+	p.weight = weight
+	p.dimensions = dimensions
+
+	p.addDomainEvent(ProductShippingDetailsChanged{
+		ProductId:  p.id,
+		Weight:     weight,
+		Dimensions: dimensions,
+	})
+
+	return nil
+}
+
+func (p *Product) Archive() {
+	if p.archived {
+		return
+	}
+
+	p.archived = true
+
+	p.addDomainEvent(ProductArchived{
+		ProductId: p.id,
+	})
+}
+
+func (p *Product) Restore() {
+	if !p.archived {
+		return
+	}
+
+	p.archived = false
+
+	p.addDomainEvent(ProductRestored{
+		ProductId: p.id,
+	})
+}
+
+func (p Product) IsArchived() bool {
+	return p.archived
+}
+
+func (p Product) GetSKU() SKU {
+	return p.sku
+}
+
 func (p Product) GetName() string {
 	return p.name
 }
 
-func (p Product) GetPrice() float64 {
+func (p Product) GetPrice() Money {
 	return p.unitPrice
 }
 
+func (p Product) GetTaxCategory() TaxCategory {
+	return p.taxCategory
+}
+
+func (p Product) GetWeight() Weight {
+	return p.weight
+}
+
+func (p Product) GetDimensions() Dimensions {
+	return p.dimensions
+}
+
 func (p *Product) EqualsTo(entity Entity[ProductId]) bool {
 	return reflect.TypeOf(p) == reflect.TypeOf(entity) &&
 		p.GetID() == entity.GetID()
 }
+
+func (p *Product) Clone() *Product {
+	clone := *p
+	clone.baseEntity = p.baseEntity.clone()
+
+	return &clone
+}
+
+func isValidProductName(name string) bool {
+	return len(name) >= 10
+}
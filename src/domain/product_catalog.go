@@ -0,0 +1,28 @@
+package domain
+
+import "errors"
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ProductSortField string
+
+const (
+	SortProductsByName  ProductSortField = "name"
+	SortProductsByPrice ProductSortField = "price"
+)
+
+type ProductListQuery struct {
+	NameContains    string
+	MinPrice        *Money
+	MaxPrice        *Money
+	SortBy          ProductSortField
+	Descending      bool
+	Cursor          string
+	Limit           int
+	IncludeArchived bool
+}
+
+type ProductPage struct {
+	Products   []*Product
+	NextCursor string
+}
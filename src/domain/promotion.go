@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"sort"
+)
+
+type Promotion interface {
+	Evaluate(cart *Cart, balance Money) []Adjustment
+}
+
+type PromotionCatalog interface {
+	FindCoupon(code CouponCode) (Coupon, error)
+	GetAutomaticPromotions() []Promotion
+}
+
+type Adjustment struct {
+	promotion string
+	productId ProductId
+	amount    Money
+}
+
+func NewLineAdjustment(promotion string, productId ProductId, amount Money) Adjustment {
+	return Adjustment{
+		promotion: promotion,
+		productId: productId,
+		amount:    amount,
+	}
+}
+
+func NewCartAdjustment(promotion string, amount Money) Adjustment {
+	return Adjustment{
+		promotion: promotion,
+		amount:    amount,
+	}
+}
+
+func (a Adjustment) GetPromotion() string {
+	return a.promotion
+}
+
+func (a Adjustment) GetProductId() ProductId {
+	return a.productId
+}
+
+func (a Adjustment) GetAmount() Money {
+	return a.amount
+}
+
+func (a Adjustment) IsLineAdjustment() bool {
+	return a.productId != ProductId{}
+}
+
+func (a Adjustment) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(a, other)
+}
+
+func filterAdjustments(adjustments []Adjustment, lineAdjustments bool) []Adjustment {
+	var filtered []Adjustment
+	for _, adjustment := range adjustments {
+		if adjustment.IsLineAdjustment() == lineAdjustments {
+			filtered = append(filtered, adjustment)
+		}
+	}
+
+	return filtered
+}
+
+type percentageOff struct {
+	name    string
+	percent int64
+}
+
+func NewPercentageOff(name string, percent int) (Promotion, error) {
+	if name == "" || percent < 1 || percent > 100 {
+		return nil, errors.New("invalid percentage off promotion")
+	}
+
+	return percentageOff{name: name, percent: int64(percent)}, nil
+}
+
+func (p percentageOff) Evaluate(cart *Cart, balance Money) []Adjustment {
+	amount, err := balance.MultiplyByRate(big.NewRat(p.percent, 100))
+	if err != nil {
+		return nil
+	}
+
+	return []Adjustment{NewCartAdjustment(p.name, amount)}
+}
+
+type fixedAmountOff struct {
+	name   string
+	amount Money
+}
+
+func NewFixedAmountOff(name string, amount Money) (Promotion, error) {
+	if name == "" || !amount.IsPositive() {
+		return nil, errors.New("invalid fixed amount off promotion")
+	}
+
+	return fixedAmountOff{name: name, amount: amount}, nil
+}
+
+func (p fixedAmountOff) Evaluate(cart *Cart, balance Money) []Adjustment {
+	if p.amount.Currency() != cart.GetCurrency() {
+		return nil
+	}
+
+	return []Adjustment{NewCartAdjustment(p.name, p.amount)}
+}
+
+type buyXGetY struct {
+	name      string
+	productId ProductId
+	buy       int
+	free      int
+}
+
+func NewBuyXGetY(name string, productId ProductId, buy int, free int) (Promotion, error) {
+	if name == "" || productId == (ProductId{}) || buy < 1 || free < 1 {
+		return nil, errors.New("invalid buy x get y promotion")
+	}
+
+	return buyXGetY{name: name, productId: productId, buy: buy, free: free}, nil
+}
+
+func (p buyXGetY) Evaluate(cart *Cart, balance Money) []Adjustment {
+	cartItem, found := cart.items[p.productId]
+	if !found {
+		return nil
+	}
+
+	freeUnits := cartItem.quantity / (p.buy + p.free) * p.free
+	if freeUnits == 0 {
+		return nil
+	}
+
+	amount, err := cartItem.totalFor(freeUnits, cart.currency)
+	if err != nil {
+		return nil
+	}
+
+	return []Adjustment{NewLineAdjustment(p.name, p.productId, amount)}
+}
+
+type QuantityTier struct {
+	MinQuantity int
+	Percent     int
+}
+
+type tieredQuantityDiscount struct {
+	name      string
+	productId ProductId
+	tiers     []QuantityTier
+}
+
+func NewTieredQuantityDiscount(name string, productId ProductId, tiers ...QuantityTier) (Promotion, error) {
+	if name == "" || productId == (ProductId{}) || len(tiers) == 0 {
+		return nil, errors.New("invalid tiered quantity discount")
+	}
+
+	sortedTiers := append([]QuantityTier{}, tiers...)
+	sort.Slice(sortedTiers, func(i, j int) bool {
+		return sortedTiers[i].MinQuantity < sortedTiers[j].MinQuantity
+	})
+
+	for position, tier := range sortedTiers {
+		if tier.MinQuantity < 1 || tier.Percent < 1 || tier.Percent > 100 {
+			return nil, errors.New("invalid tiered quantity discount")
+		}
+		if position > 0 && tier.MinQuantity == sortedTiers[position-1].MinQuantity {
+			return nil, errors.New("invalid tiered quantity discount")
+		}
+	}
+
+	return tieredQuantityDiscount{name: name, productId: productId, tiers: sortedTiers}, nil
+}
+
+func (p tieredQuantityDiscount) Evaluate(cart *Cart, balance Money) []Adjustment {
+	cartItem, found := cart.items[p.productId]
+	if !found {
+		return nil
+	}
+
+	var percent int64
+	for _, tier := range p.tiers {
+		if cartItem.quantity >= tier.MinQuantity {
+			percent = int64(tier.Percent)
+		}
+	}
+
+	if percent == 0 {
+		return nil
+	}
+
+	lineTotal, err := cartItem.getTotalIn(cart.currency)
+	if err != nil {
+		return nil
+	}
+
+	amount, err := lineTotal.MultiplyByRate(big.NewRat(percent, 100))
+	if err != nil {
+		return nil
+	}
+
+	return []Adjustment{NewLineAdjustment(p.name, p.productId, amount)}
+}
+
+type minimumSpend struct {
+	minimum   Money
+	promotion Promotion
+}
+
+func NewMinimumSpend(minimum Money, promotion Promotion) (Promotion, error) {
+	if !minimum.IsPositive() || promotion == nil {
+		return nil, errors.New("invalid minimum spend rule")
+	}
+
+	return minimumSpend{minimum: minimum, promotion: promotion}, nil
+}
+
+func (p minimumSpend) Evaluate(cart *Cart, balance Money) []Adjustment {
+	comparison, err := cart.GetSubtotal().Compare(p.minimum)
+	if err != nil || comparison < 0 {
+		return nil
+	}
+
+	return p.promotion.Evaluate(cart, balance)
+}
@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type QuoteId uuid.UUID
+
+func (id QuoteId) String() string {
+	return uuid.UUID(id).String()
+}
+
+func (id QuoteId) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+func (id *QuoteId) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(id).UnmarshalText(data)
+}
+
+type QuoteStatus string
+
+const (
+	QuoteStatusPending  QuoteStatus = "pending"
+	QuoteStatusAccepted QuoteStatus = "accepted"
+	QuoteStatusDeclined QuoteStatus = "declined"
+	QuoteStatusExpired  QuoteStatus = "expired"
+)
+
+var ErrQuoteExpired = errors.New("quote has expired")
+
+var ErrQuoteNotPending = errors.New("quote is no longer pending")
+
+var ErrQuoteNotAccepted = errors.New("quote has not been accepted")
+
+var ErrQuoteCartMismatch = errors.New("quote was issued for another cart")
+
+var ErrQuoteCartChanged = errors.New("cart changed since the quote was issued")
+
+type Quote struct {
+	*baseEntity[QuoteId]
+	number      string
+	cartId      CartId
+	customerId  CustomerId
+	currency    Currency
+	lines       []LineSnapshot
+	subtotal    Money
+	adjustments []Adjustment
+	shipping    ShippingQuote
+	taxes       []LineTax
+	total       Money
+	issuedAt    time.Time
+	expiresAt   time.Time
+	status      QuoteStatus
+}
+
+func IssueQuote(cart *Cart, number string, issuedAt time.Time, validFor time.Duration) (*Quote, error) {
+	if cart == nil {
+		return nil, errors.New("no cart provided")
+	}
+
+	if number == "" {
+		return nil, errors.New("invalid quote number")
+	}
+
+	if validFor <= 0 {
+		return nil, errors.New("invalid quote validity")
+	}
+
+	if err := cart.ensureActive(); err != nil {
+		return nil, err
+	}
+
+	if cart.Size() == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	quote := &Quote{
+		baseEntity: &baseEntity[QuoteId]{
+			id: QuoteId(uuid.New()),
+		},
+		number:      number,
+		cartId:      cart.GetID(),
+		customerId:  cart.GetCustomerID(),
+		currency:    cart.GetCurrency(),
+		lines:       snapshotCartLines(cart),
+		subtotal:    cart.GetSubtotal(),
+		adjustments: cloneSlice(cart.adjustments),
+		shipping:    cart.GetShipping(),
+		taxes:       cloneSlice(cart.taxes),
+		total:       cart.GetTotal(),
+		issuedAt:    issuedAt,
+		expiresAt:   issuedAt.Add(validFor),
+		status:      QuoteStatusPending,
+	}
+
+	quote.addDomainEvent(QuoteIssued{
+		QuoteId:    quote.id,
+		Number:     quote.number,
+		CartId:     quote.cartId,
+		CustomerId: quote.customerId,
+		Total:      quote.total,
+		ExpiresAt:  quote.expiresAt,
+	})
+
+	return quote, nil
+}
+
+func (q *Quote) EqualsTo(entity Entity[QuoteId]) bool {
+	return reflect.TypeOf(q) == reflect.TypeOf(entity) && q.GetID() == entity.GetID()
+}
+
+func (q *Quote) Clone() *Quote {
+	clone := *q
+	clone.baseEntity = q.baseEntity.clone()
+	clone.lines = cloneSlice(q.lines)
+	clone.adjustments = cloneSlice(q.adjustments)
+	clone.taxes = cloneSlice(q.taxes)
+
+	return &clone
+}
+
+func (q *Quote) Accept(clock Clock) error {
+	if err := q.ensurePending(clock); err != nil {
+		return err
+	}
+
+	q.status = QuoteStatusAccepted
+
+	q.addDomainEvent(QuoteAccepted{
+		QuoteId: q.id,
+		Number:  q.number,
+		Total:   q.total,
+	})
+
+	return nil
+}
+
+func (q *Quote) Decline(clock Clock) error {
+	if err := q.ensurePending(clock); err != nil {
+		return err
+	}
+
+	q.status = QuoteStatusDeclined
+
+	q.addDomainEvent(QuoteDeclined{
+		QuoteId: q.id,
+		Number:  q.number,
+	})
+
+	return nil
+}
+
+func (q *Quote) GetCurrentStatus(clock Clock) QuoteStatus {
+	if q.status == QuoteStatusPending && !clock.Now().Before(q.expiresAt) {
+		return QuoteStatusExpired
+	}
+
+	return q.status
+}
+
+func (q *Quote) Expire(clock Clock) bool {
+	if q.status != QuoteStatusPending || clock.Now().Before(q.expiresAt) {
+		return false
+	}
+
+	q.status = QuoteStatusExpired
+
+	q.addDomainEvent(QuoteExpired{
+		QuoteId: q.id,
+		Number:  q.number,
+	})
+
+	return true
+}
+
+func (q *Quote) ensurePending(clock Clock) error {
+	if q.Expire(clock) || q.status == QuoteStatusExpired {
+		return ErrQuoteExpired
+	}
+
+	if q.status != QuoteStatusPending {
+		return ErrQuoteNotPending
+	}
+
+	return nil
+}
+
+func (q *Quote) ensureOrderable(cart *Cart, placedOn time.Time) error {
+	if q.cartId != cart.GetID() {
+		return ErrQuoteCartMismatch
+	}
+
+	if q.status != QuoteStatusAccepted {
+		return ErrQuoteNotAccepted
+	}
+
+	if !placedOn.Before(q.expiresAt) {
+		return ErrQuoteExpired
+	}
+
+	if q.currency != cart.GetCurrency() || !sameQuantities(q.lines, snapshotCartLines(cart)) {
+		return ErrQuoteCartChanged
+	}
+
+	return nil
+}
+
+func (q Quote) GetNumber() string {
+	return q.number
+}
+
+func (q Quote) GetCartID() CartId {
+	return q.cartId
+}
+
+func (q Quote) GetCustomerID() CustomerId {
+	return q.customerId
+}
+
+func (q Quote) GetCurrency() Currency {
+	return q.currency
+}
+
+func (q Quote) GetLines() []LineSnapshot {
+	return append([]LineSnapshot{}, q.lines...)
+}
+
+func (q Quote) GetSubtotal() Money {
+	return q.subtotal
+}
+
+func (q Quote) GetLineAdjustments() []Adjustment {
+	return filterAdjustments(q.adjustments, true)
+}
+
+func (q Quote) GetCartAdjustments() []Adjustment {
+	return filterAdjustments(q.adjustments, false)
+}
+
+func (q Quote) HasShipping() bool {
+	return q.shipping.method != ""
+}
+
+func (q Quote) GetShipping() ShippingQuote {
+	return q.shipping
+}
+
+func (q Quote) GetTax() Money {
+	return sumLineTaxes(q.currency, q.taxes)
+}
+
+func (q Quote) GetLineTaxes() []LineTax {
+	return append([]LineTax{}, q.taxes...)
+}
+
+func (q Quote) IsTaxed() bool {
+	return q.taxes != nil
+}
+
+func (q Quote) GetTotal() Money {
+	return q.total
+}
+
+func (q Quote) GetIssuedAt() time.Time {
+	return q.issuedAt
+}
+
+func (q Quote) GetExpiresAt() time.Time {
+	return q.expiresAt
+}
+
+func (q Quote) GetStatus() QuoteStatus {
+	return q.status
+}
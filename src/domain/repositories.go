@@ -1,7 +1,34 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
+type UniqueConstraintError struct {
+	Field string
+	Value string
+}
+
+func (e UniqueConstraintError) Error() string {
+	return fmt.Sprintf("%s %q is already in use", e.Field, e.Value)
+}
+
+type ConcurrencyConflictError struct {
+	Id              string
+	ExpectedVersion int
+	ActualVersion   int
+}
+
+func (e ConcurrencyConflictError) Error() string {
+	return fmt.Sprintf("%s was modified concurrently: expected version %d, found %d", e.Id, e.ExpectedVersion, e.ActualVersion)
+}
+
 type ProductRepository interface {
 	Repository[ProductId, *Product]
+	FindBySKU(sku SKU) (*Product, error)
+	FindByName(name string) (*Product, error)
+	List(query ProductListQuery) (ProductPage, error)
 }
 
 type CustomerRepository interface {
@@ -10,5 +37,21 @@ type CustomerRepository interface {
 
 type CartRepository interface {
 	Repository[CartId, *Cart]
-	GetCustomerCarts(customerId CustomerId) []*Cart
+	GetCustomerCarts(customerId CustomerId) ([]*Cart, error)
+	FindIdleCarts(lastActivityBefore time.Time) ([]*Cart, error)
+}
+
+type OrderRepository interface {
+	Repository[OrderId, *Order]
+}
+
+type QuoteRepository interface {
+	Repository[QuoteId, *Quote]
+	NextQuoteNumber(issuedAt time.Time) (string, error)
+	FindExpiredQuotes(now time.Time) ([]*Quote, error)
+}
+
+type StockRepository interface {
+	Repository[ProductId, *StockItem]
+	GetCartReservations(cartId CartId) ([]*StockItem, error)
 }
@@ -0,0 +1,283 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var ErrShippingUnavailable = errors.New("shipping method is not available for this cart")
+
+type Weight int64
+
+func NewWeight(grams int) (Weight, error) {
+	if grams < 0 {
+		return 0, errors.New("invalid weight")
+	}
+
+	return Weight(grams), nil
+}
+
+func (w Weight) Grams() int64 {
+	return int64(w)
+}
+
+type Dimensions struct {
+	length int
+	width  int
+	height int
+}
+
+func NewDimensions(length int, width int, height int) (Dimensions, error) {
+	if length < 1 || width < 1 || height < 1 {
+		return Dimensions{}, errors.New("invalid dimensions")
+	}
+
+	return Dimensions{
+		length: length,
+		width:  width,
+		height: height,
+	}, nil
+}
+
+func (d Dimensions) GetLength() int {
+	return d.length
+}
+
+func (d Dimensions) GetWidth() int {
+	return d.width
+}
+
+func (d Dimensions) GetHeight() int {
+	return d.height
+}
+
+func (d Dimensions) IsZero() bool {
+	return d == Dimensions{}
+}
+
+func (d Dimensions) volume() int64 {
+	return int64(d.length) * int64(d.width) * int64(d.height)
+}
+
+func (d Dimensions) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(d, other)
+}
+
+type dimensionsJSON struct {
+	LengthMm int `json:"length_mm"`
+	WidthMm  int `json:"width_mm"`
+	HeightMm int `json:"height_mm"`
+}
+
+func (d Dimensions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dimensionsJSON{
+		LengthMm: d.length,
+		WidthMm:  d.width,
+		HeightMm: d.height,
+	})
+}
+
+func (d *Dimensions) UnmarshalJSON(data []byte) error {
+	var decoded dimensionsJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded == (dimensionsJSON{}) {
+		*d = Dimensions{}
+		return nil
+	}
+
+	dimensions, err := NewDimensions(decoded.LengthMm, decoded.WidthMm, decoded.HeightMm)
+	if err != nil {
+		return err
+	}
+
+	*d = dimensions
+	return nil
+}
+
+type ShippingMethodCode string
+
+var shippingMethodCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+func NewShippingMethodCode(code string) (ShippingMethodCode, error) {
+	normalizedCode := strings.ToLower(strings.TrimSpace(code))
+	if !shippingMethodCodePattern.MatchString(normalizedCode) {
+		return "", errors.New("invalid shipping method")
+	}
+
+	return ShippingMethodCode(normalizedCode), nil
+}
+
+type ShippingMethod interface {
+	GetCode() ShippingMethodCode
+	GetName() string
+	Quote(cart *Cart) (Money, error)
+}
+
+type ShippingCatalog interface {
+	FindShippingMethod(code ShippingMethodCode) (ShippingMethod, error)
+	GetShippingMethods() []ShippingMethod
+}
+
+type ShippingQuote struct {
+	method ShippingMethodCode
+	name   string
+	cost   Money
+}
+
+func NewShippingQuote(method ShippingMethodCode, name string, cost Money) ShippingQuote {
+	return ShippingQuote{
+		method: method,
+		name:   name,
+		cost:   cost,
+	}
+}
+
+func (q ShippingQuote) GetMethod() ShippingMethodCode {
+	return q.method
+}
+
+func (q ShippingQuote) GetName() string {
+	return q.name
+}
+
+func (q ShippingQuote) GetCost() Money {
+	return q.cost
+}
+
+func (q ShippingQuote) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(q, other)
+}
+
+type flatRateShipping struct {
+	code ShippingMethodCode
+	name string
+	cost Money
+}
+
+func NewFlatRateShipping(code ShippingMethodCode, name string, cost Money) (ShippingMethod, error) {
+	if code == "" || name == "" || cost.IsNegative() || cost.Currency() == "" {
+		return nil, errors.New("invalid flat rate shipping")
+	}
+
+	return flatRateShipping{code: code, name: name, cost: cost}, nil
+}
+
+func (m flatRateShipping) GetCode() ShippingMethodCode {
+	return m.code
+}
+
+func (m flatRateShipping) GetName() string {
+	return m.name
+}
+
+func (m flatRateShipping) Quote(cart *Cart) (Money, error) {
+	if m.cost.Currency() != cart.GetCurrency() {
+		return Money{}, ErrShippingUnavailable
+	}
+
+	return m.cost, nil
+}
+
+type weightBasedShipping struct {
+	code              ShippingMethodCode
+	name              string
+	base              Money
+	perKilogram       Money
+	volumetricDivisor int64
+}
+
+func NewWeightBasedShipping(code ShippingMethodCode, name string, base Money, perKilogram Money, volumetricDivisor int) (ShippingMethod, error) {
+	if code == "" || name == "" || base.IsNegative() || !perKilogram.IsPositive() || base.Currency() != perKilogram.Currency() || volumetricDivisor < 0 {
+		return nil, errors.New("invalid weight based shipping")
+	}
+
+	return weightBasedShipping{
+		code:              code,
+		name:              name,
+		base:              base,
+		perKilogram:       perKilogram,
+		volumetricDivisor: int64(volumetricDivisor),
+	}, nil
+}
+
+func (m weightBasedShipping) GetCode() ShippingMethodCode {
+	return m.code
+}
+
+func (m weightBasedShipping) GetName() string {
+	return m.name
+}
+
+func (m weightBasedShipping) Quote(cart *Cart) (Money, error) {
+	if m.base.Currency() != cart.GetCurrency() {
+		return Money{}, ErrShippingUnavailable
+	}
+
+	var grams int64
+	for _, cartItem := range cart.items {
+		grams += m.chargeableGrams(cartItem) * int64(cartItem.quantity)
+	}
+
+	kilograms := (grams + 999) / 1000
+	weightCost, err := m.perKilogram.Multiply(kilograms)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return m.base.Add(weightCost)
+}
+
+func (m weightBasedShipping) chargeableGrams(cartItem item) int64 {
+	grams := cartItem.weight.Grams()
+	if m.volumetricDivisor == 0 || cartItem.dimensions.IsZero() {
+		return grams
+	}
+
+	volumetricGrams := (cartItem.dimensions.volume() + m.volumetricDivisor - 1) / m.volumetricDivisor
+	if volumetricGrams > grams {
+		return volumetricGrams
+	}
+
+	return grams
+}
+
+type freeShippingAbove struct {
+	threshold Money
+	method    ShippingMethod
+}
+
+func NewFreeShippingAbove(threshold Money, method ShippingMethod) (ShippingMethod, error) {
+	if !threshold.IsPositive() || method == nil {
+		return nil, errors.New("invalid free shipping threshold")
+	}
+
+	return freeShippingAbove{threshold: threshold, method: method}, nil
+}
+
+func (m freeShippingAbove) GetCode() ShippingMethodCode {
+	return m.method.GetCode()
+}
+
+func (m freeShippingAbove) GetName() string {
+	return m.method.GetName()
+}
+
+func (m freeShippingAbove) Quote(cart *Cart) (Money, error) {
+	cost, err := m.method.Quote(cart)
+	if err != nil {
+		return Money{}, err
+	}
+
+	comparison, err := cart.getMerchandiseTotal().Compare(m.threshold)
+	if err != nil || comparison < 0 {
+		return cost, nil
+	}
+
+	return ZeroMoney(cost.Currency()), nil
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+type SKU string
+
+var skuPattern = regexp.MustCompile(`^[A-Z0-9]+(-[A-Z0-9]+)*$`)
+
+func NewSKU(code string) (SKU, error) {
+	normalizedCode := strings.ToUpper(strings.TrimSpace(code))
+	if len(normalizedCode) < 3 || len(normalizedCode) > 32 || !skuPattern.MatchString(normalizedCode) {
+		return "", errors.New("invalid sku")
+	}
+
+	return SKU(normalizedCode), nil
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+)
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+var ErrOnHandBelowReserved = errors.New("on hand quantity is below the reserved quantity")
+
+type StockItem struct {
+	*baseEntity[ProductId]
+	onHand       int
+	reservations map[CartId]int
+}
+
+func NewStockItem(productId ProductId, onHand int) (*StockItem, error) {
+	if onHand < 0 {
+		return nil, errors.New("invalid on hand quantity")
+	}
+
+	stockItem := &StockItem{
+		baseEntity: &baseEntity[ProductId]{
+			id: productId,
+		},
+		onHand:       onHand,
+		reservations: map[CartId]int{},
+	}
+
+	stockItem.addDomainEvent(StockRestocked{
+		ProductId: productId,
+		OnHand:    onHand,
+	})
+
+	return stockItem, nil
+}
+
+func (s *StockItem) EqualsTo(entity Entity[ProductId]) bool {
+	return reflect.TypeOf(s) == reflect.TypeOf(entity) && s.GetID() == entity.GetID()
+}
+
+func (s *StockItem) Clone() *StockItem {
+	clone := *s
+	clone.baseEntity = s.baseEntity.clone()
+	if s.reservations != nil {
+		clone.reservations = make(map[CartId]int, len(s.reservations))
+		for cartId, quantity := range s.reservations {
+			clone.reservations[cartId] = quantity
+		}
+	}
+
+	return &clone
+}
+
+func (s *StockItem) Restock(onHand int) error {
+	if onHand < 0 {
+		return errors.New("invalid on hand quantity")
+	}
+
+	if onHand < s.GetReserved() {
+		return ErrOnHandBelowReserved
+	}
+
+	if onHand == s.onHand {
+		return nil
+	}
+
+	s.onHand = onHand
+
+	s.addDomainEvent(StockRestocked{
+		ProductId: s.id,
+		OnHand:    onHand,
+	})
+
+	return nil
+}
+
+func (s *StockItem) Reserve(cartId CartId, quantity int) error {
+	if quantity < 1 {
+		return errors.New("invalid quantity")
+	}
+
+	if quantity > s.GetAvailable() {
+		return ErrInsufficientStock
+	}
+
+	s.reservations[cartId] += quantity
+
+	s.addDomainEvent(StockReserved{
+		ProductId: s.id,
+		CartId:    cartId,
+		Quantity:  quantity,
+	})
+
+	return nil
+}
+
+func (s *StockItem) Release(cartId CartId, quantity int) {
+	reserved := s.reservations[cartId]
+	if quantity > reserved {
+		quantity = reserved
+	}
+
+	if quantity < 1 {
+		return
+	}
+
+	if quantity == reserved {
+		delete(s.reservations, cartId)
+	} else {
+		s.reservations[cartId] = reserved - quantity
+	}
+
+	s.addDomainEvent(StockReleased{
+		ProductId: s.id,
+		CartId:    cartId,
+		Quantity:  quantity,
+	})
+}
+
+func (s *StockItem) ReleaseAll(cartId CartId) {
+	s.Release(cartId, s.reservations[cartId])
+}
+
+func (s *StockItem) Commit(cartId CartId) {
+	quantity := s.reservations[cartId]
+	if quantity == 0 {
+		return
+	}
+
+	delete(s.reservations, cartId)
+	s.onHand -= quantity
+
+	s.addDomainEvent(StockCommitted{
+		ProductId: s.id,
+		CartId:    cartId,
+		Quantity:  quantity,
+	})
+}
+
+func (s StockItem) GetOnHand() int {
+	return s.onHand
+}
+
+func (s StockItem) GetReserved() int {
+	var reserved int
+	for _, quantity := range s.reservations {
+		reserved += quantity
+	}
+
+	return reserved
+}
+
+func (s StockItem) GetReservedFor(cartId CartId) int {
+	return s.reservations[cartId]
+}
+
+func (s StockItem) GetAvailable() int {
+	return s.onHand - s.GetReserved()
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type TaxCategory string
+
+const StandardTaxCategory TaxCategory = "standard"
+
+var taxCategoryPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+func NewTaxCategory(category string) (TaxCategory, error) {
+	normalizedCategory := strings.ToLower(strings.TrimSpace(category))
+	if !taxCategoryPattern.MatchString(normalizedCategory) {
+		return "", errors.New("invalid tax category")
+	}
+
+	return TaxCategory(normalizedCategory), nil
+}
+
+type TaxRegion string
+
+var taxRegionPattern = regexp.MustCompile(`^[A-Z]{2}(-[A-Z0-9]{1,3})?$`)
+
+func NewTaxRegion(region string) (TaxRegion, error) {
+	normalizedRegion := strings.ToUpper(strings.TrimSpace(region))
+	if !taxRegionPattern.MatchString(normalizedRegion) {
+		return "", errors.New("invalid tax region")
+	}
+
+	return TaxRegion(normalizedRegion), nil
+}
+
+type TaxRounding string
+
+const (
+	RoundTaxPerLine  TaxRounding = "per_line"
+	RoundTaxPerTotal TaxRounding = "per_total"
+)
+
+type TaxableLine struct {
+	ProductId ProductId
+	Category  TaxCategory
+	Amount    Money
+}
+
+type TaxCalculator interface {
+	Calculate(region TaxRegion, lines []TaxableLine) []LineTax
+}
+
+type LineTax struct {
+	productId ProductId
+	rate      *big.Rat
+	inclusive bool
+	amount    Money
+}
+
+func NewLineTax(productId ProductId, rate *big.Rat, inclusive bool, amount Money) LineTax {
+	return LineTax{
+		productId: productId,
+		rate:      new(big.Rat).Set(rate),
+		inclusive: inclusive,
+		amount:    amount,
+	}
+}
+
+func (t LineTax) GetProductId() ProductId {
+	return t.productId
+}
+
+func (t LineTax) GetRate() *big.Rat {
+	return new(big.Rat).Set(t.rate)
+}
+
+func (t LineTax) IsInclusive() bool {
+	return t.inclusive
+}
+
+func (t LineTax) GetAmount() Money {
+	return t.amount
+}
+
+func (t LineTax) EqualsTo(other ValueObject) bool {
+	return reflect.DeepEqual(t, other)
+}
+
+type TaxRule struct {
+	Region    TaxRegion
+	Category  TaxCategory
+	Rate      *big.Rat
+	Inclusive bool
+}
+
+type taxRuleKey struct {
+	region   TaxRegion
+	category TaxCategory
+}
+
+type ruleTableTaxCalculator struct {
+	rounding TaxRounding
+	rules    map[taxRuleKey]TaxRule
+}
+
+func NewRuleTableTaxCalculator(rounding TaxRounding, rules ...TaxRule) (TaxCalculator, error) {
+	if rounding != RoundTaxPerLine && rounding != RoundTaxPerTotal {
+		return nil, errors.New("invalid tax rounding")
+	}
+
+	calculator := ruleTableTaxCalculator{
+		rounding: rounding,
+		rules:    map[taxRuleKey]TaxRule{},
+	}
+
+	for _, rule := range rules {
+		if rule.Region == "" || rule.Category == "" || rule.Rate == nil || rule.Rate.Sign() < 0 || rule.Rate.Cmp(big.NewRat(1, 1)) > 0 {
+			return nil, errors.New("invalid tax rule")
+		}
+
+		key := taxRuleKey{region: rule.Region, category: rule.Category}
+		if _, found := calculator.rules[key]; found {
+			return nil, errors.New("duplicated tax rule for " + string(rule.Region) + "/" + string(rule.Category))
+		}
+
+		rule.Rate = new(big.Rat).Set(rule.Rate)
+		calculator.rules[key] = rule
+	}
+
+	return calculator, nil
+}
+
+func (c ruleTableTaxCalculator) Calculate(region TaxRegion, lines []TaxableLine) []LineTax {
+	appliedRules := make([]TaxRule, len(lines))
+	exactTaxes := make([]*big.Rat, len(lines))
+	for position, line := range lines {
+		rule, found := c.rules[taxRuleKey{region: region, category: line.Category}]
+		if !found {
+			rule = TaxRule{Rate: new(big.Rat)}
+		}
+
+		appliedRules[position] = rule
+		exactTaxes[position] = rule.taxOn(line.Amount)
+	}
+
+	var roundedTaxes []int64
+	if c.rounding == RoundTaxPerTotal {
+		roundedTaxes = roundTotalAcrossLines(exactTaxes)
+	} else {
+		for _, exactTax := range exactTaxes {
+			roundedTax, _ := roundHalfEven(exactTax)
+			roundedTaxes = append(roundedTaxes, roundedTax)
+		}
+	}
+
+	var taxes []LineTax
+	for position, line := range lines {
+		rule := appliedRules[position]
+		taxes = append(taxes, NewLineTax(line.ProductId, rule.Rate, rule.Inclusive, NewMoney(roundedTaxes[position], line.Amount.Currency())))
+	}
+
+	return taxes
+}
+
+func (r TaxRule) taxOn(amount Money) *big.Rat {
+	tax := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.MinorUnits()), r.Rate)
+	if r.Inclusive {
+		tax.Quo(tax, new(big.Rat).Add(big.NewRat(1, 1), r.Rate))
+	}
+
+	return tax
+}
+
+func roundTotalAcrossLines(exactTaxes []*big.Rat) []int64 {
+	exactTotal := new(big.Rat)
+	for _, exactTax := range exactTaxes {
+		exactTotal.Add(exactTotal, exactTax)
+	}
+	remainder, _ := roundHalfEven(exactTotal)
+
+	roundedTaxes := make([]int64, len(exactTaxes))
+	fractions := make([]*big.Rat, len(exactTaxes))
+	for position, exactTax := range exactTaxes {
+		floor := new(big.Int).Div(exactTax.Num(), exactTax.Denom())
+		roundedTaxes[position] = floor.Int64()
+		fractions[position] = new(big.Rat).Sub(exactTax, new(big.Rat).SetInt(floor))
+		remainder -= roundedTaxes[position]
+	}
+
+	positions := make([]int, len(exactTaxes))
+	for position := range positions {
+		positions[position] = position
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return fractions[positions[i]].Cmp(fractions[positions[j]]) > 0
+	})
+
+	for _, position := range positions {
+		if remainder <= 0 {
+			break
+		}
+		roundedTaxes[position]++
+		remainder--
+	}
+
+	return roundedTaxes
+}
+
+func sumLineTaxes(currency Currency, taxes []LineTax) Money {
+	tax := ZeroMoney(currency)
+	for _, lineTax := range taxes {
+		tax, _ = tax.Add(lineTax.amount)
+	}
+
+	return tax
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/scheduler"
+)
+
+const (
+	defaultCartSweepInterval  = time.Minute
+	defaultCartAbandonAfter   = 24 * time.Hour
+	defaultCartExpireAfter    = 7 * 24 * time.Hour
+	defaultQuoteSweepInterval = time.Minute
+)
+
+func StartBackgroundJobs() {
+	outboxRelay.Start()
+	jobScheduler.Start()
+}
+
+func StopBackgroundJobs() {
+	jobScheduler.Stop()
+	outboxRelay.Stop()
+}
+
+func newJobScheduler(cartRepository domain.CartRepository, quoteService *application.QuoteService) (*scheduler.Scheduler, error) {
+	clock := domain.SystemClock()
+	jobScheduler, err := scheduler.NewScheduler(clock, time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := application.CartExpirationPolicy{
+		AbandonAfter: durationFromEnv("CART_ABANDON_AFTER", defaultCartAbandonAfter),
+		ExpireAfter:  durationFromEnv("CART_EXPIRE_AFTER", defaultCartExpireAfter),
+	}
+	cartExpirationService, err := application.NewCartExpirationService(cartRepository, EventDispatcher, clock, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	err = jobScheduler.Every("expire-idle-carts", durationFromEnv("CART_SWEEP_INTERVAL", defaultCartSweepInterval), scheduler.JobFunc(func() error {
+		_, err := cartExpirationService.ExpireIdleCarts()
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	err = jobScheduler.Every("expire-quotes", durationFromEnv("QUOTE_SWEEP_INTERVAL", defaultQuoteSweepInterval), scheduler.JobFunc(func() error {
+		_, err := quoteService.ExpireQuotes()
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return jobScheduler, nil
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", name, err)
+	}
+
+	return duration
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"net/http"
+	"reflect"
 
+	"github.com/bitlogic/go-startup/src/application"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -29,6 +31,9 @@ func NewRequestValidator() *requestValidator {
 	trans := newTranslator()
 	validator := validator.New()
 	en_translations.RegisterDefaultTranslations(validator, trans)
+	registerTranslation(validator, trans, "required_without", "{0} is required when {1} is not set")
+	registerTranslation(validator, trans, "excluded_with", "{0} cannot be set together with {1}")
+	validator.RegisterCustomTypeFunc(amountValue, application.AmountDto(""))
 	return &requestValidator{
 		validator: validator,
 		trans:     trans,
@@ -42,6 +47,28 @@ func newTranslator() ut.Translator {
 	return trans
 }
 
+func registerTranslation(v *validator.Validate, trans ut.Translator, tag string, text string) {
+	v.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		translation, _ := ut.T(tag, fe.Field(), fe.Param())
+		return translation
+	})
+}
+
+func amountValue(field reflect.Value) interface{} {
+	amount, ok := field.Interface().(application.AmountDto)
+	if !ok || amount == "" {
+		return nil
+	}
+
+	if value, ok := amount.Float64(); ok {
+		return value
+	}
+
+	return nil
+}
+
 func (rv *requestValidator) Validate(i interface{}) error {
 	if err := rv.validator.Struct(i); err != nil {
 		resp := rv.createValidationErrorResponse(err.(validator.ValidationErrors))
@@ -1,30 +1,280 @@
 package config
 
 import (
+	"database/sql"
+	"fmt"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
 
 	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/exchangerates"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/infrastructure/promotions"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/infrastructure/scheduler"
+	"github.com/bitlogic/go-startup/src/infrastructure/shipping"
+	"github.com/bitlogic/go-startup/src/infrastructure/taxes"
 	"github.com/labstack/echo/v4"
 )
 
 var productController *controllers.ProductController
 var customerController *controllers.CustomerController
 var cartController *controllers.CartController
+var orderController *controllers.OrderController
+var quoteController *controllers.QuoteController
+var stockController *controllers.StockController
+
+var EventDispatcher domain.EventDispatcher
+
+var outboxRelay *outbox.Relay
+
+var jobScheduler *scheduler.Scheduler
 
 func init() {
-	productRepository := repositories.NewInMemoryProductRepository()
-	productService, _ := application.NewProductService(productRepository)
+	EventDispatcher = events.NewSynchronousEventDispatcher()
+
+	db, err := newDatabase()
+	if err != nil {
+		log.Fatalf("failed to open database: %v", err)
+	}
+
+	outboxStore, err := newOutboxStore(db)
+	if err != nil {
+		log.Fatalf("failed to open outbox: %v", err)
+	}
+	outboxRelay, _ = outbox.NewRelay(outboxStore, outbox.NewLogPublisher(nil), outbox.RelayConfig{})
+
+	repos, err := newRepositories(db, repositories.WithOutbox(outboxStore))
+	if err != nil {
+		log.Fatalf("failed to open repositories: %v", err)
+	}
+	productRepository, customerRepository, cartRepository := repos.products, repos.customers, repos.carts
+
+	productService, _ := application.NewProductService(productRepository, EventDispatcher)
 	productController, _ = controllers.NewProductController(productService)
 
-	customerRepository := repositories.NewInMemoryCustomerRepository()
-	customerService, _ := application.NewCustomerService(customerRepository)
+	customerService, _ := application.NewCustomerService(customerRepository, EventDispatcher)
 	customerController, _ = controllers.NewCustomerController(customerService)
 
-	cartRepository := repositories.NewInMemoryCartRepository()
-	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository)
+	stockRepository := repos.stock
+	stockService, _ := application.NewStockService(productRepository, stockRepository, EventDispatcher)
+	stockController, _ = controllers.NewStockController(stockService)
+	domain.RegisterEventHandler(EventDispatcher, stockService.CommitCartReservations)
+	domain.RegisterEventHandler(EventDispatcher, stockService.ReleaseCartReservations)
+	domain.RegisterEventHandler(EventDispatcher, stockService.ReleaseAbandonedCartReservations)
+
+	exchangeRates, err := newExchangeRateProvider()
+	if err != nil {
+		log.Fatalf("failed to load exchange rates: %v", err)
+	}
+	promotionCatalog, err := newPromotionCatalog()
+	if err != nil {
+		log.Fatalf("failed to load promotions: %v", err)
+	}
+	shippingCatalog, err := newShippingCatalog()
+	if err != nil {
+		log.Fatalf("failed to load shipping methods: %v", err)
+	}
+	pricingPolicy := cartPricingPolicy()
+	cartOptions := []application.CartServiceOption{application.WithActiveCartPolicy(activeCartPolicy()), application.WithCartPricingPolicy(pricingPolicy), application.WithStockReservations(stockRepository), application.WithPromotions(promotionCatalog), application.WithShipping(shippingCatalog)}
+	if path := os.Getenv("TAX_RULES_FILE"); path != "" {
+		taxCalculator, err := taxes.NewFileTaxCalculator(path)
+		if err != nil {
+			log.Fatalf("failed to load tax rules: %v", err)
+		}
+		cartOptions = append(cartOptions, application.WithTaxes(taxCalculator))
+	}
+	if retries := os.Getenv("CART_CONFLICT_RETRIES"); retries != "" {
+		conflictRetries, err := strconv.Atoi(retries)
+		if err != nil {
+			log.Fatalf("invalid CART_CONFLICT_RETRIES: %v", err)
+		}
+		cartOptions = append(cartOptions, application.WithConflictRetries(conflictRetries))
+	}
+	cartService, err := application.NewCartService(cartRepository, customerRepository, productRepository, EventDispatcher, exchangeRates, cartOptions...)
+	if err != nil {
+		log.Fatalf("failed to create cart service: %v", err)
+	}
 	cartController, _ = controllers.NewCartController(cartService)
+
+	quoteRepository := repos.quotes
+	quoteService, _ := application.NewQuoteService(cartRepository, quoteRepository, EventDispatcher, domain.SystemClock())
+	quoteController, _ = controllers.NewQuoteController(quoteService)
+
+	orderRepository := repos.orders
+	orderOptions := []application.OrderServiceOption{application.WithQuotes(quoteRepository, domain.SystemClock())}
+	if pricingPolicy == application.RepriceOnCheckout {
+		orderOptions = append(orderOptions, application.WithCheckoutRepricing(productRepository, exchangeRates))
+	}
+	orderService, _ := application.NewOrderService(cartRepository, orderRepository, EventDispatcher, orderOptions...)
+	orderController, _ = controllers.NewOrderController(orderService)
+
+	jobScheduler, err = newJobScheduler(cartRepository, quoteService)
+	if err != nil {
+		log.Fatalf("failed to schedule background jobs: %v", err)
+	}
+}
+
+func newDatabase() (*sql.DB, error) {
+	if os.Getenv("REPOSITORY_BACKEND") != "sql" {
+		return nil, nil
+	}
+
+	driver := os.Getenv("DATABASE_DRIVER")
+	if driver == "" {
+		driver = database.SQLiteDriver
+	}
+
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		dsn = "go-startup.db"
+	}
+
+	return database.Open(driver, dsn)
+}
+
+func newOutboxStore(db *sql.DB) (outbox.Store, error) {
+	if path := os.Getenv("OUTBOX_FILE"); path != "" {
+		return outbox.NewFileStore(path)
+	}
+
+	if db != nil {
+		return outbox.NewSQLStore(db)
+	}
+
+	return outbox.NewInMemoryStore(), nil
+}
+
+type repositorySet struct {
+	products  domain.ProductRepository
+	customers domain.CustomerRepository
+	carts     domain.CartRepository
+	orders    domain.OrderRepository
+	quotes    domain.QuoteRepository
+	stock     domain.StockRepository
+}
+
+func newRepositories(db *sql.DB, options ...repositories.RepositoryOption) (repositorySet, error) {
+	switch backend := os.Getenv("REPOSITORY_BACKEND"); backend {
+	case "", "memory":
+		return repositorySet{
+			products:  repositories.NewInMemoryProductRepository(options...),
+			customers: repositories.NewInMemoryCustomerRepository(options...),
+			carts:     repositories.NewInMemoryCartRepository(options...),
+			orders:    repositories.NewInMemoryOrderRepository(options...),
+			quotes:    repositories.NewInMemoryQuoteRepository(options...),
+			stock:     repositories.NewInMemoryStockRepository(options...),
+		}, nil
+	case "file":
+		directory := os.Getenv("REPOSITORY_DIR")
+		if directory == "" {
+			directory = "data"
+		}
+
+		var set repositorySet
+		var err error
+		if set.products, err = repositories.NewFileProductRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.customers, err = repositories.NewFileCustomerRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.carts, err = repositories.NewFileCartRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.orders, err = repositories.NewFileOrderRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.quotes, err = repositories.NewFileQuoteRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.stock, err = repositories.NewFileStockRepository(directory, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		return set, nil
+	case "sql":
+		var set repositorySet
+		var err error
+		if set.products, err = repositories.NewSQLProductRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.customers, err = repositories.NewSQLCustomerRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.carts, err = repositories.NewSQLCartRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.orders, err = repositories.NewSQLOrderRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.quotes, err = repositories.NewSQLQuoteRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		if set.stock, err = repositories.NewSQLStockRepository(db, options...); err != nil {
+			return repositorySet{}, err
+		}
+
+		return set, nil
+	default:
+		return repositorySet{}, fmt.Errorf("unknown repository backend %q", backend)
+	}
+}
+
+func activeCartPolicy() application.ActiveCartPolicy {
+	if policy := os.Getenv("CART_ACTIVE_POLICY"); policy != "" {
+		return application.ActiveCartPolicy(policy)
+	}
+
+	return application.ReuseActiveCart
+}
+
+func cartPricingPolicy() application.CartPricingPolicy {
+	if policy := os.Getenv("CART_PRICING_POLICY"); policy != "" {
+		return application.CartPricingPolicy(policy)
+	}
+
+	return application.KeepPriceSnapshot
+}
+
+func newExchangeRateProvider() (domain.ExchangeRateProvider, error) {
+	if path := os.Getenv("EXCHANGE_RATES_FILE"); path != "" {
+		return exchangerates.NewFileExchangeRateProvider(path)
+	}
+
+	return exchangerates.NewStaticExchangeRateProvider(nil)
+}
+
+func newPromotionCatalog() (domain.PromotionCatalog, error) {
+	if path := os.Getenv("PROMOTIONS_FILE"); path != "" {
+		return promotions.NewFilePromotionCatalog(path)
+	}
+
+	return promotions.NewStaticPromotionCatalog(nil, nil)
+}
+
+func newShippingCatalog() (domain.ShippingCatalog, error) {
+	if path := os.Getenv("SHIPPING_METHODS_FILE"); path != "" {
+		return shipping.NewFileShippingCatalog(path)
+	}
+
+	return shipping.NewStaticShippingCatalog(nil)
 }
 
 func MapEndpoints(e *echo.Echo) {
@@ -35,7 +285,32 @@ func MapEndpoints(e *echo.Echo) {
 	})
 
 	e.POST("/products", productController.CreateNewProduct)
+	e.GET("/products", productController.ListProducts)
+	e.GET("/products/by-sku/:sku", productController.GetProductBySKU)
+	e.GET("/products/:productId", productController.GetProduct)
+	e.PATCH("/products/:productId", productController.UpdateProduct)
+	e.DELETE("/products/:productId", productController.ArchiveProduct)
+	e.POST("/products/:productId/restore", productController.RestoreProduct)
+	e.PUT("/products/:productId/stock", stockController.UpdateStock)
 	e.POST("/customers", customerController.CreateNewCustomer)
+	e.GET("/customers/:customerId", customerController.GetCustomer)
+	e.PATCH("/customers/:customerId", customerController.UpdateCustomer)
+	e.DELETE("/customers/:customerId", customerController.DeactivateCustomer)
+	e.GET("/customers/:customerId/carts", cartController.GetCustomerCarts)
 	e.POST("/carts", cartController.CreateNewCart)
+	e.GET("/carts/:cartId", cartController.GetCart)
 	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.PUT("/carts/:cartId/items/:productId", cartController.UpdateItemQuantity)
+	e.DELETE("/carts/:cartId/items/:productId", cartController.RemoveItemFromCart)
+	e.DELETE("/carts/:cartId/items", cartController.ClearCart)
+	e.POST("/carts/:cartId/coupons", cartController.ApplyCoupon)
+	e.DELETE("/carts/:cartId/coupons/:code", cartController.RemoveCoupon)
+	e.GET("/carts/:cartId/shipping-options", cartController.GetShippingOptions)
+	e.PUT("/carts/:cartId/shipping", cartController.SelectShipping)
+	e.POST("/carts/:cartId/checkout", orderController.CheckoutCart)
+	e.GET("/orders/:orderId", orderController.GetOrder)
+	e.POST("/carts/:cartId/quotes", quoteController.CreateQuote)
+	e.GET("/quotes/:quoteId", quoteController.GetQuote)
+	e.POST("/quotes/:quoteId/accept", quoteController.AcceptQuote)
+	e.POST("/quotes/:quoteId/decline", quoteController.DeclineQuote)
 }
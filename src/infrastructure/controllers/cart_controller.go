@@ -11,6 +11,15 @@ import (
 type CartService interface {
 	CreateNewCart(application.CreateCartCommand) (application.CartDto, error)
 	AddItemToCart(application.AddItemToCartCommand) (application.CartDto, error)
+	RemoveItemFromCart(application.RemoveItemFromCartCommand) (application.CartDto, error)
+	UpdateItemQuantity(application.UpdateItemQuantityCommand) (application.CartDto, error)
+	ClearCart(application.ClearCartCommand) (application.CartDto, error)
+	ApplyCoupon(application.ApplyCouponCommand) (application.CartDto, error)
+	RemoveCoupon(application.RemoveCouponCommand) (application.CartDto, error)
+	GetShippingOptions(application.GetShippingOptionsQuery) ([]application.ShippingOptionDto, error)
+	SelectShipping(application.SelectShippingCommand) (application.CartDto, error)
+	GetCart(application.GetCartQuery) (application.CartDto, error)
+	GetCustomerCarts(application.GetCustomerCartsQuery) ([]application.CartDto, error)
 }
 
 type CartController struct {
@@ -42,9 +51,25 @@ func (cc *CartController) CreateNewCart(c echo.Context) error {
 		if err, ok := err.(*application.NotFoundError); ok {
 			return echo.NewHTTPError(404, err.Error())
 		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.InsufficientStockError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		if err, ok := err.(*application.ConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
 		return echo.NewHTTPError(500, err.Error())
 	}
 
+	setETag(c, cartDto.Version)
+	if cartDto.Reused {
+		return c.JSON(200, cartDto)
+	}
 	return c.JSON(201, cartDto)
 }
 
@@ -62,13 +87,358 @@ func (cc *CartController) AddItemToCart(c echo.Context) error {
 		return err
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
 	cartDto, err := cc.cartService.AddItemToCart(command)
 	if err != nil {
 		if err, ok := err.(*application.NotFoundError); ok {
 			return echo.NewHTTPError(404, err.Error())
 		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.InsufficientStockError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		if err, ok := err.(*application.ProductArchivedError); ok {
+			return echo.NewHTTPError(422, map[string]string{"code": "product_archived", "message": err.Error()})
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) RemoveItemFromCart(c echo.Context) error {
+	var command application.RemoveItemFromCartCommand
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.RemoveItemFromCart(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) UpdateItemQuantity(c echo.Context) error {
+	var command application.UpdateItemQuantityCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.UpdateItemQuantity(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.InsufficientStockError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) ClearCart(c echo.Context) error {
+	var command application.ClearCartCommand
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.ClearCart(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) ApplyCoupon(c echo.Context) error {
+	var command application.ApplyCouponCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.ApplyCoupon(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) RemoveCoupon(c echo.Context) error {
+	var command application.RemoveCouponCommand
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+	command.Code = c.Param("code")
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.RemoveCoupon(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) GetShippingOptions(c echo.Context) error {
+	var query application.GetShippingOptionsQuery
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		query.CartId = cartId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	optionDtos, err := cc.cartService.GetShippingOptions(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, optionDtos)
+}
+
+func (cc *CartController) SelectShipping(c echo.Context) error {
+	var command application.SelectShippingCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	cartDto, err := cc.cartService.SelectShipping(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, cartDto.Version)
+	return c.JSON(200, cartDto)
+}
+
+func (cc *CartController) GetCart(c echo.Context) error {
+	var query application.GetCartQuery
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		query.CartId = cartId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	cartDto, err := cc.cartService.GetCart(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
 		return echo.NewHTTPError(500, err.Error())
 	}
 
+	setETag(c, cartDto.Version)
 	return c.JSON(200, cartDto)
 }
+
+func (cc *CartController) GetCustomerCarts(c echo.Context) error {
+	var query application.GetCustomerCartsQuery
+	if err := c.Bind(&query); err != nil {
+		return err
+	}
+
+	if customerId, err := uuid.Parse(c.Param("customerId")); err == nil {
+		query.CustomerId = customerId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	cartDtos, err := cc.cartService.GetCustomerCarts(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, cartDtos)
+}
@@ -4,11 +4,15 @@ import (
 	"errors"
 
 	"github.com/bitlogic/go-startup/src/application"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type CustomerService interface {
 	CreateNewCustomer(application.CreateCustomerCommand) (application.CustomerDto, error)
+	GetCustomer(application.GetCustomerQuery) (application.CustomerDto, error)
+	UpdateCustomer(application.UpdateCustomerCommand) (application.CustomerDto, error)
+	DeactivateCustomer(application.DeactivateCustomerCommand) (application.CustomerDto, error)
 }
 
 type CustomerController struct {
@@ -37,8 +41,115 @@ func (cc *CustomerController) CreateNewCustomer(c echo.Context) error {
 
 	customerDto, err := cc.customerService.CreateNewCustomer(command)
 	if err != nil {
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.ConflictError); ok {
+			return echo.NewHTTPError(409, application.ConflictDto{Message: err.Error(), Field: err.Field()})
+		}
 		return echo.NewHTTPError(500, err.Error())
 	}
 
+	setETag(c, customerDto.Version)
 	return c.JSON(201, customerDto)
 }
+
+func (cc *CustomerController) GetCustomer(c echo.Context) error {
+	var query application.GetCustomerQuery
+	if customerId, err := uuid.Parse(c.Param("customerId")); err == nil {
+		query.CustomerId = customerId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	customerDto, err := cc.customerService.GetCustomer(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, customerDto.Version)
+	return c.JSON(200, customerDto)
+}
+
+func (cc *CustomerController) UpdateCustomer(c echo.Context) error {
+	var command application.UpdateCustomerCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if customerId, err := uuid.Parse(c.Param("customerId")); err == nil {
+		command.CustomerId = customerId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	customerDto, err := cc.customerService.UpdateCustomer(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.ConflictError); ok {
+			return echo.NewHTTPError(409, application.ConflictDto{Message: err.Error(), Field: err.Field()})
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, customerDto.Version)
+	return c.JSON(200, customerDto)
+}
+
+func (cc *CustomerController) DeactivateCustomer(c echo.Context) error {
+	var command application.DeactivateCustomerCommand
+	if customerId, err := uuid.Parse(c.Param("customerId")); err == nil {
+		command.CustomerId = customerId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	customerDto, err := cc.customerService.DeactivateCustomer(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, customerDto.Version)
+	return c.JSON(200, customerDto)
+}
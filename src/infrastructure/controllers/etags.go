@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+func setETag(c echo.Context, version int) {
+	c.Response().Header().Set(headerETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+func parseIfMatch(c echo.Context) (*int, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return nil, nil
+	}
+
+	tag, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return nil, echo.NewHTTPError(400, "invalid If-Match header")
+	}
+
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 0 {
+		return nil, echo.NewHTTPError(400, "invalid If-Match header")
+	}
+
+	return &version, nil
+}
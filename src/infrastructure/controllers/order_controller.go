@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type OrderService interface {
+	CheckoutCart(application.CheckoutCartCommand) (application.OrderDto, error)
+	GetOrder(application.GetOrderQuery) (application.OrderDto, error)
+}
+
+type OrderController struct {
+	orderService OrderService
+}
+
+func NewOrderController(orderService OrderService) (*OrderController, error) {
+	if orderService == nil {
+		return nil, errors.New("order service was nil")
+	}
+
+	return &OrderController{
+		orderService: orderService,
+	}, nil
+}
+
+func (oc *OrderController) CheckoutCart(c echo.Context) error {
+	var command application.CheckoutCartCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	orderDto, err := oc.orderService.CheckoutCart(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(201, orderDto)
+}
+
+func (oc *OrderController) GetOrder(c echo.Context) error {
+	var query application.GetOrderQuery
+	if orderId, err := uuid.Parse(c.Param("orderId")); err == nil {
+		query.OrderId = orderId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	orderDto, err := oc.orderService.GetOrder(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, orderDto)
+}
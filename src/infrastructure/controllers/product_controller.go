@@ -4,11 +4,18 @@ import (
 	"errors"
 
 	"github.com/bitlogic/go-startup/src/application"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
 type ProductService interface {
 	CreateNewProduct(application.CreateProductCommand) (application.ProductDto, error)
+	GetProduct(application.GetProductQuery) (application.ProductDto, error)
+	GetProductBySKU(application.GetProductBySKUQuery) (application.ProductDto, error)
+	ListProducts(application.ListProductsQuery) (application.ProductPageDto, error)
+	UpdateProduct(application.UpdateProductCommand) (application.ProductDto, error)
+	ArchiveProduct(application.ArchiveProductCommand) (application.ProductDto, error)
+	RestoreProduct(application.RestoreProductCommand) (application.ProductDto, error)
 }
 
 type ProductController struct {
@@ -37,8 +44,194 @@ func (pc *ProductController) CreateNewProduct(c echo.Context) error {
 
 	productDto, err := pc.service.CreateNewProduct(command)
 	if err != nil {
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.ConflictError); ok {
+			return echo.NewHTTPError(409, application.ConflictDto{Message: err.Error(), Field: err.Field()})
+		}
 		return echo.NewHTTPError(500, err.Error())
 	}
 
+	setETag(c, productDto.Version)
 	return c.JSON(201, productDto)
 }
+
+func (pc *ProductController) GetProduct(c echo.Context) error {
+	var query application.GetProductQuery
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		query.ProductId = productId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	productDto, err := pc.service.GetProduct(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, productDto.Version)
+	return c.JSON(200, productDto)
+}
+
+func (pc *ProductController) GetProductBySKU(c echo.Context) error {
+	query := application.GetProductBySKUQuery{
+		SKU: c.Param("sku"),
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	productDto, err := pc.service.GetProductBySKU(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, productDto.Version)
+	return c.JSON(200, productDto)
+}
+
+func (pc *ProductController) ListProducts(c echo.Context) error {
+	var query application.ListProductsQuery
+	if err := c.Bind(&query); err != nil {
+		return err
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	pageDto, err := pc.service.ListProducts(query)
+	if err != nil {
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, pageDto)
+}
+
+func (pc *ProductController) UpdateProduct(c echo.Context) error {
+	var command application.UpdateProductCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	productDto, err := pc.service.UpdateProduct(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.ConflictError); ok {
+			return echo.NewHTTPError(409, application.ConflictDto{Message: err.Error(), Field: err.Field()})
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, productDto.Version)
+	return c.JSON(200, productDto)
+}
+
+func (pc *ProductController) ArchiveProduct(c echo.Context) error {
+	var command application.ArchiveProductCommand
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	productDto, err := pc.service.ArchiveProduct(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, productDto.Version)
+	return c.JSON(200, productDto)
+}
+
+func (pc *ProductController) RestoreProduct(c echo.Context) error {
+	var command application.RestoreProductCommand
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	productDto, err := pc.service.RestoreProduct(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, productDto.Version)
+	return c.JSON(200, productDto)
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type QuoteService interface {
+	CreateQuote(application.CreateQuoteCommand) (application.QuoteDto, error)
+	GetQuote(application.GetQuoteQuery) (application.QuoteDto, error)
+	AcceptQuote(application.AcceptQuoteCommand) (application.QuoteDto, error)
+	DeclineQuote(application.DeclineQuoteCommand) (application.QuoteDto, error)
+}
+
+type QuoteController struct {
+	quoteService QuoteService
+}
+
+func NewQuoteController(quoteService QuoteService) (*QuoteController, error) {
+	if quoteService == nil {
+		return nil, errors.New("quote service was nil")
+	}
+
+	return &QuoteController{
+		quoteService: quoteService,
+	}, nil
+}
+
+func (qc *QuoteController) CreateQuote(c echo.Context) error {
+	var command application.CreateQuoteCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if cartId, err := uuid.Parse(c.Param("cartId")); err == nil {
+		command.CartId = cartId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	quoteDto, err := qc.quoteService.CreateQuote(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(201, quoteDto)
+}
+
+func (qc *QuoteController) GetQuote(c echo.Context) error {
+	var query application.GetQuoteQuery
+	if quoteId, err := uuid.Parse(c.Param("quoteId")); err == nil {
+		query.QuoteId = quoteId
+	}
+
+	if err := c.Validate(query); err != nil {
+		return err
+	}
+
+	quoteDto, err := qc.quoteService.GetQuote(query)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, quoteDto)
+}
+
+func (qc *QuoteController) AcceptQuote(c echo.Context) error {
+	var command application.AcceptQuoteCommand
+	if quoteId, err := uuid.Parse(c.Param("quoteId")); err == nil {
+		command.QuoteId = quoteId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	quoteDto, err := qc.quoteService.AcceptQuote(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, quoteDto)
+}
+
+func (qc *QuoteController) DeclineQuote(c echo.Context) error {
+	var command application.DeclineQuoteCommand
+	if quoteId, err := uuid.Parse(c.Param("quoteId")); err == nil {
+		command.QuoteId = quoteId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	quoteDto, err := qc.quoteService.DeclineQuote(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	return c.JSON(200, quoteDto)
+}
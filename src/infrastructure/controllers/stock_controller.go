@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"errors"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type StockService interface {
+	UpdateStock(application.UpdateStockCommand) (application.StockDto, error)
+}
+
+type StockController struct {
+	stockService StockService
+}
+
+func NewStockController(stockService StockService) (*StockController, error) {
+	if stockService == nil {
+		return nil, errors.New("stock service was nil")
+	}
+
+	return &StockController{
+		stockService: stockService,
+	}, nil
+}
+
+func (sc *StockController) UpdateStock(c echo.Context) error {
+	var command application.UpdateStockCommand
+	if err := c.Bind(&command); err != nil {
+		return err
+	}
+
+	if productId, err := uuid.Parse(c.Param("productId")); err == nil {
+		command.ProductId = productId
+	}
+
+	if err := c.Validate(command); err != nil {
+		return err
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		return err
+	}
+	command.ExpectedVersion = expectedVersion
+
+	stockDto, err := sc.stockService.UpdateStock(command)
+	if err != nil {
+		if err, ok := err.(*application.NotFoundError); ok {
+			return echo.NewHTTPError(404, err.Error())
+		}
+		if err, ok := err.(*application.InvalidArgumentError); ok {
+			return echo.NewHTTPError(400, err.Error())
+		}
+		if err, ok := err.(*application.PreconditionFailedError); ok {
+			return echo.NewHTTPError(412, err.Error())
+		}
+		if err, ok := err.(*application.ConcurrencyConflictError); ok {
+			return echo.NewHTTPError(409, err.Error())
+		}
+		return echo.NewHTTPError(500, err.Error())
+	}
+
+	setETag(c, stockDto.Version)
+	return c.JSON(200, stockDto)
+}
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+func Migrate(db *sql.DB) ([]int, error) {
+	migrations, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	return ApplyMigrations(db, migrations)
+}
+
+func ApplyMigrations(db *sql.DB, migrations fs.FS) ([]int, error) {
+	if db == nil {
+		return nil, errors.New("db was nil")
+	}
+
+	pending, err := LoadMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at INTEGER NOT NULL
+)`); err != nil {
+		return nil, err
+	}
+
+	applied, err := AppliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	alreadyApplied := map[int]bool{}
+	for _, version := range applied {
+		alreadyApplied[version] = true
+	}
+
+	var versions []int
+	for _, migration := range pending {
+		if alreadyApplied[migration.Version] {
+			continue
+		}
+
+		if err := applyMigration(db, migration); err != nil {
+			return versions, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		versions = append(versions, migration.Version)
+	}
+
+	return versions, nil
+}
+
+func LoadMigrations(migrations fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []Migration
+	versions := map[int]string{}
+	for _, file := range files {
+		prefix, name, found := strings.Cut(strings.TrimSuffix(path.Base(file), ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if !found || err != nil || version < 1 || name == "" {
+			return nil, fmt.Errorf("invalid migration file name %q", file)
+		}
+
+		if previous, found := versions[version]; found {
+			return nil, fmt.Errorf("duplicated migration version %d in %q and %q", version, previous, file)
+		}
+		versions[version] = file
+
+		statements, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, Migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(statements),
+		})
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].Version < loaded[j].Version
+	})
+
+	return loaded, nil
+}
+
+func AppliedVersions(db *sql.DB) ([]int, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+func applyMigration(db *sql.DB, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.SQL); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`, migration.Version, migration.Name, time.Now().UTC().UnixNano()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,16 @@
+CREATE TABLE products (
+    id TEXT NOT NULL PRIMARY KEY,
+    sku TEXT UNIQUE,
+    name TEXT NOT NULL,
+    name_key TEXT NOT NULL UNIQUE,
+    currency TEXT NOT NULL,
+    unit_price INTEGER NOT NULL,
+    tax_category TEXT NOT NULL,
+    weight_grams INTEGER NOT NULL DEFAULT 0,
+    length_mm INTEGER NOT NULL DEFAULT 0,
+    width_mm INTEGER NOT NULL DEFAULT 0,
+    height_mm INTEGER NOT NULL DEFAULT 0,
+    archived INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX products_currency_unit_price ON products (currency, unit_price, id);
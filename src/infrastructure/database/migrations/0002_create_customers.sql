@@ -0,0 +1,15 @@
+CREATE TABLE customers (
+    id TEXT NOT NULL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT UNIQUE,
+    phone TEXT NOT NULL DEFAULT '',
+    shipping_street TEXT NOT NULL DEFAULT '',
+    shipping_city TEXT NOT NULL DEFAULT '',
+    shipping_postal_code TEXT NOT NULL DEFAULT '',
+    shipping_country TEXT NOT NULL DEFAULT '',
+    billing_street TEXT NOT NULL DEFAULT '',
+    billing_city TEXT NOT NULL DEFAULT '',
+    billing_postal_code TEXT NOT NULL DEFAULT '',
+    billing_country TEXT NOT NULL DEFAULT '',
+    deactivated INTEGER NOT NULL DEFAULT 0
+);
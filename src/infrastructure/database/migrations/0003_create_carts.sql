@@ -0,0 +1,43 @@
+CREATE TABLE carts (
+    id TEXT NOT NULL PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL,
+    last_activity_at INTEGER NOT NULL,
+    shipping_method TEXT,
+    shipping_name TEXT,
+    shipping_cost INTEGER,
+    shipping_currency TEXT,
+    tax_region TEXT NOT NULL DEFAULT '',
+    adjustments TEXT NOT NULL DEFAULT '[]',
+    taxed INTEGER NOT NULL DEFAULT 0,
+    taxes TEXT NOT NULL DEFAULT '[]'
+);
+
+CREATE INDEX carts_customer_id ON carts (customer_id);
+
+CREATE INDEX carts_status_last_activity_at ON carts (status, last_activity_at);
+
+CREATE TABLE cart_items (
+    cart_id TEXT NOT NULL REFERENCES carts (id) ON DELETE CASCADE,
+    product_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    unit_price INTEGER NOT NULL,
+    added_currency TEXT NOT NULL,
+    added_unit_price INTEGER NOT NULL,
+    exchange_rate TEXT NOT NULL,
+    tax_category TEXT NOT NULL,
+    weight_grams INTEGER NOT NULL DEFAULT 0,
+    length_mm INTEGER NOT NULL DEFAULT 0,
+    width_mm INTEGER NOT NULL DEFAULT 0,
+    height_mm INTEGER NOT NULL DEFAULT 0,
+    quantity INTEGER NOT NULL,
+    PRIMARY KEY (cart_id, product_id)
+);
+
+CREATE TABLE cart_coupons (
+    cart_id TEXT NOT NULL REFERENCES carts (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    code TEXT NOT NULL,
+    PRIMARY KEY (cart_id, position)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE products ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE customers ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE carts ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
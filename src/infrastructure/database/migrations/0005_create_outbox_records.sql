@@ -0,0 +1,13 @@
+CREATE TABLE outbox_records (
+    id TEXT NOT NULL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    occurred_on INTEGER NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    delivered_at INTEGER
+);
+
+CREATE INDEX outbox_records_pending ON outbox_records (delivered_at, next_attempt_at);
@@ -0,0 +1,9 @@
+UPDATE carts SET status = 'abandoned'
+WHERE status = 'active' AND EXISTS (
+    SELECT 1 FROM carts AS newer
+    WHERE newer.customer_id = carts.customer_id
+      AND newer.status = 'active'
+      AND (newer.last_activity_at, newer.rowid) > (carts.last_activity_at, carts.rowid)
+);
+
+CREATE UNIQUE INDEX carts_active_customer_id ON carts (customer_id) WHERE status = 'active';
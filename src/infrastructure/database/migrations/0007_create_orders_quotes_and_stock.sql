@@ -0,0 +1,63 @@
+CREATE TABLE orders (
+    id TEXT NOT NULL PRIMARY KEY,
+    quote_id TEXT,
+    cart_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    lines TEXT NOT NULL,
+    subtotal INTEGER NOT NULL,
+    adjustments TEXT NOT NULL DEFAULT '[]',
+    shipping_method TEXT,
+    shipping_name TEXT,
+    shipping_cost INTEGER,
+    shipping_currency TEXT,
+    taxed INTEGER NOT NULL DEFAULT 0,
+    taxes TEXT NOT NULL DEFAULT '[]',
+    total INTEGER NOT NULL,
+    placed_on INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE quotes (
+    id TEXT NOT NULL PRIMARY KEY,
+    number TEXT NOT NULL UNIQUE,
+    cart_id TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    lines TEXT NOT NULL,
+    subtotal INTEGER NOT NULL,
+    adjustments TEXT NOT NULL DEFAULT '[]',
+    shipping_method TEXT,
+    shipping_name TEXT,
+    shipping_cost INTEGER,
+    shipping_currency TEXT,
+    taxed INTEGER NOT NULL DEFAULT 0,
+    taxes TEXT NOT NULL DEFAULT '[]',
+    total INTEGER NOT NULL,
+    issued_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE quote_number_sequence (
+    id INTEGER NOT NULL PRIMARY KEY CHECK (id = 1),
+    value INTEGER NOT NULL
+);
+
+INSERT INTO quote_number_sequence (id, value) VALUES (1, 0);
+
+CREATE TABLE stock_items (
+    id TEXT NOT NULL PRIMARY KEY,
+    on_hand INTEGER NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE stock_reservations (
+    product_id TEXT NOT NULL REFERENCES stock_items (id) ON DELETE CASCADE,
+    cart_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    PRIMARY KEY (product_id, cart_id)
+);
+
+CREATE INDEX stock_reservations_cart_id ON stock_reservations (cart_id);
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type SynchronousEventDispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]domain.DomainEventHandler
+}
+
+func NewSynchronousEventDispatcher() domain.EventDispatcher {
+	return &SynchronousEventDispatcher{
+		handlers: map[string][]domain.DomainEventHandler{},
+	}
+}
+
+func (d *SynchronousEventDispatcher) Register(eventName string, handler domain.DomainEventHandler) {
+	if handler == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventName] = append(d.handlers[eventName], handler)
+}
+
+func (d *SynchronousEventDispatcher) Dispatch(events ...domain.DomainEvent) error {
+	var firstErr error
+	for _, event := range events {
+		for _, handler := range d.handlersFor(domain.EventName(event)) {
+			if err := handler(event); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
+
+func (d *SynchronousEventDispatcher) handlersFor(eventName string) []domain.DomainEventHandler {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return append([]domain.DomainEventHandler{}, d.handlers[eventName]...)
+}
@@ -0,0 +1,81 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type StaticExchangeRateProvider struct {
+	rates map[domain.Currency]map[domain.Currency]*big.Rat
+}
+
+func NewStaticExchangeRateProvider(rates map[string]map[string]string) (*StaticExchangeRateProvider, error) {
+	provider := &StaticExchangeRateProvider{
+		rates: map[domain.Currency]map[domain.Currency]*big.Rat{},
+	}
+
+	for fromCode, targets := range rates {
+		from, err := domain.NewCurrency(fromCode)
+		if err != nil {
+			return nil, err
+		}
+
+		for toCode, value := range targets {
+			to, err := domain.NewCurrency(toCode)
+			if err != nil {
+				return nil, err
+			}
+
+			rate, ok := new(big.Rat).SetString(value)
+			if !ok || rate.Sign() <= 0 {
+				return nil, fmt.Errorf("invalid exchange rate %q from %s to %s", value, from, to)
+			}
+
+			if provider.rates[from] == nil {
+				provider.rates[from] = map[domain.Currency]*big.Rat{}
+			}
+			provider.rates[from][to] = rate
+		}
+	}
+
+	return provider, nil
+}
+
+func NewFileExchangeRateProvider(path string) (*StaticExchangeRateProvider, error) {
+	if path == "" {
+		return nil, errors.New("exchange rates file path was empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates map[string]map[string]string
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+
+	return NewStaticExchangeRateProvider(rates)
+}
+
+func (p *StaticExchangeRateProvider) GetRate(from domain.Currency, to domain.Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	if rate, found := p.rates[from][to]; found {
+		return new(big.Rat).Set(rate), nil
+	}
+
+	if rate, found := p.rates[to][from]; found {
+		return new(big.Rat).Inv(rate), nil
+	}
+
+	return nil, domain.ErrCurrencyNotConvertible
+}
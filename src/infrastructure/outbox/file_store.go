@@ -0,0 +1,318 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const defaultCompactEvery = 1000
+
+type FileStoreOption func(*FileStore)
+
+func WithCompactEvery(writes int) FileStoreOption {
+	return func(store *FileStore) {
+		store.compactEvery = writes
+	}
+}
+
+type fileEntry struct {
+	Record
+	Discarded bool `json:"discarded,omitempty"`
+}
+
+type FileStore struct {
+	mu           sync.Mutex
+	path         string
+	memory       *InMemoryStore
+	compactEvery int
+	writes       int
+}
+
+func NewFileStore(path string, options ...FileStoreOption) (*FileStore, error) {
+	if path == "" {
+		return nil, errors.New("outbox file path was empty")
+	}
+
+	store := &FileStore{
+		path:   path,
+		memory: NewInMemoryStore(WithPruneEvery(0)),
+	}
+	for _, option := range options {
+		option(store)
+	}
+	if store.compactEvery <= 0 {
+		store.compactEvery = defaultCompactEvery
+	}
+
+	entries, err := readEntries(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	replayEntries(store.memory, entries)
+	store.writes = len(entries)
+
+	if store.writes >= store.compactEvery {
+		if err := store.compact(); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+func (s *FileStore) Append(records ...Record) error {
+	return s.mutate(func(memory *InMemoryStore) ([]fileEntry, error) {
+		if err := memory.Append(records...); err != nil {
+			return nil, err
+		}
+
+		var entries []fileEntry
+		for _, record := range records {
+			entries = append(entries, fileEntry{Record: record})
+		}
+
+		return entries, nil
+	})
+}
+
+func (s *FileStore) Pending(now time.Time, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.Pending(now, limit)
+}
+
+func (s *FileStore) MarkDelivered(id uuid.UUID, deliveredAt time.Time) error {
+	return s.mutate(func(memory *InMemoryStore) ([]fileEntry, error) {
+		if err := memory.MarkDelivered(id, deliveredAt); err != nil {
+			return nil, err
+		}
+
+		record, _ := memory.find(id)
+		return []fileEntry{{Record: record}}, nil
+	})
+}
+
+func (s *FileStore) MarkFailed(id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	return s.mutate(func(memory *InMemoryStore) ([]fileEntry, error) {
+		if err := memory.MarkFailed(id, reason, nextAttemptAt); err != nil {
+			return nil, err
+		}
+
+		record, _ := memory.find(id)
+		return []fileEntry{{Record: record}}, nil
+	})
+}
+
+func (s *FileStore) Discard(ids ...uuid.UUID) error {
+	return s.mutate(func(memory *InMemoryStore) ([]fileEntry, error) {
+		var entries []fileEntry
+		for _, id := range ids {
+			if _, found := memory.find(id); found {
+				entries = append(entries, fileEntry{Record: Record{Id: id}, Discarded: true})
+			}
+		}
+
+		return entries, memory.Discard(ids...)
+	})
+}
+
+func (s *FileStore) All() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.memory.All()
+}
+
+func (s *FileStore) mutate(apply func(*InMemoryStore) ([]fileEntry, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.memory.snapshot()
+	entries, err := apply(s.memory)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := appendEntries(s.path, entries); err != nil {
+		s.memory.restore(previous)
+		return err
+	}
+
+	s.writes += len(entries)
+	if s.writes >= s.compactEvery {
+		if err := s.compact(); err != nil {
+			log.Printf("failed to compact %s, the outbox file keeps growing: %v", s.path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *FileStore) compact() error {
+	var undelivered []Record
+	for _, record := range s.memory.snapshot() {
+		if !record.IsDelivered() {
+			undelivered = append(undelivered, record)
+		}
+	}
+
+	if err := writeRecords(s.path, undelivered); err != nil {
+		return err
+	}
+
+	s.memory.restore(undelivered)
+	s.writes = 0
+	return nil
+}
+
+func replayEntries(memory *InMemoryStore, entries []fileEntry) {
+	for _, entry := range entries {
+		record := entry.Record
+		if entry.Discarded {
+			memory.Discard(record.Id)
+			continue
+		}
+
+		if err := memory.update(record.Id, func(stored *Record) { *stored = record }); err != nil {
+			memory.Append(record)
+		}
+	}
+}
+
+func readEntries(path string) ([]fileEntry, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []fileEntry
+	var offset int64
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for number := 1; ; number++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return entries, truncateTornWrite(file, offset)
+			}
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry fileEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+					return entries, truncateTornWrite(file, offset)
+				}
+				return nil, fmt.Errorf("corrupt outbox record at line %d: %w", number, err)
+			}
+			entries = append(entries, entry)
+		}
+
+		offset += int64(len(line))
+	}
+}
+
+func truncateTornWrite(file *os.File, size int64) error {
+	if err := file.Truncate(size); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func appendEntries(path string, entries []fileEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(buffer.Bytes()); err != nil {
+		file.Truncate(info.Size())
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Truncate(info.Size())
+		return err
+	}
+
+	return nil
+}
+
+func writeRecords(path string, records []Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	writer := bufio.NewWriter(tempFile)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			tempFile.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
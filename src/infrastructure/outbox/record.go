@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+type Record struct {
+	Id            uuid.UUID       `json:"id"`
+	EventType     string          `json:"event_type"`
+	AggregateId   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredOn    time.Time       `json:"occurred_on"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+}
+
+func NewRecords(aggregateId string, events []domain.DomainEvent, occurredOn time.Time) ([]Record, error) {
+	var records []Record
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, Record{
+			Id:            uuid.New(),
+			EventType:     domain.EventName(event),
+			AggregateId:   aggregateId,
+			Payload:       payload,
+			OccurredOn:    occurredOn,
+			NextAttemptAt: occurredOn,
+		})
+	}
+
+	return records, nil
+}
+
+func (r Record) IsDelivered() bool {
+	return r.DeliveredAt != nil
+}
+
+func (r Record) isDue(now time.Time) bool {
+	return !r.IsDelivered() && !r.NextAttemptAt.After(now)
+}
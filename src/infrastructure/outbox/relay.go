@@ -0,0 +1,173 @@
+package outbox
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+type Publisher interface {
+	Publish(Record) error
+}
+
+type PublisherFunc func(Record) error
+
+func (f PublisherFunc) Publish(record Record) error {
+	return f(record)
+}
+
+type LogPublisher struct {
+	logger *log.Logger
+}
+
+func NewLogPublisher(logger *log.Logger) *LogPublisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &LogPublisher{
+		logger: logger,
+	}
+}
+
+func (p *LogPublisher) Publish(record Record) error {
+	p.logger.Printf("outbox: %s for aggregate %s: %s", record.EventType, record.AggregateId, record.Payload)
+	return nil
+}
+
+type RelayConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Now            func() time.Time
+}
+
+type Relay struct {
+	store     Store
+	publisher Publisher
+	config    RelayConfig
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func NewRelay(store Store, publisher Publisher, config RelayConfig) (*Relay, error) {
+	if store == nil {
+		return nil, errors.New("outbox store was nil")
+	}
+
+	if publisher == nil {
+		return nil, errors.New("outbox publisher was nil")
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = config.PollInterval
+	}
+
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = 64 * config.InitialBackoff
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		config:    config,
+	}, nil
+}
+
+func (r *Relay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		return
+	}
+
+	r.stop = make(chan struct{})
+	r.stopped = make(chan struct{})
+	go r.run(r.stop, r.stopped)
+}
+
+func (r *Relay) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop == nil {
+		return
+	}
+
+	close(r.stop)
+	<-r.stopped
+	r.stop = nil
+	r.stopped = nil
+}
+
+func (r *Relay) ProcessPending() error {
+	records, err := r.store.Pending(r.config.Now(), r.config.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err := r.publisher.Publish(record); err != nil {
+			nextAttemptAt := r.config.Now().Add(r.backoff(record.Attempts + 1))
+			if err := r.store.MarkFailed(record.Id, err.Error(), nextAttemptAt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.store.MarkDelivered(record.Id, r.config.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Relay) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.ProcessPending(); err != nil {
+			log.Printf("outbox relay: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.config.InitialBackoff
+	for i := 1; i < attempts && backoff < r.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > r.config.MaxBackoff {
+		return r.config.MaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const recordColumns = `id, event_type, aggregate_id, payload, occurred_on, attempts, next_attempt_at, last_error, delivered_at`
+
+type SQLStore struct {
+	db *sql.DB
+}
+
+func NewSQLStore(db *sql.DB) (*SQLStore, error) {
+	if db == nil {
+		return nil, errors.New("db was nil")
+	}
+
+	return &SQLStore{
+		db: db,
+	}, nil
+}
+
+func (s *SQLStore) Append(records ...Record) error {
+	return s.inTransaction(func(tx *sql.Tx) error {
+		return s.AppendTx(tx, records...)
+	})
+}
+
+func (s *SQLStore) AppendTx(tx *sql.Tx, records ...Record) error {
+	for _, record := range records {
+		result, err := tx.Exec(`INSERT INTO outbox_records (`+recordColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`,
+			record.Id.String(),
+			record.EventType,
+			record.AggregateId,
+			string(record.Payload),
+			record.OccurredOn.UnixNano(),
+			record.Attempts,
+			record.NextAttemptAt.UnixNano(),
+			record.LastError,
+			nullableTime(record.DeliveredAt),
+		)
+		if err != nil {
+			return fmt.Errorf("outbox record %s: %w", record.Id, err)
+		}
+
+		appended, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if appended == 0 {
+			return fmt.Errorf("outbox record %s: %w", record.Id, ErrRecordExists)
+		}
+	}
+
+	return nil
+}
+
+func (s *SQLStore) Pending(now time.Time, limit int) ([]Record, error) {
+	query := `SELECT ` + recordColumns + ` FROM outbox_records WHERE delivered_at IS NULL AND next_attempt_at <= ? ORDER BY occurred_on, rowid`
+	args := []any{now.UnixNano()}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	return s.query(query, args...)
+}
+
+func (s *SQLStore) MarkDelivered(id uuid.UUID, deliveredAt time.Time) error {
+	return s.update(id, `UPDATE outbox_records SET attempts = attempts + 1, last_error = '', delivered_at = ? WHERE id = ?`, deliveredAt.UnixNano(), id.String())
+}
+
+func (s *SQLStore) MarkFailed(id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	return s.update(id, `UPDATE outbox_records SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`, reason, nextAttemptAt.UnixNano(), id.String())
+}
+
+func (s *SQLStore) Discard(ids ...uuid.UUID) error {
+	return s.inTransaction(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := tx.Exec(`DELETE FROM outbox_records WHERE id = ?`, id.String()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLStore) All() ([]Record, error) {
+	return s.query(`SELECT ` + recordColumns + ` FROM outbox_records ORDER BY occurred_on, rowid`)
+}
+
+func (s *SQLStore) inTransaction(work func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := work(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLStore) update(id uuid.UUID, statement string, args ...any) error {
+	result, err := s.db.Exec(statement, args...)
+	if err != nil {
+		return err
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return fmt.Errorf("outbox record %s not found", id)
+	}
+
+	return nil
+}
+
+func (s *SQLStore) query(query string, args ...any) ([]Record, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+func scanRecord(rows *sql.Rows) (Record, error) {
+	var record Record
+	var id, payload string
+	var occurredOn, nextAttemptAt int64
+	var deliveredAt sql.NullInt64
+	err := rows.Scan(&id, &record.EventType, &record.AggregateId, &payload, &occurredOn, &record.Attempts, &nextAttemptAt, &record.LastError, &deliveredAt)
+	if err != nil {
+		return record, err
+	}
+
+	if record.Id, err = uuid.Parse(id); err != nil {
+		return record, err
+	}
+
+	record.Payload = []byte(payload)
+	record.OccurredOn = time.Unix(0, occurredOn).UTC()
+	record.NextAttemptAt = time.Unix(0, nextAttemptAt).UTC()
+	if deliveredAt.Valid {
+		delivered := time.Unix(0, deliveredAt.Int64).UTC()
+		record.DeliveredAt = &delivered
+	}
+
+	return record, nil
+}
+
+func nullableTime(value *time.Time) sql.NullInt64 {
+	if value == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: value.UnixNano(), Valid: true}
+}
@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrRecordExists = errors.New("already exists")
+
+type Store interface {
+	Append(records ...Record) error
+	Pending(now time.Time, limit int) ([]Record, error)
+	MarkDelivered(id uuid.UUID, deliveredAt time.Time) error
+	MarkFailed(id uuid.UUID, reason string, nextAttemptAt time.Time) error
+	Discard(ids ...uuid.UUID) error
+}
+
+type InMemoryStoreOption func(*InMemoryStore)
+
+func WithPruneEvery(deliveries int) InMemoryStoreOption {
+	return func(store *InMemoryStore) {
+		store.pruneEvery = deliveries
+	}
+}
+
+type InMemoryStore struct {
+	mu         sync.Mutex
+	records    []Record
+	index      map[uuid.UUID]int
+	pruneEvery int
+	delivered  int
+}
+
+func NewInMemoryStore(options ...InMemoryStoreOption) *InMemoryStore {
+	store := &InMemoryStore{
+		index:      map[uuid.UUID]int{},
+		pruneEvery: defaultCompactEvery,
+	}
+	for _, option := range options {
+		option(store)
+	}
+
+	return store
+}
+
+func (s *InMemoryStore) Append(records ...Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, record := range records {
+		if _, found := s.index[record.Id]; found {
+			return fmt.Errorf("outbox record %s: %w", record.Id, ErrRecordExists)
+		}
+	}
+
+	for _, record := range records {
+		s.index[record.Id] = len(s.records)
+		s.records = append(s.records, record)
+	}
+
+	return nil
+}
+
+func AppendMissing(store Store, records ...Record) error {
+	for _, record := range records {
+		if err := store.Append(record); err != nil && !errors.Is(err, ErrRecordExists) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *InMemoryStore) Pending(now time.Time, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []Record
+	for _, record := range s.records {
+		if limit > 0 && len(pending) == limit {
+			break
+		}
+
+		if record.isDue(now) {
+			pending = append(pending, record)
+		}
+	}
+
+	return pending, nil
+}
+
+func (s *InMemoryStore) MarkDelivered(id uuid.UUID, deliveredAt time.Time) error {
+	err := s.update(id, func(record *Record) {
+		record.Attempts++
+		record.LastError = ""
+		record.DeliveredAt = &deliveredAt
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delivered++
+	if s.pruneEvery > 0 && s.delivered >= s.pruneEvery {
+		s.pruneDelivered()
+	}
+
+	return nil
+}
+
+func (s *InMemoryStore) MarkFailed(id uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	return s.update(id, func(record *Record) {
+		record.Attempts++
+		record.LastError = reason
+		record.NextAttemptAt = nextAttemptAt
+	})
+}
+
+func (s *InMemoryStore) Discard(ids ...uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	discarded := map[uuid.UUID]bool{}
+	for _, id := range ids {
+		discarded[id] = true
+	}
+
+	var records []Record
+	for _, record := range s.records {
+		if !discarded[record.Id] {
+			records = append(records, record)
+		}
+	}
+
+	s.restore(records)
+	return nil
+}
+
+func (s *InMemoryStore) All() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Record{}, s.records...)
+}
+
+func (s *InMemoryStore) update(id uuid.UUID, apply func(*Record)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, found := s.index[id]
+	if !found {
+		return fmt.Errorf("outbox record %s not found", id)
+	}
+
+	apply(&s.records[position])
+	return nil
+}
+
+func (s *InMemoryStore) find(id uuid.UUID) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	position, found := s.index[id]
+	if !found {
+		return Record{}, false
+	}
+
+	return s.records[position], true
+}
+
+func (s *InMemoryStore) pruneDelivered() {
+	var undelivered []Record
+	for _, record := range s.records {
+		if !record.IsDelivered() {
+			undelivered = append(undelivered, record)
+		}
+	}
+
+	s.restore(undelivered)
+	s.delivered = 0
+}
+
+func (s *InMemoryStore) snapshot() []Record {
+	return append([]Record{}, s.records...)
+}
+
+func (s *InMemoryStore) restore(records []Record) {
+	s.records = records
+	s.index = map[uuid.UUID]int{}
+	for position, record := range records {
+		s.index[record.Id] = position
+	}
+}
@@ -0,0 +1,168 @@
+package promotions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+)
+
+type StaticPromotionCatalog struct {
+	coupons   map[domain.CouponCode]domain.Coupon
+	automatic []domain.Promotion
+}
+
+func NewStaticPromotionCatalog(coupons []domain.Coupon, automatic []domain.Promotion) (*StaticPromotionCatalog, error) {
+	catalog := &StaticPromotionCatalog{
+		coupons:   map[domain.CouponCode]domain.Coupon{},
+		automatic: append([]domain.Promotion{}, automatic...),
+	}
+
+	for _, coupon := range coupons {
+		if _, found := catalog.coupons[coupon.GetCode()]; found {
+			return nil, fmt.Errorf("duplicated coupon %s", coupon.GetCode())
+		}
+		catalog.coupons[coupon.GetCode()] = coupon
+	}
+
+	return catalog, nil
+}
+
+type catalogDefinition struct {
+	Coupons   []promotionDefinition `json:"coupons"`
+	Automatic []promotionDefinition `json:"automatic"`
+}
+
+type promotionDefinition struct {
+	Code         string           `json:"code"`
+	Name         string           `json:"name"`
+	Type         string           `json:"type"`
+	Percent      int              `json:"percent"`
+	Amount       string           `json:"amount"`
+	Currency     string           `json:"currency"`
+	ProductId    string           `json:"product_id"`
+	Buy          int              `json:"buy"`
+	Free         int              `json:"free"`
+	Tiers        []tierDefinition `json:"tiers"`
+	MinimumSpend string           `json:"minimum_spend"`
+}
+
+type tierDefinition struct {
+	MinQuantity int `json:"min_quantity"`
+	Percent     int `json:"percent"`
+}
+
+func NewFilePromotionCatalog(path string) (*StaticPromotionCatalog, error) {
+	if path == "" {
+		return nil, errors.New("promotions file path was empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition catalogDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+
+	var coupons []domain.Coupon
+	for _, couponDefinition := range definition.Coupons {
+		code, err := domain.NewCouponCode(couponDefinition.Code)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", err, couponDefinition.Code)
+		}
+
+		couponDefinition.Name = string(code)
+		promotion, err := newPromotion(couponDefinition)
+		if err != nil {
+			return nil, err
+		}
+
+		coupon, err := domain.NewCoupon(code, promotion)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, coupon)
+	}
+
+	var automatic []domain.Promotion
+	for _, promotionDefinition := range definition.Automatic {
+		promotion, err := newPromotion(promotionDefinition)
+		if err != nil {
+			return nil, err
+		}
+		automatic = append(automatic, promotion)
+	}
+
+	return NewStaticPromotionCatalog(coupons, automatic)
+}
+
+func (c *StaticPromotionCatalog) FindCoupon(code domain.CouponCode) (domain.Coupon, error) {
+	if coupon, found := c.coupons[code]; found {
+		return coupon, nil
+	}
+
+	return domain.Coupon{}, errors.New("coupon not found")
+}
+
+func (c *StaticPromotionCatalog) GetAutomaticPromotions() []domain.Promotion {
+	return append([]domain.Promotion{}, c.automatic...)
+}
+
+func newPromotion(definition promotionDefinition) (domain.Promotion, error) {
+	currency := domain.DefaultCurrency
+	if definition.Currency != "" {
+		var err error
+		if currency, err = domain.NewCurrency(definition.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	var promotion domain.Promotion
+	var err error
+	switch definition.Type {
+	case "percentage_off":
+		promotion, err = domain.NewPercentageOff(definition.Name, definition.Percent)
+	case "fixed_amount_off":
+		var amount domain.Money
+		if amount, err = domain.ParseMoney(definition.Amount, currency); err == nil {
+			promotion, err = domain.NewFixedAmountOff(definition.Name, amount)
+		}
+	case "buy_x_get_y":
+		var productId uuid.UUID
+		if productId, err = uuid.Parse(definition.ProductId); err == nil {
+			promotion, err = domain.NewBuyXGetY(definition.Name, domain.ProductId(productId), definition.Buy, definition.Free)
+		}
+	case "tiered_quantity":
+		var productId uuid.UUID
+		if productId, err = uuid.Parse(definition.ProductId); err == nil {
+			var tiers []domain.QuantityTier
+			for _, tier := range definition.Tiers {
+				tiers = append(tiers, domain.QuantityTier{MinQuantity: tier.MinQuantity, Percent: tier.Percent})
+			}
+			promotion, err = domain.NewTieredQuantityDiscount(definition.Name, domain.ProductId(productId), tiers...)
+		}
+	default:
+		err = fmt.Errorf("unknown promotion type %q", definition.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("promotion %q: %w", definition.Name, err)
+	}
+
+	if definition.MinimumSpend == "" {
+		return promotion, nil
+	}
+
+	minimum, err := domain.ParseMoney(definition.MinimumSpend, currency)
+	if err != nil {
+		return nil, fmt.Errorf("promotion %q: %w", definition.Name, err)
+	}
+
+	return domain.NewMinimumSpend(minimum, promotion)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+)
+
+func NewFileProductRepository(directory string, options ...RepositoryOption) (domain.ProductRepository, error) {
+	repository := newInMemoryProductRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "products", (*domain.Product).ToMemento, domain.RestoreProduct, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func NewFileCustomerRepository(directory string, options ...RepositoryOption) (domain.CustomerRepository, error) {
+	repository := newInMemoryCustomerRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "customers", (*domain.Customer).ToMemento, domain.RestoreCustomer, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func NewFileCartRepository(directory string, options ...RepositoryOption) (domain.CartRepository, error) {
+	repository := newInMemoryCartRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "carts", (*domain.Cart).ToMemento, domain.RestoreCart, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func NewFileOrderRepository(directory string, options ...RepositoryOption) (domain.OrderRepository, error) {
+	repository := newInMemoryOrderRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "orders", (*domain.Order).ToMemento, domain.RestoreOrder, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func NewFileQuoteRepository(directory string, options ...RepositoryOption) (domain.QuoteRepository, error) {
+	repository := newInMemoryQuoteRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "quotes", (*domain.Quote).ToMemento, domain.RestoreQuote, options)
+	if err != nil {
+		return nil, err
+	}
+
+	repository.resumeSequence()
+	return repository, nil
+}
+
+func NewFileStockRepository(directory string, options ...RepositoryOption) (domain.StockRepository, error) {
+	repository := newInMemoryStockRepository(options...)
+	err := attachWriteAheadLog(repository.inMemoryBaseRepository, directory, "stock", (*domain.StockItem).ToMemento, domain.RestoreStockItem, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return repository, nil
+}
+
+func attachWriteAheadLog[K comparable, E domain.Entity[K], M any](repository *inMemoryBaseRepository[K, E], directory string, name string, toMemento func(E) M, restore func(M) (E, error), options []RepositoryOption) error {
+	wal, entities, unpublished, err := openWriteAheadLog(directory, name, newRepositoryOptions(options).snapshotEvery, toMemento, restore)
+	if err != nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		if err := repository.restore(entity); err != nil {
+			return err
+		}
+	}
+
+	if repository.outbox != nil && len(unpublished) > 0 {
+		if err := outbox.AppendMissing(repository.outbox, unpublished...); err != nil {
+			return err
+		}
+	}
+
+	repository.journal = wal
+	return nil
+}
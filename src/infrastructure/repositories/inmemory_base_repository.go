@@ -2,24 +2,253 @@ package repositories
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
 )
 
+type RepositoryOption func(*repositoryOptions)
+
+type repositoryOptions struct {
+	outbox        outbox.Store
+	snapshotEvery int
+}
+
+func WithOutbox(store outbox.Store) RepositoryOption {
+	return func(options *repositoryOptions) {
+		options.outbox = store
+	}
+}
+
+func WithSnapshotEvery(commits int) RepositoryOption {
+	return func(options *repositoryOptions) {
+		options.snapshotEvery = commits
+	}
+}
+
+type journal[E any] interface {
+	commit(entity E, records []outbox.Record, publish func() error) error
+}
+
 type inMemoryBaseRepository[K comparable, E domain.Entity[K]] struct {
-	entities map[K]E
+	mu            sync.RWMutex
+	clone         func(E) E
+	entities      map[K]E
+	outbox        outbox.Store
+	journal       journal[E]
+	uniqueIndexes []*uniqueIndex[K, E]
+	indexes       map[string]*secondaryIndex[K, E]
+}
+
+func newRepositoryOptions(options []RepositoryOption) repositoryOptions {
+	var config repositoryOptions
+	for _, option := range options {
+		option(&config)
+	}
+
+	return config
+}
+
+func newInMemoryBaseRepository[K comparable, E domain.Entity[K]](clone func(E) E, options ...RepositoryOption) *inMemoryBaseRepository[K, E] {
+	config := newRepositoryOptions(options)
+
+	return &inMemoryBaseRepository[K, E]{
+		clone:    clone,
+		entities: map[K]E{},
+		outbox:   config.outbox,
+		indexes:  map[string]*secondaryIndex[K, E]{},
+	}
 }
 
 func (i *inMemoryBaseRepository[K, E]) FindByID(key K) (E, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
 	var entity E
 	if entity, found := i.entities[key]; found {
-		return entity, nil
+		return i.clone(entity), nil
 	}
 
 	return entity, errors.New("entity not found")
 }
 
 func (i *inMemoryBaseRepository[K, E]) Save(entity E) error {
-	i.entities[entity.GetID()] = entity
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.save(entity)
+}
+
+func (i *inMemoryBaseRepository[K, E]) Delete(key K) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, found := i.entities[key]; !found {
+		return errors.New("entity not found")
+	}
+
+	if i.journal != nil {
+		return errors.New("delete is not supported by file repositories")
+	}
+
+	delete(i.entities, key)
+	for _, index := range i.uniqueIndexes {
+		index.remove(key)
+	}
+	for _, index := range i.indexes {
+		index.remove(key)
+	}
+
+	return nil
+}
+
+func (i *inMemoryBaseRepository[K, E]) save(entity E) error {
+	currentVersion := 0
+	if stored, found := i.entities[entity.GetID()]; found {
+		currentVersion = stored.GetVersion()
+	}
+
+	if entity.GetVersion() != currentVersion {
+		return &domain.ConcurrencyConflictError{
+			Id:              fmt.Sprint(entity.GetID()),
+			ExpectedVersion: entity.GetVersion(),
+			ActualVersion:   currentVersion,
+		}
+	}
+
+	for _, index := range i.uniqueIndexes {
+		if err := index.check(entity); err != nil {
+			return err
+		}
+	}
+
+	records, err := newOutboxRecords[K](i.outbox, entity)
+	if err != nil {
+		return err
+	}
+
+	publish := func() error {
+		return appendRecords(i.outbox, records)
+	}
+
+	entity.SetVersion(currentVersion + 1)
+	if i.journal != nil {
+		err = i.journal.commit(entity, records, publish)
+	} else {
+		err = publish()
+	}
+	if err != nil {
+		entity.SetVersion(currentVersion)
+		return err
+	}
+
+	i.store(entity)
+	return nil
+}
+
+func (i *inMemoryBaseRepository[K, E]) restore(entity E) error {
+	for _, index := range i.uniqueIndexes {
+		if err := index.check(entity); err != nil {
+			return err
+		}
+	}
+
+	i.store(entity)
 	return nil
 }
+
+func (i *inMemoryBaseRepository[K, E]) store(entity E) {
+	stored := i.clone(entity)
+	stored.ClearDomainEvents()
+	i.entities[entity.GetID()] = stored
+	for _, index := range i.uniqueIndexes {
+		index.update(stored)
+	}
+	for _, index := range i.indexes {
+		index.update(stored)
+	}
+}
+
+func (i *inMemoryBaseRepository[K, E]) findAll(matches func(E) bool) []E {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var entities []E
+	for _, entity := range i.entities {
+		if matches(entity) {
+			entities = append(entities, i.clone(entity))
+		}
+	}
+
+	return entities
+}
+
+func (i *inMemoryBaseRepository[K, E]) findByIndex(name string, key string) []E {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var entities []E
+	for _, id := range i.indexes[name].lookup(key) {
+		entities = append(entities, i.clone(i.entities[id]))
+	}
+
+	return entities
+}
+
+func (i *inMemoryBaseRepository[K, E]) findByUniqueIndex(field string, key string) (E, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var entity E
+	if key == "" {
+		return entity, errors.New("entity not found")
+	}
+
+	for _, index := range i.uniqueIndexes {
+		if index.field != field {
+			continue
+		}
+
+		if id, found := index.lookup(key); found {
+			return i.clone(i.entities[id]), nil
+		}
+	}
+
+	return entity, errors.New("entity not found")
+}
+
+func (i *inMemoryBaseRepository[K, E]) addIndex(name string, key func(E) string) {
+	i.indexes[name] = newSecondaryIndex[K, E](key)
+}
+
+func (i *inMemoryBaseRepository[K, E]) addUniqueIndex(field string, key func(E) string) {
+	i.uniqueIndexes = append(i.uniqueIndexes, newUniqueIndex[K, E](field, key))
+}
+
+func appendToOutbox[K comparable, E domain.Entity[K]](store outbox.Store, entity E) ([]outbox.Record, error) {
+	records, err := newOutboxRecords[K](store, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, appendRecords(store, records)
+}
+
+func newOutboxRecords[K comparable, E domain.Entity[K]](store outbox.Store, entity E) ([]outbox.Record, error) {
+	if store == nil {
+		return nil, nil
+	}
+
+	return outbox.NewRecords(fmt.Sprint(entity.GetID()), entity.GetDomainEvents(), time.Now().UTC())
+}
+
+func appendRecords(store outbox.Store, records []outbox.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	return store.Append(records...)
+}
@@ -1,32 +1,57 @@
 package repositories
 
 import (
+	"sort"
+	"time"
+
 	"github.com/bitlogic/go-startup/src/domain"
 )
 
+const cartCustomerIndex = "customer_id"
+
 type InMemoryCartRepository struct {
 	*inMemoryBaseRepository[domain.CartId, *domain.Cart]
-	customerIndex map[domain.CustomerId][]*domain.Cart
 }
 
-func (i *InMemoryCartRepository) Save(entity *domain.Cart) error {
-	if err := i.inMemoryBaseRepository.Save(entity); err != nil {
-		return err
-	}
+func (i *InMemoryCartRepository) GetCustomerCarts(customerId domain.CustomerId) ([]*domain.Cart, error) {
+	return i.findByIndex(cartCustomerIndex, customerId.String()), nil
+}
+
+func (i *InMemoryCartRepository) FindIdleCarts(lastActivityBefore time.Time) ([]*domain.Cart, error) {
+	carts := i.findAll(func(cart *domain.Cart) bool {
+		status := cart.GetStatus()
+		if status != domain.CartStatusActive && status != domain.CartStatusAbandoned {
+			return false
+		}
 
-	i.customerIndex[entity.GetCustomerID()] = append(i.customerIndex[entity.GetCustomerID()], entity)
-	return nil
+		return cart.GetLastActivityAt().Before(lastActivityBefore)
+	})
+
+	sort.Slice(carts, func(a, b int) bool {
+		return carts[a].GetLastActivityAt().Before(carts[b].GetLastActivityAt())
+	})
+
+	return carts, nil
 }
 
-func (i *InMemoryCartRepository) GetCustomerCarts(customerId domain.CustomerId) []*domain.Cart {
-	return i.customerIndex[customerId]
+func NewInMemoryCartRepository(options ...RepositoryOption) domain.CartRepository {
+	return newInMemoryCartRepository(options...)
 }
 
-func NewInMemoryCartRepository() domain.CartRepository {
-	return &InMemoryCartRepository{
-		inMemoryBaseRepository: &inMemoryBaseRepository[domain.CartId, *domain.Cart]{
-			entities: map[domain.CartId]*domain.Cart{},
-		},
-		customerIndex: map[domain.CustomerId][]*domain.Cart{},
+func newInMemoryCartRepository(options ...RepositoryOption) *InMemoryCartRepository {
+	repository := &InMemoryCartRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.CartId, *domain.Cart]((*domain.Cart).Clone, options...),
 	}
+	repository.addUniqueIndex("active_cart", func(cart *domain.Cart) string {
+		if !cart.IsActive() {
+			return ""
+		}
+
+		return cart.GetCustomerID().String()
+	})
+	repository.addIndex(cartCustomerIndex, func(cart *domain.Cart) string {
+		return cart.GetCustomerID().String()
+	})
+
+	return repository
 }
@@ -8,10 +8,17 @@ type InMemoryCustomerRepository struct {
 	*inMemoryBaseRepository[domain.CustomerId, *domain.Customer]
 }
 
-func NewInMemoryCustomerRepository() domain.CustomerRepository {
-	return &InMemoryCustomerRepository{
-		inMemoryBaseRepository: &inMemoryBaseRepository[domain.CustomerId, *domain.Customer]{
-			entities: map[domain.CustomerId]*domain.Customer{},
-		},
+func NewInMemoryCustomerRepository(options ...RepositoryOption) domain.CustomerRepository {
+	return newInMemoryCustomerRepository(options...)
+}
+
+func newInMemoryCustomerRepository(options ...RepositoryOption) *InMemoryCustomerRepository {
+	repository := &InMemoryCustomerRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.CustomerId, *domain.Customer]((*domain.Customer).Clone, options...),
 	}
+	repository.addUniqueIndex("email", func(customer *domain.Customer) string {
+		return string(customer.GetEmail())
+	})
+
+	return repository
 }
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type InMemoryOrderRepository struct {
+	*inMemoryBaseRepository[domain.OrderId, *domain.Order]
+}
+
+func NewInMemoryOrderRepository(options ...RepositoryOption) domain.OrderRepository {
+	return newInMemoryOrderRepository(options...)
+}
+
+func newInMemoryOrderRepository(options ...RepositoryOption) *InMemoryOrderRepository {
+	return &InMemoryOrderRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.OrderId, *domain.Order]((*domain.Order).Clone, options...),
+	}
+}
@@ -1,17 +1,200 @@
 package repositories
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+
 	"github.com/bitlogic/go-startup/src/domain"
 )
 
+const (
+	defaultProductPageSize = 20
+	maxProductPageSize     = 100
+)
+
 type InMemoryProductRepository struct {
 	*inMemoryBaseRepository[domain.ProductId, *domain.Product]
 }
 
-func NewInMemoryProductRepository() domain.ProductRepository {
-	return &InMemoryProductRepository{
-		inMemoryBaseRepository: &inMemoryBaseRepository[domain.ProductId, *domain.Product]{
-			entities: map[domain.ProductId]*domain.Product{},
-		},
+func NewInMemoryProductRepository(options ...RepositoryOption) domain.ProductRepository {
+	return newInMemoryProductRepository(options...)
+}
+
+func newInMemoryProductRepository(options ...RepositoryOption) *InMemoryProductRepository {
+	repository := &InMemoryProductRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.ProductId, *domain.Product]((*domain.Product).Clone, options...),
+	}
+	repository.addUniqueIndex("product_name", func(product *domain.Product) string {
+		return strings.ToLower(product.GetName())
+	})
+	repository.addUniqueIndex("sku", func(product *domain.Product) string {
+		return string(product.GetSKU())
+	})
+
+	return repository
+}
+
+func (i *InMemoryProductRepository) FindBySKU(sku domain.SKU) (*domain.Product, error) {
+	return i.findByUniqueIndex("sku", string(sku))
+}
+
+func (i *InMemoryProductRepository) FindByName(name string) (*domain.Product, error) {
+	return i.findByUniqueIndex("product_name", strings.ToLower(strings.TrimSpace(name)))
+}
+
+func (i *InMemoryProductRepository) List(query domain.ProductListQuery) (domain.ProductPage, error) {
+	query, after, err := prepareProductListQuery(query)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+
+	products := i.findAll(func(product *domain.Product) bool {
+		return matchesProductQuery(product, query)
+	})
+
+	sort.Slice(products, func(a, b int) bool {
+		return compareProductKeys(query, newProductCursor(query, products[a]), newProductCursor(query, products[b])) < 0
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(products), func(position int) bool {
+			return compareProductKeys(query, newProductCursor(query, products[position]), *after) > 0
+		})
+	}
+
+	end := start + query.Limit
+	if end > len(products) {
+		end = len(products)
+	}
+
+	page := domain.ProductPage{
+		Products: products[start:end],
+	}
+	if end < len(products) {
+		page.NextCursor = newProductCursor(query, products[end-1]).encode()
+	}
+
+	return page, nil
+}
+
+func prepareProductListQuery(query domain.ProductListQuery) (domain.ProductListQuery, *productCursor, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.SortProductsByName
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultProductPageSize
+	}
+
+	if query.Limit > maxProductPageSize {
+		query.Limit = maxProductPageSize
+	}
+
+	if query.Cursor == "" {
+		return query, nil, nil
+	}
+
+	cursor, err := decodeProductCursor(query.Cursor)
+	if err != nil || cursor.SortBy != query.SortBy || cursor.Descending != query.Descending {
+		return query, nil, domain.ErrInvalidCursor
+	}
+
+	return query, &cursor, nil
+}
+
+func matchesProductQuery(product *domain.Product, query domain.ProductListQuery) bool {
+	if product.IsArchived() && !query.IncludeArchived {
+		return false
+	}
+
+	if query.NameContains != "" && !strings.Contains(strings.ToLower(product.GetName()), strings.ToLower(query.NameContains)) {
+		return false
+	}
+
+	if query.MinPrice != nil {
+		if comparison, err := product.GetPrice().Compare(*query.MinPrice); err != nil || comparison < 0 {
+			return false
+		}
+	}
+
+	if query.MaxPrice != nil {
+		if comparison, err := product.GetPrice().Compare(*query.MaxPrice); err != nil || comparison > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+type productCursor struct {
+	SortBy     domain.ProductSortField `json:"s"`
+	Descending bool                    `json:"d,omitempty"`
+	Name       string                  `json:"n"`
+	Currency   domain.Currency         `json:"c"`
+	Price      int64                   `json:"p"`
+	Id         string                  `json:"i"`
+}
+
+func newProductCursor(query domain.ProductListQuery, product *domain.Product) productCursor {
+	return productCursor{
+		SortBy:     query.SortBy,
+		Descending: query.Descending,
+		Name:       strings.ToLower(product.GetName()),
+		Currency:   product.GetPrice().Currency(),
+		Price:      product.GetPrice().MinorUnits(),
+		Id:         product.GetID().String(),
+	}
+}
+
+func decodeProductCursor(encoded string) (productCursor, error) {
+	var cursor productCursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+func (c productCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func compareProductKeys(query domain.ProductListQuery, a productCursor, b productCursor) int {
+	result := 0
+	switch query.SortBy {
+	case domain.SortProductsByPrice:
+		result = strings.Compare(string(a.Currency), string(b.Currency))
+		if result == 0 {
+			result = compareInts(a.Price, b.Price)
+		}
+	default:
+		result = strings.Compare(a.Name, b.Name)
+	}
+
+	if result == 0 {
+		result = strings.Compare(a.Id, b.Id)
+	}
+
+	if query.Descending {
+		return -result
+	}
+
+	return result
+}
+
+func compareInts(a int64, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
 	}
 }
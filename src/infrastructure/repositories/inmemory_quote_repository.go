@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type InMemoryQuoteRepository struct {
+	*inMemoryBaseRepository[domain.QuoteId, *domain.Quote]
+	sequence int64
+}
+
+func NewInMemoryQuoteRepository(options ...RepositoryOption) domain.QuoteRepository {
+	return newInMemoryQuoteRepository(options...)
+}
+
+func newInMemoryQuoteRepository(options ...RepositoryOption) *InMemoryQuoteRepository {
+	repository := &InMemoryQuoteRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.QuoteId, *domain.Quote]((*domain.Quote).Clone, options...),
+	}
+	repository.addUniqueIndex("number", (*domain.Quote).GetNumber)
+
+	return repository
+}
+
+func (r *InMemoryQuoteRepository) NextQuoteNumber(issuedAt time.Time) (string, error) {
+	return formatQuoteNumber(issuedAt, atomic.LoadInt64(&r.sequence)+1), nil
+}
+
+func (r *InMemoryQuoteRepository) Save(quote *domain.Quote) error {
+	if err := r.inMemoryBaseRepository.Save(quote); err != nil {
+		return err
+	}
+
+	r.advanceSequence(parseQuoteSequence(quote.GetNumber()))
+	return nil
+}
+
+func (r *InMemoryQuoteRepository) FindExpiredQuotes(now time.Time) ([]*domain.Quote, error) {
+	quotes := r.findAll(func(quote *domain.Quote) bool {
+		return quote.GetStatus() == domain.QuoteStatusPending && !now.Before(quote.GetExpiresAt())
+	})
+
+	sort.Slice(quotes, func(a, b int) bool {
+		return quotes[a].GetExpiresAt().Before(quotes[b].GetExpiresAt())
+	})
+
+	return quotes, nil
+}
+
+func (r *InMemoryQuoteRepository) resumeSequence() {
+	for _, quote := range r.findAll(func(*domain.Quote) bool { return true }) {
+		r.advanceSequence(parseQuoteSequence(quote.GetNumber()))
+	}
+}
+
+func (r *InMemoryQuoteRepository) advanceSequence(sequence int64) {
+	for {
+		current := atomic.LoadInt64(&r.sequence)
+		if sequence <= current || atomic.CompareAndSwapInt64(&r.sequence, current, sequence) {
+			return
+		}
+	}
+}
+
+func formatQuoteNumber(issuedAt time.Time, sequence int64) string {
+	return fmt.Sprintf("Q-%s-%06d", issuedAt.UTC().Format("20060102"), sequence)
+}
+
+func parseQuoteSequence(number string) int64 {
+	sequence, err := strconv.ParseInt(number[strings.LastIndex(number, "-")+1:], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return sequence
+}
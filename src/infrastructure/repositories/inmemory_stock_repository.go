@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"sort"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type InMemoryStockRepository struct {
+	*inMemoryBaseRepository[domain.ProductId, *domain.StockItem]
+}
+
+func (i *InMemoryStockRepository) GetCartReservations(cartId domain.CartId) ([]*domain.StockItem, error) {
+	stockItems := i.findAll(func(stockItem *domain.StockItem) bool {
+		return stockItem.GetReservedFor(cartId) > 0
+	})
+
+	sort.Slice(stockItems, func(a, b int) bool {
+		return stockItems[a].GetID().String() < stockItems[b].GetID().String()
+	})
+
+	return stockItems, nil
+}
+
+func NewInMemoryStockRepository(options ...RepositoryOption) domain.StockRepository {
+	return newInMemoryStockRepository(options...)
+}
+
+func newInMemoryStockRepository(options ...RepositoryOption) *InMemoryStockRepository {
+	return &InMemoryStockRepository{
+		inMemoryBaseRepository: newInMemoryBaseRepository[domain.ProductId, *domain.StockItem]((*domain.StockItem).Clone, options...),
+	}
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type secondaryIndex[K comparable, E domain.Entity[K]] struct {
+	key     func(E) string
+	members map[string][]K
+	keys    map[K]string
+}
+
+func newSecondaryIndex[K comparable, E domain.Entity[K]](key func(E) string) *secondaryIndex[K, E] {
+	return &secondaryIndex[K, E]{
+		key:     key,
+		members: map[string][]K{},
+		keys:    map[K]string{},
+	}
+}
+
+func (s *secondaryIndex[K, E]) update(entity E) {
+	id := entity.GetID()
+	key := s.key(entity)
+	if previousKey, found := s.keys[id]; found {
+		if previousKey == key {
+			return
+		}
+		s.remove(id)
+	}
+
+	if key != "" {
+		s.members[key] = append(s.members[key], id)
+		s.keys[id] = key
+	}
+}
+
+func (s *secondaryIndex[K, E]) remove(id K) {
+	key, found := s.keys[id]
+	if !found {
+		return
+	}
+
+	members := s.members[key]
+	for position, member := range members {
+		if member == id {
+			members = append(members[:position:position], members[position+1:]...)
+			break
+		}
+	}
+
+	if len(members) == 0 {
+		delete(s.members, key)
+	} else {
+		s.members[key] = members
+	}
+	delete(s.keys, id)
+}
+
+func (s *secondaryIndex[K, E]) lookup(key string) []K {
+	return s.members[key]
+}
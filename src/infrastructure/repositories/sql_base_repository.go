@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+)
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+type transactionalOutbox interface {
+	AppendTx(tx *sql.Tx, records ...outbox.Record) error
+}
+
+type sqlBaseRepository struct {
+	db     *sql.DB
+	outbox outbox.Store
+}
+
+func newSQLBaseRepository(db *sql.DB, options []RepositoryOption) (*sqlBaseRepository, error) {
+	if db == nil {
+		return nil, errors.New("db was nil")
+	}
+
+	config := newRepositoryOptions(options)
+
+	return &sqlBaseRepository{
+		db:     db,
+		outbox: config.outbox,
+	}, nil
+}
+
+func (s *sqlBaseRepository) inTransaction(work func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := work(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func saveInTransaction[K comparable, E domain.Entity[K]](s *sqlBaseRepository, entity E, work func(tx *sql.Tx) error) error {
+	transactional, appendsInTransaction := s.outbox.(transactionalOutbox)
+	err := s.inTransaction(func(tx *sql.Tx) error {
+		if err := work(tx); err != nil {
+			return err
+		}
+
+		if !appendsInTransaction {
+			return nil
+		}
+
+		records, err := outbox.NewRecords(fmt.Sprint(entity.GetID()), entity.GetDomainEvents(), time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		return transactional.AppendTx(tx, records...)
+	})
+	if err != nil {
+		return err
+	}
+
+	entity.SetVersion(entity.GetVersion() + 1)
+	if appendsInTransaction {
+		return nil
+	}
+
+	_, err = appendToOutbox[K](s.outbox, entity)
+	return err
+}
+
+func checkUniqueColumn(tx *sql.Tx, table string, column string, field string, value string, id string) error {
+	if value == "" {
+		return nil
+	}
+
+	var owner string
+	err := tx.QueryRow(fmt.Sprintf(`SELECT id FROM %s WHERE %s = ? AND id <> ?`, table, column), value, id).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &domain.UniqueConstraintError{Field: field, Value: value}
+}
+
+func saveVersionedRow(tx *sql.Tx, table string, columns string, values []any, expectedVersion int) error {
+	names := strings.Split(columns, ", ")
+	id := fmt.Sprint(values[0])
+
+	var result sql.Result
+	var err error
+	if expectedVersion == 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+		result, err = tx.Exec(fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (id) DO NOTHING`, table, columns, placeholders), values...)
+	} else {
+		var assignments []string
+		for _, name := range names[1:] {
+			assignments = append(assignments, name+" = ?")
+		}
+
+		args := append(append([]any{}, values[1:]...), id, expectedVersion)
+		result, err = tx.Exec(fmt.Sprintf(`UPDATE %s SET %s WHERE id = ? AND version = ?`, table, strings.Join(assignments, ", ")), args...)
+	}
+	if err != nil {
+		return err
+	}
+
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if saved == 0 {
+		return versionConflict(tx, table, id, expectedVersion)
+	}
+
+	return nil
+}
+
+func versionConflict(tx *sql.Tx, table string, id string, expected int) error {
+	current := 0
+	err := tx.QueryRow(fmt.Sprintf(`SELECT version FROM %s WHERE id = ?`, table), id).Scan(&current)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return &domain.ConcurrencyConflictError{Id: id, ExpectedVersion: expected, ActualVersion: current}
+}
+
+func shippingValues(memento *domain.ShippingMemento) (sql.NullString, sql.NullString, sql.NullInt64, sql.NullString) {
+	if memento == nil {
+		return sql.NullString{}, sql.NullString{}, sql.NullInt64{}, sql.NullString{}
+	}
+
+	return sql.NullString{String: string(memento.Method), Valid: true},
+		sql.NullString{String: memento.Name, Valid: true},
+		sql.NullInt64{Int64: memento.Cost.MinorUnits(), Valid: true},
+		sql.NullString{String: string(memento.Cost.Currency()), Valid: true}
+}
+
+func restoreShippingMemento(method sql.NullString, name sql.NullString, cost sql.NullInt64, currency sql.NullString) *domain.ShippingMemento {
+	if !method.Valid {
+		return nil
+	}
+
+	return &domain.ShippingMemento{
+		Method: domain.ShippingMethodCode(method.String),
+		Name:   name.String,
+		Cost:   domain.NewMoney(cost.Int64, domain.Currency(currency.String)),
+	}
+}
+
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func parseID(text string, id encoding.TextUnmarshaler) error {
+	return id.UnmarshalText([]byte(text))
+}
+
+func restoreDimensions(length int, width int, height int) (domain.Dimensions, error) {
+	if length == 0 && width == 0 && height == 0 {
+		return domain.Dimensions{}, nil
+	}
+
+	return domain.NewDimensions(length, width, height)
+}
+
+func restoreAddress(street string, city string, postalCode string, country string) (domain.Address, error) {
+	if street == "" && city == "" && postalCode == "" && country == "" {
+		return domain.Address{}, nil
+	}
+
+	return domain.NewAddress(street, city, postalCode, country)
+}
+
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
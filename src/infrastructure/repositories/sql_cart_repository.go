@@ -0,0 +1,312 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const cartColumns = `id, customer_id, currency, status, last_activity_at, shipping_method, shipping_name, shipping_cost, shipping_currency, tax_region, adjustments, taxed, taxes, version`
+
+const cartItemColumns = `product_id, currency, unit_price, added_currency, added_unit_price, exchange_rate, tax_category, weight_grams, length_mm, width_mm, height_mm, quantity`
+
+type SQLCartRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLCartRepository(db *sql.DB, options ...RepositoryOption) (domain.CartRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLCartRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLCartRepository) FindByID(id domain.CartId) (*domain.Cart, error) {
+	carts, err := r.findMany(`WHERE id = ?`, id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(carts) == 0 {
+		return nil, errors.New("entity not found")
+	}
+
+	return carts[0], nil
+}
+
+func (r *SQLCartRepository) GetCustomerCarts(customerId domain.CustomerId) ([]*domain.Cart, error) {
+	return r.findMany(`WHERE customer_id = ? ORDER BY rowid`, customerId.String())
+}
+
+func (r *SQLCartRepository) FindIdleCarts(lastActivityBefore time.Time) ([]*domain.Cart, error) {
+	return r.findMany(`WHERE status IN (?, ?) AND last_activity_at < ? ORDER BY last_activity_at`, string(domain.CartStatusActive), string(domain.CartStatusAbandoned), lastActivityBefore.UnixNano())
+}
+
+func (r *SQLCartRepository) Save(cart *domain.Cart) error {
+	memento := cart.ToMemento()
+	id := memento.Id.String()
+
+	adjustments, err := json.Marshal(memento.Adjustments)
+	if err != nil {
+		return err
+	}
+
+	taxes, err := json.Marshal(memento.Taxes)
+	if err != nil {
+		return err
+	}
+
+	shippingMethod, shippingName, shippingCost, shippingCurrency := shippingValues(memento.Shipping)
+
+	return saveInTransaction[domain.CartId](r.sqlBaseRepository, cart, func(tx *sql.Tx) error {
+		if err := checkActiveCart(tx, memento); err != nil {
+			return err
+		}
+
+		err := saveVersionedRow(tx, "carts", cartColumns, []any{
+			id,
+			memento.CustomerId.String(),
+			string(memento.Currency),
+			string(memento.Status),
+			memento.LastActivityAt.UnixNano(),
+			shippingMethod,
+			shippingName,
+			shippingCost,
+			shippingCurrency,
+			string(memento.TaxRegion),
+			string(adjustments),
+			memento.Taxed,
+			string(taxes),
+			memento.Version + 1,
+		}, memento.Version)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM cart_items WHERE cart_id = ?`, id); err != nil {
+			return err
+		}
+
+		for _, item := range memento.Items {
+			_, err := tx.Exec(`INSERT INTO cart_items (cart_id, `+cartItemColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				id,
+				item.ProductId.String(),
+				string(item.UnitPrice.Currency()),
+				item.UnitPrice.MinorUnits(),
+				string(item.AddedUnitPrice.Currency()),
+				item.AddedUnitPrice.MinorUnits(),
+				item.ExchangeRate.RatString(),
+				string(item.TaxCategory),
+				item.Weight.Grams(),
+				item.Dimensions.GetLength(),
+				item.Dimensions.GetWidth(),
+				item.Dimensions.GetHeight(),
+				item.Quantity,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(`DELETE FROM cart_coupons WHERE cart_id = ?`, id); err != nil {
+			return err
+		}
+
+		for position, code := range memento.Coupons {
+			if _, err := tx.Exec(`INSERT INTO cart_coupons (cart_id, position, code) VALUES (?, ?, ?)`, id, position, string(code)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *SQLCartRepository) findMany(where string, args ...any) ([]*domain.Cart, error) {
+	var mementos []domain.CartMemento
+	err := r.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		if mementos, err = findCartMementos(tx, where, args...); err != nil {
+			return err
+		}
+
+		for position := range mementos {
+			if mementos[position].Items, err = findCartItems(tx, mementos[position].Id); err != nil {
+				return err
+			}
+
+			if mementos[position].Coupons, err = findCartCoupons(tx, mementos[position].Id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var carts []*domain.Cart
+	for _, memento := range mementos {
+		cart, err := domain.RestoreCart(memento)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, cart)
+	}
+
+	return carts, nil
+}
+
+func findCartMementos(tx *sql.Tx, where string, args ...any) ([]domain.CartMemento, error) {
+	rows, err := tx.Query(`SELECT `+cartColumns+` FROM carts `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mementos []domain.CartMemento
+	for rows.Next() {
+		memento, err := scanCart(rows)
+		if err != nil {
+			return nil, err
+		}
+		mementos = append(mementos, memento)
+	}
+
+	return mementos, rows.Err()
+}
+
+func findCartItems(tx *sql.Tx, cartId domain.CartId) ([]domain.CartItemMemento, error) {
+	rows, err := tx.Query(`SELECT `+cartItemColumns+` FROM cart_items WHERE cart_id = ? ORDER BY product_id`, cartId.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []domain.CartItemMemento{}
+	for rows.Next() {
+		item, err := scanCartItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func findCartCoupons(tx *sql.Tx, cartId domain.CartId) ([]domain.CouponCode, error) {
+	rows, err := tx.Query(`SELECT code FROM cart_coupons WHERE cart_id = ? ORDER BY position`, cartId.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var coupons []domain.CouponCode
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, domain.CouponCode(code))
+	}
+
+	return coupons, rows.Err()
+}
+
+func scanCart(row rowScanner) (domain.CartMemento, error) {
+	var memento domain.CartMemento
+	var id, customerId, currency, status, taxRegion, adjustments, taxes string
+	var lastActivityAt int64
+	var shippingMethod, shippingName, shippingCurrency sql.NullString
+	var shippingCost sql.NullInt64
+	err := row.Scan(&id, &customerId, &currency, &status, &lastActivityAt, &shippingMethod, &shippingName, &shippingCost, &shippingCurrency, &taxRegion, &adjustments, &memento.Taxed, &taxes, &memento.Version)
+	if err != nil {
+		return memento, err
+	}
+
+	if err := parseID(id, &memento.Id); err != nil {
+		return memento, err
+	}
+
+	if err := parseID(customerId, &memento.CustomerId); err != nil {
+		return memento, err
+	}
+
+	memento.Currency = domain.Currency(currency)
+	memento.Status = domain.CartStatus(status)
+	memento.LastActivityAt = time.Unix(0, lastActivityAt).UTC()
+	memento.TaxRegion = domain.TaxRegion(taxRegion)
+
+	memento.Shipping = restoreShippingMemento(shippingMethod, shippingName, shippingCost, shippingCurrency)
+
+	if err := json.Unmarshal([]byte(adjustments), &memento.Adjustments); err != nil {
+		return memento, err
+	}
+
+	if err := json.Unmarshal([]byte(taxes), &memento.Taxes); err != nil {
+		return memento, err
+	}
+
+	return memento, nil
+}
+
+func scanCartItem(row rowScanner) (domain.CartItemMemento, error) {
+	var item domain.CartItemMemento
+	var productId, currency, addedCurrency, exchangeRate, taxCategory string
+	var unitPrice, addedUnitPrice, weight int64
+	var length, width, height int
+	err := row.Scan(&productId, &currency, &unitPrice, &addedCurrency, &addedUnitPrice, &exchangeRate, &taxCategory, &weight, &length, &width, &height, &item.Quantity)
+	if err != nil {
+		return item, err
+	}
+
+	if err := parseID(productId, &item.ProductId); err != nil {
+		return item, err
+	}
+
+	rate, ok := new(big.Rat).SetString(exchangeRate)
+	if !ok {
+		return item, errors.New("invalid cart item exchange rate")
+	}
+
+	dimensions, err := restoreDimensions(length, width, height)
+	if err != nil {
+		return item, err
+	}
+
+	item.UnitPrice = domain.NewMoney(unitPrice, domain.Currency(currency))
+	item.AddedUnitPrice = domain.NewMoney(addedUnitPrice, domain.Currency(addedCurrency))
+	item.ExchangeRate = rate
+	item.TaxCategory = domain.TaxCategory(taxCategory)
+	item.Weight = domain.Weight(weight)
+	item.Dimensions = dimensions
+
+	return item, nil
+}
+
+func checkActiveCart(tx *sql.Tx, memento domain.CartMemento) error {
+	if memento.Status != domain.CartStatusActive {
+		return nil
+	}
+
+	var owner string
+	err := tx.QueryRow(`SELECT id FROM carts WHERE customer_id = ? AND status = ? AND id <> ?`, memento.CustomerId.String(), string(domain.CartStatusActive), memento.Id.String()).Scan(&owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return &domain.UniqueConstraintError{Field: "active_cart", Value: memento.CustomerId.String()}
+}
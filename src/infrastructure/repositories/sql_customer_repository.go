@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const customerColumns = `id, name, email, phone, shipping_street, shipping_city, shipping_postal_code, shipping_country, billing_street, billing_city, billing_postal_code, billing_country, deactivated, version`
+
+type SQLCustomerRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLCustomerRepository(db *sql.DB, options ...RepositoryOption) (domain.CustomerRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLCustomerRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLCustomerRepository) FindByID(id domain.CustomerId) (*domain.Customer, error) {
+	return r.findOne(`WHERE id = ?`, id.String())
+}
+
+func (r *SQLCustomerRepository) Save(customer *domain.Customer) error {
+	memento := customer.ToMemento()
+	id := memento.Id.String()
+
+	return saveInTransaction[domain.CustomerId](r.sqlBaseRepository, customer, func(tx *sql.Tx) error {
+		if err := checkUniqueColumn(tx, "customers", "email", "email", string(memento.Email), id); err != nil {
+			return err
+		}
+
+		return saveVersionedRow(tx, "customers", customerColumns, []any{
+			id,
+			memento.Name,
+			nullableString(string(memento.Email)),
+			string(memento.Phone),
+			memento.ShippingAddress.GetStreet(),
+			memento.ShippingAddress.GetCity(),
+			memento.ShippingAddress.GetPostalCode(),
+			memento.ShippingAddress.GetCountry(),
+			memento.BillingAddress.GetStreet(),
+			memento.BillingAddress.GetCity(),
+			memento.BillingAddress.GetPostalCode(),
+			memento.BillingAddress.GetCountry(),
+			memento.Deactivated,
+			memento.Version + 1,
+		}, memento.Version)
+	})
+}
+
+func (r *SQLCustomerRepository) findOne(where string, args ...any) (*domain.Customer, error) {
+	customer, err := scanCustomer(r.db.QueryRow(`SELECT `+customerColumns+` FROM customers `+where, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("entity not found")
+	}
+
+	return customer, err
+}
+
+func scanCustomer(row rowScanner) (*domain.Customer, error) {
+	var id, name, phone string
+	var email sql.NullString
+	var shipping, billing [4]string
+	var deactivated bool
+	var version int
+	err := row.Scan(&id, &name, &email, &phone,
+		&shipping[0], &shipping[1], &shipping[2], &shipping[3],
+		&billing[0], &billing[1], &billing[2], &billing[3],
+		&deactivated, &version)
+	if err != nil {
+		return nil, err
+	}
+
+	memento := domain.CustomerMemento{
+		Name:        name,
+		Email:       domain.Email(email.String),
+		Phone:       domain.PhoneNumber(phone),
+		Deactivated: deactivated,
+		Version:     version,
+	}
+
+	if err := parseID(id, &memento.Id); err != nil {
+		return nil, err
+	}
+
+	if memento.ShippingAddress, err = restoreAddress(shipping[0], shipping[1], shipping[2], shipping[3]); err != nil {
+		return nil, err
+	}
+
+	if memento.BillingAddress, err = restoreAddress(billing[0], billing[1], billing[2], billing[3]); err != nil {
+		return nil, err
+	}
+
+	return domain.RestoreCustomer(memento)
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const orderColumns = `id, quote_id, cart_id, customer_id, currency, lines, subtotal, adjustments, shipping_method, shipping_name, shipping_cost, shipping_currency, taxed, taxes, total, placed_on, version`
+
+type SQLOrderRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLOrderRepository(db *sql.DB, options ...RepositoryOption) (domain.OrderRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLOrderRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLOrderRepository) FindByID(id domain.OrderId) (*domain.Order, error) {
+	order, err := scanOrder(r.db.QueryRow(`SELECT `+orderColumns+` FROM orders WHERE id = ?`, id.String()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("entity not found")
+	}
+
+	return order, err
+}
+
+func (r *SQLOrderRepository) Save(order *domain.Order) error {
+	memento := order.ToMemento()
+
+	lines, err := json.Marshal(memento.Lines)
+	if err != nil {
+		return err
+	}
+
+	adjustments, err := json.Marshal(memento.Adjustments)
+	if err != nil {
+		return err
+	}
+
+	taxes, err := json.Marshal(memento.Taxes)
+	if err != nil {
+		return err
+	}
+
+	var quoteId sql.NullString
+	if memento.QuoteId != nil {
+		quoteId = sql.NullString{String: memento.QuoteId.String(), Valid: true}
+	}
+
+	shippingMethod, shippingName, shippingCost, shippingCurrency := shippingValues(memento.Shipping)
+
+	return saveInTransaction[domain.OrderId](r.sqlBaseRepository, order, func(tx *sql.Tx) error {
+		return saveVersionedRow(tx, "orders", orderColumns, []any{
+			memento.Id.String(),
+			quoteId,
+			memento.CartId.String(),
+			memento.CustomerId.String(),
+			string(memento.Currency),
+			string(lines),
+			memento.Subtotal.MinorUnits(),
+			string(adjustments),
+			shippingMethod,
+			shippingName,
+			shippingCost,
+			shippingCurrency,
+			memento.Taxed,
+			string(taxes),
+			memento.Total.MinorUnits(),
+			memento.PlacedOn.UnixNano(),
+			memento.Version + 1,
+		}, memento.Version)
+	})
+}
+
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var memento domain.OrderMemento
+	var id, cartId, customerId, currency, lines, adjustments, taxes string
+	var quoteId, shippingMethod, shippingName, shippingCurrency sql.NullString
+	var subtotal, total, placedOn int64
+	var shippingCost sql.NullInt64
+	err := row.Scan(&id, &quoteId, &cartId, &customerId, &currency, &lines, &subtotal, &adjustments, &shippingMethod, &shippingName, &shippingCost, &shippingCurrency, &memento.Taxed, &taxes, &total, &placedOn, &memento.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseID(id, &memento.Id); err != nil {
+		return nil, err
+	}
+
+	if quoteId.Valid {
+		memento.QuoteId = new(domain.QuoteId)
+		if err := parseID(quoteId.String, memento.QuoteId); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parseID(cartId, &memento.CartId); err != nil {
+		return nil, err
+	}
+
+	if err := parseID(customerId, &memento.CustomerId); err != nil {
+		return nil, err
+	}
+
+	memento.Currency = domain.Currency(currency)
+	memento.Subtotal = domain.NewMoney(subtotal, memento.Currency)
+	memento.Shipping = restoreShippingMemento(shippingMethod, shippingName, shippingCost, shippingCurrency)
+	memento.Total = domain.NewMoney(total, memento.Currency)
+	memento.PlacedOn = time.Unix(0, placedOn).UTC()
+
+	if err := json.Unmarshal([]byte(lines), &memento.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(adjustments), &memento.Adjustments); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(taxes), &memento.Taxes); err != nil {
+		return nil, err
+	}
+
+	return domain.RestoreOrder(memento)
+}
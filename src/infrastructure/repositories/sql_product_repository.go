@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const productColumns = `id, sku, name, currency, unit_price, tax_category, weight_grams, length_mm, width_mm, height_mm, archived, version`
+
+type SQLProductRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLProductRepository(db *sql.DB, options ...RepositoryOption) (domain.ProductRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLProductRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLProductRepository) FindByID(id domain.ProductId) (*domain.Product, error) {
+	return r.findOne(`WHERE id = ?`, id.String())
+}
+
+func (r *SQLProductRepository) FindBySKU(sku domain.SKU) (*domain.Product, error) {
+	if sku == "" {
+		return nil, errors.New("entity not found")
+	}
+
+	return r.findOne(`WHERE sku = ?`, string(sku))
+}
+
+func (r *SQLProductRepository) FindByName(name string) (*domain.Product, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return nil, errors.New("entity not found")
+	}
+
+	return r.findOne(`WHERE name_key = ?`, key)
+}
+
+func (r *SQLProductRepository) Save(product *domain.Product) error {
+	memento := product.ToMemento()
+	id := memento.Id.String()
+
+	return saveInTransaction[domain.ProductId](r.sqlBaseRepository, product, func(tx *sql.Tx) error {
+		if err := checkUniqueColumn(tx, "products", "name_key", "product_name", strings.ToLower(memento.Name), id); err != nil {
+			return err
+		}
+
+		if err := checkUniqueColumn(tx, "products", "sku", "sku", string(memento.SKU), id); err != nil {
+			return err
+		}
+
+		return saveVersionedRow(tx, "products", productColumns+", name_key", []any{
+			id,
+			nullableString(string(memento.SKU)),
+			memento.Name,
+			string(memento.UnitPrice.Currency()),
+			memento.UnitPrice.MinorUnits(),
+			string(memento.TaxCategory),
+			memento.Weight.Grams(),
+			memento.Dimensions.GetLength(),
+			memento.Dimensions.GetWidth(),
+			memento.Dimensions.GetHeight(),
+			memento.Archived,
+			memento.Version + 1,
+			strings.ToLower(memento.Name),
+		}, memento.Version)
+	})
+}
+
+func (r *SQLProductRepository) List(query domain.ProductListQuery) (domain.ProductPage, error) {
+	query, after, err := prepareProductListQuery(query)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+
+	var conditions []string
+	var args []any
+	if !query.IncludeArchived {
+		conditions = append(conditions, `archived = ?`)
+		args = append(args, false)
+	}
+
+	if query.NameContains != "" {
+		conditions = append(conditions, `name_key LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLikePattern(strings.ToLower(query.NameContains))+"%")
+	}
+
+	if query.MinPrice != nil {
+		conditions = append(conditions, `currency = ? AND unit_price >= ?`)
+		args = append(args, string(query.MinPrice.Currency()), query.MinPrice.MinorUnits())
+	}
+
+	if query.MaxPrice != nil {
+		conditions = append(conditions, `currency = ? AND unit_price <= ?`)
+		args = append(args, string(query.MaxPrice.Currency()), query.MaxPrice.MinorUnits())
+	}
+
+	keyColumns := []string{"name_key", "id"}
+	var cursorValues []any
+	if after != nil {
+		cursorValues = []any{after.Name, after.Id}
+	}
+	if query.SortBy == domain.SortProductsByPrice {
+		keyColumns = []string{"currency", "unit_price", "id"}
+		if after != nil {
+			cursorValues = []any{string(after.Currency), after.Price, after.Id}
+		}
+	}
+
+	direction, comparison := " ASC", ">"
+	if query.Descending {
+		direction, comparison = " DESC", "<"
+	}
+
+	if after != nil {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(cursorValues)), ", ")
+		conditions = append(conditions, "("+strings.Join(keyColumns, ", ")+") "+comparison+" ("+placeholders+")")
+		args = append(args, cursorValues...)
+	}
+
+	statement := `SELECT ` + productColumns + ` FROM products`
+	if len(conditions) > 0 {
+		statement += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	statement += ` ORDER BY ` + strings.Join(keyColumns, direction+", ") + direction + ` LIMIT ?`
+	args = append(args, query.Limit+1)
+
+	products, err := r.findMany(statement, args...)
+	if err != nil {
+		return domain.ProductPage{}, err
+	}
+
+	page := domain.ProductPage{
+		Products: products,
+	}
+	if len(products) > query.Limit {
+		page.Products = products[:query.Limit]
+		page.NextCursor = newProductCursor(query, products[query.Limit-1]).encode()
+	}
+
+	return page, nil
+}
+
+func (r *SQLProductRepository) findOne(where string, args ...any) (*domain.Product, error) {
+	product, err := scanProduct(r.db.QueryRow(`SELECT `+productColumns+` FROM products `+where, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("entity not found")
+	}
+
+	return product, err
+}
+
+func (r *SQLProductRepository) findMany(statement string, args ...any) ([]*domain.Product, error) {
+	rows, err := r.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+func scanProduct(row rowScanner) (*domain.Product, error) {
+	var id, name, currency, taxCategory string
+	var sku sql.NullString
+	var unitPrice, weight int64
+	var length, width, height int
+	var archived bool
+	var version int
+	if err := row.Scan(&id, &sku, &name, &currency, &unitPrice, &taxCategory, &weight, &length, &width, &height, &archived, &version); err != nil {
+		return nil, err
+	}
+
+	memento := domain.ProductMemento{
+		SKU:         domain.SKU(sku.String),
+		Name:        name,
+		UnitPrice:   domain.NewMoney(unitPrice, domain.Currency(currency)),
+		TaxCategory: domain.TaxCategory(taxCategory),
+		Weight:      domain.Weight(weight),
+		Archived:    archived,
+		Version:     version,
+	}
+
+	if err := parseID(id, &memento.Id); err != nil {
+		return nil, err
+	}
+
+	dimensions, err := restoreDimensions(length, width, height)
+	if err != nil {
+		return nil, err
+	}
+	memento.Dimensions = dimensions
+
+	return domain.RestoreProduct(memento)
+}
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const quoteColumns = `id, number, cart_id, customer_id, currency, lines, subtotal, adjustments, shipping_method, shipping_name, shipping_cost, shipping_currency, taxed, taxes, total, issued_at, expires_at, status, version`
+
+type SQLQuoteRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLQuoteRepository(db *sql.DB, options ...RepositoryOption) (domain.QuoteRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLQuoteRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLQuoteRepository) FindByID(id domain.QuoteId) (*domain.Quote, error) {
+	quote, err := scanQuote(r.db.QueryRow(`SELECT `+quoteColumns+` FROM quotes WHERE id = ?`, id.String()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.New("entity not found")
+	}
+
+	return quote, err
+}
+
+func (r *SQLQuoteRepository) NextQuoteNumber(issuedAt time.Time) (string, error) {
+	var sequence int64
+	if err := r.db.QueryRow(`SELECT value FROM quote_number_sequence WHERE id = 1`).Scan(&sequence); err != nil {
+		return "", err
+	}
+
+	return formatQuoteNumber(issuedAt, sequence+1), nil
+}
+
+func (r *SQLQuoteRepository) FindExpiredQuotes(now time.Time) ([]*domain.Quote, error) {
+	rows, err := r.db.Query(`SELECT `+quoteColumns+` FROM quotes WHERE status = ? AND expires_at <= ? ORDER BY expires_at`, string(domain.QuoteStatusPending), now.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var quotes []*domain.Quote
+	for rows.Next() {
+		quote, err := scanQuote(rows)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, rows.Err()
+}
+
+func (r *SQLQuoteRepository) Save(quote *domain.Quote) error {
+	memento := quote.ToMemento()
+	id := memento.Id.String()
+
+	lines, err := json.Marshal(memento.Lines)
+	if err != nil {
+		return err
+	}
+
+	adjustments, err := json.Marshal(memento.Adjustments)
+	if err != nil {
+		return err
+	}
+
+	taxes, err := json.Marshal(memento.Taxes)
+	if err != nil {
+		return err
+	}
+
+	shippingMethod, shippingName, shippingCost, shippingCurrency := shippingValues(memento.Shipping)
+
+	return saveInTransaction[domain.QuoteId](r.sqlBaseRepository, quote, func(tx *sql.Tx) error {
+		if err := checkUniqueColumn(tx, "quotes", "number", "number", memento.Number, id); err != nil {
+			return err
+		}
+
+		sequence := parseQuoteSequence(memento.Number)
+		if _, err := tx.Exec(`UPDATE quote_number_sequence SET value = ? WHERE id = 1 AND value < ?`, sequence, sequence); err != nil {
+			return err
+		}
+
+		return saveVersionedRow(tx, "quotes", quoteColumns, []any{
+			id,
+			memento.Number,
+			memento.CartId.String(),
+			memento.CustomerId.String(),
+			string(memento.Currency),
+			string(lines),
+			memento.Subtotal.MinorUnits(),
+			string(adjustments),
+			shippingMethod,
+			shippingName,
+			shippingCost,
+			shippingCurrency,
+			memento.Taxed,
+			string(taxes),
+			memento.Total.MinorUnits(),
+			memento.IssuedAt.UnixNano(),
+			memento.ExpiresAt.UnixNano(),
+			string(memento.Status),
+			memento.Version + 1,
+		}, memento.Version)
+	})
+}
+
+func scanQuote(row rowScanner) (*domain.Quote, error) {
+	var memento domain.QuoteMemento
+	var id, cartId, customerId, currency, lines, adjustments, taxes, status string
+	var shippingMethod, shippingName, shippingCurrency sql.NullString
+	var subtotal, total, issuedAt, expiresAt int64
+	var shippingCost sql.NullInt64
+	err := row.Scan(&id, &memento.Number, &cartId, &customerId, &currency, &lines, &subtotal, &adjustments, &shippingMethod, &shippingName, &shippingCost, &shippingCurrency, &memento.Taxed, &taxes, &total, &issuedAt, &expiresAt, &status, &memento.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parseID(id, &memento.Id); err != nil {
+		return nil, err
+	}
+
+	if err := parseID(cartId, &memento.CartId); err != nil {
+		return nil, err
+	}
+
+	if err := parseID(customerId, &memento.CustomerId); err != nil {
+		return nil, err
+	}
+
+	memento.Currency = domain.Currency(currency)
+	memento.Subtotal = domain.NewMoney(subtotal, memento.Currency)
+	memento.Shipping = restoreShippingMemento(shippingMethod, shippingName, shippingCost, shippingCurrency)
+	memento.Total = domain.NewMoney(total, memento.Currency)
+	memento.IssuedAt = time.Unix(0, issuedAt).UTC()
+	memento.ExpiresAt = time.Unix(0, expiresAt).UTC()
+	memento.Status = domain.QuoteStatus(status)
+
+	if err := json.Unmarshal([]byte(lines), &memento.Lines); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(adjustments), &memento.Adjustments); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(taxes), &memento.Taxes); err != nil {
+		return nil, err
+	}
+
+	return domain.RestoreQuote(memento)
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+const stockItemColumns = `id, on_hand, version`
+
+type SQLStockRepository struct {
+	*sqlBaseRepository
+}
+
+func NewSQLStockRepository(db *sql.DB, options ...RepositoryOption) (domain.StockRepository, error) {
+	base, err := newSQLBaseRepository(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLStockRepository{
+		sqlBaseRepository: base,
+	}, nil
+}
+
+func (r *SQLStockRepository) FindByID(id domain.ProductId) (*domain.StockItem, error) {
+	stockItems, err := r.findMany(`WHERE id = ?`, id.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stockItems) == 0 {
+		return nil, errors.New("entity not found")
+	}
+
+	return stockItems[0], nil
+}
+
+func (r *SQLStockRepository) GetCartReservations(cartId domain.CartId) ([]*domain.StockItem, error) {
+	return r.findMany(`WHERE id IN (SELECT product_id FROM stock_reservations WHERE cart_id = ?) ORDER BY id`, cartId.String())
+}
+
+func (r *SQLStockRepository) Save(stockItem *domain.StockItem) error {
+	memento := stockItem.ToMemento()
+	id := memento.ProductId.String()
+
+	return saveInTransaction[domain.ProductId](r.sqlBaseRepository, stockItem, func(tx *sql.Tx) error {
+		err := saveVersionedRow(tx, "stock_items", stockItemColumns, []any{
+			id,
+			memento.OnHand,
+			memento.Version + 1,
+		}, memento.Version)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM stock_reservations WHERE product_id = ?`, id); err != nil {
+			return err
+		}
+
+		for _, reservation := range memento.Reservations {
+			if _, err := tx.Exec(`INSERT INTO stock_reservations (product_id, cart_id, quantity) VALUES (?, ?, ?)`, id, reservation.CartId.String(), reservation.Quantity); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *SQLStockRepository) findMany(where string, args ...any) ([]*domain.StockItem, error) {
+	var mementos []domain.StockItemMemento
+	err := r.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		if mementos, err = findStockItemMementos(tx, where, args...); err != nil {
+			return err
+		}
+
+		for position := range mementos {
+			if mementos[position].Reservations, err = findStockReservations(tx, mementos[position].ProductId); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stockItems []*domain.StockItem
+	for _, memento := range mementos {
+		stockItem, err := domain.RestoreStockItem(memento)
+		if err != nil {
+			return nil, err
+		}
+		stockItems = append(stockItems, stockItem)
+	}
+
+	return stockItems, nil
+}
+
+func findStockItemMementos(tx *sql.Tx, where string, args ...any) ([]domain.StockItemMemento, error) {
+	rows, err := tx.Query(`SELECT `+stockItemColumns+` FROM stock_items `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mementos []domain.StockItemMemento
+	for rows.Next() {
+		var memento domain.StockItemMemento
+		var id string
+		if err := rows.Scan(&id, &memento.OnHand, &memento.Version); err != nil {
+			return nil, err
+		}
+
+		if err := parseID(id, &memento.ProductId); err != nil {
+			return nil, err
+		}
+		mementos = append(mementos, memento)
+	}
+
+	return mementos, rows.Err()
+}
+
+func findStockReservations(tx *sql.Tx, productId domain.ProductId) ([]domain.StockReservationMemento, error) {
+	rows, err := tx.Query(`SELECT cart_id, quantity FROM stock_reservations WHERE product_id = ? ORDER BY cart_id`, productId.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []domain.StockReservationMemento
+	for rows.Next() {
+		var reservation domain.StockReservationMemento
+		var cartId string
+		if err := rows.Scan(&cartId, &reservation.Quantity); err != nil {
+			return nil, err
+		}
+
+		if err := parseID(cartId, &reservation.CartId); err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, rows.Err()
+}
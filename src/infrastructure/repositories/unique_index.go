@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type uniqueIndex[K comparable, E domain.Entity[K]] struct {
+	field  string
+	key    func(E) string
+	owners map[string]K
+	keys   map[K]string
+}
+
+func newUniqueIndex[K comparable, E domain.Entity[K]](field string, key func(E) string) *uniqueIndex[K, E] {
+	return &uniqueIndex[K, E]{
+		field:  field,
+		key:    key,
+		owners: map[string]K{},
+		keys:   map[K]string{},
+	}
+}
+
+func (u *uniqueIndex[K, E]) check(entity E) error {
+	key := u.key(entity)
+	if key == "" {
+		return nil
+	}
+
+	if owner, found := u.owners[key]; found && owner != entity.GetID() {
+		return &domain.UniqueConstraintError{Field: u.field, Value: key}
+	}
+
+	return nil
+}
+
+func (u *uniqueIndex[K, E]) lookup(key string) (K, bool) {
+	owner, found := u.owners[key]
+	return owner, found
+}
+
+func (u *uniqueIndex[K, E]) update(entity E) {
+	id := entity.GetID()
+	u.remove(id)
+
+	if key := u.key(entity); key != "" {
+		u.owners[key] = id
+		u.keys[id] = key
+	}
+}
+
+func (u *uniqueIndex[K, E]) remove(id K) {
+	if key, found := u.keys[id]; found {
+		delete(u.owners, key)
+		delete(u.keys, id)
+	}
+}
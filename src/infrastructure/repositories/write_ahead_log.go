@@ -0,0 +1,320 @@
+package repositories
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+)
+
+const defaultSnapshotEvery = 1000
+
+type logEntry[M any] struct {
+	Entity M               `json:"entity"`
+	Outbox []outbox.Record `json:"outbox,omitempty"`
+}
+
+type writeAheadLog[K comparable, E domain.Entity[K], M any] struct {
+	mu            sync.Mutex
+	directory     string
+	logPath       string
+	snapshotPath  string
+	toMemento     func(E) M
+	snapshotEvery int
+	commits       int
+	state         map[K]M
+}
+
+func openWriteAheadLog[K comparable, E domain.Entity[K], M any](directory string, name string, snapshotEvery int, toMemento func(E) M, restore func(M) (E, error)) (*writeAheadLog[K, E, M], []E, []outbox.Record, error) {
+	if directory == "" {
+		return nil, nil, nil, errors.New("repository directory was empty")
+	}
+
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if snapshotEvery <= 0 {
+		snapshotEvery = defaultSnapshotEvery
+	}
+
+	wal := &writeAheadLog[K, E, M]{
+		directory:     directory,
+		logPath:       filepath.Join(directory, name+".wal"),
+		snapshotPath:  filepath.Join(directory, name+".snapshot"),
+		toMemento:     toMemento,
+		snapshotEvery: snapshotEvery,
+		state:         map[K]M{},
+	}
+
+	mementos, err := readSnapshot[M](wal.snapshotPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", wal.snapshotPath, err)
+	}
+
+	entries, err := replayLog[M](wal.logPath)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", wal.logPath, err)
+	}
+	wal.commits = len(entries)
+
+	var unpublished []outbox.Record
+	for _, entry := range entries {
+		mementos = append(mementos, entry.Entity)
+		unpublished = entry.Outbox
+	}
+
+	var keys []K
+	entities := map[K]E{}
+	for _, memento := range mementos {
+		entity, err := restore(memento)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		key := entity.GetID()
+		if _, found := entities[key]; !found {
+			keys = append(keys, key)
+		}
+		entities[key] = entity
+		wal.state[key] = memento
+	}
+
+	var restored []E
+	for _, key := range keys {
+		restored = append(restored, entities[key])
+	}
+
+	return wal, restored, unpublished, nil
+}
+
+func (w *writeAheadLog[K, E, M]) commit(entity E, records []outbox.Record, publish func() error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	memento := w.toMemento(entity)
+	line, err := json.Marshal(logEntry[M]{Entity: memento, Outbox: records})
+	if err != nil {
+		return err
+	}
+
+	offset, err := appendLine(w.logPath, append(line, '\n'))
+	if err != nil {
+		return err
+	}
+
+	if err := publish(); err != nil {
+		if truncateErr := truncateLog(w.logPath, offset); truncateErr != nil {
+			return fmt.Errorf("%w (removing the entry from the write-ahead log also failed: %v)", err, truncateErr)
+		}
+		return err
+	}
+
+	w.state[entity.GetID()] = memento
+	w.commits++
+	if w.commits >= w.snapshotEvery {
+		if err := w.snapshot(); err != nil {
+			log.Printf("failed to snapshot %s, the write-ahead log keeps growing: %v", w.logPath, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *writeAheadLog[K, E, M]) snapshot() error {
+	keys := make([]K, 0, len(w.state))
+	for key := range w.state {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	var mementos []M
+	for _, key := range keys {
+		mementos = append(mementos, w.state[key])
+	}
+
+	if err := writeSnapshot(w.snapshotPath, mementos); err != nil {
+		return err
+	}
+
+	if err := syncDirectory(w.directory); err != nil {
+		return err
+	}
+
+	if err := os.Truncate(w.logPath, 0); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	w.commits = 0
+	return nil
+}
+
+func appendLine(path string, line []byte) (int64, error) {
+	_, err := os.Stat(path)
+	created := errors.Is(err, os.ErrNotExist)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := file.Write(line); err != nil {
+		file.Truncate(info.Size())
+		return 0, err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Truncate(info.Size())
+		return 0, err
+	}
+
+	if created {
+		return info.Size(), syncDirectory(filepath.Dir(path))
+	}
+
+	return info.Size(), nil
+}
+
+func truncateLog(path string, size int64) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return truncateTornWrite(file, size)
+}
+
+func readSnapshot[M any](path string) ([]M, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var mementos []M
+	for number, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+
+		var memento M
+		if err := json.Unmarshal(line, &memento); err != nil {
+			return nil, fmt.Errorf("corrupt snapshot at line %d: %w", number+1, err)
+		}
+		mementos = append(mementos, memento)
+	}
+
+	return mementos, nil
+}
+
+func replayLog[M any](path string) ([]logEntry[M], error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []logEntry[M]
+	var offset int64
+	reader := bufio.NewReader(file)
+	for number := 1; ; number++ {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return entries, truncateTornWrite(file, offset)
+			}
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry logEntry[M]
+			if err := json.Unmarshal(line, &entry); err != nil {
+				if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+					return entries, truncateTornWrite(file, offset)
+				}
+				return nil, fmt.Errorf("corrupt write-ahead log at line %d: %w", number, err)
+			}
+			entries = append(entries, entry)
+		}
+
+		offset += int64(len(line))
+	}
+}
+
+func truncateTornWrite(file *os.File, size int64) error {
+	if err := file.Truncate(size); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func writeSnapshot[M any](path string, mementos []M) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	writer := bufio.NewWriter(tempFile)
+	encoder := json.NewEncoder(writer)
+	for _, memento := range mementos {
+		if err := encoder.Encode(memento); err != nil {
+			tempFile.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
+
+func syncDirectory(directory string) error {
+	dir, err := os.Open(directory)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
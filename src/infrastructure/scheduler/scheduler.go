@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type Job interface {
+	Run() error
+}
+
+type JobFunc func() error
+
+func (f JobFunc) Run() error {
+	return f()
+}
+
+type scheduledJob struct {
+	name      string
+	interval  time.Duration
+	job       Job
+	nextRunAt time.Time
+}
+
+type Scheduler struct {
+	clock        domain.Clock
+	pollInterval time.Duration
+
+	jobsMu sync.Mutex
+	jobs   []*scheduledJob
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func NewScheduler(clock domain.Clock, pollInterval time.Duration) (*Scheduler, error) {
+	if clock == nil {
+		return nil, errors.New("clock was nil")
+	}
+
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	return &Scheduler{
+		clock:        clock,
+		pollInterval: pollInterval,
+	}, nil
+}
+
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) error {
+	if name == "" {
+		return errors.New("invalid job name")
+	}
+
+	if interval <= 0 {
+		return errors.New("invalid job interval")
+	}
+
+	if job == nil {
+		return errors.New("job was nil")
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	s.jobs = append(s.jobs, &scheduledJob{
+		name:      name,
+		interval:  interval,
+		job:       job,
+		nextRunAt: s.clock.Now(),
+	})
+
+	return nil
+}
+
+func (s *Scheduler) RunDue() {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	for _, scheduled := range s.jobs {
+		now := s.clock.Now()
+		if now.Before(scheduled.nextRunAt) {
+			continue
+		}
+
+		if err := scheduled.job.Run(); err != nil {
+			log.Printf("scheduler: job %s failed: %v", scheduled.name, err)
+		}
+		scheduled.nextRunAt = now.Add(scheduled.interval)
+	}
+}
+
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		return
+	}
+
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.run(s.stop, s.stopped)
+}
+
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop == nil {
+		return
+	}
+
+	close(s.stop)
+	<-s.stopped
+	s.stop = nil
+	s.stopped = nil
+}
+
+func (s *Scheduler) run(stop <-chan struct{}, stopped chan<- struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue()
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
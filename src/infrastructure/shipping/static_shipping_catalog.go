@@ -0,0 +1,139 @@
+package shipping
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type StaticShippingCatalog struct {
+	methods []domain.ShippingMethod
+	byCode  map[domain.ShippingMethodCode]domain.ShippingMethod
+}
+
+func NewStaticShippingCatalog(methods []domain.ShippingMethod) (*StaticShippingCatalog, error) {
+	catalog := &StaticShippingCatalog{
+		byCode: map[domain.ShippingMethodCode]domain.ShippingMethod{},
+	}
+
+	for _, method := range methods {
+		if method == nil {
+			return nil, errors.New("shipping method was nil")
+		}
+
+		if _, found := catalog.byCode[method.GetCode()]; found {
+			return nil, fmt.Errorf("duplicated shipping method %s", method.GetCode())
+		}
+
+		catalog.methods = append(catalog.methods, method)
+		catalog.byCode[method.GetCode()] = method
+	}
+
+	return catalog, nil
+}
+
+type catalogDefinition struct {
+	Methods []methodDefinition `json:"methods"`
+}
+
+type methodDefinition struct {
+	Code              string `json:"code"`
+	Name              string `json:"name"`
+	Type              string `json:"type"`
+	Currency          string `json:"currency"`
+	Cost              string `json:"cost"`
+	Base              string `json:"base"`
+	PerKilogram       string `json:"per_kg"`
+	VolumetricDivisor int    `json:"volumetric_divisor"`
+	FreeAbove         string `json:"free_above"`
+}
+
+func NewFileShippingCatalog(path string) (*StaticShippingCatalog, error) {
+	if path == "" {
+		return nil, errors.New("shipping methods file path was empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition catalogDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+
+	var methods []domain.ShippingMethod
+	for _, methodDefinition := range definition.Methods {
+		method, err := newShippingMethod(methodDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("shipping method %q: %w", methodDefinition.Code, err)
+		}
+		methods = append(methods, method)
+	}
+
+	return NewStaticShippingCatalog(methods)
+}
+
+func (c *StaticShippingCatalog) FindShippingMethod(code domain.ShippingMethodCode) (domain.ShippingMethod, error) {
+	if method, found := c.byCode[code]; found {
+		return method, nil
+	}
+
+	return nil, errors.New("shipping method not found")
+}
+
+func (c *StaticShippingCatalog) GetShippingMethods() []domain.ShippingMethod {
+	return append([]domain.ShippingMethod{}, c.methods...)
+}
+
+func newShippingMethod(definition methodDefinition) (domain.ShippingMethod, error) {
+	code, err := domain.NewShippingMethodCode(definition.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := domain.DefaultCurrency
+	if definition.Currency != "" {
+		if currency, err = domain.NewCurrency(definition.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	name := definition.Name
+	if name == "" {
+		name = string(code)
+	}
+
+	var method domain.ShippingMethod
+	switch definition.Type {
+	case "flat_rate":
+		var cost domain.Money
+		if cost, err = domain.ParseMoney(definition.Cost, currency); err == nil {
+			method, err = domain.NewFlatRateShipping(code, name, cost)
+		}
+	case "weight_based":
+		var base, perKilogram domain.Money
+		if base, err = domain.ParseMoney(definition.Base, currency); err == nil {
+			if perKilogram, err = domain.ParseMoney(definition.PerKilogram, currency); err == nil {
+				method, err = domain.NewWeightBasedShipping(code, name, base, perKilogram, definition.VolumetricDivisor)
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown shipping method type %q", definition.Type)
+	}
+
+	if err != nil || definition.FreeAbove == "" {
+		return method, err
+	}
+
+	threshold, err := domain.ParseMoney(definition.FreeAbove, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewFreeShippingAbove(threshold, method)
+}
@@ -0,0 +1,71 @@
+package taxes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/bitlogic/go-startup/src/domain"
+)
+
+type taxTableDefinition struct {
+	Rounding string           `json:"rounding"`
+	Rules    []ruleDefinition `json:"rules"`
+}
+
+type ruleDefinition struct {
+	Region    string `json:"region"`
+	Category  string `json:"category"`
+	Rate      string `json:"rate"`
+	Inclusive bool   `json:"inclusive"`
+}
+
+func NewFileTaxCalculator(path string) (domain.TaxCalculator, error) {
+	if path == "" {
+		return nil, errors.New("tax rules file path was empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition taxTableDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return nil, err
+	}
+
+	rounding := domain.RoundTaxPerLine
+	if definition.Rounding != "" {
+		rounding = domain.TaxRounding(definition.Rounding)
+	}
+
+	var rules []domain.TaxRule
+	for _, ruleDefinition := range definition.Rules {
+		region, err := domain.NewTaxRegion(ruleDefinition.Region)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", err, ruleDefinition.Region)
+		}
+
+		category, err := domain.NewTaxCategory(ruleDefinition.Category)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q", err, ruleDefinition.Category)
+		}
+
+		rate, ok := new(big.Rat).SetString(ruleDefinition.Rate)
+		if !ok {
+			return nil, fmt.Errorf("invalid tax rate %q for %s/%s", ruleDefinition.Rate, region, category)
+		}
+
+		rules = append(rules, domain.TaxRule{
+			Region:    region,
+			Category:  category,
+			Rate:      rate,
+			Inclusive: ruleDefinition.Inclusive,
+		})
+	}
+
+	return domain.NewRuleTableTaxCalculator(rounding, rules...)
+}
@@ -1,6 +1,13 @@
 package main
 
 import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/labstack/echo/v4"
 )
@@ -9,6 +16,22 @@ func main() {
 	e := echo.New()
 
 	config.MapEndpoints(e)
+	config.StartBackgroundJobs()
+	defer config.StopBackgroundJobs()
+
+	go func() {
+		if err := e.Start(":8080"); err != nil && err != http.ErrServerClosed {
+			e.Logger.Fatal(err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
 
-	e.Logger.Fatal(e.Start(":8080"))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		e.Logger.Error(err)
+	}
 }
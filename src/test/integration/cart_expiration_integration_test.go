@@ -0,0 +1,67 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/infrastructure/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAnIdleCart_WhenTheSchedulerRunsAsTimePasses_ThenTheCartIsAbandonedAndLaterExpired(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingCart, _ := domain.NewCart(existingCustomer, domain.WithCartClock(clock))
+	existingCart.ClearDomainEvents()
+
+	outboxStore := outbox.NewInMemoryStore()
+	cartRepository := repositories.NewInMemoryCartRepository(repositories.WithOutbox(outboxStore))
+	cartRepository.Save(existingCart)
+	savedCartStatus := func() domain.CartStatus {
+		savedCart, _ := cartRepository.FindByID(existingCart.GetID())
+		return savedCart.GetStatus()
+	}
+	dispatcher := events.NewSynchronousEventDispatcher()
+	var abandonedEvents []domain.CartAbandoned
+	domain.RegisterEventHandler(dispatcher, func(event domain.CartAbandoned) error {
+		abandonedEvents = append(abandonedEvents, event)
+		return nil
+	})
+	cartExpirationService, _ := application.NewCartExpirationService(cartRepository, dispatcher, clock, application.CartExpirationPolicy{
+		AbandonAfter: time.Hour,
+		ExpireAfter:  24 * time.Hour,
+	})
+	jobScheduler, _ := scheduler.NewScheduler(clock, time.Second)
+	jobScheduler.Every("expire-idle-carts", time.Minute, scheduler.JobFunc(func() error {
+		_, err := cartExpirationService.ExpireIdleCarts()
+		return err
+	}))
+
+	clock.now = clock.now.Add(59 * time.Minute)
+	jobScheduler.RunDue()
+	assert.Equal(t, domain.CartStatusActive, savedCartStatus())
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	jobScheduler.RunDue()
+	assert.Equal(t, domain.CartStatusAbandoned, savedCartStatus())
+	if assert.Len(t, abandonedEvents, 1) {
+		assert.Equal(t, existingCart.GetID(), abandonedEvents[0].CartId)
+		assert.Equal(t, existingCustomer.GetID(), abandonedEvents[0].CustomerId)
+	}
+
+	clock.now = clock.now.Add(23 * time.Hour)
+	jobScheduler.RunDue()
+	assert.Equal(t, domain.CartStatusExpired, savedCartStatus())
+	assert.Len(t, abandonedEvents, 1)
+
+	var eventTypes []string
+	for _, record := range outboxStore.All() {
+		eventTypes = append(eventTypes, record.EventType)
+	}
+	assert.Equal(t, []string{"CartAbandoned", "CartExpired"}, eventTypes)
+}
@@ -3,16 +3,23 @@ package test
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/bitlogic/go-startup/src/application"
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/exchangerates"
+	"github.com/bitlogic/go-startup/src/infrastructure/promotions"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/infrastructure/shipping"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -23,7 +30,7 @@ func Test_GivenAValidNewCartRequest_WhenPOSTNewCart_ThenReturn201(t *testing.T)
 	cartRepository := repositories.NewInMemoryCartRepository()
 	customerRepository := repositories.NewInMemoryCustomerRepository()
 	productRepository := repositories.NewInMemoryProductRepository()
-	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository)
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
 	cartController, _ := controllers.NewCartController(cartService)
 
 	customerRepository.Save(existingCustomer)
@@ -104,7 +111,7 @@ func Test_GivenAnInvalidNewCartRequest_WhenPOSTNewCart_ThenReturn400ErrorRespons
 			cartRepository := repositories.NewInMemoryCartRepository()
 			customerRepository := repositories.NewInMemoryCustomerRepository()
 			productRepository := repositories.NewInMemoryProductRepository()
-			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository)
+			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
 			cartController, _ := controllers.NewCartController(cartService)
 
 			request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(tc.requestBody))
@@ -126,13 +133,13 @@ func Test_GivenAnInvalidNewCartRequest_WhenPOSTNewCart_ThenReturn400ErrorRespons
 
 func Test_GivenAValidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn200(t *testing.T) {
 	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
-	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", 10.00)
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
 	existingCart, _ := domain.NewCart(existingCustomer)
 
 	cartRepository := repositories.NewInMemoryCartRepository()
 	customerRepository := repositories.NewInMemoryCustomerRepository()
 	productRepository := repositories.NewInMemoryProductRepository()
-	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository)
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
 	cartController, _ := controllers.NewCartController(cartService)
 
 	customerRepository.Save(existingCustomer)
@@ -159,7 +166,7 @@ func Test_GivenAValidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn200(t
 		assert.Equal(t, 1, len(cartDto.Items))
 		assert.Equal(t, uuid.UUID(existingProduct.GetID()), cartDto.Items[0].ProductId)
 		assert.Equal(t, 2, cartDto.Items[0].Quantity)
-		assert.Equal(t, existingProduct.GetPrice(), float64(cartDto.Items[0].UnitPrice))
+		assert.Equal(t, existingProduct.GetPrice(), domain.Money(cartDto.Items[0].UnitPrice))
 	}
 
 	savedCart, _ := cartRepository.FindByID(domain.CartId(cartDto.Id))
@@ -168,15 +175,68 @@ func Test_GivenAValidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn200(t
 		if assert.Equal(t, 1, len(savedCart.GetItems())) {
 			assert.Equal(t, uuid.UUID(existingProduct.GetID()), uuid.UUID(savedCart.GetItems()[0].GetProductId()))
 			assert.Equal(t, 2, savedCart.GetItems()[0].GetQuantity())
-			assert.Equal(t, 10.00, savedCart.GetItems()[0].GetUnitPrice())
+			assert.Equal(t, testutil.USD("10.00"), savedCart.GetItems()[0].GetUnitPrice())
 		}
-		assert.Equal(t, 20.00, savedCart.GetTotal())
+		assert.Equal(t, testutil.USD("20.00"), savedCart.GetTotal())
 	}
 }
 
+func Test_GivenACartInEuros_WhenPOSTAddItemToCartPricedInDollars_ThenTheTotalIsConverted(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	unconvertiblePrice, _ := domain.ParseMoney("5000", "ARS")
+	unconvertibleProduct, _ := domain.NewProduct("Salame Milan 1 Kg", unconvertiblePrice)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	exchangeRates := newExchangeRates(map[string]map[string]string{"EUR": {"USD": "1.25"}})
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), exchangeRates)
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+	productRepository.Save(unconvertibleProduct)
+
+	e := echo.New()
+	e.POST("/carts", cartController.CreateNewCart)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(
+		fmt.Sprintf(`{"customer_id":"%s","currency":"eur"}`, existingCustomer.GetID().String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "EUR", cartDto.Currency)
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s", cartDto.Id.String()), strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":3}`, existingProduct.GetID().String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"currency":"EUR"`)
+	assert.Contains(t, rec.Body.String(), `"total":24.00`)
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s", cartDto.Id.String()), strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":1}`, unconvertibleProduct.GetID().String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"invalid product: its price in ARS cannot be converted to EUR"}`, strings.Trim(rec.Body.String(), "\n"))
+}
+
 func Test_GivenAnInvalidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn400ErrorResponse(t *testing.T) {
 	nonExistantProductId := uuid.New()
-	existantProduct, _ := domain.NewProduct("Mortadela 1Kg", 10)
+	existantProduct, _ := domain.NewProduct("Mortadela 1Kg", testutil.USD("10.00"))
 	cartId := uuid.New()
 
 	tests := []struct {
@@ -211,9 +271,9 @@ func Test_GivenAnInvalidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn400
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
-			testName:             "product id is nil",
+			testName:             "product id and sku are nil",
 			requestBody:          `{"quantity":1}`,
-			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"ProductId","error":"ProductId is a required field"}]}`,
+			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"ProductId","error":"ProductId is required when SKU is not set"},{"field":"SKU","error":"SKU is required when ProductId is not set"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
@@ -253,7 +313,7 @@ func Test_GivenAnInvalidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn400
 			cartRepository := repositories.NewInMemoryCartRepository()
 			customerRepository := repositories.NewInMemoryCustomerRepository()
 			productRepository := repositories.NewInMemoryProductRepository()
-			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository)
+			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
 			cartController, _ := controllers.NewCartController(cartService)
 
 			productRepository.Save(existantProduct)
@@ -274,3 +334,476 @@ func Test_GivenAnInvalidAddItemToCartRequest_WhenPOSTAddItemToCart_ThenReturn400
 	}
 
 }
+
+func Test_GivenACartWithItems_WhenPUTAndDELETECartItems_ThenTheCartIsUpdated(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	mortadela, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	salame, _ := domain.NewProduct("Salame Milan 1 Kg", testutil.USD("15.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(mortadela, 1)
+	existingCart.AddItem(salame, 1)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(mortadela)
+	productRepository.Save(salame)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.PUT("/carts/:cartId/items/:productId", cartController.UpdateItemQuantity)
+	e.DELETE("/carts/:cartId/items/:productId", cartController.RemoveItemFromCart)
+	e.DELETE("/carts/:cartId/items", cartController.ClearCart)
+	e.Validator = config.NewRequestValidator()
+
+	cartPath := fmt.Sprintf("/carts/%s/items", uuid.UUID(existingCart.GetID()).String())
+
+	request := httptest.NewRequest(http.MethodPut, fmt.Sprintf("%s/%s", cartPath, uuid.UUID(mortadela.GetID()).String()), strings.NewReader(`{"quantity":3}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	savedCart, _ := cartRepository.FindByID(existingCart.GetID())
+	assert.Equal(t, 4, savedCart.Size())
+	assert.Equal(t, testutil.USD("45.00"), savedCart.GetTotal())
+
+	request = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s", cartPath, uuid.UUID(salame.GetID()).String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Equal(t, 1, len(cartDto.Items)) {
+		assert.Equal(t, uuid.UUID(mortadela.GetID()), cartDto.Items[0].ProductId)
+		assert.Equal(t, 3, cartDto.Items[0].Quantity)
+	}
+
+	request = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%s", cartPath, uuid.UUID(salame.GetID()).String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, fmt.Sprintf(`{"message":"item with id %s not found"}`, uuid.UUID(salame.GetID()).String()), strings.Trim(rec.Body.String(), "\n"))
+
+	request = httptest.NewRequest(http.MethodDelete, cartPath, nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	savedCart, _ = cartRepository.FindByID(existingCart.GetID())
+	assert.Equal(t, 0, savedCart.Size())
+}
+
+func Test_GivenExistingCarts_WhenGETCartAndCustomerCarts_ThenReturnThem(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(existingProduct, 2)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.GET("/carts/:cartId", cartController.GetCart)
+	e.GET("/customers/:customerId/carts", cartController.GetCustomerCarts)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/carts/%s", existingCart.GetID().String()), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uuid.UUID(existingCart.GetID()), cartDto.Id)
+	if assert.Equal(t, 1, len(cartDto.Items)) {
+		assert.Equal(t, 2, cartDto.Items[0].Quantity)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/customers/%s/carts", existingCustomer.GetID().String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var cartDtos []application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDtos)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Equal(t, 1, len(cartDtos)) {
+		assert.Equal(t, uuid.UUID(existingCart.GetID()), cartDtos[0].Id)
+	}
+
+	unknownCartId := uuid.New()
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/carts/%s", unknownCartId.String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, fmt.Sprintf(`{"message":"cart with id %s not found"}`, unknownCartId.String()), strings.Trim(rec.Body.String(), "\n"))
+}
+
+func Test_GivenACustomerWithAnActiveCart_WhenPOSTNewCartTwice_ThenTheActiveCartIsReusedOrRejectedByPolicy(t *testing.T) {
+	tests := []struct {
+		testName             string
+		policy               application.ActiveCartPolicy
+		expectedResponseCode int
+	}{
+		{testName: "reuse", policy: application.ReuseActiveCart, expectedResponseCode: http.StatusOK},
+		{testName: "reject", policy: application.RejectSecondActiveCart, expectedResponseCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+			cartRepository := repositories.NewInMemoryCartRepository()
+			customerRepository := repositories.NewInMemoryCustomerRepository()
+			productRepository := repositories.NewInMemoryProductRepository()
+			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithActiveCartPolicy(tc.policy))
+			cartController, _ := controllers.NewCartController(cartService)
+			customerRepository.Save(existingCustomer)
+
+			e := echo.New()
+			e.POST("/carts", cartController.CreateNewCart)
+			e.Validator = config.NewRequestValidator()
+
+			var cartIds []uuid.UUID
+			var rec *httptest.ResponseRecorder
+			for i := 0; i < 2; i++ {
+				request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, existingCustomer.GetID().String())))
+				request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec = httptest.NewRecorder()
+				e.ServeHTTP(rec, request)
+
+				var cartDto application.CartDto
+				json.Unmarshal(rec.Body.Bytes(), &cartDto)
+				cartIds = append(cartIds, cartDto.Id)
+			}
+
+			assert.Equal(t, tc.expectedResponseCode, rec.Code)
+			if tc.policy == application.ReuseActiveCart {
+				assert.Equal(t, cartIds[0], cartIds[1])
+			} else {
+				assert.Equal(t, fmt.Sprintf(`{"message":"invalid customer: it already has an active cart %s"}`, cartIds[0].String()), strings.Trim(rec.Body.String(), "\n"))
+			}
+			customerCarts, _ := cartRepository.GetCustomerCarts(existingCustomer.GetID())
+			assert.Len(t, customerCarts, 1)
+		})
+	}
+}
+
+func Test_GivenConcurrentPOSTNewCartRequests_WhenTheCustomerHasNoCart_ThenOnlyOneActiveCartIsCreated(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+	customerRepository.Save(existingCustomer)
+
+	e := echo.New()
+	e.POST("/carts", cartController.CreateNewCart)
+	e.Validator = config.NewRequestValidator()
+
+	const requests = 10
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, existingCustomer.GetID().String())))
+			request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, request)
+			codes[i] = rec.Code
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, code := range codes {
+		assert.Contains(t, []int{http.StatusCreated, http.StatusOK}, code)
+		if code == http.StatusCreated {
+			created++
+		}
+	}
+	assert.Equal(t, 1, created)
+	customerCarts, _ := cartRepository.GetCustomerCarts(existingCustomer.GetID())
+	assert.Len(t, customerCarts, 1)
+}
+
+func Test_GivenCartsInDifferentStatuses_WhenGETCustomerCartsWithStatus_ThenReturnOnlyMatchingCarts(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	abandonedCart, _ := domain.NewCart(existingCustomer)
+	abandonedCart.Abandon()
+	activeCart, _ := domain.NewCart(existingCustomer)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, repositories.NewInMemoryProductRepository(), events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	cartRepository.Save(abandonedCart)
+	cartRepository.Save(activeCart)
+
+	e := echo.New()
+	e.GET("/customers/:customerId/carts", cartController.GetCustomerCarts)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/customers/%s/carts?status=abandoned", existingCustomer.GetID().String()), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var cartDtos []application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDtos)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Equal(t, 1, len(cartDtos)) {
+		assert.Equal(t, uuid.UUID(abandonedCart.GetID()), cartDtos[0].Id)
+		assert.Equal(t, "abandoned", cartDtos[0].Status)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/customers/%s/carts?status=forgotten", existingCustomer.GetID().String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_GivenACartWithAnItem_WhenTheProductPriceIsPATCHed_ThenTheCartIsRepricedAccordingToThePricingPolicy(t *testing.T) {
+	tests := []struct {
+		testName              string
+		policy                application.CartPricingPolicy
+		expectedItemResponse  string
+		expectedCheckoutCodes []int
+	}{
+		{"keep snapshot", application.KeepPriceSnapshot, `"unit_price":10.00,"currency":"USD","quantity":2,"price_changed":false}],"subtotal":20.00,"total":20.00}`, []int{http.StatusCreated}},
+		{"reprice on read", application.RepriceOnRead, `"unit_price":12.50,"currency":"USD","quantity":2,"price_changed":true,"added_unit_price":10.00}],"subtotal":25.00,"total":25.00}`, []int{http.StatusCreated}},
+		{"reprice on checkout", application.RepriceOnCheckout, `"unit_price":10.00,"currency":"USD","quantity":2,"price_changed":false}],"subtotal":20.00,"total":20.00}`, []int{http.StatusBadRequest, http.StatusCreated}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+			existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+			existingCart, _ := domain.NewCart(existingCustomer)
+			existingCart.AddItem(existingProduct, 2)
+
+			cartRepository := repositories.NewInMemoryCartRepository()
+			customerRepository := repositories.NewInMemoryCustomerRepository()
+			productRepository := repositories.NewInMemoryProductRepository()
+			eventDispatcher := events.NewSynchronousEventDispatcher()
+			exchangeRates := newExchangeRates(nil)
+			productService, _ := application.NewProductService(productRepository, eventDispatcher)
+			productController, _ := controllers.NewProductController(productService)
+			cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, eventDispatcher, exchangeRates, application.WithCartPricingPolicy(tc.policy))
+			cartController, _ := controllers.NewCartController(cartService)
+			var orderOptions []application.OrderServiceOption
+			if tc.policy == application.RepriceOnCheckout {
+				orderOptions = append(orderOptions, application.WithCheckoutRepricing(productRepository, exchangeRates))
+			}
+			orderService, _ := application.NewOrderService(cartRepository, repositories.NewInMemoryOrderRepository(), eventDispatcher, orderOptions...)
+			orderController, _ := controllers.NewOrderController(orderService)
+
+			customerRepository.Save(existingCustomer)
+			productRepository.Save(existingProduct)
+			cartRepository.Save(existingCart)
+
+			e := echo.New()
+			e.PATCH("/products/:productId", productController.UpdateProduct)
+			e.GET("/carts/:cartId", cartController.GetCart)
+			e.POST("/carts/:cartId/checkout", orderController.CheckoutCart)
+			e.Validator = config.NewRequestValidator()
+			serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+				request := httptest.NewRequest(method, path, strings.NewReader(body))
+				request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, request)
+				return rec
+			}
+
+			rec := serve(http.MethodPatch, "/products/"+existingProduct.GetID().String(), `{"unit_price":"12.50"}`)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			rec = serve(http.MethodGet, "/carts/"+existingCart.GetID().String(), "")
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Contains(t, rec.Body.String(), tc.expectedItemResponse)
+
+			for _, expectedCode := range tc.expectedCheckoutCodes {
+				rec = serve(http.MethodPost, "/carts/"+existingCart.GetID().String()+"/checkout", "")
+				assert.Equal(t, expectedCode, rec.Code)
+			}
+		})
+	}
+}
+
+func Test_GivenACartWithItems_WhenPOSTAndDELETECoupon_ThenTheCartTotalIsDiscounted(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	mortadela, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(mortadela, 3)
+
+	threeForTwo, _ := domain.NewBuyXGetY("mortadela 3x2", mortadela.GetID(), 2, 1)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+	promotionCatalog, _ := promotions.NewStaticPromotionCatalog([]domain.Coupon{coupon}, []domain.Promotion{threeForTwo})
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithPromotions(promotionCatalog))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(mortadela)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.POST("/carts/:cartId/coupons", cartController.ApplyCoupon)
+	e.DELETE("/carts/:cartId/coupons/:code", cartController.RemoveCoupon)
+	e.Validator = config.NewRequestValidator()
+
+	couponsPath := fmt.Sprintf("/carts/%s/coupons", uuid.UUID(existingCart.GetID()).String())
+	mortadelaId := uuid.UUID(mortadela.GetID()).String()
+
+	request := httptest.NewRequest(http.MethodPost, couponsPath, strings.NewReader(`{"code":"save10"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"coupons":["SAVE10"],"subtotal":30.00,"line_discounts":[{"promotion":"mortadela 3x2","product_id":"%s","amount":10.00}],"cart_discounts":[{"promotion":"SAVE10","amount":2.00}],"total":18.00}`, mortadelaId))
+
+	request = httptest.NewRequest(http.MethodPost, couponsPath, strings.NewReader(`{"code":"SAVE20"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, `{"message":"coupon with id SAVE20 not found"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	request = httptest.NewRequest(http.MethodDelete, couponsPath+"/SAVE10", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"subtotal":30.00,"line_discounts":[{"promotion":"mortadela 3x2","product_id":"%s","amount":10.00}],"total":20.00}`, mortadelaId))
+	savedCart, _ := cartRepository.FindByID(existingCart.GetID())
+	assert.Empty(t, savedCart.GetCoupons())
+	assert.Equal(t, testutil.USD("20.00"), savedCart.GetTotal())
+
+	request = httptest.NewRequest(http.MethodDelete, couponsPath+"/SAVE10", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func Test_GivenACustomerInATaxedRegion_WhenPOSTAddItemToCart_ThenTheCartIncludesLineAndTotalTax(t *testing.T) {
+	shippingAddress, _ := domain.NewAddress("350 5th Ave", "New York", "10118", "US")
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup", domain.WithShippingAddress(shippingAddress))
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	taxCalculator, _ := domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(8875, 100000)},
+	)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithTaxes(taxCalculator))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+	cartRepository.Save(existingCart)
+
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s", uuid.UUID(existingCart.GetID()).String()), strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":2}`, uuid.UUID(existingProduct.GetID()).String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.Validator = config.NewRequestValidator()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), fmt.Sprintf(`"items":[{"product_id":"%s","unit_price":10.00,"currency":"USD","quantity":2,"price_changed":false,"tax_category":"standard","tax_rate":"0.08875","tax":1.78}],"subtotal":20.00,"tax_region":"US","tax":1.78,"total":21.78}`, uuid.UUID(existingProduct.GetID()).String()))
+
+	savedCart, _ := cartRepository.FindByID(existingCart.GetID())
+	assert.Equal(t, testutil.USD("21.78"), savedCart.GetTotal())
+}
+
+func Test_GivenACartWithItems_WhenGETShippingOptionsAndPUTShipping_ThenTheCartTotalIncludesTheShippingCost(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	mortadela, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"), domain.WithWeight(1000))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(mortadela, 3)
+
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	express, _ := domain.NewWeightBasedShipping("express", "Express", testutil.USD("4.00"), testutil.USD("2.00"), 0)
+	shippingCatalog, _ := shipping.NewStaticShippingCatalog([]domain.ShippingMethod{standard, express})
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithShipping(shippingCatalog))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(mortadela)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.GET("/carts/:cartId/shipping-options", cartController.GetShippingOptions)
+	e.PUT("/carts/:cartId/shipping", cartController.SelectShipping)
+	e.Validator = config.NewRequestValidator()
+
+	cartPath := fmt.Sprintf("/carts/%s", uuid.UUID(existingCart.GetID()).String())
+
+	request := httptest.NewRequest(http.MethodGet, cartPath+"/shipping-options", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `[{"method":"standard","name":"Standard","cost":5.00,"currency":"USD","selected":false},{"method":"express","name":"Express","cost":10.00,"currency":"USD","selected":false}]`, strings.Trim(rec.Body.String(), "\n"))
+
+	request = httptest.NewRequest(http.MethodPut, cartPath+"/shipping", strings.NewReader(`{"method":"express"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"subtotal":30.00,"shipping":{"method":"express","name":"Express","cost":10.00},"total":40.00}`)
+	savedCart, _ := cartRepository.FindByID(existingCart.GetID())
+	assert.Equal(t, testutil.USD("40.00"), savedCart.GetTotal())
+
+	request = httptest.NewRequest(http.MethodPut, cartPath+"/shipping", strings.NewReader(`{"method":"overnight"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, `{"message":"shipping method with id overnight not found"}`, strings.Trim(rec.Body.String(), "\n"))
+}
+
+func newExchangeRates(rates map[string]map[string]string) domain.ExchangeRateProvider {
+	provider, err := exchangerates.NewStaticExchangeRateProvider(rates)
+	if err != nil {
+		panic(err)
+	}
+
+	return provider
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConcurrentCartServer(t *testing.T) (*echo.Echo, domain.CustomerRepository, domain.ProductRepository, domain.CartRepository) {
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartService, err := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cartController, _ := controllers.NewCartController(cartService)
+
+	e := echo.New()
+	e.POST("/carts", cartController.CreateNewCart)
+	e.GET("/carts/:cartId", cartController.GetCart)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.Validator = config.NewRequestValidator()
+
+	return e, customerRepository, productRepository, cartRepository
+}
+
+func createCart(t *testing.T, e *echo.Echo, customer *domain.Customer) string {
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, uuid.UUID(customer.GetID()).String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	return cartDto.Id.String()
+}
+
+func addToCart(e *echo.Echo, cartId string, product *domain.Product, quantity int) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/carts/"+cartId, strings.NewReader(fmt.Sprintf(`{"product_id":"%s","quantity":%d}`, uuid.UUID(product.GetID()).String(), quantity)))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	return rec
+}
+
+func Test_GivenManyCustomers_WhenPOSTAddItemToCartConcurrently_ThenEveryCartHoldsItsOwnItems(t *testing.T) {
+	e, customerRepository, productRepository, cartRepository := newConcurrentCartServer(t)
+	var products []*domain.Product
+	for _, name := range []string{"Mortadela 1 Kg", "Queso Cremoso 1 Kg", "Salame Milan 1 Kg"} {
+		product, _ := domain.NewProduct(name, testutil.USD("10.00"))
+		productRepository.Save(product)
+		products = append(products, product)
+	}
+
+	const customers = 20
+	var cartIds []string
+	for customerNumber := 0; customerNumber < customers; customerNumber++ {
+		customer, _ := domain.NewCustomer(fmt.Sprintf("Customer %03d", customerNumber))
+		customerRepository.Save(customer)
+		cartIds = append(cartIds, createCart(t, e, customer))
+	}
+
+	var wg sync.WaitGroup
+	for _, cartId := range cartIds {
+		wg.Add(1)
+		go func(cartId string) {
+			defer wg.Done()
+			for _, product := range products {
+				rec := addToCart(e, cartId, product, 2)
+				assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+				request := httptest.NewRequest(http.MethodGet, "/carts/"+cartId, nil)
+				e.ServeHTTP(httptest.NewRecorder(), request)
+			}
+		}(cartId)
+	}
+	wg.Wait()
+
+	for _, cartId := range cartIds {
+		id, _ := uuid.Parse(cartId)
+		cart, err := cartRepository.FindByID(domain.CartId(id))
+		if assert.Nil(t, err) {
+			assert.Equal(t, 6, cart.Size())
+			assert.Equal(t, testutil.USD("60.00"), cart.GetTotal())
+		}
+	}
+}
+
+func Test_GivenASharedCart_WhenPOSTAddItemToCartFromManyGoroutines_ThenEveryAcceptedItemIsKept(t *testing.T) {
+	e, customerRepository, productRepository, cartRepository := newConcurrentCartServer(t)
+	customer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	customerRepository.Save(customer)
+	product, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	productRepository.Save(product)
+	cartId := createCart(t, e, customer)
+
+	const requests = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for request := 0; request < requests; request++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := addToCart(e, cartId, product, 1)
+			if rec.Code != http.StatusConflict {
+				assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			}
+			if rec.Code == http.StatusOK {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	id, _ := uuid.Parse(cartId)
+	cart, err := cartRepository.FindByID(domain.CartId(id))
+	if assert.Nil(t, err) && assert.Len(t, cart.GetItems(), 1) {
+		assert.Equal(t, accepted, cart.Size())
+		assert.Equal(t, accepted+1, cart.GetVersion())
+		total, err := product.GetPrice().Multiply(int64(accepted))
+		assert.Nil(t, err)
+		assert.Equal(t, total, cart.GetTotal())
+	}
+}
+
+func Test_GivenACart_WhenPOSTAddItemToCartWithIfMatch_ThenOnlyTheCurrentETagIsAccepted(t *testing.T) {
+	e, customerRepository, productRepository, cartRepository := newConcurrentCartServer(t)
+	customer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	customerRepository.Save(customer)
+	product, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	productRepository.Save(product)
+	cartId := createCart(t, e, customer)
+
+	request := httptest.NewRequest(http.MethodGet, "/carts/"+cartId, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+
+	addWithIfMatch := func(ifMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/carts/"+cartId, strings.NewReader(fmt.Sprintf(`{"product_id":"%s","quantity":1}`, uuid.UUID(product.GetID()).String())))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Add("If-Match", ifMatch)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+
+	rec = addWithIfMatch(etag)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"2"`, rec.Header().Get("ETag"))
+
+	rec = addWithIfMatch(etag)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	rec = addWithIfMatch("*")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	id, _ := uuid.Parse(cartId)
+	cart, _ := cartRepository.FindByID(domain.CartId(id))
+	assert.Equal(t, 2, cart.Size())
+}
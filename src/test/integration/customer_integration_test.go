@@ -11,6 +11,7 @@ import (
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ import (
 
 func Test_GivenAValidNewCustomerRequest_WhenPOSTNewCustomer_ThenReturn200(t *testing.T) {
 	customerRepository := repositories.NewInMemoryCustomerRepository()
-	customerService, _ := application.NewCustomerService(customerRepository)
+	customerService, _ := application.NewCustomerService(customerRepository, events.NewSynchronousEventDispatcher())
 	customerController, _ := controllers.NewCustomerController(customerService)
 
 	request := httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(`{"customer_name":"Linus Torvalds"}`))
@@ -78,7 +79,7 @@ func Test_GivenAnInvalidNewCustomerRequest_WhenPOSTNewCustomer_ThenReturn400Erro
 	for _, tc := range tests {
 		t.Run(tc.testName, func(t *testing.T) {
 			customerRepository := repositories.NewInMemoryCustomerRepository()
-			customerService, _ := application.NewCustomerService(customerRepository)
+			customerService, _ := application.NewCustomerService(customerRepository, events.NewSynchronousEventDispatcher())
 			customerController, _ := controllers.NewCustomerController(customerService)
 
 			request := httptest.NewRequest(http.MethodPost, "/customers", strings.NewReader(tc.requestBody))
@@ -97,3 +98,70 @@ func Test_GivenAnInvalidNewCustomerRequest_WhenPOSTNewCustomer_ThenReturn400Erro
 	}
 
 }
+
+func Test_GivenAnExistingCustomer_WhenGETCustomer_ThenReturn200(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Linus Torvalds")
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	customerService, _ := application.NewCustomerService(customerRepository, events.NewSynchronousEventDispatcher())
+	customerController, _ := controllers.NewCustomerController(customerService)
+	customerRepository.Save(existingCustomer)
+
+	request := httptest.NewRequest(http.MethodGet, "/customers/"+existingCustomer.GetID().String(), nil)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.GET("/customers/:customerId", customerController.GetCustomer)
+	e.Validator = config.NewRequestValidator()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"id":"`+existingCustomer.GetID().String()+`","name":"Linus Torvalds","active":true}`, strings.Trim(rec.Body.String(), "\n"))
+}
+
+func Test_GivenExistingCustomers_WhenPATCHedAndDELETEd_ThenTheProfileIsUpdatedAndCartsAreBlocked(t *testing.T) {
+	linus, _ := domain.NewCustomer("Linus Torvalds")
+	takenEmail, _ := domain.NewEmail("ken@example.com")
+	ken, _ := domain.NewCustomer("Ken Thompson", domain.WithEmail(takenEmail))
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	customerService, _ := application.NewCustomerService(customerRepository, eventDispatcher)
+	customerController, _ := controllers.NewCustomerController(customerService)
+	cartService, _ := application.NewCartService(repositories.NewInMemoryCartRepository(), customerRepository, repositories.NewInMemoryProductRepository(), eventDispatcher, newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+	customerRepository.Save(linus)
+	customerRepository.Save(ken)
+	customerPath := "/customers/" + linus.GetID().String()
+
+	e := echo.New()
+	e.POST("/customers", customerController.CreateNewCustomer)
+	e.PATCH("/customers/:customerId", customerController.UpdateCustomer)
+	e.DELETE("/customers/:customerId", customerController.DeactivateCustomer)
+	e.POST("/carts", cartController.CreateNewCart)
+	e.Validator = config.NewRequestValidator()
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+
+	rec := serve(http.MethodPatch, customerPath, `{"email":"linus@example.com","phone":"+1 503 555 0100","billing_address":{"street":"1 Kernel Way","city":"Portland","postal_code":"97201","country":"us"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"id":"`+linus.GetID().String()+`","name":"Linus Torvalds","email":"linus@example.com","phone":"+15035550100","billing_address":{"street":"1 Kernel Way","city":"Portland","postal_code":"97201","country":"US"},"active":true}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodPost, "/customers", `{"customer_name":"Kenneth Thompson","email":"KEN@example.com"}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, `{"message":"email \"ken@example.com\" is already in use","field":"email"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodPatch, customerPath, `{"shipping_address":{"street":"1 Kernel Way"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(http.MethodDelete, customerPath, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"active":false`)
+
+	rec = serve(http.MethodPost, "/carts", `{"customer_id":"`+linus.GetID().String()+`"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"invalid customer: it is deactivated"}`, strings.Trim(rec.Body.String(), "\n"))
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/infrastructure/shipping"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileBackedServer(t *testing.T, directory string) (*echo.Echo, domain.CustomerRepository, domain.ProductRepository) {
+	productRepository, err := repositories.NewFileProductRepository(directory)
+	assert.Nil(t, err)
+	customerRepository, err := repositories.NewFileCustomerRepository(directory)
+	assert.Nil(t, err)
+	cartRepository, err := repositories.NewFileCartRepository(directory)
+	assert.Nil(t, err)
+
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	shippingCatalog, _ := shipping.NewStaticShippingCatalog([]domain.ShippingMethod{standard})
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithShipping(shippingCatalog))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	e := echo.New()
+	e.POST("/carts", cartController.CreateNewCart)
+	e.GET("/carts/:cartId", cartController.GetCart)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.PUT("/carts/:cartId/shipping", cartController.SelectShipping)
+	e.Validator = config.NewRequestValidator()
+
+	return e, customerRepository, productRepository
+}
+
+func Test_GivenFileBackedRepositories_WhenTheServerRestarts_ThenTheCartIsServedUnchanged(t *testing.T) {
+	directory := t.TempDir()
+	e, customerRepository, productRepository := newFileBackedServer(t, directory)
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, uuid.UUID(existingCustomer.GetID()).String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	cartId := cartDto.Id.String()
+
+	request = httptest.NewRequest(http.MethodPost, "/carts/"+cartId, strings.NewReader(fmt.Sprintf(`{"product_id":"%s","quantity":2}`, uuid.UUID(existingProduct.GetID()).String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	request = httptest.NewRequest(http.MethodPut, "/carts/"+cartId+"/shipping", strings.NewReader(`{"method":"standard"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	cartBeforeRestart := rec.Body.String()
+
+	restarted, _, _ := newFileBackedServer(t, directory)
+	request = httptest.NewRequest(http.MethodGet, "/carts/"+cartId, nil)
+	rec = httptest.NewRecorder()
+	restarted.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, cartBeforeRestart, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"subtotal":20.00,"shipping":{"method":"standard","name":"Standard","cost":5.00},"total":25.00}`)
+}
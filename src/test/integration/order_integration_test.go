@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenACartWithItems_WhenPOSTCheckoutAndGETOrder_ThenTheOrderIsPlacedAndTheCartIsLocked(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(existingProduct, 2)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	orderRepository := repositories.NewInMemoryOrderRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, eventDispatcher, newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+	orderService, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher)
+	orderController, _ := controllers.NewOrderController(orderService)
+
+	var placedOrders []domain.OrderPlaced
+	domain.RegisterEventHandler(eventDispatcher, func(event domain.OrderPlaced) error {
+		placedOrders = append(placedOrders, event)
+		return nil
+	})
+
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.POST("/carts/:cartId/checkout", orderController.CheckoutCart)
+	e.GET("/orders/:orderId", orderController.GetOrder)
+	e.Validator = config.NewRequestValidator()
+
+	checkoutPath := fmt.Sprintf("/carts/%s/checkout", existingCart.GetID().String())
+	request := httptest.NewRequest(http.MethodPost, checkoutPath, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var orderDto application.OrderDto
+	json.Unmarshal(rec.Body.Bytes(), &orderDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, uuid.UUID(existingCart.GetID()), orderDto.CartId)
+	assert.Equal(t, application.PriceDto(testutil.USD("20.00")), orderDto.Total)
+	if assert.Equal(t, 1, len(placedOrders)) {
+		assert.Equal(t, domain.OrderId(orderDto.Id), placedOrders[0].OrderId)
+	}
+
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/orders/%s", orderDto.Id.String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var fetchedOrder application.OrderDto
+	json.Unmarshal(rec.Body.Bytes(), &fetchedOrder)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, orderDto.Id, fetchedOrder.Id)
+	assert.Equal(t, 1, len(fetchedOrder.Lines))
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s", existingCart.GetID().String()), strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":1}`, existingProduct.GetID().String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"invalid cart: it is already checked out"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	request = httptest.NewRequest(http.MethodPost, checkoutPath, nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
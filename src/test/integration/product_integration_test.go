@@ -11,17 +11,19 @@ import (
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GivenAValidNewProductRequest_WhenPOSTNewProduct_ThenReturn200(t *testing.T) {
 	productRepository := repositories.NewInMemoryProductRepository()
-	productService, _ := application.NewProductService(productRepository)
+	productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
 	productController, _ := controllers.NewProductController(productService)
 
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":1.10}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":1.10,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 
@@ -36,11 +38,36 @@ func Test_GivenAValidNewProductRequest_WhenPOSTNewProduct_ThenReturn200(t *testi
 	assert.Equal(t, http.StatusCreated, rec.Code)
 	assert.NotEmpty(t, productDto.Id)
 	assert.Equal(t, "Pepsi Light 2.5Lt", productDto.Name)
-	assert.Equal(t, application.PriceDto(1.10), productDto.UnitPrice)
+	assert.Equal(t, application.PriceDto(testutil.USD("1.10")), productDto.UnitPrice)
 	savedProduct, _ := productRepository.FindByID(domain.ProductId(productDto.Id))
 	if assert.NotNil(t, savedProduct) {
 		assert.Equal(t, "Pepsi Light 2.5Lt", savedProduct.GetName())
-		assert.Equal(t, 1.10, savedProduct.GetPrice())
+		assert.Equal(t, testutil.USD("1.10"), savedProduct.GetPrice())
+	}
+}
+
+func Test_GivenANewProductRequestWithAStringUnitPrice_WhenPOSTNewProduct_ThenStoreTheExactAmount(t *testing.T) {
+	productRepository := repositories.NewInMemoryProductRepository()
+	productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
+	productController, _ := controllers.NewProductController(productService)
+
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":"0.10","sku":"PEP-25"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.POST("/products", productController.CreateNewProduct)
+	e.Validator = config.NewRequestValidator()
+	e.ServeHTTP(rec, request)
+
+	var productDto application.ProductDto
+	json.Unmarshal(rec.Body.Bytes(), &productDto)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"unit_price":0.10`)
+	savedProduct, _ := productRepository.FindByID(domain.ProductId(productDto.Id))
+	if assert.NotNil(t, savedProduct) {
+		assert.Equal(t, int64(10), savedProduct.GetPrice().MinorUnits())
 	}
 }
 
@@ -53,50 +80,56 @@ func Test_GivenAnInvalidNewProductRequest_WhenPOSTNewProduct_ThenReturn400ErrorR
 	}{
 		{
 			testName:             "product name too short",
-			requestBody:          `{"product_name":"PepsiPeps","unit_price":1.10}`,
+			requestBody:          `{"product_name":"PepsiPeps","unit_price":1.10,"sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"ProductName","error":"ProductName must be at least 10 characters in length"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "product name filled with whitespaces",
-			requestBody:          `{"product_name":"PepsiPeps          ","unit_price":1.10}`,
+			requestBody:          `{"product_name":"PepsiPeps          ","unit_price":1.10,"sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"invalid arguments"}`,
 			expectedResponseCode: http.StatusInternalServerError,
 		},
 		{
 			testName:             "product name is nil",
-			requestBody:          `{"unit_price":1.10}`,
+			requestBody:          `{"unit_price":1.10,"sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"ProductName","error":"ProductName is a required field"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "product name is of invalid type",
-			requestBody:          `{"product_name":123,"unit_price":1.10}`,
+			requestBody:          `{"product_name":123,"unit_price":1.10,"sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"Unmarshal type error: expected=string, got=number, field=product_name, offset=19"}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "unit price is nil",
-			requestBody:          `{"product_name":"Pepsi Light 2.5Lt"}`,
+			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"UnitPrice","error":"UnitPrice is a required field"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "unit price is negative",
-			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","unit_price":-1.10}`,
+			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","unit_price":-1.10,"sku":"PEP-25"}`,
+			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"UnitPrice","error":"UnitPrice must be greater than 0"}]}`,
+			expectedResponseCode: http.StatusBadRequest,
+		},
+		{
+			testName:             "unit price is a negative numeric string",
+			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","unit_price":"-1.10","sku":"PEP-25"}`,
 			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"UnitPrice","error":"UnitPrice must be greater than 0"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "unit price is of invalid type",
-			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","unit_price":"-1.10"}`,
-			expectedResponseBody: `{"message":"Unmarshal type error: expected=float64, got=string, field=unit_price, offset=56"}`,
+			requestBody:          `{"product_name":"Pepsi Light 2.5Lt","unit_price":true,"sku":"PEP-25"}`,
+			expectedResponseBody: `{"message":"amount must be a number or a numeric string"}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 		{
 			testName:             "multiple validation errors",
 			requestBody:          `{}`,
-			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"ProductName","error":"ProductName is a required field"},{"field":"UnitPrice","error":"UnitPrice is a required field"}]}`,
+			expectedResponseBody: `{"message":"there were validation errors","validation_errors":[{"field":"SKU","error":"SKU is a required field"},{"field":"ProductName","error":"ProductName is a required field"},{"field":"UnitPrice","error":"UnitPrice is a required field"}]}`,
 			expectedResponseCode: http.StatusBadRequest,
 		},
 	}
@@ -104,7 +137,7 @@ func Test_GivenAnInvalidNewProductRequest_WhenPOSTNewProduct_ThenReturn400ErrorR
 	for _, tc := range tests {
 		t.Run(tc.testName, func(t *testing.T) {
 			productRepository := repositories.NewInMemoryProductRepository()
-			productService, _ := application.NewProductService(productRepository)
+			productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
 			productController, _ := controllers.NewProductController(productService)
 
 			request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(tc.requestBody))
@@ -123,3 +156,190 @@ func Test_GivenAnInvalidNewProductRequest_WhenPOSTNewProduct_ThenReturn400ErrorR
 	}
 
 }
+
+func Test_GivenAnExistingProduct_WhenPOSTProductWithTheSameName_ThenReturn409(t *testing.T) {
+	existingProduct, _ := domain.NewProduct("Pepsi Light 2.5Lt", testutil.USD("1.10"))
+	productRepository := repositories.NewInMemoryProductRepository()
+	productRepository.Save(existingProduct)
+	productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
+	productController, _ := controllers.NewProductController(productService)
+
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"PEPSI LIGHT 2.5LT","unit_price":1.20,"sku":"PEP-25"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.POST("/products", productController.CreateNewProduct)
+	e.Validator = config.NewRequestValidator()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, `{"message":"product_name \"pepsi light 2.5lt\" is already in use","field":"product_name"}`, strings.Trim(rec.Body.String(), "\n"))
+	page, _ := productRepository.List(domain.ProductListQuery{})
+	assert.Len(t, page.Products, 1)
+}
+
+func Test_GivenAnExistingProduct_WhenGETProduct_ThenReturn200(t *testing.T) {
+	existingProduct, _ := domain.NewProduct("Pepsi Light 2.5Lt", testutil.USD("1.10"))
+	productRepository := repositories.NewInMemoryProductRepository()
+	productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
+	productController, _ := controllers.NewProductController(productService)
+	productRepository.Save(existingProduct)
+
+	request := httptest.NewRequest(http.MethodGet, "/products/"+existingProduct.GetID().String(), nil)
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.GET("/products/:productId", productController.GetProduct)
+	e.Validator = config.NewRequestValidator()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"id":"`+existingProduct.GetID().String()+`","name":"Pepsi Light 2.5Lt","unit_price":1.10,"currency":"USD","tax_category":"standard","archived":false}`, strings.Trim(rec.Body.String(), "\n"))
+}
+
+func Test_GivenAProductCreatedWithASKU_WhenGETBySKUAndAddedToACartBySKU_ThenTheSameProductIsUsed(t *testing.T) {
+	customer, _ := domain.NewCustomer("Barbara Liskov")
+	cart, _ := domain.NewCart(customer)
+	cartRepository := repositories.NewInMemoryCartRepository()
+	cartRepository.Save(cart)
+	productRepository := repositories.NewInMemoryProductRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	productService, _ := application.NewProductService(productRepository, eventDispatcher)
+	productController, _ := controllers.NewProductController(productService)
+	cartService, _ := application.NewCartService(cartRepository, repositories.NewInMemoryCustomerRepository(), productRepository, eventDispatcher, newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	e := echo.New()
+	e.POST("/products", productController.CreateNewProduct)
+	e.GET("/products/by-sku/:sku", productController.GetProductBySKU)
+	e.GET("/products/:productId", productController.GetProduct)
+	e.POST("/carts/:cartId/items", cartController.AddItemToCart)
+	e.Validator = config.NewRequestValidator()
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/products", `{"sku":"pep-25","product_name":"Pepsi Light 2.5Lt","unit_price":1.10}`)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var created application.ProductDto
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	assert.Equal(t, "PEP-25", created.SKU)
+
+	rec = serve(http.MethodPost, "/products", `{"sku":"PEP-25","product_name":"Pepsi Regular 2.5Lt","unit_price":1.10}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, `{"message":"sku \"PEP-25\" is already in use","field":"sku"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodGet, "/products/by-sku/PEP-25", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"id":"`+created.Id.String()+`","sku":"PEP-25","name":"Pepsi Light 2.5Lt","unit_price":1.10,"currency":"USD","tax_category":"standard","archived":false}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodGet, "/products/by-sku/PEP-99", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = serve(http.MethodPost, "/carts/"+cart.GetID().String()+"/items", `{"sku":"pep-25","quantity":2}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"product_id":"`+created.Id.String()+`"`)
+
+	rec = serve(http.MethodPost, "/carts/"+cart.GetID().String()+"/items", `{"product_id":"`+created.Id.String()+`","sku":"PEP-25","quantity":1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"there were validation errors","validation_errors":[{"field":"SKU","error":"SKU cannot be set together with ProductId"}]}`, strings.Trim(rec.Body.String(), "\n"))
+}
+
+func Test_GivenAProductCatalog_WhenGETProductsPageByPage_ThenReturnEveryMatchingProduct(t *testing.T) {
+	productRepository := repositories.NewInMemoryProductRepository()
+	productService, _ := application.NewProductService(productRepository, events.NewSynchronousEventDispatcher())
+	productController, _ := controllers.NewProductController(productService)
+	for _, name := range []string{"Pepsi Light 2.5Lt", "Pepsi Regular 1Lt", "Pepsi Black 500ml", "Mortadela 1 Kg"} {
+		product, _ := domain.NewProduct(name, testutil.USD("1.50"))
+		productRepository.Save(product)
+	}
+
+	e := echo.New()
+	e.GET("/products", productController.ListProducts)
+	e.Validator = config.NewRequestValidator()
+
+	var names []string
+	path := "/products?q=pepsi&limit=2"
+	for path != "" {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+
+		var page application.ProductPageDto
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		if !assert.Equal(t, http.StatusOK, rec.Code) {
+			return
+		}
+		for _, product := range page.Items {
+			names = append(names, product.Name)
+		}
+
+		path = ""
+		if page.NextCursor != nil {
+			path = "/products?q=pepsi&limit=2&cursor=" + *page.NextCursor
+		}
+	}
+
+	assert.Equal(t, []string{"Pepsi Black 500ml", "Pepsi Light 2.5Lt", "Pepsi Regular 1Lt"}, names)
+}
+
+func Test_GivenAnExistingProduct_WhenPATCHedAndArchived_ThenItIsHiddenFromListingAndCannotBeAddedToCarts(t *testing.T) {
+	customer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	cart, _ := domain.NewCart(customer)
+	existingProduct, _ := domain.NewProduct("Pepsi Light 2.5Lt", testutil.USD("1.10"))
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	productService, _ := application.NewProductService(productRepository, eventDispatcher)
+	productController, _ := controllers.NewProductController(productService)
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, eventDispatcher, newExchangeRates(nil))
+	cartController, _ := controllers.NewCartController(cartService)
+	productRepository.Save(existingProduct)
+	customerRepository.Save(customer)
+	cartRepository.Save(cart)
+	productPath := "/products/" + existingProduct.GetID().String()
+
+	e := echo.New()
+	e.GET("/products", productController.ListProducts)
+	e.PATCH("/products/:productId", productController.UpdateProduct)
+	e.DELETE("/products/:productId", productController.ArchiveProduct)
+	e.POST("/products/:productId/restore", productController.RestoreProduct)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.Validator = config.NewRequestValidator()
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+	addItemBody := `{"product_id":"` + existingProduct.GetID().String() + `","quantity":1}`
+
+	rec := serve(http.MethodPatch, productPath, `{"product_name":"Pepsi Light 3Lt","unit_price":"1.45","sku":"PEP-25"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"id":"`+existingProduct.GetID().String()+`","name":"Pepsi Light 3Lt","unit_price":1.45,"currency":"USD","tax_category":"standard","archived":false}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodDelete, productPath, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"archived":true`)
+
+	rec = serve(http.MethodGet, "/products", "")
+	assert.Equal(t, `{"items":[],"next_cursor":null}`, strings.Trim(rec.Body.String(), "\n"))
+	rec = serve(http.MethodGet, "/products?include_archived=true", "")
+	assert.Contains(t, rec.Body.String(), existingProduct.GetID().String())
+
+	rec = serve(http.MethodPost, "/carts/"+cart.GetID().String(), addItemBody)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, `{"code":"product_archived","message":"product `+existingProduct.GetID().String()+` is archived and can no longer be added to carts"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodPost, productPath+"/restore", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = serve(http.MethodPost, "/carts/"+cart.GetID().String(), addItemBody)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,179 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenACart_WhenPOSTQuoteAndAcceptIt_ThenTheQuoteIsAcceptedWithFrozenPrices(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(existingProduct, 3)
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	quoteRepository := repositories.NewInMemoryQuoteRepository()
+	quoteService, _ := application.NewQuoteService(cartRepository, quoteRepository, events.NewSynchronousEventDispatcher(), clock)
+	quoteController, _ := controllers.NewQuoteController(quoteService)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.POST("/carts/:cartId/quotes", quoteController.CreateQuote)
+	e.GET("/quotes/:quoteId", quoteController.GetQuote)
+	e.POST("/quotes/:quoteId/accept", quoteController.AcceptQuote)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s/quotes", existingCart.GetID().String()), strings.NewReader(`{"valid_for_hours":2}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var quoteDto application.QuoteDto
+	json.Unmarshal(rec.Body.Bytes(), &quoteDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "Q-20240102-000001", quoteDto.Number)
+	assert.Equal(t, application.PriceDto(testutil.USD("30.00")), quoteDto.Total)
+
+	existingCart.AddItem(existingProduct, 1)
+	clock.now = clock.now.Add(time.Hour)
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/quotes/%s/accept", quoteDto.Id.String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var acceptedQuote application.QuoteDto
+	json.Unmarshal(rec.Body.Bytes(), &acceptedQuote)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "accepted", acceptedQuote.Status)
+	assert.Equal(t, application.PriceDto(testutil.USD("30.00")), acceptedQuote.Total)
+}
+
+func Test_GivenAnExpiredQuote_WhenPOSTAccept_ThenReturn400AndTheQuoteIsExpired(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(existingProduct, 1)
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	quoteRepository := repositories.NewInMemoryQuoteRepository()
+	quoteService, _ := application.NewQuoteService(cartRepository, quoteRepository, events.NewSynchronousEventDispatcher(), clock)
+	quoteController, _ := controllers.NewQuoteController(quoteService)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.POST("/carts/:cartId/quotes", quoteController.CreateQuote)
+	e.GET("/quotes/:quoteId", quoteController.GetQuote)
+	e.POST("/quotes/:quoteId/accept", quoteController.AcceptQuote)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s/quotes", existingCart.GetID().String()), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	var quoteDto application.QuoteDto
+	json.Unmarshal(rec.Body.Bytes(), &quoteDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, clock.now.Add(72*time.Hour), quoteDto.ExpiresAt)
+
+	clock.now = clock.now.Add(72 * time.Hour)
+
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/quotes/%s/accept", quoteDto.Id.String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"invalid quote: it expired at 2024-01-05T03:04:05Z"}`, strings.Trim(rec.Body.String(), "\n"))
+
+	request = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/quotes/%s", quoteDto.Id.String()), nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"expired"`)
+}
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func Test_GivenAnAcceptedQuote_WhenPOSTCheckoutWithTheQuote_ThenTheOrderIsPlacedAtTheQuotedPrices(t *testing.T) {
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	existingCart, _ := domain.NewCart(existingCustomer)
+	existingCart.AddItem(existingProduct, 3)
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	productRepository := repositories.NewInMemoryProductRepository()
+	cartRepository := repositories.NewInMemoryCartRepository()
+	quoteRepository := repositories.NewInMemoryQuoteRepository()
+	orderRepository := repositories.NewInMemoryOrderRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	quoteService, _ := application.NewQuoteService(cartRepository, quoteRepository, eventDispatcher, clock)
+	quoteController, _ := controllers.NewQuoteController(quoteService)
+	orderService, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher, application.WithCheckoutRepricing(productRepository, newExchangeRates(nil)), application.WithQuotes(quoteRepository, clock))
+	orderController, _ := controllers.NewOrderController(orderService)
+	productRepository.Save(existingProduct)
+	cartRepository.Save(existingCart)
+
+	e := echo.New()
+	e.POST("/carts/:cartId/quotes", quoteController.CreateQuote)
+	e.POST("/quotes/:quoteId/accept", quoteController.AcceptQuote)
+	e.POST("/carts/:cartId/checkout", orderController.CheckoutCart)
+	e.Validator = config.NewRequestValidator()
+
+	request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s/quotes", existingCart.GetID().String()), nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	var quoteDto application.QuoteDto
+	json.Unmarshal(rec.Body.Bytes(), &quoteDto)
+
+	checkout := func() *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/carts/%s/checkout", existingCart.GetID().String()), strings.NewReader(fmt.Sprintf(`{"quote_id":"%s"}`, quoteDto.Id.String())))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+
+	rec = checkout()
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"message":"invalid quote: it is pending and must be accepted before checkout"}`+"\n", rec.Body.String())
+
+	existingProduct.ChangePrice(testutil.USD("12.00"))
+	productRepository.Save(existingProduct)
+	clock.now = clock.now.Add(time.Hour)
+	request = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/quotes/%s/accept", quoteDto.Id.String()), nil)
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	rec = checkout()
+
+	var orderDto application.OrderDto
+	json.Unmarshal(rec.Body.Bytes(), &orderDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	if assert.NotNil(t, orderDto.QuoteId) {
+		assert.Equal(t, quoteDto.Id, *orderDto.QuoteId)
+	}
+	assert.Equal(t, application.PriceDto(testutil.USD("30.00")), orderDto.Total)
+	assert.Equal(t, clock.now, orderDto.PlacedOn)
+}
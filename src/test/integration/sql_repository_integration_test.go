@@ -0,0 +1,117 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/infrastructure/shipping"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLBackedServer(t *testing.T, dsn string) (*echo.Echo, domain.CustomerRepository, domain.ProductRepository) {
+	db, err := database.Open(database.SQLiteDriver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	productRepository, err := repositories.NewSQLProductRepository(db)
+	assert.Nil(t, err)
+	customerRepository, err := repositories.NewSQLCustomerRepository(db)
+	assert.Nil(t, err)
+	cartRepository, err := repositories.NewSQLCartRepository(db)
+	assert.Nil(t, err)
+
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	shippingCatalog, _ := shipping.NewStaticShippingCatalog([]domain.ShippingMethod{standard})
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, events.NewSynchronousEventDispatcher(), newExchangeRates(nil), application.WithShipping(shippingCatalog))
+	cartController, _ := controllers.NewCartController(cartService)
+
+	e := echo.New()
+	e.POST("/carts", cartController.CreateNewCart)
+	e.GET("/carts/:cartId", cartController.GetCart)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.PUT("/carts/:cartId/shipping", cartController.SelectShipping)
+	e.GET("/customers/:customerId/carts", cartController.GetCustomerCarts)
+	e.Validator = config.NewRequestValidator()
+
+	return e, customerRepository, productRepository
+}
+
+func Test_GivenSQLRepositories_WhenTheServerRestarts_ThenTheCartIsServedUnchanged(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "go-startup.db")
+	e, customerRepository, productRepository := newSQLBackedServer(t, dsn)
+	existingCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	anotherProduct, _ := domain.NewProduct("Queso Cremoso 1 Kg", testutil.USD("9.00"))
+	customerRepository.Save(existingCustomer)
+	productRepository.Save(existingProduct)
+	productRepository.Save(anotherProduct)
+
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, uuid.UUID(existingCustomer.GetID()).String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	var cartDto application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartDto)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	cartId := cartDto.Id.String()
+
+	for _, item := range []string{
+		fmt.Sprintf(`{"product_id":"%s","quantity":2}`, uuid.UUID(existingProduct.GetID()).String()),
+		fmt.Sprintf(`{"product_id":"%s","quantity":1}`, uuid.UUID(anotherProduct.GetID()).String()),
+	} {
+		request = httptest.NewRequest(http.MethodPost, "/carts/"+cartId, strings.NewReader(item))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	request = httptest.NewRequest(http.MethodPut, "/carts/"+cartId+"/shipping", strings.NewReader(`{"method":"standard"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, request)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var cartBeforeRestart application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &cartBeforeRestart)
+
+	restarted, _, _ := newSQLBackedServer(t, dsn)
+	request = httptest.NewRequest(http.MethodGet, "/carts/"+cartId, nil)
+	rec = httptest.NewRecorder()
+	restarted.ServeHTTP(rec, request)
+
+	var restartedCart application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &restartedCart)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.ElementsMatch(t, cartBeforeRestart.Items, restartedCart.Items)
+	cartBeforeRestart.Items, restartedCart.Items = nil, nil
+	assert.Equal(t, cartBeforeRestart, restartedCart)
+	assert.Contains(t, rec.Body.String(), `"subtotal":29.00,"shipping":{"method":"standard","name":"Standard","cost":5.00},"total":34.00}`)
+
+	request = httptest.NewRequest(http.MethodGet, "/customers/"+uuid.UUID(existingCustomer.GetID()).String()+"/carts", nil)
+	rec = httptest.NewRecorder()
+	restarted.ServeHTTP(rec, request)
+
+	var customerCarts []application.CartDto
+	json.Unmarshal(rec.Body.Bytes(), &customerCarts)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Equal(t, 1, len(customerCarts)) {
+		assert.Equal(t, cartId, customerCarts[0].Id.String())
+	}
+}
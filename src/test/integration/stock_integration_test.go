@@ -0,0 +1,85 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenARestockedProduct_WhenTwoCartsCompeteForItAndOneChecksOut_ThenStockIsReservedAndCommitted(t *testing.T) {
+	firstCustomer, _ := domain.NewCustomer("Bjarne Stroustrup")
+	secondCustomer, _ := domain.NewCustomer("Dennis Ritchie")
+	existingProduct, _ := domain.NewProduct("Mortadela 1 Kg", testutil.USD("10.00"))
+	firstCart, _ := domain.NewCart(firstCustomer)
+	secondCart, _ := domain.NewCart(secondCustomer)
+
+	cartRepository := repositories.NewInMemoryCartRepository()
+	customerRepository := repositories.NewInMemoryCustomerRepository()
+	productRepository := repositories.NewInMemoryProductRepository()
+	stockRepository := repositories.NewInMemoryStockRepository()
+	eventDispatcher := events.NewSynchronousEventDispatcher()
+	stockService, _ := application.NewStockService(productRepository, stockRepository, eventDispatcher)
+	stockController, _ := controllers.NewStockController(stockService)
+	domain.RegisterEventHandler(eventDispatcher, stockService.CommitCartReservations)
+	cartService, _ := application.NewCartService(cartRepository, customerRepository, productRepository, eventDispatcher, newExchangeRates(nil), application.WithStockReservations(stockRepository))
+	cartController, _ := controllers.NewCartController(cartService)
+	orderService, _ := application.NewOrderService(cartRepository, repositories.NewInMemoryOrderRepository(), eventDispatcher)
+	orderController, _ := controllers.NewOrderController(orderService)
+
+	customerRepository.Save(firstCustomer)
+	customerRepository.Save(secondCustomer)
+	productRepository.Save(existingProduct)
+	cartRepository.Save(firstCart)
+	cartRepository.Save(secondCart)
+
+	e := echo.New()
+	e.PUT("/products/:productId/stock", stockController.UpdateStock)
+	e.POST("/carts/:cartId", cartController.AddItemToCart)
+	e.POST("/carts/:cartId/checkout", orderController.CheckoutCart)
+	e.Validator = config.NewRequestValidator()
+	serve := func(method string, path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, request)
+		return rec
+	}
+	addItemBody := fmt.Sprintf(`{"product_id":"%s","quantity":2}`, existingProduct.GetID().String())
+
+	rec := serve(http.MethodPut, fmt.Sprintf("/products/%s/stock", existingProduct.GetID().String()), `{"on_hand":3}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(http.MethodPost, fmt.Sprintf("/carts/%s", firstCart.GetID().String()), addItemBody)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(http.MethodPost, fmt.Sprintf("/carts/%s", secondCart.GetID().String()), addItemBody)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, fmt.Sprintf(`{"message":"insufficient stock for product %s: requested 2, available 1"}`, existingProduct.GetID().String()), strings.Trim(rec.Body.String(), "\n"))
+
+	rec = serve(http.MethodPost, fmt.Sprintf("/carts/%s/checkout", firstCart.GetID().String()), "")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = serve(http.MethodPut, fmt.Sprintf("/products/%s/stock", existingProduct.GetID().String()), `{"on_hand":1}`)
+	var stockDto application.StockDto
+	json.Unmarshal(rec.Body.Bytes(), &stockDto)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, stockDto.OnHand)
+	assert.Equal(t, 0, stockDto.Reserved)
+	stockItem, _ := stockRepository.FindByID(existingProduct.GetID())
+	if assert.NotNil(t, stockItem) {
+		assert.Equal(t, 1, stockItem.GetOnHand())
+	}
+}
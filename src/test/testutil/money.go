@@ -0,0 +1,12 @@
+package testutil
+
+import "github.com/bitlogic/go-startup/src/domain"
+
+func USD(amount string) domain.Money {
+	money, err := domain.ParseMoney(amount, domain.DefaultCurrency)
+	if err != nil {
+		panic(err)
+	}
+
+	return money
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var cartExpirationPolicy = application.CartExpirationPolicy{
+	AbandonAfter: time.Hour,
+	ExpireAfter:  24 * time.Hour,
+}
+
+func Test_GivenNilDependencies_WhenNewCartExpirationService_ThenReturnError(t *testing.T) {
+	tests := []struct {
+		testName       string
+		cartRepository domain.CartRepository
+		dispatcher     domain.EventDispatcher
+		clock          domain.Clock
+		policy         application.CartExpirationPolicy
+		expectedError  string
+	}{
+		{"nil cart repository", nil, &eventDispatcherMock{}, &fixedClock{}, cartExpirationPolicy, "cart repository was nil"},
+		{"nil event dispatcher", &cartRepositoryMock{}, nil, &fixedClock{}, cartExpirationPolicy, "event dispatcher was nil"},
+		{"nil clock", &cartRepositoryMock{}, &eventDispatcherMock{}, nil, cartExpirationPolicy, "clock was nil"},
+		{"no abandon after", &cartRepositoryMock{}, &eventDispatcherMock{}, &fixedClock{}, application.CartExpirationPolicy{ExpireAfter: time.Hour}, "invalid abandon after duration"},
+		{"expire before abandon", &cartRepositoryMock{}, &eventDispatcherMock{}, &fixedClock{}, application.CartExpirationPolicy{AbandonAfter: time.Hour, ExpireAfter: time.Minute}, "expire after must be longer than abandon after"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			service, err := application.NewCartExpirationService(tc.cartRepository, tc.dispatcher, tc.clock, tc.policy)
+
+			assert.Nil(t, service)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_GivenIdleCarts_WhenExpireIdleCarts_ThenAbandonOrExpireThemByIdleTime(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	idleCart, _ := domain.NewCart(customer, domain.WithCartClock(clock))
+	alreadyAbandonedCart, _ := domain.NewCart(customer, domain.WithCartClock(clock))
+	alreadyAbandonedCart.Abandon()
+	alreadyAbandonedCart.ClearDomainEvents()
+	idleCart.ClearDomainEvents()
+	clock.now = clock.now.Add(2 * time.Hour)
+	forgottenCart, _ := domain.NewCart(customer, domain.WithCartClock(&fixedClock{now: clock.now.Add(-48 * time.Hour)}))
+	forgottenCart.ClearDomainEvents()
+
+	var savedCarts []*domain.Cart
+	cartRepository := &cartRepositoryMock{
+		findIdleCarts: func(lastActivityBefore time.Time) ([]*domain.Cart, error) {
+			assert.Equal(t, clock.now.Add(-time.Hour), lastActivityBefore)
+			return []*domain.Cart{forgottenCart, idleCart, alreadyAbandonedCart}, nil
+		},
+		save: func(cart *domain.Cart) error {
+			savedCarts = append(savedCarts, cart)
+			return nil
+		},
+	}
+	dispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartExpirationService(cartRepository, dispatcher, clock, cartExpirationPolicy)
+
+	result, err := service.ExpireIdleCarts()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{uuid.UUID(idleCart.GetID())}, result.Abandoned)
+	assert.Equal(t, []uuid.UUID{uuid.UUID(forgottenCart.GetID())}, result.Expired)
+	assert.Equal(t, domain.CartStatusAbandoned, idleCart.GetStatus())
+	assert.Equal(t, domain.CartStatusExpired, forgottenCart.GetStatus())
+	assert.Equal(t, domain.CartStatusAbandoned, alreadyAbandonedCart.GetStatus())
+	assert.Equal(t, []*domain.Cart{forgottenCart, idleCart}, savedCarts)
+	if assert.Len(t, dispatcher.dispatchedEvents, 2) {
+		assert.IsType(t, domain.CartExpired{}, dispatcher.dispatchedEvents[0])
+		assert.Equal(t, domain.CartAbandoned{
+			CartId:         idleCart.GetID(),
+			CustomerId:     customer.GetID(),
+			LastActivityAt: idleCart.GetLastActivityAt(),
+		}, dispatcher.dispatchedEvents[1])
+	}
+}
+
+func Test_GivenCartRepositoryFailsToSave_WhenExpireIdleCarts_ThenReturnError(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	idleCart, _ := domain.NewCart(customer, domain.WithCartClock(clock))
+	clock.now = clock.now.Add(2 * time.Hour)
+
+	cartRepository := &cartRepositoryMock{
+		findIdleCarts: func(time.Time) ([]*domain.Cart, error) {
+			return []*domain.Cart{idleCart}, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return errors.New("failed to save entity")
+		},
+	}
+	dispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartExpirationService(cartRepository, dispatcher, clock, cartExpirationPolicy)
+
+	_, err := service.ExpireIdleCarts()
+
+	assert.EqualError(t, err, "failed to save entity")
+	assert.Empty(t, dispatcher.dispatchedEvents)
+}
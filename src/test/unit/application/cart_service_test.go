@@ -1,18 +1,21 @@
 package test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/bitlogic/go-startup/src/application"
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GivenANilCartRepository_WhenNewCartService_ThenReturnError(t *testing.T) {
-	service, err := application.NewCartService(nil, &customerRepositoryMock{}, &productRepositoryMock{})
+	service, err := application.NewCartService(nil, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 
 	if assert.Error(t, err) {
 		assert.Equal(t, "cart repository was nil", err.Error())
@@ -21,7 +24,7 @@ func Test_GivenANilCartRepository_WhenNewCartService_ThenReturnError(t *testing.
 }
 
 func Test_GivenANilCustomerRepository_WhenNewCartService_ThenReturnError(t *testing.T) {
-	service, err := application.NewCartService(&cartRepositoryMock{}, nil, &productRepositoryMock{})
+	service, err := application.NewCartService(&cartRepositoryMock{}, nil, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 
 	if assert.Error(t, err) {
 		assert.Equal(t, "customer repository was nil", err.Error())
@@ -29,8 +32,17 @@ func Test_GivenANilCustomerRepository_WhenNewCartService_ThenReturnError(t *test
 	assert.Nil(t, service)
 }
 
+func Test_GivenANilExchangeRateProvider_WhenNewCartService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, nil)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "exchange rate provider was nil", err.Error())
+	}
+	assert.Nil(t, service)
+}
+
 func Test_GivenANilProductRepository_WhenNewCartService_ThenReturnError(t *testing.T) {
-	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, nil)
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, nil, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 
 	if assert.Error(t, err) {
 		assert.Equal(t, "product repository was nil", err.Error())
@@ -38,8 +50,17 @@ func Test_GivenANilProductRepository_WhenNewCartService_ThenReturnError(t *testi
 	assert.Nil(t, service)
 }
 
+func Test_GivenANilEventDispatcher_WhenNewCartService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, nil, &exchangeRateProviderMock{})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "event dispatcher was nil", err.Error())
+	}
+	assert.Nil(t, service)
+}
+
 func Test_GivenAllRepositories_WhenNewCartService_ThenReturnACartService(t *testing.T) {
-	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{})
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 
 	assert.Nil(t, err)
 	assert.NotEmpty(t, service)
@@ -57,7 +78,7 @@ func Test_GivenAValidCreateCartCommand_WhenCreateNewCart_ThenReturnACreateCartRe
 			return savedCustomer, nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{})
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.CreateCartCommand{
 		CustomerId: uuid.UUID(savedCustomer.GetID()),
 	}
@@ -71,8 +92,295 @@ func Test_GivenAValidCreateCartCommand_WhenCreateNewCart_ThenReturnACreateCartRe
 		assert.Empty(t, result.Items)
 	}
 	assert.Equal(t, 1, customerRepository.callCount)
+	assert.Equal(t, 2, cartRepository.callCount)
+
+}
+
+func Test_GivenACustomerWithAnActiveCart_WhenCreateNewCart_ThenReturnTheActiveCart(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	activeCart, _ := domain.NewCart(savedCustomer)
+	cartRepository := &cartRepositoryMock{
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{activeCart}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(activeCart.GetID()), result.Id)
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenARejectPolicyAndACustomerWithAnActiveCart_WhenCreateNewCart_ThenReturnInvalidArgumentError(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	activeCart, _ := domain.NewCart(savedCustomer)
+	cartRepository := &cartRepositoryMock{
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{activeCart}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithActiveCartPolicy(application.RejectSecondActiveCart))
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid customer: it already has an active cart "+activeCart.GetID().String(), err.Error())
+	}
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenACustomerWithOnlyAnAbandonedCart_WhenCreateNewCart_ThenReactivateItAndReserveItsStockAgain(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	book, _ := domain.NewProduct("Object Oriented Analysis and Design", testutil.USD("40.00"))
+	abandonedCart, _ := domain.NewCart(savedCustomer)
+	abandonedCart.AddItem(book, 3)
+	abandonedCart.Abandon()
+	storedStockItem, _ := domain.NewStockItem(book.GetID(), 10)
+	var savedCart *domain.Cart
+	cartRepository := &cartRepositoryMock{
+		save: func(cart *domain.Cart) error {
+			savedCart = cart.Clone()
+			return nil
+		},
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{abandonedCart.Clone()}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return storedStockItem.Clone(), nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			storedStockItem = stockItem.Clone()
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.Nil(t, err)
+	assert.True(t, result.Reused)
+	assert.Equal(t, uuid.UUID(abandonedCart.GetID()), result.Id)
+	assert.Equal(t, "active", result.Status)
+	if assert.NotNil(t, savedCart) {
+		assert.True(t, savedCart.IsActive())
+	}
+	assert.Equal(t, 3, storedStockItem.GetReservedFor(abandonedCart.GetID()))
+}
+
+func Test_GivenARejectPolicyAndACustomerWithOnlyAnAbandonedCart_WhenCreateNewCart_ThenCreateAFreshCart(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	abandonedCart, _ := domain.NewCart(savedCustomer)
+	abandonedCart.Abandon()
+	var savedCart *domain.Cart
+	cartRepository := &cartRepositoryMock{
+		save: func(cart *domain.Cart) error {
+			savedCart = cart.Clone()
+			return nil
+		},
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{abandonedCart.Clone()}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithActiveCartPolicy(application.RejectSecondActiveCart))
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.Nil(t, err)
+	assert.False(t, result.Reused)
+	assert.NotEqual(t, uuid.UUID(abandonedCart.GetID()), result.Id)
+	if assert.NotNil(t, savedCart) {
+		assert.Equal(t, result.Id, uuid.UUID(savedCart.GetID()))
+	}
+}
+
+func Test_GivenAnAbandonedCartInAnotherCurrency_WhenCreateNewCart_ThenCreateAFreshCartInTheRequestedCurrency(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	abandonedCart, _ := domain.NewCart(savedCustomer)
+	abandonedCart.Abandon()
+	var savedCart *domain.Cart
+	cartRepository := &cartRepositoryMock{
+		save: func(cart *domain.Cart) error {
+			savedCart = cart.Clone()
+			return nil
+		},
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{abandonedCart.Clone()}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID()), Currency: "EUR"})
+
+	assert.Nil(t, err)
+	assert.False(t, result.Reused)
+	assert.NotEqual(t, uuid.UUID(abandonedCart.GetID()), result.Id)
+	if assert.NotNil(t, savedCart) {
+		assert.Equal(t, domain.Currency("EUR"), savedCart.GetCurrency())
+	}
+}
+
+func Test_GivenAnActiveCartInAnotherCurrency_WhenCreateNewCart_ThenReturnInvalidArgumentError(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	activeCart, _ := domain.NewCart(savedCustomer)
+	cartRepository := &cartRepositoryMock{
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{activeCart}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID()), Currency: "EUR"})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid currency: the customer already has an active cart "+activeCart.GetID().String()+" in USD", err.Error())
+	}
 	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenAnAbandonedCartWhoseStockIsGone_WhenCreateNewCart_ThenReturnInsufficientStockWithoutReactivatingIt(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	book, _ := domain.NewProduct("Object Oriented Analysis and Design", testutil.USD("40.00"))
+	abandonedCart, _ := domain.NewCart(savedCustomer)
+	abandonedCart.AddItem(book, 3)
+	abandonedCart.Abandon()
+	storedStockItem, _ := domain.NewStockItem(book.GetID(), 2)
+	saves := 0
+	cartRepository := &cartRepositoryMock{
+		save: func(cart *domain.Cart) error {
+			saves++
+			return nil
+		},
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{abandonedCart.Clone()}, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return storedStockItem.Clone(), nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			storedStockItem = stockItem.Clone()
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+
+	_, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.IsType(t, &application.InsufficientStockError{}, err)
+	assert.Equal(t, 0, saves)
+	assert.Equal(t, 0, storedStockItem.GetReserved())
+}
+
+func Test_GivenAConcurrentRequestCreatedTheActiveCart_WhenCreateNewCart_ThenReuseTheCartThatWasStored(t *testing.T) {
+	savedCustomer, _ := domain.NewCustomer("Grady Booch")
+	concurrentCart, _ := domain.NewCart(savedCustomer)
+	var stored []*domain.Cart
+	cartRepository := &cartRepositoryMock{
+		save: func(cart *domain.Cart) error {
+			return &domain.UniqueConstraintError{Field: "active_cart", Value: savedCustomer.GetID().String()}
+		},
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			carts := stored
+			stored = []*domain.Cart{concurrentCart}
+			return carts, nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return savedCustomer, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(savedCustomer.GetID())})
+
+	assert.Nil(t, err)
+	assert.True(t, result.Reused)
+	assert.Equal(t, uuid.UUID(concurrentCart.GetID()), result.Id)
+}
 
+func Test_GivenAnUnknownActiveCartPolicy_WhenNewCartService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithActiveCartPolicy("sometimes"))
+
+	assert.Nil(t, service)
+	assert.EqualError(t, err, "invalid active cart policy")
+}
+
+func Test_GivenAnUnknownPricingPolicy_WhenNewCartService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithCartPricingPolicy("whenever"))
+
+	assert.Nil(t, service)
+	assert.EqualError(t, err, "invalid cart pricing policy")
+}
+
+func Test_GivenADeactivatedCustomer_WhenCreateNewCart_ThenReturnInvalidArgumentError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernon.Deactivate()
+	cartRepository := &cartRepositoryMock{}
+	customerRepository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return vaughnVernon, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.CreateNewCart(application.CreateCartCommand{CustomerId: uuid.UUID(vaughnVernon.GetID())})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid customer: it is deactivated", err.Error())
+	}
+	assert.Equal(t, 0, cartRepository.callCount)
 }
 
 func Test_GivenACreateCartCommandWithNonExistinantCustomerId_WhenCreateNewCart_ThenReturnError(t *testing.T) {
@@ -87,7 +395,7 @@ func Test_GivenACreateCartCommandWithNonExistinantCustomerId_WhenCreateNewCart_T
 		},
 	}
 	customerId := uuid.New()
-	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{})
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.CreateCartCommand{
 		CustomerId: customerId,
 	}
@@ -115,7 +423,7 @@ func Test_GivenCartRepositoryFailsToSaveNewlyCreatedCart_WhenCreateNewCart_ThenR
 			return savedCustomer, nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{})
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.CreateCartCommand{
 		CustomerId: uuid.UUID(savedCustomer.GetID()),
 	}
@@ -127,7 +435,7 @@ func Test_GivenCartRepositoryFailsToSaveNewlyCreatedCart_WhenCreateNewCart_ThenR
 		assert.Equal(t, "failed to save entity", err.Error())
 	}
 	assert.Equal(t, 1, customerRepository.callCount)
-	assert.Equal(t, 1, cartRepository.callCount)
+	assert.Equal(t, 2, cartRepository.callCount)
 }
 
 func Test_GivenCustomerRepositoryReturnsNilCustomerAndNilError_WhenCreateNewCart_ThenReturnError(t *testing.T) {
@@ -141,7 +449,7 @@ func Test_GivenCustomerRepositoryReturnsNilCustomerAndNilError_WhenCreateNewCart
 			return nil, nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{})
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.CreateCartCommand{
 		CustomerId: uuid.New(),
 	}
@@ -159,7 +467,7 @@ func Test_GivenCustomerRepositoryReturnsNilCustomerAndNilError_WhenCreateNewCart
 func Test_GivenACart_WhenAddItemToCart_ThenTheItemIsAddedToTheCart(t *testing.T) {
 	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
 	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
-	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", 50.00)
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
 
 	productRepository := &productRepositoryMock{
 		findByID: func(productId domain.ProductId) (*domain.Product, error) {
@@ -175,7 +483,7 @@ func Test_GivenACart_WhenAddItemToCart_ThenTheItemIsAddedToTheCart(t *testing.T)
 			return nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository)
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.AddItemToCartCommand{
 		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
 		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
@@ -190,7 +498,7 @@ func Test_GivenACart_WhenAddItemToCart_ThenTheItemIsAddedToTheCart(t *testing.T)
 		assert.Equal(t, uuid.UUID(vaughnVernonsCart.GetCustomerID()), result.CustomerId)
 		if assert.NotEmpty(t, result.Items) {
 			assert.Equal(t, 1, result.Items[0].Quantity)
-			assert.Equal(t, application.PriceDto(50.00), result.Items[0].UnitPrice)
+			assert.Equal(t, application.PriceDto(testutil.USD("50.00")), result.Items[0].UnitPrice)
 			assert.Equal(t, uuid.UUID(productVaughnVernonWantsToAdd.GetID()), result.Items[0].ProductId)
 		}
 	}
@@ -198,13 +506,16 @@ func Test_GivenACart_WhenAddItemToCart_ThenTheItemIsAddedToTheCart(t *testing.T)
 	assert.Equal(t, 1, productRepository.callCount)
 }
 
-func Test_GivenAnInvalidQuantity_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+func Test_GivenASKU_WhenAddItemToCart_ThenTheProductIsResolvedBySKU(t *testing.T) {
 	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
 	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
-	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", 50.00)
+	sku, _ := domain.NewSKU("IDDD-BOOK")
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"), domain.WithSKU(sku))
 
+	var requestedSKU domain.SKU
 	productRepository := &productRepositoryMock{
-		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+		findBySKU: func(sku domain.SKU) (*domain.Product, error) {
+			requestedSKU = sku
 			return productVaughnVernonWantsToAdd, nil
 		},
 	}
@@ -217,46 +528,35 @@ func Test_GivenAnInvalidQuantity_WhenAddItemToCart_ThenReturnError(t *testing.T)
 			return nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository)
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.AddItemToCartCommand{
-		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
-		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
-		Quantity:  0,
+		CartId:   uuid.UUID(vaughnVernonsCart.GetID()),
+		SKU:      "iddd-book",
+		Quantity: 1,
 	}
 
 	result, err := service.AddItemToCart(command)
 
-	assert.Empty(t, result)
-	if assert.Error(t, err) {
-		assert.Equal(t, "invalid quantity", err.Error())
+	assert.Nil(t, err)
+	assert.Equal(t, sku, requestedSKU)
+	if assert.Len(t, result.Items, 1) {
+		assert.Equal(t, uuid.UUID(productVaughnVernonWantsToAdd.GetID()), result.Items[0].ProductId)
 	}
 	assert.Equal(t, 1, productRepository.callCount)
-	assert.Equal(t, 1, cartRepository.callCount)
 }
 
-func Test_GivenANonExistantProduct_WhenAddItemToCart_ThenReturnError(t *testing.T) {
-	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
-	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
-
+func Test_GivenAnUnknownSKU_WhenAddItemToCart_ThenReturnNotFoundError(t *testing.T) {
 	productRepository := &productRepositoryMock{
-		findByID: func(productId domain.ProductId) (*domain.Product, error) {
-			return nil, errors.New("product not found")
-		},
-	}
-
-	cartRepository := &cartRepositoryMock{
-		findById: func(cartId domain.CartId) (*domain.Cart, error) {
-			return vaughnVernonsCart, nil
-		},
-		save: func(cart *domain.Cart) error {
-			return nil
+		findBySKU: func(sku domain.SKU) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository)
+	cartRepository := &cartRepositoryMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
 	command := application.AddItemToCartCommand{
-		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
-		ProductId: uuid.New(),
-		Quantity:  0,
+		CartId:   uuid.New(),
+		SKU:      "IDDD-BOOK",
+		Quantity: 1,
 	}
 
 	result, err := service.AddItemToCart(command)
@@ -264,79 +564,1358 @@ func Test_GivenANonExistantProduct_WhenAddItemToCart_ThenReturnError(t *testing.
 	assert.Empty(t, result)
 	if assert.Error(t, err) {
 		assert.IsType(t, &application.NotFoundError{}, err)
-		assert.Equal(t, fmt.Sprintf("product with id %s not found", command.ProductId), err.Error())
+		assert.Equal(t, "product with id IDDD-BOOK not found", err.Error())
 	}
-	assert.Equal(t, 1, productRepository.callCount)
 	assert.Equal(t, 0, cartRepository.callCount)
 }
 
-func Test_GivenANonExistantCart_WhenAddItemToCart_ThenReturnError(t *testing.T) {
-	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", 50.00)
+func Test_GivenAProductInAnotherCurrency_WhenAddItemToCart_ThenTheLineIsConvertedToTheCartCurrency(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon, domain.WithDisplayCurrency("EUR"))
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
 
 	productRepository := &productRepositoryMock{
 		findByID: func(productId domain.ProductId) (*domain.Product, error) {
-			return productVaughnVernonWantsToAdd, nil
+			return book, nil
 		},
 	}
-
 	cartRepository := &cartRepositoryMock{
 		findById: func(cartId domain.CartId) (*domain.Cart, error) {
-			return nil, errors.New("cart not found")
+			return vaughnVernonsCart, nil
 		},
 		save: func(cart *domain.Cart) error {
 			return nil
 		},
 	}
-	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository)
-	command := application.AddItemToCartCommand{
-		CartId:    uuid.New(),
-		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
-		Quantity:  1,
-	}
+	exchangeRates := &exchangeRateProviderMock{rates: map[string]*big.Rat{"USD/EUR": big.NewRat(9, 10)}}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, exchangeRates)
 
-	result, err := service.AddItemToCart(command)
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  2,
+	})
 
-	assert.Empty(t, result)
-	if assert.Error(t, err) {
-		assert.IsType(t, &application.NotFoundError{}, err)
-		assert.Equal(t, fmt.Sprintf("cart with id %s not found", command.CartId.String()), err.Error())
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", result.Currency)
+	euros, _ := domain.ParseMoney("90.00", "EUR")
+	assert.Equal(t, application.PriceDto(euros), result.Total)
+	if assert.Equal(t, 1, len(result.Items)) {
+		assert.Equal(t, "USD", result.Items[0].Currency)
 	}
-	assert.Equal(t, 1, productRepository.callCount)
-	assert.Equal(t, 1, cartRepository.callCount)
 }
 
-func Test_GivenCartRepositoryFailsToSave_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+func Test_GivenAProductWhoseCurrencyCannotBeConverted_WhenAddItemToCart_ThenReturnInvalidArgumentError(t *testing.T) {
 	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
-	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
-	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", 50.00)
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon, domain.WithDisplayCurrency("EUR"))
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
 
 	productRepository := &productRepositoryMock{
 		findByID: func(productId domain.ProductId) (*domain.Product, error) {
-			return productVaughnVernonWantsToAdd, nil
+			return book, nil
 		},
 	}
-
 	cartRepository := &cartRepositoryMock{
 		findById: func(cartId domain.CartId) (*domain.Cart, error) {
 			return vaughnVernonsCart, nil
 		},
-		save: func(cart *domain.Cart) error {
-			return errors.New("failed to save cart")
-		},
 	}
-	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository)
-	command := application.AddItemToCartCommand{
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{
 		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
-		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
 		Quantity:  1,
+	})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid product: its price in USD cannot be converted to EUR", err.Error())
 	}
+	assert.Equal(t, 0, vaughnVernonsCart.Size())
+	assert.Equal(t, 1, cartRepository.callCount)
+}
 
-	result, err := service.AddItemToCart(command)
+func Test_GivenAnArchivedProduct_WhenAddItemToCart_ThenReturnProductArchivedError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	book.Archive()
 
-	assert.Empty(t, result)
-	if assert.Error(t, err) {
-		assert.Equal(t, "failed to save cart", err.Error())
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
 	}
-	assert.Equal(t, 1, productRepository.callCount)
-	assert.Equal(t, 2, cartRepository.callCount)
+	cartRepository := &cartRepositoryMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  1,
+	})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.ProductArchivedError{}, err)
+		assert.ErrorIs(t, err, domain.ErrProductArchived)
+		assert.Equal(t, "product "+book.GetID().String()+" is archived and can no longer be added to carts", err.Error())
+	}
+	assert.Equal(t, 0, vaughnVernonsCart.Size())
+	assert.Equal(t, 0, cartRepository.callCount)
+}
+
+func Test_GivenAnInvalidQuantity_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return productVaughnVernonWantsToAdd, nil
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		Quantity:  0,
+	}
+
+	result, err := service.AddItemToCart(command)
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid quantity", err.Error())
+	}
+	assert.Equal(t, 1, productRepository.callCount)
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return nil, errors.New("product not found")
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.New(),
+		Quantity:  0,
+	}
+
+	result, err := service.AddItemToCart(command)
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", command.ProductId), err.Error())
+	}
+	assert.Equal(t, 1, productRepository.callCount)
+	assert.Equal(t, 0, cartRepository.callCount)
+}
+
+func Test_GivenANonExistantCart_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return productVaughnVernonWantsToAdd, nil
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return nil, errors.New("cart not found")
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.New(),
+		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		Quantity:  1,
+	}
+
+	result, err := service.AddItemToCart(command)
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("cart with id %s not found", command.CartId.String()), err.Error())
+	}
+	assert.Equal(t, 1, productRepository.callCount)
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenCartRepositoryFailsToSave_WhenAddItemToCart_ThenReturnError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return productVaughnVernonWantsToAdd, nil
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return errors.New("failed to save cart")
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		Quantity:  1,
+	}
+
+	result, err := service.AddItemToCart(command)
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.Equal(t, "failed to save cart", err.Error())
+	}
+	assert.Equal(t, 1, productRepository.callCount)
+	assert.Equal(t, 2, cartRepository.callCount)
+}
+
+func Test_GivenACart_WhenAddItemToCart_ThenDispatchTheCartDomainEventsAfterSaving(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	vaughnVernonsCart.ClearDomainEvents()
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return productVaughnVernonWantsToAdd, nil
+		},
+	}
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, eventDispatcher, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		Quantity:  3,
+	}
+
+	_, err := service.AddItemToCart(command)
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.Equal(t, domain.ItemAddedToCart{
+			CartId:    vaughnVernonsCart.GetID(),
+			ProductId: productVaughnVernonWantsToAdd.GetID(),
+			Quantity:  3,
+		}, eventDispatcher.dispatchedEvents[0])
+	}
+	assert.Empty(t, vaughnVernonsCart.GetDomainEvents())
+}
+
+func Test_GivenCartRepositoryFailsToSave_WhenAddItemToCart_ThenNoEventsAreDispatched(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	productVaughnVernonWantsToAdd, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return productVaughnVernonWantsToAdd, nil
+		},
+	}
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return errors.New("failed to save cart")
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, eventDispatcher, &exchangeRateProviderMock{})
+	command := application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(productVaughnVernonWantsToAdd.GetID()),
+		Quantity:  1,
+	}
+
+	_, err := service.AddItemToCart(command)
+
+	assert.Error(t, err)
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+	assert.NotEmpty(t, vaughnVernonsCart.GetDomainEvents())
+}
+
+func Test_GivenACartWithAnItem_WhenRemoveItemFromCart_ThenTheItemIsRemoved(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 1)
+	vaughnVernonsCart.ClearDomainEvents()
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, eventDispatcher, &exchangeRateProviderMock{})
+
+	result, err := service.RemoveItemFromCart(application.RemoveItemFromCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, result.Items)
+	assert.Equal(t, 2, cartRepository.callCount)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.ItemRemovedFromCart{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAProductNotInTheCart_WhenRemoveItemFromCart_ThenReturnNotFoundError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	productId := uuid.New()
+
+	result, err := service.RemoveItemFromCart(application.RemoveItemFromCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: productId,
+	})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("item with id %s not found", productId.String()), err.Error())
+	}
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenACartWithAnItem_WhenUpdateItemQuantity_ThenTheQuantityIsUpdated(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 1)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.UpdateItemQuantity(application.UpdateItemQuantityCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  4,
+	})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result.Items)) {
+		assert.Equal(t, 4, result.Items[0].Quantity)
+	}
+	assert.Equal(t, 2, cartRepository.callCount)
+}
+
+func Test_GivenANonExistantCart_WhenUpdateItemQuantity_ThenReturnNotFoundError(t *testing.T) {
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	cartId := uuid.New()
+
+	result, err := service.UpdateItemQuantity(application.UpdateItemQuantityCommand{
+		CartId:    cartId,
+		ProductId: uuid.New(),
+		Quantity:  4,
+	})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("cart with id %s not found", cartId.String()), err.Error())
+	}
+}
+
+func Test_GivenANonEmptyCart_WhenClearCart_ThenTheCartIsEmptied(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 3)
+	vaughnVernonsCart.ClearDomainEvents()
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, eventDispatcher, &exchangeRateProviderMock{})
+
+	result, err := service.ClearCart(application.ClearCartCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, result.Items)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.CartCleared{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnExistingCart_WhenGetCart_ThenReturnTheCartDto(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.GetCart(application.GetCartQuery{CartId: uuid.UUID(vaughnVernonsCart.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(vaughnVernonsCart.GetID()), result.Id)
+	if assert.Equal(t, 1, len(result.Items)) {
+		assert.Equal(t, 2, result.Items[0].Quantity)
+	}
+}
+
+func Test_GivenAProductWhosePriceChanged_WhenGetCartKeepingThePriceSnapshot_ThenTheLineKeepsItsPrice(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+	book.ChangePrice(testutil.USD("55.00"))
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepositoryReturning(book), &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.GetCart(application.GetCartQuery{CartId: uuid.UUID(vaughnVernonsCart.GetID())})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result.Items)) {
+		assert.Equal(t, application.PriceDto(testutil.USD("50.00")), result.Items[0].UnitPrice)
+		assert.False(t, result.Items[0].PriceChanged)
+		assert.Nil(t, result.Items[0].AddedUnitPrice)
+	}
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Total)
+	assert.Equal(t, 1, cartRepository.callCount)
+}
+
+func Test_GivenAProductWhosePriceChanged_WhenGetCartRepricingOnRead_ThenTheLineIsRepricedAndFlaggedWithoutSaving(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+	vaughnVernonsCart.ClearDomainEvents()
+	book.ChangePrice(testutil.USD("55.00"))
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			t.Fatal("a read must not save the repriced cart")
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepositoryReturning(book), eventDispatcher, &exchangeRateProviderMock{}, application.WithCartPricingPolicy(application.RepriceOnRead))
+
+	result, err := service.GetCart(application.GetCartQuery{CartId: uuid.UUID(vaughnVernonsCart.GetID())})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result.Items)) {
+		addedUnitPrice := application.PriceDto(testutil.USD("50.00"))
+		assert.Equal(t, application.PriceDto(testutil.USD("55.00")), result.Items[0].UnitPrice)
+		assert.True(t, result.Items[0].PriceChanged)
+		assert.Equal(t, &addedUnitPrice, result.Items[0].AddedUnitPrice)
+	}
+	assert.Equal(t, application.PriceDto(testutil.USD("110.00")), result.Total)
+	assert.Equal(t, 1, cartRepository.callCount)
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenACartRepricedOnRead_WhenTheNextMutationSavesIt_ThenTheNewPriceIsPersisted(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	storedCart.AddItem(book, 2)
+	storedCart.ClearDomainEvents()
+	book.ChangePrice(testutil.USD("55.00"))
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			storedCart = cart.Clone()
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepositoryReturning(book), eventDispatcher, &exchangeRateProviderMock{}, application.WithCartPricingPolicy(application.RepriceOnRead))
+	cartId := uuid.UUID(storedCart.GetID())
+
+	service.GetCart(application.GetCartQuery{CartId: cartId})
+	assert.Equal(t, testutil.USD("100.00"), storedCart.GetTotal())
+
+	result, err := service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: uuid.UUID(book.GetID()), Quantity: 3})
+
+	assert.Nil(t, err)
+	assert.Equal(t, application.PriceDto(testutil.USD("165.00")), result.Total)
+	assert.Equal(t, testutil.USD("165.00"), storedCart.GetTotal())
+	assert.Contains(t, eventDispatcher.dispatchedEvents, domain.DomainEvent(domain.ItemRepriced{CartId: storedCart.GetID(), ProductId: book.GetID(), PreviousUnitPrice: testutil.USD("50.00"), UnitPrice: testutil.USD("55.00")}))
+}
+
+func Test_GivenANonExistantCart_WhenGetCart_ThenReturnNotFoundError(t *testing.T) {
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	cartId := uuid.New()
+
+	result, err := service.GetCart(application.GetCartQuery{CartId: cartId})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("cart with id %s not found", cartId.String()), err.Error())
+	}
+}
+
+func Test_GivenACustomerWithCarts_WhenGetCustomerCarts_ThenReturnTheCustomerCartDtos(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	firstCart, _ := domain.NewCart(vaughnVernon)
+	secondCart, _ := domain.NewCart(vaughnVernon)
+
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return vaughnVernon, nil
+		},
+	}
+	cartRepository := &cartRepositoryMock{
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{firstCart, secondCart}, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.GetCustomerCarts(application.GetCustomerCartsQuery{CustomerId: uuid.UUID(vaughnVernon.GetID())})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(result)) {
+		assert.Equal(t, uuid.UUID(firstCart.GetID()), result[0].Id)
+		assert.Equal(t, uuid.UUID(secondCart.GetID()), result[1].Id)
+	}
+}
+
+func Test_GivenACustomerWithCartsInDifferentStatuses_WhenGetCustomerCartsFilteredByStatus_ThenReturnOnlyMatchingCarts(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	abandonedCart, _ := domain.NewCart(vaughnVernon)
+	abandonedCart.Abandon()
+	activeCart, _ := domain.NewCart(vaughnVernon)
+
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return vaughnVernon, nil
+		},
+	}
+	cartRepository := &cartRepositoryMock{
+		getCustomerCarts: func(customerId domain.CustomerId) ([]*domain.Cart, error) {
+			return []*domain.Cart{abandonedCart, activeCart}, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.GetCustomerCarts(application.GetCustomerCartsQuery{CustomerId: uuid.UUID(vaughnVernon.GetID()), Status: "active"})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result)) {
+		assert.Equal(t, uuid.UUID(activeCart.GetID()), result[0].Id)
+		assert.Equal(t, "active", result[0].Status)
+	}
+}
+
+func Test_GivenANonExistantCustomer_WhenGetCustomerCarts_ThenReturnNotFoundError(t *testing.T) {
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	cartRepository := &cartRepositoryMock{}
+	service, _ := application.NewCartService(cartRepository, customerRepository, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	customerId := uuid.New()
+
+	result, err := service.GetCustomerCarts(application.GetCustomerCartsQuery{CustomerId: customerId})
+
+	assert.Nil(t, result)
+	if assert.Error(t, err) {
+		assert.Equal(t, fmt.Sprintf("customer with id %s not found", customerId.String()), err.Error())
+	}
+	assert.Equal(t, 0, cartRepository.callCount)
+}
+
+func Test_GivenAnExistingCoupon_WhenApplyCoupon_ThenTheCartIsDiscountedAndSaved(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+	vaughnVernonsCart.ClearDomainEvents()
+
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+	promotionCatalog := &promotionCatalogMock{coupons: map[domain.CouponCode]domain.Coupon{"SAVE10": coupon}}
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, eventDispatcher, &exchangeRateProviderMock{},
+		application.WithPromotions(promotionCatalog))
+
+	result, err := service.ApplyCoupon(application.ApplyCouponCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Code:   "save10",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, cartRepository.callCount)
+	assert.Equal(t, []string{"SAVE10"}, result.Coupons)
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Subtotal)
+	assert.Equal(t, []application.DiscountDto{{Promotion: "SAVE10", Amount: application.PriceDto(testutil.USD("10.00"))}}, result.CartDiscounts)
+	assert.Equal(t, application.PriceDto(testutil.USD("90.00")), result.Total)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.CouponApplied{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnAutomaticPromotion_WhenAddItemToCart_ThenTheLineDiscountIsReturned(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.ClearDomainEvents()
+
+	threeForTwo, _ := domain.NewBuyXGetY("books 3x2", book.GetID(), 2, 1)
+	promotionCatalog := &promotionCatalogMock{automatic: []domain.Promotion{threeForTwo}}
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithPromotions(promotionCatalog))
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  3,
+	})
+
+	assert.Nil(t, err)
+	bookId := uuid.UUID(book.GetID())
+	assert.Equal(t, []application.DiscountDto{{Promotion: "books 3x2", ProductId: &bookId, Amount: application.PriceDto(testutil.USD("50.00"))}}, result.LineDiscounts)
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Total)
+}
+
+func Test_GivenAnUnknownCoupon_WhenApplyCoupon_ThenReturnNotFoundError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithPromotions(&promotionCatalogMock{}))
+
+	result, err := service.ApplyCoupon(application.ApplyCouponCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Code:   "SAVE10",
+	})
+
+	assert.Empty(t, result)
+	assert.Equal(t, 1, cartRepository.callCount)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, "coupon with id SAVE10 not found", err.Error())
+	}
+}
+
+func Test_GivenAnInvalidCouponCode_WhenApplyCoupon_ThenReturnInvalidArgumentError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithPromotions(&promotionCatalogMock{}))
+
+	_, err := service.ApplyCoupon(application.ApplyCouponCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Code:   "SAVE 10",
+	})
+
+	assert.IsType(t, &application.InvalidArgumentError{}, err)
+}
+
+func Test_GivenACouponThatIsNotApplied_WhenRemoveCoupon_ThenReturnNotFoundError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.RemoveCoupon(application.RemoveCouponCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Code:   "SAVE10",
+	})
+
+	assert.Empty(t, result)
+	assert.Equal(t, 1, cartRepository.callCount)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, "coupon with id SAVE10 not found", err.Error())
+	}
+}
+
+func Test_GivenATaxCalculator_WhenAddItemToCart_ThenTheCartDtoExposesLineAndTotalTax(t *testing.T) {
+	shippingAddress, _ := domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "AR")
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon", domain.WithShippingAddress(shippingAddress))
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"), domain.WithTaxCategory("reduced"))
+	vaughnVernonsCart.ClearDomainEvents()
+
+	taxCalculator, _ := domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "AR", Category: "reduced", Rate: big.NewRat(105, 1000)},
+	)
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	customerRepository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return vaughnVernon, nil
+		},
+	}
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, customerRepository, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithTaxes(taxCalculator))
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  2,
+	})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(result.Items)) {
+		lineTax := application.PriceDto(testutil.USD("10.50"))
+		assert.Equal(t, "reduced", result.Items[0].TaxCategory)
+		assert.Equal(t, "0.105", result.Items[0].TaxRate)
+		assert.False(t, result.Items[0].TaxIncluded)
+		assert.Equal(t, &lineTax, result.Items[0].Tax)
+	}
+	totalTax := application.PriceDto(testutil.USD("10.50"))
+	assert.Equal(t, "AR", result.TaxRegion)
+	assert.Equal(t, &totalTax, result.Tax)
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Subtotal)
+	assert.Equal(t, application.PriceDto(testutil.USD("110.50")), result.Total)
+}
+
+func Test_GivenNoTaxCalculator_WhenAddItemToCart_ThenTheCartDtoHasNoTax(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  2,
+	})
+
+	assert.Nil(t, err)
+	assert.Nil(t, result.Tax)
+	assert.Nil(t, result.Items[0].Tax)
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Total)
+}
+
+func newShippingCatalogMock() *shippingCatalogMock {
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	express, _ := domain.NewWeightBasedShipping("express", "Express", testutil.USD("10.00"), testutil.USD("3.00"), 0)
+	euros, _ := domain.ParseMoney("5.00", "EUR")
+	european, _ := domain.NewFlatRateShipping("european", "European", euros)
+
+	return &shippingCatalogMock{methods: []domain.ShippingMethod{standard, express, european}}
+}
+
+func Test_GivenACartWithItems_WhenGetShippingOptions_ThenReturnTheQuoteOfEachAvailableMethod(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"), domain.WithWeight(1500))
+	vaughnVernonsCart.AddItem(book, 1)
+	shippingCatalog := newShippingCatalogMock()
+	vaughnVernonsCart.SelectShipping(shippingCatalog.methods[1])
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithShipping(shippingCatalog))
+
+	result, err := service.GetShippingOptions(application.GetShippingOptionsQuery{CartId: uuid.UUID(vaughnVernonsCart.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []application.ShippingOptionDto{
+		{Method: "standard", Name: "Standard", Cost: application.PriceDto(testutil.USD("5.00")), Currency: "USD"},
+		{Method: "express", Name: "Express", Cost: application.PriceDto(testutil.USD("16.00")), Currency: "USD", Selected: true},
+	}, result)
+}
+
+func Test_GivenAnEmptyCart_WhenGetShippingOptions_ThenReturnInvalidArgumentError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithShipping(newShippingCatalogMock()))
+
+	result, err := service.GetShippingOptions(application.GetShippingOptionsQuery{CartId: uuid.UUID(vaughnVernonsCart.GetID())})
+
+	assert.Nil(t, result)
+	assert.IsType(t, &application.InvalidArgumentError{}, err)
+}
+
+func Test_GivenAnAvailableMethod_WhenSelectShipping_ThenTheCostIsAddedToTheTotalAndSaved(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+	vaughnVernonsCart.ClearDomainEvents()
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, eventDispatcher, &exchangeRateProviderMock{},
+		application.WithShipping(newShippingCatalogMock()))
+
+	result, err := service.SelectShipping(application.SelectShippingCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Method: "Standard",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, cartRepository.callCount)
+	assert.Equal(t, &application.ShippingDto{Method: "standard", Name: "Standard", Cost: application.PriceDto(testutil.USD("5.00"))}, result.Shipping)
+	assert.Equal(t, application.PriceDto(testutil.USD("100.00")), result.Subtotal)
+	assert.Equal(t, application.PriceDto(testutil.USD("105.00")), result.Total)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.ShippingSelected{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnUnknownOrUnavailableMethod_WhenSelectShipping_ThenReturnError(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	vaughnVernonsCart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	vaughnVernonsCart.AddItem(book, 2)
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithShipping(newShippingCatalogMock()))
+
+	_, err := service.SelectShipping(application.SelectShippingCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Method: "overnight",
+	})
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, "shipping method with id overnight not found", err.Error())
+	}
+
+	_, err = service.SelectShipping(application.SelectShippingCommand{
+		CartId: uuid.UUID(vaughnVernonsCart.GetID()),
+		Method: "european",
+	})
+	assert.IsType(t, &application.InvalidArgumentError{}, err)
+	assert.False(t, vaughnVernonsCart.HasShipping())
+}
+
+func Test_GivenARestoredCartWithShipping_WhenAddItemToCart_ThenTheShippingIsQuotedAgainFromTheCatalog(t *testing.T) {
+	vaughnVernon, _ := domain.NewCustomer("Vaughn Vernon")
+	cart, _ := domain.NewCart(vaughnVernon)
+	book, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"), domain.WithWeight(1500))
+	cart.AddItem(book, 1)
+	shippingCatalog := newShippingCatalogMock()
+	cart.SelectShipping(shippingCatalog.methods[1])
+	vaughnVernonsCart, _ := domain.RestoreCart(cart.ToMemento())
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return vaughnVernonsCart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	productRepository := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return book, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{},
+		application.WithShipping(shippingCatalog))
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(vaughnVernonsCart.GetID()),
+		ProductId: uuid.UUID(book.GetID()),
+		Quantity:  1,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, &application.ShippingDto{Method: "express", Name: "Express", Cost: application.PriceDto(testutil.USD("19.00"))}, result.Shipping)
+	assert.Equal(t, application.PriceDto(testutil.USD("119.00")), result.Total)
+}
+
+func Test_GivenNegativeConflictRetries_WhenNewCartService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCartService(&cartRepositoryMock{}, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithConflictRetries(-1))
+
+	assert.Nil(t, service)
+	assert.EqualError(t, err, "invalid conflict retries")
+}
+
+func Test_GivenACartSavedConcurrently_WhenAddItemToCart_ThenRetryWithAFreshCopyAndSucceed(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+
+	saves := 0
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			saves++
+			if saves == 1 {
+				return &domain.ConcurrencyConflictError{Id: cart.GetID().String(), ExpectedVersion: 0, ActualVersion: 1}
+			}
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(storedCart.GetID()),
+		ProductId: uuid.UUID(product.GetID()),
+		Quantity:  2,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, saves)
+	if assert.Len(t, result.Items, 1) {
+		assert.Equal(t, 2, result.Items[0].Quantity)
+	}
+}
+
+func Test_GivenACartThatKeepsConflicting_WhenAddItemToCart_ThenReturnConcurrencyConflictErrorAfterTheConfiguredRetries(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+
+	saves := 0
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			saves++
+			return &domain.ConcurrencyConflictError{Id: cart.GetID().String(), ExpectedVersion: 0, ActualVersion: saves}
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithConflictRetries(2))
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(storedCart.GetID()),
+		ProductId: uuid.UUID(product.GetID()),
+		Quantity:  1,
+	})
+
+	assert.IsType(t, &application.ConcurrencyConflictError{}, err)
+	assert.Equal(t, 3, saves)
+}
+
+func Test_GivenAnExpectedVersion_WhenAddItemToCartConflicts_ThenDoNotRetry(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+
+	saves := 0
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			saves++
+			return &domain.ConcurrencyConflictError{Id: cart.GetID().String(), ExpectedVersion: 0, ActualVersion: 1}
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	expectedVersion := 0
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:          uuid.UUID(storedCart.GetID()),
+		ProductId:       uuid.UUID(product.GetID()),
+		Quantity:        1,
+		ExpectedVersion: &expectedVersion,
+	})
+
+	assert.IsType(t, &application.ConcurrencyConflictError{}, err)
+	assert.Equal(t, 1, saves)
+}
+
+func Test_GivenAStaleExpectedVersion_WhenMutatingTheCart_ThenReturnPreconditionFailedErrorWithoutSaving(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	storedCart.SetVersion(3)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	storedCart.AddItem(product, 1)
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(*domain.Cart) error {
+			t.Fatal("a stale cart must not be saved")
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{})
+	staleVersion := 2
+	cartId := uuid.UUID(storedCart.GetID())
+	productId := uuid.UUID(product.GetID())
+
+	for name, mutate := range map[string]func() (application.CartDto, error){
+		"add item": func() (application.CartDto, error) {
+			return service.AddItemToCart(application.AddItemToCartCommand{CartId: cartId, ProductId: productId, Quantity: 1, ExpectedVersion: &staleVersion})
+		},
+		"update quantity": func() (application.CartDto, error) {
+			return service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 2, ExpectedVersion: &staleVersion})
+		},
+		"remove item": func() (application.CartDto, error) {
+			return service.RemoveItemFromCart(application.RemoveItemFromCartCommand{CartId: cartId, ProductId: productId, ExpectedVersion: &staleVersion})
+		},
+		"clear": func() (application.CartDto, error) {
+			return service.ClearCart(application.ClearCartCommand{CartId: cartId, ExpectedVersion: &staleVersion})
+		},
+	} {
+		_, err := mutate()
+
+		assert.IsType(t, &application.PreconditionFailedError{}, err, name)
+	}
+}
+
+func Test_GivenAReservedStockItem_WhenTheCartFailsToSave_ThenTheReservationIsCancelled(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	storedStockItem, _ := domain.NewStockItem(product.GetID(), 10)
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(*domain.Cart) error {
+			return errors.New("disk full")
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return storedStockItem.Clone(), nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			storedStockItem = stockItem.Clone()
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepository, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(storedCart.GetID()),
+		ProductId: uuid.UUID(product.GetID()),
+		Quantity:  4,
+	})
+
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 0, storedStockItem.GetReserved())
+	assert.Equal(t, 10, storedStockItem.GetAvailable())
+}
+
+func Test_GivenACartThatFailsToSave_WhenRemovingUpdatingOrClearingItems_ThenTheReservationsFollowTheStoredCart(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	storedCart.AddItem(product, 4)
+	storedStockItem, _ := domain.NewStockItem(product.GetID(), 10)
+	storedStockItem.Reserve(storedCart.GetID(), 4)
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(*domain.Cart) error {
+			return errors.New("disk full")
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return storedStockItem.Clone(), nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			storedStockItem = stockItem.Clone()
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+	cartId := uuid.UUID(storedCart.GetID())
+	productId := uuid.UUID(product.GetID())
+
+	for name, mutate := range map[string]func() (application.CartDto, error){
+		"remove": func() (application.CartDto, error) {
+			return service.RemoveItemFromCart(application.RemoveItemFromCartCommand{CartId: cartId, ProductId: productId})
+		},
+		"decrease": func() (application.CartDto, error) {
+			return service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 1})
+		},
+		"increase": func() (application.CartDto, error) {
+			return service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 9})
+		},
+		"clear": func() (application.CartDto, error) {
+			return service.ClearCart(application.ClearCartCommand{CartId: cartId})
+		},
+	} {
+		_, err := mutate()
+
+		assert.EqualError(t, err, "disk full", name)
+		assert.Equal(t, 4, storedStockItem.GetReserved(), name)
+		assert.Equal(t, 6, storedStockItem.GetAvailable(), name)
+	}
+}
+
+func Test_GivenAReservedItem_WhenRemoveItemFromCart_ThenTheReservationIsReleasedAfterTheCartIsSaved(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	storedCart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	storedCart.AddItem(product, 4)
+	storedStockItem, _ := domain.NewStockItem(product.GetID(), 10)
+	storedStockItem.Reserve(storedCart.GetID(), 4)
+	var saves []string
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			saves = append(saves, "cart")
+			storedCart = cart.Clone()
+			return nil
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return storedStockItem.Clone(), nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			saves = append(saves, "stock")
+			storedStockItem = stockItem.Clone()
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, &productRepositoryMock{}, &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+
+	_, err := service.RemoveItemFromCart(application.RemoveItemFromCartCommand{CartId: uuid.UUID(storedCart.GetID()), ProductId: uuid.UUID(product.GetID())})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cart", "stock"}, saves)
+	assert.Equal(t, 0, storedStockItem.GetReserved())
+	assert.Equal(t, 0, storedCart.Size())
+}
+
+func Test_GivenACartDtoInAnotherCurrency_WhenMarshalAndUnmarshalJSON_ThenPricesKeepTheirCurrency(t *testing.T) {
+	yen, _ := domain.ParseMoney("1500", "JPY")
+	euros, _ := domain.ParseMoney("9.99", "EUR")
+	tax := application.PriceDto(yen)
+	cartDto := application.CartDto{
+		Currency: "JPY",
+		Status:   "active",
+		Items: []application.ItemDto{
+			{UnitPrice: application.PriceDto(euros), Currency: "EUR", Quantity: 1, Tax: &tax},
+		},
+		CartDiscounts: []application.DiscountDto{{Promotion: "welcome", Amount: application.PriceDto(yen)}},
+		Shipping:      &application.ShippingDto{Method: "standard", Name: "Standard", Cost: application.PriceDto(yen)},
+		Subtotal:      application.PriceDto(yen),
+		Tax:           &tax,
+		Total:         application.PriceDto(yen),
+	}
+
+	data, err := json.Marshal(cartDto)
+	assert.NoError(t, err)
+
+	var decoded application.CartDto
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, cartDto, decoded)
+}
+
+func Test_GivenAnOrderDtoWithAnInvalidAmount_WhenUnmarshalJSON_ThenReturnError(t *testing.T) {
+	var decoded application.OrderDto
+
+	err := json.Unmarshal([]byte(`{"currency":"USD","subtotal":"1e1000","total":"1.00"}`), &decoded)
+
+	assert.Error(t, err)
 }
@@ -2,15 +2,17 @@ package test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/bitlogic/go-startup/src/application"
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GivenANilCustomerRepository_WhenNewCustomerService_ThenReturnError(t *testing.T) {
-	service, err := application.NewCustomerService(nil)
+	service, err := application.NewCustomerService(nil, &eventDispatcherMock{})
 
 	if assert.Error(t, err) {
 		assert.Equal(t, "customer repository was nil", err.Error())
@@ -18,8 +20,17 @@ func Test_GivenANilCustomerRepository_WhenNewCustomerService_ThenReturnError(t *
 	assert.Nil(t, service)
 }
 
+func Test_GivenANilEventDispatcher_WhenNewCustomerService_ThenReturnError(t *testing.T) {
+	service, err := application.NewCustomerService(&customerRepositoryMock{}, nil)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "event dispatcher was nil", err.Error())
+	}
+	assert.Nil(t, service)
+}
+
 func Test_GivenACustomerRepository_WhenNewCustomerService_ThenReturnACustomerService(t *testing.T) {
-	service, err := application.NewCustomerService(&customerRepositoryMock{})
+	service, err := application.NewCustomerService(&customerRepositoryMock{}, &eventDispatcherMock{})
 
 	assert.Nil(t, err)
 	assert.NotEmpty(t, service)
@@ -31,7 +42,7 @@ func Test_GivenAValidCreateCustomerCommand_WhenCreateNewCustomer_ThenReturnACust
 			return nil
 		},
 	}
-	service, _ := application.NewCustomerService(repository)
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
 	customerToSave := application.CreateCustomerCommand{
 		CustomerName: "Robert Smith Jr.",
 	}
@@ -52,7 +63,7 @@ func Test_GivenACreateCustomerCommandWithInvalidName_WhenCreateNewCustomer_ThenR
 			return nil
 		},
 	}
-	service, _ := application.NewCustomerService(repository)
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
 	customerToSave := application.CreateCustomerCommand{
 		CustomerName: "bob",
 	}
@@ -72,7 +83,7 @@ func Test_GivenRepositoryFailsToSaveNewCustomer_WhenCreateNewCustomer_ThenReturn
 			return errors.New("failed to save entity")
 		},
 	}
-	service, _ := application.NewCustomerService(repository)
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
 	customerToSave := application.CreateCustomerCommand{
 		CustomerName: "Uncle Bob",
 	}
@@ -85,3 +96,252 @@ func Test_GivenRepositoryFailsToSaveNewCustomer_WhenCreateNewCustomer_ThenReturn
 	}
 	assert.Equal(t, 1, repository.callCount)
 }
+
+func Test_GivenAValidCreateCustomerCommand_WhenCreateNewCustomer_ThenDispatchCustomerCreated(t *testing.T) {
+	repository := &customerRepositoryMock{
+		save: func(customer *domain.Customer) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCustomerService(repository, eventDispatcher)
+
+	result, err := service.CreateNewCustomer(application.CreateCustomerCommand{
+		CustomerName: "Robert Smith Jr.",
+	})
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.Equal(t, domain.CustomerCreated{
+			CustomerId:   domain.CustomerId(result.Id),
+			CustomerName: "Robert Smith Jr.",
+		}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenEventDispatchFails_WhenCreateNewCustomer_ThenTheSavedCustomerIsReturned(t *testing.T) {
+	var savedCustomer *domain.Customer
+	repository := &customerRepositoryMock{
+		save: func(customer *domain.Customer) error {
+			savedCustomer = customer
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{
+		dispatch: func(...domain.DomainEvent) error {
+			return errors.New("failed to handle event")
+		},
+	}
+	service, _ := application.NewCustomerService(repository, eventDispatcher)
+
+	result, err := service.CreateNewCustomer(application.CreateCustomerCommand{
+		CustomerName: "Robert Smith Jr.",
+	})
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, savedCustomer) {
+		assert.Equal(t, uuid.UUID(savedCustomer.GetID()), result.Id)
+	}
+	assert.Len(t, eventDispatcher.dispatchedEvents, 1)
+	assert.Empty(t, savedCustomer.GetDomainEvents())
+}
+
+func Test_GivenAnExistingCustomer_WhenGetCustomer_ThenReturnTheCustomerDto(t *testing.T) {
+	customer, _ := domain.NewCustomer("Robert Smith Jr.")
+	repository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+	}
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
+
+	result, err := service.GetCustomer(application.GetCustomerQuery{CustomerId: uuid.UUID(customer.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(customer.GetID()), result.Id)
+	assert.Equal(t, "Robert Smith Jr.", result.Name)
+}
+
+func Test_GivenANonExistantCustomer_WhenGetCustomer_ThenReturnNotFoundError(t *testing.T) {
+	repository := &customerRepositoryMock{
+		findById: func(customerId domain.CustomerId) (*domain.Customer, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
+	customerId := uuid.New()
+
+	result, err := service.GetCustomer(application.GetCustomerQuery{CustomerId: customerId})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("customer with id %s not found", customerId.String()), err.Error())
+	}
+}
+
+func Test_GivenACreateCustomerCommandWithAnEmailInUse_WhenCreateNewCustomer_ThenReturnConflictError(t *testing.T) {
+	repository := &customerRepositoryMock{
+		save: func(*domain.Customer) error {
+			return &domain.UniqueConstraintError{Field: "email", Value: "robert@example.com"}
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCustomerService(repository, eventDispatcher)
+
+	result, err := service.CreateNewCustomer(application.CreateCustomerCommand{
+		CustomerName: "Robert Smith Jr.",
+		Email:        "Robert@example.com",
+	})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.ConflictError{}, err)
+		assert.Equal(t, `email "robert@example.com" is already in use`, err.Error())
+		assert.Equal(t, "email", err.(*application.ConflictError).Field())
+	}
+	assert.Equal(t, 1, repository.callCount)
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenAnUpdateCustomerCommand_WhenUpdateCustomer_ThenTheProfileIsUpdated(t *testing.T) {
+	customer, _ := domain.NewCustomer("Robert Smith Jr.")
+	customer.ClearDomainEvents()
+	repository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+		save: func(*domain.Customer) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCustomerService(repository, eventDispatcher)
+	name := "Robert Smith III"
+	email := "Robert@Example.com"
+	phone := "+54 11 4321 8765"
+	address := application.AddressDto{Street: "Av. Corrientes 1234", City: "Buenos Aires", PostalCode: "C1043", Country: "ar"}
+
+	result, err := service.UpdateCustomer(application.UpdateCustomerCommand{
+		CustomerId:      uuid.UUID(customer.GetID()),
+		CustomerName:    &name,
+		Email:           &email,
+		Phone:           &phone,
+		ShippingAddress: &address,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, application.CustomerDto{
+		Id:              uuid.UUID(customer.GetID()),
+		Name:            "Robert Smith III",
+		Email:           "robert@example.com",
+		Phone:           "+541143218765",
+		ShippingAddress: &application.AddressDto{Street: "Av. Corrientes 1234", City: "Buenos Aires", PostalCode: "C1043", Country: "AR"},
+		Active:          true,
+	}, result)
+	if assert.Equal(t, 3, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.CustomerRenamed{}, eventDispatcher.dispatchedEvents[0])
+		assert.IsType(t, domain.CustomerEmailChanged{}, eventDispatcher.dispatchedEvents[1])
+		assert.IsType(t, domain.CustomerContactDetailsChanged{}, eventDispatcher.dispatchedEvents[2])
+	}
+}
+
+func Test_GivenAnInvalidPhone_WhenUpdateCustomer_ThenReturnInvalidArgumentError(t *testing.T) {
+	customer, _ := domain.NewCustomer("Robert Smith Jr.")
+	repository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+	}
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
+	phone := "not a phone"
+
+	_, err := service.UpdateCustomer(application.UpdateCustomerCommand{CustomerId: uuid.UUID(customer.GetID()), Phone: &phone})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid phone: invalid phone number", err.Error())
+	}
+	assert.Equal(t, 1, repository.callCount)
+}
+
+func Test_GivenADeactivatedCustomer_WhenUpdateCustomer_ThenReturnInvalidArgumentError(t *testing.T) {
+	customer, _ := domain.NewCustomer("Robert Smith Jr.")
+	customer.Deactivate()
+	repository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+	}
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
+	name := "Robert Smith III"
+
+	_, err := service.UpdateCustomer(application.UpdateCustomerCommand{CustomerId: uuid.UUID(customer.GetID()), CustomerName: &name})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid customer: it is deactivated", err.Error())
+	}
+}
+
+func Test_GivenAnExistingCustomer_WhenDeactivateCustomer_ThenTheCustomerIsNoLongerActive(t *testing.T) {
+	customer, _ := domain.NewCustomer("Robert Smith Jr.")
+	customer.ClearDomainEvents()
+	repository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+		save: func(*domain.Customer) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewCustomerService(repository, eventDispatcher)
+
+	result, err := service.DeactivateCustomer(application.DeactivateCustomerCommand{CustomerId: uuid.UUID(customer.GetID())})
+
+	assert.Nil(t, err)
+	assert.False(t, result.Active)
+	assert.Equal(t, []domain.DomainEvent{domain.CustomerDeactivated{CustomerId: customer.GetID()}}, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenANonExistantCustomer_WhenDeactivateCustomer_ThenReturnNotFoundError(t *testing.T) {
+	repository := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewCustomerService(repository, &eventDispatcherMock{})
+	customerId := uuid.New()
+
+	_, err := service.DeactivateCustomer(application.DeactivateCustomerCommand{CustomerId: customerId})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("customer with id %s not found", customerId.String()), err.Error())
+	}
+}
+
+func Test_GivenAStaleExpectedVersion_WhenDeactivateCustomer_ThenReturnPreconditionFailedError(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	customer.SetVersion(5)
+	repositoryMock := &customerRepositoryMock{
+		findById: func(domain.CustomerId) (*domain.Customer, error) {
+			return customer, nil
+		},
+		save: func(*domain.Customer) error {
+			t.Fatal("a stale customer must not be saved")
+			return nil
+		},
+	}
+	customerService, _ := application.NewCustomerService(repositoryMock, &eventDispatcherMock{})
+	staleVersion := 4
+
+	_, err := customerService.DeactivateCustomer(application.DeactivateCustomerCommand{
+		CustomerId:      uuid.UUID(customer.GetID()),
+		ExpectedVersion: &staleVersion,
+	})
+
+	assert.IsType(t, &application.PreconditionFailedError{}, err)
+	assert.True(t, customer.IsActive())
+}
@@ -1,13 +1,19 @@
 package test
 
 import (
+	"errors"
+	"math/big"
+	"time"
+
 	"github.com/bitlogic/go-startup/src/domain"
 )
 
 type cartRepositoryMock struct {
-	callCount int
-	findById  func(domain.CartId) (*domain.Cart, error)
-	save      func(*domain.Cart) error
+	callCount        int
+	findById         func(domain.CartId) (*domain.Cart, error)
+	save             func(*domain.Cart) error
+	getCustomerCarts func(domain.CustomerId) ([]*domain.Cart, error)
+	findIdleCarts    func(time.Time) ([]*domain.Cart, error)
 }
 
 func (r *cartRepositoryMock) FindByID(cartId domain.CartId) (*domain.Cart, error) {
@@ -20,14 +26,29 @@ func (r *cartRepositoryMock) Save(cart *domain.Cart) error {
 	return r.save(cart)
 }
 
-func (r *cartRepositoryMock) GetCustomerCarts(customerId domain.CustomerId) []*domain.Cart {
-	return nil
+func (r *cartRepositoryMock) GetCustomerCarts(customerId domain.CustomerId) ([]*domain.Cart, error) {
+	r.callCount++
+	if r.getCustomerCarts == nil {
+		return nil, nil
+	}
+	return r.getCustomerCarts(customerId)
+}
+
+func (r *cartRepositoryMock) FindIdleCarts(lastActivityBefore time.Time) ([]*domain.Cart, error) {
+	r.callCount++
+	if r.findIdleCarts == nil {
+		return nil, nil
+	}
+	return r.findIdleCarts(lastActivityBefore)
 }
 
 type productRepositoryMock struct {
-	callCount int
-	findByID  func(domain.ProductId) (*domain.Product, error)
-	save      func(*domain.Product) error
+	callCount  int
+	findByID   func(domain.ProductId) (*domain.Product, error)
+	findBySKU  func(domain.SKU) (*domain.Product, error)
+	findByName func(string) (*domain.Product, error)
+	save       func(*domain.Product) error
+	list       func(domain.ProductListQuery) (domain.ProductPage, error)
 }
 
 func (m *productRepositoryMock) FindByID(productId domain.ProductId) (*domain.Product, error) {
@@ -35,11 +56,26 @@ func (m *productRepositoryMock) FindByID(productId domain.ProductId) (*domain.Pr
 	return m.findByID(productId)
 }
 
+func (m *productRepositoryMock) FindBySKU(sku domain.SKU) (*domain.Product, error) {
+	m.callCount++
+	return m.findBySKU(sku)
+}
+
+func (m *productRepositoryMock) FindByName(name string) (*domain.Product, error) {
+	m.callCount++
+	return m.findByName(name)
+}
+
 func (m *productRepositoryMock) Save(newProduct *domain.Product) error {
 	m.callCount++
 	return m.save(newProduct)
 }
 
+func (m *productRepositoryMock) List(query domain.ProductListQuery) (domain.ProductPage, error) {
+	m.callCount++
+	return m.list(query)
+}
+
 type customerRepositoryMock struct {
 	callCount int
 	findById  func(domain.CustomerId) (*domain.Customer, error)
@@ -55,3 +91,147 @@ func (r *customerRepositoryMock) Save(customer *domain.Customer) error {
 	r.callCount++
 	return r.save(customer)
 }
+
+type orderRepositoryMock struct {
+	callCount int
+	findById  func(domain.OrderId) (*domain.Order, error)
+	save      func(*domain.Order) error
+}
+
+func (r *orderRepositoryMock) FindByID(orderId domain.OrderId) (*domain.Order, error) {
+	r.callCount++
+	return r.findById(orderId)
+}
+
+func (r *orderRepositoryMock) Save(order *domain.Order) error {
+	r.callCount++
+	return r.save(order)
+}
+
+type stockRepositoryMock struct {
+	callCount           int
+	findById            func(domain.ProductId) (*domain.StockItem, error)
+	save                func(*domain.StockItem) error
+	getCartReservations func(domain.CartId) ([]*domain.StockItem, error)
+}
+
+func (r *stockRepositoryMock) FindByID(productId domain.ProductId) (*domain.StockItem, error) {
+	r.callCount++
+	return r.findById(productId)
+}
+
+func (r *stockRepositoryMock) Save(stockItem *domain.StockItem) error {
+	r.callCount++
+	return r.save(stockItem)
+}
+
+func (r *stockRepositoryMock) GetCartReservations(cartId domain.CartId) ([]*domain.StockItem, error) {
+	r.callCount++
+	return r.getCartReservations(cartId)
+}
+
+type quoteRepositoryMock struct {
+	callCount       int
+	findById        func(domain.QuoteId) (*domain.Quote, error)
+	save            func(*domain.Quote) error
+	nextQuoteNumber func(time.Time) (string, error)
+	findExpired     func(time.Time) ([]*domain.Quote, error)
+}
+
+func (r *quoteRepositoryMock) FindByID(quoteId domain.QuoteId) (*domain.Quote, error) {
+	r.callCount++
+	return r.findById(quoteId)
+}
+
+func (r *quoteRepositoryMock) Save(quote *domain.Quote) error {
+	r.callCount++
+	return r.save(quote)
+}
+
+func (r *quoteRepositoryMock) NextQuoteNumber(issuedAt time.Time) (string, error) {
+	r.callCount++
+	return r.nextQuoteNumber(issuedAt)
+}
+
+func (r *quoteRepositoryMock) FindExpiredQuotes(now time.Time) ([]*domain.Quote, error) {
+	r.callCount++
+	return r.findExpired(now)
+}
+
+type eventDispatcherMock struct {
+	dispatchedEvents []domain.DomainEvent
+	dispatch         func(...domain.DomainEvent) error
+}
+
+func (d *eventDispatcherMock) Register(eventName string, handler domain.DomainEventHandler) {}
+
+func (d *eventDispatcherMock) Dispatch(events ...domain.DomainEvent) error {
+	d.dispatchedEvents = append(d.dispatchedEvents, events...)
+	if d.dispatch == nil {
+		return nil
+	}
+	return d.dispatch(events...)
+}
+
+type exchangeRateProviderMock struct {
+	rates map[string]*big.Rat
+}
+
+func (m *exchangeRateProviderMock) GetRate(from domain.Currency, to domain.Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	if rate, found := m.rates[string(from)+"/"+string(to)]; found {
+		return rate, nil
+	}
+
+	return nil, domain.ErrCurrencyNotConvertible
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func moneyRef(money domain.Money) *domain.Money {
+	return &money
+}
+
+type promotionCatalogMock struct {
+	coupons   map[domain.CouponCode]domain.Coupon
+	automatic []domain.Promotion
+}
+
+func (m *promotionCatalogMock) FindCoupon(code domain.CouponCode) (domain.Coupon, error) {
+	if coupon, found := m.coupons[code]; found {
+		return coupon, nil
+	}
+
+	return domain.Coupon{}, errors.New("coupon not found")
+}
+
+func (m *promotionCatalogMock) GetAutomaticPromotions() []domain.Promotion {
+	return m.automatic
+}
+
+type shippingCatalogMock struct {
+	methods []domain.ShippingMethod
+}
+
+func (m *shippingCatalogMock) FindShippingMethod(code domain.ShippingMethodCode) (domain.ShippingMethod, error) {
+	for _, method := range m.methods {
+		if method.GetCode() == code {
+			return method, nil
+		}
+	}
+
+	return nil, errors.New("shipping method not found")
+}
+
+func (m *shippingCatalogMock) GetShippingMethods() []domain.ShippingMethod {
+	return m.methods
+}
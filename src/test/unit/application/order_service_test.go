@@ -0,0 +1,329 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilOrderRepository_WhenNewOrderService_ThenReturnError(t *testing.T) {
+	service, err := application.NewOrderService(&cartRepositoryMock{}, nil, &eventDispatcherMock{})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "order repository was nil", err.Error())
+	}
+	assert.Nil(t, service)
+}
+
+func Test_GivenACartWithItems_WhenCheckoutCart_ThenPlaceAnOrderAndDispatchEvents(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 2)
+	cart.ClearDomainEvents()
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	var savedOrder *domain.Order
+	orderRepository := &orderRepositoryMock{
+		save: func(order *domain.Order) error {
+			savedOrder = order
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher)
+
+	result, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID())})
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, savedOrder) {
+		assert.Equal(t, uuid.UUID(savedOrder.GetID()), result.Id)
+	}
+	assert.Equal(t, uuid.UUID(cart.GetID()), result.CartId)
+	assert.Equal(t, application.PriceDto(testutil.USD("90.00")), result.Total)
+	if assert.Equal(t, 1, len(result.Lines)) {
+		assert.Equal(t, 2, result.Lines[0].Quantity)
+		assert.Equal(t, application.PriceDto(testutil.USD("45.00")), result.Lines[0].UnitPrice)
+	}
+	assert.True(t, cart.IsCheckedOut())
+	if assert.Equal(t, 2, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.CartCheckedOut{}, eventDispatcher.dispatchedEvents[0])
+		assert.IsType(t, domain.OrderPlaced{}, eventDispatcher.dispatchedEvents[1])
+	}
+}
+
+func Test_GivenAProductWhosePriceChanged_WhenCheckoutCartRepricing_ThenRejectOnceAndPlaceTheOrderAtTheNewPrice(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 2)
+	cart.ClearDomainEvents()
+	book.ChangePrice(testutil.USD("40.00"))
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+		save: func(cart *domain.Cart) error {
+			return nil
+		},
+	}
+	orderRepository := &orderRepositoryMock{
+		save: func(order *domain.Order) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher, application.WithCheckoutRepricing(productRepositoryReturning(book), &exchangeRateProviderMock{}))
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID())})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid cart: prices changed for products "+book.GetID().String()+" since they were added; review the cart and check out again", err.Error())
+	}
+	assert.False(t, cart.IsCheckedOut())
+	assert.Equal(t, 0, orderRepository.callCount)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.ItemRepriced{}, eventDispatcher.dispatchedEvents[0])
+	}
+
+	result, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, application.PriceDto(testutil.USD("80.00")), result.Total)
+	assert.True(t, cart.IsCheckedOut())
+}
+
+func Test_GivenAnEmptyCart_WhenCheckoutCart_ThenReturnInvalidArgumentError(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+	}
+	orderRepository := &orderRepositoryMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, &eventDispatcherMock{})
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID())})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid cart: it has no items", err.Error())
+	}
+	assert.Equal(t, 0, orderRepository.callCount)
+}
+
+func Test_GivenANonExistentCart_WhenCheckoutCart_ThenReturnNotFoundError(t *testing.T) {
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewOrderService(cartRepository, &orderRepositoryMock{}, &eventDispatcherMock{})
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.New()})
+
+	assert.IsType(t, &application.NotFoundError{}, err)
+}
+
+func Test_GivenAnExistingOrder_WhenGetOrder_ThenReturnTheOrderDto(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 1)
+	placedOn := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	order, _ := domain.PlaceOrder(cart, placedOn)
+	orderRepository := &orderRepositoryMock{
+		findById: func(orderId domain.OrderId) (*domain.Order, error) {
+			return order, nil
+		},
+	}
+	service, _ := application.NewOrderService(&cartRepositoryMock{}, orderRepository, &eventDispatcherMock{})
+
+	result, err := service.GetOrder(application.GetOrderQuery{OrderId: uuid.UUID(order.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(order.GetID()), result.Id)
+	assert.Equal(t, uuid.UUID(martinFowler.GetID()), result.CustomerId)
+	assert.Equal(t, placedOn, result.PlacedOn)
+}
+
+func Test_GivenATaxedOrder_WhenGetOrder_ThenReturnTheTaxOfEachLine(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 1)
+	calculator, _ := domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100), Inclusive: true},
+	)
+	cart.ApplyTaxes("AR", calculator)
+	order, _ := domain.PlaceOrder(cart, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	orderRepository := &orderRepositoryMock{
+		findById: func(orderId domain.OrderId) (*domain.Order, error) {
+			return order, nil
+		},
+	}
+	service, _ := application.NewOrderService(&cartRepositoryMock{}, orderRepository, &eventDispatcherMock{})
+
+	result, err := service.GetOrder(application.GetOrderQuery{OrderId: uuid.UUID(order.GetID())})
+
+	assert.Nil(t, err)
+	tax := application.PriceDto(testutil.USD("7.81"))
+	assert.Equal(t, &tax, result.Tax)
+	if assert.Equal(t, 1, len(result.Lines)) {
+		assert.Equal(t, "0.21", result.Lines[0].TaxRate)
+		assert.True(t, result.Lines[0].TaxIncluded)
+		assert.Equal(t, &tax, result.Lines[0].Tax)
+	}
+	assert.Equal(t, application.PriceDto(testutil.USD("45.00")), result.Total)
+}
+
+func Test_GivenANonExistentOrder_WhenGetOrder_ThenReturnNotFoundError(t *testing.T) {
+	orderRepository := &orderRepositoryMock{
+		findById: func(orderId domain.OrderId) (*domain.Order, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	service, _ := application.NewOrderService(&cartRepositoryMock{}, orderRepository, &eventDispatcherMock{})
+
+	_, err := service.GetOrder(application.GetOrderQuery{OrderId: uuid.New()})
+
+	assert.IsType(t, &application.NotFoundError{}, err)
+}
+
+func Test_GivenAStaleExpectedVersion_WhenCheckoutCart_ThenReturnPreconditionFailedErrorWithoutPlacingAnOrder(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 2)
+	cart.SetVersion(4)
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+	}
+	orderRepository := &orderRepositoryMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, &eventDispatcherMock{})
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID()), ExpectedVersion: intRef(3)})
+
+	assert.Equal(t, application.NewPreconditionFailedError(cart.GetID().String(), "cart", 3, 4), err)
+	assert.True(t, cart.IsActive())
+	assert.Equal(t, 1, cartRepository.callCount)
+	assert.Equal(t, 0, orderRepository.callCount)
+}
+
+func Test_GivenAnOrderThatFailsToSave_WhenCheckoutCart_ThenTheCartIsNotCheckedOut(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	storedCart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	storedCart.AddItem(book, 2)
+	storedCart.ClearDomainEvents()
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(*domain.Cart) error {
+			return nil
+		},
+	}
+	orderRepository := &orderRepositoryMock{
+		save: func(*domain.Order) error {
+			return errors.New("disk full")
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher)
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(storedCart.GetID())})
+
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 1, cartRepository.callCount)
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenACartThatFailsToSave_WhenCheckoutCart_ThenTheOrderEventsAreNotDispatched(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	storedCart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	storedCart.AddItem(book, 2)
+	storedCart.ClearDomainEvents()
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return storedCart.Clone(), nil
+		},
+		save: func(cart *domain.Cart) error {
+			return &domain.ConcurrencyConflictError{Id: cart.GetID().String(), ExpectedVersion: 0, ActualVersion: 1}
+		},
+	}
+	var savedOrder *domain.Order
+	orderRepository := &orderRepositoryMock{
+		save: func(order *domain.Order) error {
+			savedOrder = order
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher)
+
+	_, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(storedCart.GetID())})
+
+	assert.IsType(t, &application.ConcurrencyConflictError{}, err)
+	if assert.NotNil(t, savedOrder) {
+		assert.Empty(t, savedOrder.GetDomainEvents())
+	}
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenAFailingEventHandler_WhenCheckoutCart_ThenTheStoredOrderIsStillReturned(t *testing.T) {
+	martinFowler, _ := domain.NewCustomer("Martin Fowler")
+	cart, _ := domain.NewCart(martinFowler)
+	book, _ := domain.NewProduct("Refactoring Second Edition", testutil.USD("45.00"))
+	cart.AddItem(book, 2)
+	cart.ClearDomainEvents()
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+		save: func(*domain.Cart) error {
+			return nil
+		},
+	}
+	var savedOrder *domain.Order
+	orderRepository := &orderRepositoryMock{
+		save: func(order *domain.Order) error {
+			savedOrder = order
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{
+		dispatch: func(...domain.DomainEvent) error {
+			return errors.New("stock item was modified concurrently")
+		},
+	}
+	service, _ := application.NewOrderService(cartRepository, orderRepository, eventDispatcher)
+
+	result, err := service.CheckoutCart(application.CheckoutCartCommand{CartId: uuid.UUID(cart.GetID())})
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, savedOrder) {
+		assert.Equal(t, uuid.UUID(savedOrder.GetID()), result.Id)
+	}
+	assert.Len(t, eventDispatcher.dispatchedEvents, 2)
+}
@@ -2,16 +2,19 @@ package test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/bitlogic/go-startup/src/application"
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GivenANilProductRepository_WhenNewProductService_ThenReturnError(t *testing.T) {
-	productService, err := application.NewProductService(nil)
+	productService, err := application.NewProductService(nil, &eventDispatcherMock{})
 
 	if assert.Error(t, err) {
 		assert.Equal(t, "repository was nil", err.Error())
@@ -19,10 +22,19 @@ func Test_GivenANilProductRepository_WhenNewProductService_ThenReturnError(t *te
 	assert.Nil(t, productService)
 }
 
+func Test_GivenANilEventDispatcher_WhenNewProductService_ThenReturnError(t *testing.T) {
+	productService, err := application.NewProductService(&productRepositoryMock{}, nil)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "event dispatcher was nil", err.Error())
+	}
+	assert.Nil(t, productService)
+}
+
 func Test_GivenAProductRepository_WhenNewProductService_ThenReturnAProductService(t *testing.T) {
 	repo := repositories.NewInMemoryProductRepository()
 
-	productService, err := application.NewProductService(repo)
+	productService, err := application.NewProductService(repo, &eventDispatcherMock{})
 
 	assert.Nil(t, err)
 	assert.NotNil(t, productService)
@@ -34,10 +46,11 @@ func Test_GivenAWellFormedCreateProductCommand_WhenCreateNewProduct_ThenReturnAN
 			return nil
 		},
 	}
-	productService, _ := application.NewProductService(repositoryMock)
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
 	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
 		ProductName: "Pepsi 2.25Lt",
-		UnitPrice:   10.00,
+		UnitPrice:   "10.00",
 	}
 
 	output, err := productService.CreateNewProduct(createProductCommand)
@@ -45,23 +58,44 @@ func Test_GivenAWellFormedCreateProductCommand_WhenCreateNewProduct_ThenReturnAN
 	assert.Nil(t, err)
 	if assert.NotEmpty(t, output) {
 		assert.NotNil(t, output.Id)
+		assert.Equal(t, "PEP-225", output.SKU)
 		assert.Equal(t, "Pepsi 2.25Lt", output.Name)
-		assert.Equal(t, application.PriceDto(10.00), output.UnitPrice)
+		assert.Equal(t, application.PriceDto(testutil.USD("10.00")), output.UnitPrice)
 	}
 
 	assert.Equal(t, 1, repositoryMock.callCount)
 }
 
+func Test_GivenACreateProductCommandWithInvalidSKU_WhenCreateNewProduct_ThenReturnInvalidArgumentError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP 2.25",
+		ProductName: "Pepsi 2.25Lt",
+		UnitPrice:   "10.00",
+	}
+
+	output, err := productService.CreateNewProduct(createProductCommand)
+
+	assert.Empty(t, output)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid sku: invalid sku", err.Error())
+	}
+	assert.Equal(t, 0, repositoryMock.callCount)
+}
+
 func Test_GivenACreateProductCommandWithInvalidName_WhenCreateNewProduct_ThenReturnError(t *testing.T) {
 	repositoryMock := &productRepositoryMock{
 		save: func(product *domain.Product) error {
 			return nil
 		},
 	}
-	productService, _ := application.NewProductService(repositoryMock)
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
 	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
 		ProductName: "Pepsi",
-		UnitPrice:   10.00,
+		UnitPrice:   "10.00",
 	}
 
 	output, err := productService.CreateNewProduct(createProductCommand)
@@ -79,10 +113,11 @@ func Test_GivenACreateProductCommandWithInvalidPrice_WhenCreateNewProduct_ThenRe
 			return nil
 		},
 	}
-	productService, _ := application.NewProductService(repositoryMock)
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
 	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
 		ProductName: "Pepsi 2.25Lts",
-		UnitPrice:   0.00,
+		UnitPrice:   "0.00",
 	}
 
 	output, err := productService.CreateNewProduct(createProductCommand)
@@ -100,10 +135,11 @@ func Test_GivenSavingProductFails_WhenCreateNewProduct_ThenReturnError(t *testin
 			return errors.New("failed to save entity")
 		},
 	}
-	productService, _ := application.NewProductService(repositoryMock)
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
 	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
 		ProductName: "Pepsi 2.25Lts",
-		UnitPrice:   10.00,
+		UnitPrice:   "10.00",
 	}
 
 	output, err := productService.CreateNewProduct(createProductCommand)
@@ -114,3 +150,491 @@ func Test_GivenSavingProductFails_WhenCreateNewProduct_ThenReturnError(t *testin
 	assert.Empty(t, output)
 	assert.Equal(t, 1, repositoryMock.callCount)
 }
+
+func Test_GivenAProductNameInUse_WhenCreateNewProduct_ThenReturnConflictError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		save: func(product *domain.Product) error {
+			return &domain.UniqueConstraintError{Field: "product_name", Value: "pepsi 2.25lts"}
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
+		ProductName: "Pepsi 2.25Lts",
+		UnitPrice:   "10.00",
+	}
+
+	output, err := productService.CreateNewProduct(createProductCommand)
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.ConflictError{}, err)
+		assert.Equal(t, `product_name "pepsi 2.25lts" is already in use`, err.Error())
+	}
+	assert.Empty(t, output)
+}
+
+func Test_GivenEventDispatchFails_WhenCreateNewProduct_ThenTheSavedProductIsReturned(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		save: func(product *domain.Product) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{
+		dispatch: func(events ...domain.DomainEvent) error {
+			return errors.New("failed to handle event")
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, eventDispatcher)
+	createProductCommand := application.CreateProductCommand{
+		SKU:         "PEP-225",
+		ProductName: "Pepsi 2.25Lts",
+		UnitPrice:   "10.00",
+	}
+
+	output, err := productService.CreateNewProduct(createProductCommand)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Pepsi 2.25Lts", output.Name)
+	assert.Equal(t, 1, repositoryMock.callCount)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.ProductCreated{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnExistingProduct_WhenGetProduct_ThenReturnTheProductDto(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"))
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.GetProduct(application.GetProductQuery{ProductId: uuid.UUID(product.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(product.GetID()), output.Id)
+	assert.Equal(t, "Pepsi 2.25Lts", output.Name)
+	assert.Equal(t, application.PriceDto(testutil.USD("10.00")), output.UnitPrice)
+}
+
+func Test_GivenANonExistantProduct_WhenGetProduct_ThenReturnNotFoundError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	productId := uuid.New()
+
+	output, err := productService.GetProduct(application.GetProductQuery{ProductId: productId})
+
+	assert.Empty(t, output)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", productId.String()), err.Error())
+	}
+}
+
+func Test_GivenAnExistingSKU_WhenGetProductBySKU_ThenReturnTheProductDto(t *testing.T) {
+	sku, _ := domain.NewSKU("PEP-225")
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"), domain.WithSKU(sku))
+	repositoryMock := &productRepositoryMock{
+		findBySKU: func(sku domain.SKU) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.GetProductBySKU(application.GetProductBySKUQuery{SKU: "pep-225"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uuid.UUID(product.GetID()), output.Id)
+	assert.Equal(t, "PEP-225", output.SKU)
+}
+
+func Test_GivenAnUnknownOrInvalidSKU_WhenGetProductBySKU_ThenReturnError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		findBySKU: func(sku domain.SKU) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.GetProductBySKU(application.GetProductBySKUQuery{SKU: "PEP-999"})
+
+	assert.Empty(t, output)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, "product with id PEP-999 not found", err.Error())
+	}
+
+	_, err = productService.GetProductBySKU(application.GetProductBySKUQuery{SKU: "PEP 999"})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+	}
+	assert.Equal(t, 1, repositoryMock.callCount)
+}
+
+func Test_GivenAListProductsQuery_WhenListProducts_ThenTranslateItToARepositoryQuery(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"))
+	var receivedQuery domain.ProductListQuery
+	repositoryMock := &productRepositoryMock{
+		list: func(query domain.ProductListQuery) (domain.ProductPage, error) {
+			receivedQuery = query
+			return domain.ProductPage{
+				Products:   []*domain.Product{product},
+				NextCursor: "next",
+			}, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.ListProducts(application.ListProductsQuery{
+		Search:   " pepsi ",
+		MinPrice: "1",
+		MaxPrice: "20",
+		Sort:     "-price",
+		Cursor:   "cursor",
+		Limit:    5,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.ProductListQuery{
+		NameContains: "pepsi",
+		MinPrice:     moneyRef(testutil.USD("1.00")),
+		MaxPrice:     moneyRef(testutil.USD("20.00")),
+		SortBy:       domain.SortProductsByPrice,
+		Descending:   true,
+		Cursor:       "cursor",
+		Limit:        5,
+	}, receivedQuery)
+	if assert.Equal(t, 1, len(output.Items)) {
+		assert.Equal(t, "Pepsi 2.25Lts", output.Items[0].Name)
+	}
+	if assert.NotNil(t, output.NextCursor) {
+		assert.Equal(t, "next", *output.NextCursor)
+	}
+}
+
+func Test_GivenTheLastPage_WhenListProducts_ThenTheNextCursorIsNil(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		list: func(query domain.ProductListQuery) (domain.ProductPage, error) {
+			return domain.ProductPage{}, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.ListProducts(application.ListProductsQuery{})
+
+	assert.Nil(t, err)
+	assert.Empty(t, output.Items)
+	assert.NotNil(t, output.Items)
+	assert.Nil(t, output.NextCursor)
+}
+
+func Test_GivenAnInvalidCursor_WhenListProducts_ThenReturnInvalidArgumentError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		list: func(query domain.ProductListQuery) (domain.ProductPage, error) {
+			return domain.ProductPage{}, domain.ErrInvalidCursor
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.ListProducts(application.ListProductsQuery{Cursor: "garbage"})
+
+	assert.Empty(t, output)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid cursor: it does not belong to this listing", err.Error())
+	}
+}
+
+func Test_GivenAnInvertedPriceRange_WhenListProducts_ThenReturnInvalidArgumentError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	_, err := productService.ListProducts(application.ListProductsQuery{MinPrice: "10", MaxPrice: "5"})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+	}
+	assert.Equal(t, 0, repositoryMock.callCount)
+}
+
+func Test_GivenAnUpdateProductCommand_WhenUpdateProduct_ThenRenameAndRepriceTheProduct(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	productService, _ := application.NewProductService(repositoryMock, eventDispatcher)
+	name := "Pepsi Light 2.25Lts"
+
+	output, err := productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId:   uuid.UUID(product.GetID()),
+		ProductName: &name,
+		UnitPrice:   "12.00",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Pepsi Light 2.25Lts", output.Name)
+	assert.Equal(t, application.PriceDto(testutil.USD("12.00")), output.UnitPrice)
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductRenamed{ProductId: product.GetID(), PreviousName: "Pepsi 2.25Lts", ProductName: "Pepsi Light 2.25Lts"},
+		domain.ProductPriceChanged{ProductId: product.GetID(), PreviousPrice: testutil.USD("10.00"), ProductUnitPrice: testutil.USD("12.00")},
+	}, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenACurrencyWithoutAPrice_WhenUpdateProduct_ThenReturnInvalidArgumentError(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"))
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId: uuid.UUID(product.GetID()),
+		Currency:  "EUR",
+	})
+
+	assert.Empty(t, output)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid currency: it can only be changed together with unit_price", err.Error())
+	}
+	assert.Equal(t, 1, repositoryMock.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenUpdateProduct_ThenReturnNotFoundError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	productId := uuid.New()
+
+	_, err := productService.UpdateProduct(application.UpdateProductCommand{ProductId: productId, UnitPrice: "1.00"})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", productId.String()), err.Error())
+	}
+}
+
+func Test_GivenAnExistingProduct_WhenArchiveAndRestoreProduct_ThenTheArchivedFlagFollows(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi 2.25Lts", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	productService, _ := application.NewProductService(repositoryMock, eventDispatcher)
+
+	archived, err := productService.ArchiveProduct(application.ArchiveProductCommand{ProductId: uuid.UUID(product.GetID())})
+	assert.NoError(t, err)
+	assert.True(t, archived.Archived)
+
+	restored, err := productService.RestoreProduct(application.RestoreProductCommand{ProductId: uuid.UUID(product.GetID())})
+	assert.NoError(t, err)
+	assert.False(t, restored.Archived)
+
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductArchived{ProductId: product.GetID()},
+		domain.ProductRestored{ProductId: product.GetID()},
+	}, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenANonExistantProduct_WhenArchiveProduct_ThenReturnNotFoundError(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	_, err := productService.ArchiveProduct(application.ArchiveProductCommand{ProductId: uuid.New()})
+
+	assert.IsType(t, &application.NotFoundError{}, err)
+}
+
+func Test_GivenACreateProductCommandWithATaxCategory_WhenCreateNewProduct_ThenTheProductUsesIt(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		save: func(product *domain.Product) error {
+			return nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.CreateNewProduct(application.CreateProductCommand{
+		SKU:         "DDD-BOOK",
+		ProductName: "Domain Driven Design",
+		UnitPrice:   "50.00",
+		TaxCategory: "Reduced",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "reduced", output.TaxCategory)
+}
+
+func Test_GivenAnInvalidTaxCategory_WhenCreateOrUpdateProduct_ThenReturnInvalidArgumentError(t *testing.T) {
+	product, _ := domain.NewProduct("Domain Driven Design", testutil.USD("50.00"))
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	_, err := productService.CreateNewProduct(application.CreateProductCommand{
+		SKU:         "DDD-BOOK",
+		ProductName: "Domain Driven Design",
+		UnitPrice:   "50.00",
+		TaxCategory: "books & magazines",
+	})
+	assert.EqualError(t, err, "invalid tax_category: invalid tax category")
+
+	_, err = productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId:   uuid.UUID(product.GetID()),
+		TaxCategory: "books & magazines",
+	})
+	assert.EqualError(t, err, "invalid tax_category: invalid tax category")
+	assert.Equal(t, domain.StandardTaxCategory, product.GetTaxCategory())
+}
+
+func Test_GivenAnUpdateProductCommandWithATaxCategory_WhenUpdateProduct_ThenChangeTheTaxCategory(t *testing.T) {
+	product, _ := domain.NewProduct("Domain Driven Design", testutil.USD("50.00"))
+	product.ClearDomainEvents()
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	productService, _ := application.NewProductService(repositoryMock, eventDispatcher)
+
+	output, err := productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId:   uuid.UUID(product.GetID()),
+		TaxCategory: "reduced",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "reduced", output.TaxCategory)
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductTaxCategoryChanged{ProductId: product.GetID(), PreviousTaxCategory: domain.StandardTaxCategory, TaxCategory: "reduced"},
+	}, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenACreateProductCommandWithShippingDetails_WhenCreateNewProduct_ThenTheProductUsesThem(t *testing.T) {
+	repositoryMock := &productRepositoryMock{
+		save: func(product *domain.Product) error {
+			return nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+
+	output, err := productService.CreateNewProduct(application.CreateProductCommand{
+		SKU:         "FOAM-PILLOW",
+		ProductName: "Memory Foam Pillow",
+		UnitPrice:   "30.00",
+		WeightGrams: 300,
+		Dimensions:  &application.DimensionsDto{LengthMm: 400, WidthMm: 300, HeightMm: 200},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 300, output.WeightGrams)
+	assert.Equal(t, &application.DimensionsDto{LengthMm: 400, WidthMm: 300, HeightMm: 200}, output.Dimensions)
+}
+
+func Test_GivenAnUpdateProductCommandWithShippingDetails_WhenUpdateProduct_ThenChangeThem(t *testing.T) {
+	product, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	product.ClearDomainEvents()
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	productService, _ := application.NewProductService(repositoryMock, eventDispatcher)
+	weightGrams := 450
+
+	output, err := productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId:   uuid.UUID(product.GetID()),
+		WeightGrams: &weightGrams,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 450, output.WeightGrams)
+	assert.Nil(t, output.Dimensions)
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductShippingDetailsChanged{ProductId: product.GetID(), Weight: 450},
+	}, eventDispatcher.dispatchedEvents)
+}
+
+func Test_GivenAStaleExpectedVersion_WhenUpdateProduct_ThenReturnPreconditionFailedError(t *testing.T) {
+	product, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	product.SetVersion(2)
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			t.Fatal("a stale product must not be saved")
+			return nil
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	staleVersion := 1
+
+	_, err := productService.ArchiveProduct(application.ArchiveProductCommand{
+		ProductId:       uuid.UUID(product.GetID()),
+		ExpectedVersion: &staleVersion,
+	})
+
+	assert.IsType(t, &application.PreconditionFailedError{}, err)
+	assert.False(t, product.IsArchived())
+}
+
+func Test_GivenAProductSavedConcurrently_WhenUpdateProduct_ThenReturnConcurrencyConflictError(t *testing.T) {
+	product, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	repositoryMock := &productRepositoryMock{
+		findByID: func(productId domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+		save: func(*domain.Product) error {
+			return &domain.ConcurrencyConflictError{Id: product.GetID().String(), ExpectedVersion: 0, ActualVersion: 1}
+		},
+	}
+	productService, _ := application.NewProductService(repositoryMock, &eventDispatcherMock{})
+	currentVersion := 0
+	newName := "Memory Foam Pillow XL"
+
+	_, err := productService.UpdateProduct(application.UpdateProductCommand{
+		ProductId:       uuid.UUID(product.GetID()),
+		ProductName:     &newName,
+		ExpectedVersion: &currentVersion,
+	})
+
+	assert.IsType(t, &application.ConcurrencyConflictError{}, err)
+}
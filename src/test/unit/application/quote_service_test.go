@@ -0,0 +1,216 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilClock_WhenNewQuoteService_ThenReturnError(t *testing.T) {
+	service, err := application.NewQuoteService(&cartRepositoryMock{}, &quoteRepositoryMock{}, &eventDispatcherMock{}, nil)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "clock was nil", err.Error())
+	}
+	assert.Nil(t, service)
+}
+
+func Test_GivenACartWithItems_WhenCreateQuote_ThenIssueANumberedQuote(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 2)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+	}
+	quoteRepository := &quoteRepositoryMock{
+		save: func(quote *domain.Quote) error {
+			return nil
+		},
+		nextQuoteNumber: func(issuedAt time.Time) (string, error) {
+			return "Q-20240102-000001", nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewQuoteService(cartRepository, quoteRepository, eventDispatcher, clock)
+
+	result, err := service.CreateQuote(application.CreateQuoteCommand{CartId: uuid.UUID(cart.GetID()), ValidForHours: 48})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Q-20240102-000001", result.Number)
+	assert.Equal(t, "pending", result.Status)
+	assert.Equal(t, application.PriceDto(testutil.USD("120.00")), result.Total)
+	assert.Equal(t, clock.now, result.IssuedAt)
+	assert.Equal(t, clock.now.Add(48*time.Hour), result.ExpiresAt)
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.QuoteIssued{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnExpiredQuote_WhenAcceptQuote_ThenSaveTheExpiryAndReturnInvalidArgumentError(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 1)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote, _ := domain.IssueQuote(cart, "Q-1", clock.now, time.Hour)
+	quote.ClearDomainEvents()
+	clock.now = clock.now.Add(2 * time.Hour)
+
+	var savedQuote *domain.Quote
+	quoteRepository := &quoteRepositoryMock{
+		findById: func(quoteId domain.QuoteId) (*domain.Quote, error) {
+			return quote, nil
+		},
+		save: func(quote *domain.Quote) error {
+			savedQuote = quote
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewQuoteService(&cartRepositoryMock{}, quoteRepository, eventDispatcher, clock)
+
+	_, err := service.AcceptQuote(application.AcceptQuoteCommand{QuoteId: uuid.UUID(quote.GetID())})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid quote: it expired at 2024-01-02T04:04:05Z", err.Error())
+	}
+	if assert.NotNil(t, savedQuote) {
+		assert.Equal(t, domain.QuoteStatusExpired, savedQuote.GetStatus())
+	}
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.QuoteExpired{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAnAcceptedQuote_WhenDeclineQuote_ThenReturnInvalidArgumentError(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 1)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote, _ := domain.IssueQuote(cart, "Q-1", clock.now, time.Hour)
+	quote.Accept(clock)
+
+	quoteRepository := &quoteRepositoryMock{
+		findById: func(quoteId domain.QuoteId) (*domain.Quote, error) {
+			return quote, nil
+		},
+	}
+	service, _ := application.NewQuoteService(&cartRepositoryMock{}, quoteRepository, &eventDispatcherMock{}, clock)
+
+	_, err := service.DeclineQuote(application.DeclineQuoteCommand{QuoteId: uuid.UUID(quote.GetID())})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid quote: it is already accepted", err.Error())
+	}
+	assert.Equal(t, 1, quoteRepository.callCount)
+}
+
+func Test_GivenAPendingQuotePastItsExpiry_WhenGetQuote_ThenReturnItAsExpiredWithoutSavingIt(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 1)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote, _ := domain.IssueQuote(cart, "Q-1", clock.now, time.Hour)
+	quote.ClearDomainEvents()
+	clock.now = clock.now.Add(time.Hour)
+
+	quoteRepository := &quoteRepositoryMock{
+		findById: func(quoteId domain.QuoteId) (*domain.Quote, error) {
+			return quote, nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewQuoteService(&cartRepositoryMock{}, quoteRepository, eventDispatcher, clock)
+
+	result, err := service.GetQuote(application.GetQuoteQuery{QuoteId: uuid.UUID(quote.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "expired", result.Status)
+	assert.Equal(t, domain.QuoteStatusPending, quote.GetStatus())
+	assert.Empty(t, eventDispatcher.dispatchedEvents)
+	assert.Equal(t, 1, quoteRepository.callCount)
+}
+
+func Test_GivenPendingQuotesPastTheirExpiry_WhenExpireQuotes_ThenSaveThemAsExpired(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 1)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote, _ := domain.IssueQuote(cart, "Q-1", clock.now, time.Hour)
+	quote.ClearDomainEvents()
+	clock.now = clock.now.Add(time.Hour)
+
+	var savedQuotes []*domain.Quote
+	quoteRepository := &quoteRepositoryMock{
+		findExpired: func(now time.Time) ([]*domain.Quote, error) {
+			assert.Equal(t, clock.now, now)
+			return []*domain.Quote{quote}, nil
+		},
+		save: func(quote *domain.Quote) error {
+			savedQuotes = append(savedQuotes, quote)
+			return nil
+		},
+	}
+	eventDispatcher := &eventDispatcherMock{}
+	service, _ := application.NewQuoteService(&cartRepositoryMock{}, quoteRepository, eventDispatcher, clock)
+
+	result, err := service.ExpireQuotes()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{uuid.UUID(quote.GetID())}, result.Expired)
+	if assert.Len(t, savedQuotes, 1) {
+		assert.Equal(t, domain.QuoteStatusExpired, savedQuotes[0].GetStatus())
+	}
+	if assert.Equal(t, 1, len(eventDispatcher.dispatchedEvents)) {
+		assert.IsType(t, domain.QuoteExpired{}, eventDispatcher.dispatchedEvents[0])
+	}
+}
+
+func Test_GivenAQuoteNumberTakenConcurrently_WhenCreateQuote_ThenRetryWithTheNextNumber(t *testing.T) {
+	ericEvans, _ := domain.NewCustomer("Eric Evans")
+	cart, _ := domain.NewCart(ericEvans)
+	book, _ := domain.NewProduct("Domain Driven Design Blue Book", testutil.USD("60.00"))
+	cart.AddItem(book, 2)
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	cartRepository := &cartRepositoryMock{
+		findById: func(cartId domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+	}
+	numbers := []string{"Q-20240102-000001", "Q-20240102-000002"}
+	var savedNumbers []string
+	quoteRepository := &quoteRepositoryMock{
+		nextQuoteNumber: func(issuedAt time.Time) (string, error) {
+			return numbers[len(savedNumbers)], nil
+		},
+		save: func(quote *domain.Quote) error {
+			savedNumbers = append(savedNumbers, quote.GetNumber())
+			if len(savedNumbers) == 1 {
+				return &domain.UniqueConstraintError{Field: "number", Value: quote.GetNumber()}
+			}
+			return nil
+		},
+	}
+	service, _ := application.NewQuoteService(cartRepository, quoteRepository, &eventDispatcherMock{}, clock)
+
+	result, err := service.CreateQuote(application.CreateQuoteCommand{CartId: uuid.UUID(cart.GetID())})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Q-20240102-000002", result.Number)
+	assert.Equal(t, numbers, savedNumbers)
+}
@@ -0,0 +1,337 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenNilDependencies_WhenNewStockService_ThenReturnError(t *testing.T) {
+	tests := []struct {
+		testName          string
+		productRepository domain.ProductRepository
+		stockRepository   domain.StockRepository
+		dispatcher        domain.EventDispatcher
+		expectedError     string
+	}{
+		{"nil product repository", nil, &stockRepositoryMock{}, &eventDispatcherMock{}, "product repository was nil"},
+		{"nil stock repository", &productRepositoryMock{}, nil, &eventDispatcherMock{}, "stock repository was nil"},
+		{"nil event dispatcher", &productRepositoryMock{}, &stockRepositoryMock{}, nil, "event dispatcher was nil"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			service, err := application.NewStockService(tc.productRepository, tc.stockRepository, tc.dispatcher)
+
+			assert.Nil(t, service)
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func Test_GivenAnUntrackedProduct_WhenUpdateStock_ThenCreateTheStockItem(t *testing.T) {
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	var savedStockItem *domain.StockItem
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return nil, errors.New("entity not found")
+		},
+		save: func(stockItem *domain.StockItem) error {
+			savedStockItem = stockItem
+			return nil
+		},
+	}
+	dispatcher := &eventDispatcherMock{}
+	service, _ := application.NewStockService(productRepositoryReturning(product), stockRepository, dispatcher)
+
+	result, err := service.UpdateStock(application.UpdateStockCommand{ProductId: uuid.UUID(product.GetID()), OnHand: intRef(7)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, application.StockDto{ProductId: uuid.UUID(product.GetID()), OnHand: 7, Available: 7}, result)
+	if assert.NotNil(t, savedStockItem) {
+		assert.Equal(t, product.GetID(), savedStockItem.GetID())
+	}
+	assert.Equal(t, []domain.DomainEvent{domain.StockRestocked{ProductId: product.GetID(), OnHand: 7}}, dispatcher.dispatchedEvents)
+}
+
+func Test_GivenReservedStock_WhenUpdateStockBelowTheReservedQuantity_ThenReturnInvalidArgumentError(t *testing.T) {
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	stockItem, _ := domain.NewStockItem(product.GetID(), 5)
+	stockItem.Reserve(domain.CartId(uuid.New()), 4)
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return stockItem, nil
+		},
+	}
+	service, _ := application.NewStockService(productRepositoryReturning(product), stockRepository, &eventDispatcherMock{})
+
+	result, err := service.UpdateStock(application.UpdateStockCommand{ProductId: uuid.UUID(product.GetID()), OnHand: intRef(3)})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InvalidArgumentError{}, err)
+		assert.Equal(t, "invalid on_hand: it is below the 4 units reserved in carts", err.Error())
+	}
+	assert.Equal(t, 1, stockRepository.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenUpdateStock_ThenReturnNotFoundError(t *testing.T) {
+	productId := uuid.New()
+	productRepository := &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return nil, errors.New("entity not found")
+		},
+	}
+	stockRepository := &stockRepositoryMock{}
+	service, _ := application.NewStockService(productRepository, stockRepository, &eventDispatcherMock{})
+
+	_, err := service.UpdateStock(application.UpdateStockCommand{ProductId: productId, OnHand: intRef(1)})
+
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.NotFoundError{}, err)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", productId.String()), err.Error())
+	}
+	assert.Equal(t, 0, stockRepository.callCount)
+}
+
+func Test_GivenACheckedOutCartWithReservations_WhenCommitCartReservations_ThenTheStockIsCommitted(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	stockItem.Reserve(cartId, 2)
+	stockRepository := &stockRepositoryMock{
+		getCartReservations: func(domain.CartId) ([]*domain.StockItem, error) {
+			return []*domain.StockItem{stockItem}, nil
+		},
+		save: func(*domain.StockItem) error {
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(&productRepositoryMock{}, stockRepository, &eventDispatcherMock{})
+
+	err := service.CommitCartReservations(domain.CartCheckedOut{CartId: cartId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stockItem.GetOnHand())
+	assert.Equal(t, 0, stockItem.GetReserved())
+}
+
+func Test_GivenAStockItemModifiedConcurrently_WhenCommitCartReservations_ThenReloadItAndRetry(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	staleItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	staleItem.Reserve(cartId, 2)
+	currentItem := staleItem.Clone()
+	currentItem.Restock(8)
+	var savedItem *domain.StockItem
+	stockRepository := &stockRepositoryMock{
+		getCartReservations: func(domain.CartId) ([]*domain.StockItem, error) {
+			return []*domain.StockItem{staleItem}, nil
+		},
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return currentItem, nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			if stockItem == staleItem {
+				return &domain.ConcurrencyConflictError{Id: stockItem.GetID().String(), ExpectedVersion: 0, ActualVersion: 1}
+			}
+			savedItem = stockItem
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(&productRepositoryMock{}, stockRepository, &eventDispatcherMock{})
+
+	err := service.CommitCartReservations(domain.CartCheckedOut{CartId: cartId})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, savedItem) {
+		assert.Equal(t, 6, savedItem.GetOnHand())
+		assert.Equal(t, 0, savedItem.GetReserved())
+	}
+}
+
+func Test_GivenAStockItemThatFailsToSave_WhenCommitCartReservations_ThenCommitTheOtherItemsAndReturnTheError(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	failingItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	failingItem.Reserve(cartId, 2)
+	committedItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	committedItem.Reserve(cartId, 1)
+	stockRepository := &stockRepositoryMock{
+		getCartReservations: func(domain.CartId) ([]*domain.StockItem, error) {
+			return []*domain.StockItem{failingItem, committedItem}, nil
+		},
+		save: func(stockItem *domain.StockItem) error {
+			if stockItem == failingItem {
+				return errors.New("disk full")
+			}
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(&productRepositoryMock{}, stockRepository, &eventDispatcherMock{})
+
+	err := service.CommitCartReservations(domain.CartCheckedOut{CartId: cartId})
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "failed to update the stock reserved for product "+failingItem.GetID().String()+": disk full", err.Error())
+	}
+	assert.Equal(t, 4, committedItem.GetOnHand())
+	assert.Equal(t, 0, committedItem.GetReserved())
+}
+
+func Test_GivenAnExpiredCartWithReservations_WhenReleaseCartReservations_ThenTheStockIsAvailableAgain(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	stockItem.Reserve(cartId, 2)
+	stockRepository := &stockRepositoryMock{
+		getCartReservations: func(domain.CartId) ([]*domain.StockItem, error) {
+			return []*domain.StockItem{stockItem}, nil
+		},
+		save: func(*domain.StockItem) error {
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(&productRepositoryMock{}, stockRepository, &eventDispatcherMock{})
+
+	err := service.ReleaseCartReservations(domain.CartExpired{CartId: cartId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stockItem.GetOnHand())
+	assert.Equal(t, 5, stockItem.GetAvailable())
+}
+
+func Test_GivenATrackedProductWithoutEnoughStock_WhenAddItemToCart_ThenReturnInsufficientStockError(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	cart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	stockItem, _ := domain.NewStockItem(product.GetID(), 3)
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return stockItem, nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepositoryReturning(product), &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+
+	result, err := service.AddItemToCart(application.AddItemToCartCommand{
+		CartId:    uuid.UUID(cart.GetID()),
+		ProductId: uuid.UUID(product.GetID()),
+		Quantity:  4,
+	})
+
+	assert.Empty(t, result)
+	if assert.Error(t, err) {
+		assert.IsType(t, &application.InsufficientStockError{}, err)
+		assert.Equal(t, fmt.Sprintf("insufficient stock for product %s: requested 4, available 3", product.GetID().String()), err.Error())
+	}
+	assert.Equal(t, 0, cart.Size())
+	assert.Equal(t, 0, stockItem.GetReserved())
+}
+
+func Test_GivenATrackedProduct_WhenAddingUpdatingAndRemovingItems_ThenTheReservationFollowsTheCart(t *testing.T) {
+	customer, _ := domain.NewCustomer("Vaughn Vernon")
+	cart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	stockItem, _ := domain.NewStockItem(product.GetID(), 10)
+	cartRepository := &cartRepositoryMock{
+		findById: func(domain.CartId) (*domain.Cart, error) {
+			return cart, nil
+		},
+		save: func(*domain.Cart) error {
+			return nil
+		},
+	}
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return stockItem, nil
+		},
+		save: func(*domain.StockItem) error {
+			return nil
+		},
+	}
+	service, _ := application.NewCartService(cartRepository, &customerRepositoryMock{}, productRepositoryReturning(product), &eventDispatcherMock{}, &exchangeRateProviderMock{}, application.WithStockReservations(stockRepository))
+	cartId := uuid.UUID(cart.GetID())
+	productId := uuid.UUID(product.GetID())
+
+	_, err := service.AddItemToCart(application.AddItemToCartCommand{CartId: cartId, ProductId: productId, Quantity: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, stockItem.GetReservedFor(cart.GetID()))
+
+	_, err = service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, stockItem.GetReservedFor(cart.GetID()))
+
+	_, err = service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 11})
+	assert.IsType(t, &application.InsufficientStockError{}, err)
+	assert.Equal(t, 6, stockItem.GetReservedFor(cart.GetID()))
+
+	_, err = service.UpdateItemQuantity(application.UpdateItemQuantityCommand{CartId: cartId, ProductId: productId, Quantity: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stockItem.GetReservedFor(cart.GetID()))
+
+	_, err = service.RemoveItemFromCart(application.RemoveItemFromCartCommand{CartId: cartId, ProductId: productId})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stockItem.GetReservedFor(cart.GetID()))
+	assert.Equal(t, 10, stockItem.GetAvailable())
+}
+
+func productRepositoryReturning(product *domain.Product) *productRepositoryMock {
+	return &productRepositoryMock{
+		findByID: func(domain.ProductId) (*domain.Product, error) {
+			return product, nil
+		},
+	}
+}
+
+func intRef(value int) *int {
+	return &value
+}
+
+func Test_GivenAStaleExpectedVersion_WhenUpdateStock_ThenReturnPreconditionFailedErrorWithoutSaving(t *testing.T) {
+	product, _ := domain.NewProduct("Implementing Domain Driven Design Book", testutil.USD("50.00"))
+	stockItem, _ := domain.NewStockItem(product.GetID(), 5)
+	stockItem.SetVersion(2)
+	stockRepository := &stockRepositoryMock{
+		findById: func(domain.ProductId) (*domain.StockItem, error) {
+			return stockItem, nil
+		},
+		save: func(*domain.StockItem) error {
+			t.Fatal("a stale stock item must not be saved")
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(productRepositoryReturning(product), stockRepository, &eventDispatcherMock{})
+
+	_, err := service.UpdateStock(application.UpdateStockCommand{ProductId: uuid.UUID(product.GetID()), OnHand: intRef(7), ExpectedVersion: intRef(1)})
+
+	assert.Equal(t, application.NewPreconditionFailedError(product.GetID().String(), "stock", 1, 2), err)
+	assert.Equal(t, 5, stockItem.GetOnHand())
+}
+
+func Test_GivenAnAbandonedCartWithReservations_WhenReleaseAbandonedCartReservations_ThenTheStockIsAvailableAgain(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	stockItem.Reserve(cartId, 2)
+	stockRepository := &stockRepositoryMock{
+		getCartReservations: func(domain.CartId) ([]*domain.StockItem, error) {
+			return []*domain.StockItem{stockItem}, nil
+		},
+		save: func(*domain.StockItem) error {
+			return nil
+		},
+	}
+	service, _ := application.NewStockService(&productRepositoryMock{}, stockRepository, &eventDispatcherMock{})
+
+	err := service.ReleaseAbandonedCartReservations(domain.CartAbandoned{CartId: cartId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stockItem.GetReserved())
+	assert.Equal(t, 5, stockItem.GetAvailable())
+}
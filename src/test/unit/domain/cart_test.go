@@ -1,9 +1,13 @@
 package test
 
 import (
+	"math"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,7 +58,7 @@ func Test_GivenAValidProduct_WhenAddProductToCart_ThenReturnAnItem(t *testing.T)
 	cart, _ := domain.NewCart(cartCustomer)
 	cart.ClearDomainEvents()
 
-	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", 8.00)
+	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
 
 	cartItem, err := cart.AddItem(productToAdd, 1)
 
@@ -71,7 +75,7 @@ func Test_GivenAnInvalidQuantity_WhenAddProductToCart_ThenReturnError(t *testing
 	cart, _ := domain.NewCart(cartCustomer)
 	cart.ClearDomainEvents()
 
-	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", 8.00)
+	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
 
 	cartItem, err := cart.AddItem(productToAdd, 0)
 
@@ -85,7 +89,7 @@ func Test_GivenAnInvalidQuantity_WhenAddProductToCart_ThenReturnError(t *testing
 func Test_GivenACartWithAnItem_WhenAddTheSameItemToCart_ThenUpdateQuantity(t *testing.T) {
 	cartCustomer, _ := domain.NewCustomer("John Mayer")
 	cart, _ := domain.NewCart(cartCustomer)
-	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", 8.00)
+	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
 	cart.AddItem(productToAdd, 1)
 	cart.ClearDomainEvents()
 
@@ -106,14 +110,14 @@ func Test_GivenAnEmptyCart_WhenGetTotal_ThenReturnZero(t *testing.T) {
 
 	price := cart.GetTotal()
 
-	assert.Equal(t, float64(0), price)
+	assert.Equal(t, testutil.USD("0.00"), price)
 	assert.Empty(t, cart.GetDomainEvents())
 }
 
 func Test_GivenANonEmptyCart_WhenGetTotal_ThenReturnCorrectTotal(t *testing.T) {
 	cartCustomer, _ := domain.NewCustomer("John Mayer")
-	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", 8.10)
-	anotherProductToAdd, _ := domain.NewProduct("Pepsi 2.5Lt", 12.00)
+	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	anotherProductToAdd, _ := domain.NewProduct("Pepsi 2.5Lt", testutil.USD("12.00"))
 
 	cart, _ := domain.NewCart(cartCustomer)
 	cart.AddItem(productToAdd, 1)
@@ -123,7 +127,7 @@ func Test_GivenANonEmptyCart_WhenGetTotal_ThenReturnCorrectTotal(t *testing.T) {
 
 	total := cart.GetTotal()
 
-	assert.Equal(t, 48.30, total)
+	assert.Equal(t, testutil.USD("48.30"), total)
 	assert.Empty(t, cart.GetDomainEvents())
 }
 
@@ -144,7 +148,7 @@ func Test_GivenACart_WhenEqualsToAnotherCart_ThenReturnsFalse(t *testing.T) {
 
 func Test_GivenAnItem_WhenEqualsToItself_ThenReturnsTrue(t *testing.T) {
 	cartCustomer, _ := domain.NewCustomer("John Mayer")
-	product, _ := domain.NewProduct("Pepsi Ligh", 10.00)
+	product, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
 	cart, _ := domain.NewCart(cartCustomer)
 
 	item, _ := cart.AddItem(product, 1)
@@ -154,7 +158,7 @@ func Test_GivenAnItem_WhenEqualsToItself_ThenReturnsTrue(t *testing.T) {
 
 func Test_GivenAnItem_WhenEqualsToADiferentItem_ThenReturnsFalse(t *testing.T) {
 	cartCustomer, _ := domain.NewCustomer("John Mayer")
-	product, _ := domain.NewProduct("Pepsi Ligh", 10.00)
+	product, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
 	cart, _ := domain.NewCart(cartCustomer)
 
 	item, _ := cart.AddItem(product, 1)
@@ -165,7 +169,7 @@ func Test_GivenAnItem_WhenEqualsToADiferentItem_ThenReturnsFalse(t *testing.T) {
 
 func Test_GivenAnItem_WhenEqualsToAnItemWithTheSameValues_ThenReturnsTrue(t *testing.T) {
 	cartCustomer, _ := domain.NewCustomer("John Mayer")
-	product, _ := domain.NewProduct("Pepsi Ligh", 10.00)
+	product, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
 	cart, _ := domain.NewCart(cartCustomer)
 	cart2, _ := domain.NewCart(cartCustomer)
 
@@ -174,3 +178,388 @@ func Test_GivenAnItem_WhenEqualsToAnItemWithTheSameValues_ThenReturnsTrue(t *tes
 
 	assert.True(t, item.EqualsTo(item2))
 }
+
+func Test_GivenACartWithAnItem_WhenRemoveItem_ThenTheItemIsRemoved(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	productToRemove, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	productToKeep, _ := domain.NewProduct("Pepsi 2.5Lt", testutil.USD("12.00"))
+	cart.AddItem(productToRemove, 2)
+	cart.AddItem(productToKeep, 1)
+	cart.ClearDomainEvents()
+
+	err := cart.RemoveItem(productToRemove.GetID())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cart.Size())
+	assert.Equal(t, testutil.USD("12.00"), cart.GetTotal())
+	if assert.Equal(t, 1, len(cart.GetDomainEvents())) {
+		assert.Equal(t, domain.ItemRemovedFromCart{
+			CartId:    cart.GetID(),
+			ProductId: productToRemove.GetID(),
+		}, cart.GetDomainEvents()[0])
+	}
+}
+
+func Test_GivenAProductThatIsNotInTheCart_WhenRemoveItem_ThenReturnError(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.ClearDomainEvents()
+
+	err := cart.RemoveItem(product.GetID())
+
+	assert.ErrorIs(t, err, domain.ErrItemNotFound)
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenACartWithAnItem_WhenUpdateItemQuantity_ThenTheQuantityIsReplaced(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 5)
+	cart.ClearDomainEvents()
+
+	cartItem, err := cart.UpdateItemQuantity(product.GetID(), 2)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, cartItem.GetQuantity())
+	assert.Equal(t, 2, cart.Size())
+	assert.Equal(t, testutil.USD("16.00"), cart.GetTotal())
+	if assert.Equal(t, 1, len(cart.GetDomainEvents())) {
+		assert.Equal(t, domain.ItemQuantityChanged{
+			CartId:           cart.GetID(),
+			ProductId:        product.GetID(),
+			PreviousQuantity: 5,
+			Quantity:         2,
+		}, cart.GetDomainEvents()[0])
+	}
+}
+
+func Test_GivenTheSameQuantity_WhenUpdateItemQuantity_ThenNoEventIsRecorded(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 3)
+	cart.ClearDomainEvents()
+
+	cartItem, err := cart.UpdateItemQuantity(product.GetID(), 3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, cartItem.GetQuantity())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenAnInvalidQuantity_WhenUpdateItemQuantity_ThenReturnError(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 3)
+	cart.ClearDomainEvents()
+
+	cartItem, err := cart.UpdateItemQuantity(product.GetID(), 0)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid quantity", err.Error())
+	}
+	assert.Empty(t, cartItem)
+	assert.Equal(t, 3, cart.Size())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenAProductThatIsNotInTheCart_WhenUpdateItemQuantity_ThenReturnError(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+
+	cartItem, err := cart.UpdateItemQuantity(product.GetID(), 1)
+
+	assert.ErrorIs(t, err, domain.ErrItemNotFound)
+	assert.Empty(t, cartItem)
+}
+
+func Test_GivenANonEmptyCart_WhenClear_ThenTheCartIsEmpty(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 3)
+	cart.ClearDomainEvents()
+
+	cart.Clear()
+
+	assert.Equal(t, 0, cart.Size())
+	assert.Empty(t, cart.GetItems())
+	if assert.Equal(t, 1, len(cart.GetDomainEvents())) {
+		assert.Equal(t, domain.CartCleared{CartId: cart.GetID()}, cart.GetDomainEvents()[0])
+	}
+}
+
+func Test_GivenAnEmptyCart_WhenClear_ThenNoEventIsRecorded(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	cart.ClearDomainEvents()
+
+	cart.Clear()
+
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenAProductInAnotherCurrencyAndNoExchangeRates_WhenAddItem_ThenReturnCurrencyNotConvertible(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	euroPrice, _ := domain.ParseMoney("8.00", "EUR")
+	productToAdd, _ := domain.NewProduct("Arroz Blanco Gallo", euroPrice)
+	cart, _ := domain.NewCart(cartCustomer)
+	cart.ClearDomainEvents()
+
+	_, err := cart.AddItem(productToAdd, 1)
+
+	assert.ErrorIs(t, err, domain.ErrCurrencyNotConvertible)
+	assert.Equal(t, 0, cart.Size())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenACartWithADisplayCurrency_WhenAddItemWithExchangeRates_ThenTheTotalIsConverted(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	euroPrice, _ := domain.ParseMoney("10.00", "EUR")
+	yenPrice, _ := domain.ParseMoney("1000", "JPY")
+	euroProduct, _ := domain.NewProduct("Arroz Blanco Gallo", euroPrice)
+	yenProduct, _ := domain.NewProduct("Matcha Uji 100g", yenPrice)
+	dollarProduct, _ := domain.NewProduct("Pepsi Light 2.5Lt", testutil.USD("1.10"))
+	exchangeRates := &exchangeRatesStub{rates: map[string]*big.Rat{
+		"EUR/USD": big.NewRat(108, 100),
+		"JPY/USD": big.NewRat(67, 10000),
+	}}
+	cart, _ := domain.NewCart(cartCustomer, domain.WithDisplayCurrency("USD"))
+
+	_, err := cart.AddItemWithExchangeRates(euroProduct, 2, exchangeRates)
+	assert.NoError(t, err)
+	_, err = cart.AddItemWithExchangeRates(yenProduct, 1, exchangeRates)
+	assert.NoError(t, err)
+	_, err = cart.AddItemWithExchangeRates(dollarProduct, 1, exchangeRates)
+	assert.NoError(t, err)
+
+	assert.Equal(t, testutil.USD("29.40"), cart.GetTotal())
+}
+
+func Test_GivenAnItemAlreadyInTheCart_WhenTheExchangeRateChanges_ThenTheSnapshottedRateIsKept(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	euroPrice, _ := domain.ParseMoney("10.00", "EUR")
+	euroProduct, _ := domain.NewProduct("Arroz Blanco Gallo", euroPrice)
+	exchangeRates := &exchangeRatesStub{rates: map[string]*big.Rat{"EUR/USD": big.NewRat(108, 100)}}
+	cart, _ := domain.NewCart(cartCustomer)
+	cart.AddItemWithExchangeRates(euroProduct, 1, exchangeRates)
+
+	exchangeRates.rates["EUR/USD"] = big.NewRat(2, 1)
+	cart.AddItemWithExchangeRates(euroProduct, 1, exchangeRates)
+
+	assert.Equal(t, testutil.USD("21.60"), cart.GetTotal())
+	assert.Equal(t, big.NewRat(108, 100), cart.GetItems()[0].GetExchangeRate())
+}
+
+func Test_GivenAnInvalidDisplayCurrency_WhenNewCart_ThenReturnError(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+
+	cart, err := domain.NewCart(cartCustomer, domain.WithDisplayCurrency("EURO"))
+
+	assert.Nil(t, cart)
+	assert.EqualError(t, err, "invalid currency")
+}
+
+func Test_GivenANewCart_WhenNewCart_ThenTheCartIsActiveWithTheClockTimeAsLastActivity(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	cart, _ := domain.NewCart(cartCustomer, domain.WithCartClock(clock))
+
+	assert.Equal(t, domain.CartStatusActive, cart.GetStatus())
+	assert.True(t, cart.IsActive())
+	assert.Equal(t, clock.now, cart.GetLastActivityAt())
+}
+
+func Test_GivenAnActiveCart_WhenMutatingTheCart_ThenTheLastActivityIsUpdated(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("9.80"))
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cart, _ := domain.NewCart(cartCustomer, domain.WithCartClock(clock))
+
+	clock.now = clock.now.Add(time.Minute)
+	cart.AddItem(product, 1)
+	assert.Equal(t, clock.now, cart.GetLastActivityAt())
+
+	clock.now = clock.now.Add(time.Minute)
+	cart.UpdateItemQuantity(product.GetID(), 3)
+	assert.Equal(t, clock.now, cart.GetLastActivityAt())
+
+	clock.now = clock.now.Add(time.Minute)
+	cart.RemoveItem(product.GetID())
+	assert.Equal(t, clock.now, cart.GetLastActivityAt())
+}
+
+func Test_GivenAnActiveCart_WhenAbandon_ThenTheCartIsAbandonedAndCanNoLongerBeModified(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("9.80"))
+	cart, _ := domain.NewCart(cartCustomer)
+	cart.AddItem(product, 1)
+	cart.ClearDomainEvents()
+
+	err := cart.Abandon()
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CartStatusAbandoned, cart.GetStatus())
+	if assert.Len(t, cart.GetDomainEvents(), 1) {
+		assert.IsType(t, domain.CartAbandoned{}, cart.GetDomainEvents()[0])
+	}
+	_, err = cart.AddItem(product, 1)
+	assert.ErrorIs(t, err, domain.ErrCartNotActive)
+	assert.ErrorIs(t, cart.Checkout(), domain.ErrCartNotActive)
+}
+
+func Test_GivenAnAbandonedCart_WhenReactivate_ThenTheCartIsActiveAgain(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cart, _ := domain.NewCart(cartCustomer, domain.WithCartClock(clock))
+	cart.Abandon()
+
+	clock.now = clock.now.Add(time.Hour)
+	err := cart.Reactivate()
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CartStatusActive, cart.GetStatus())
+	assert.Equal(t, clock.now, cart.GetLastActivityAt())
+}
+
+func Test_GivenACartInAnyStatus_WhenTransitioning_ThenOnlyAllowedTransitionsSucceed(t *testing.T) {
+	tests := []struct {
+		testName   string
+		prepare    func(*domain.Cart)
+		transition func(*domain.Cart) error
+		expected   domain.CartStatus
+		err        error
+	}{
+		{"active to expired", func(c *domain.Cart) {}, (*domain.Cart).Expire, domain.CartStatusExpired, nil},
+		{"abandoned to expired", func(c *domain.Cart) { c.Abandon() }, (*domain.Cart).Expire, domain.CartStatusExpired, nil},
+		{"active to active", func(c *domain.Cart) {}, (*domain.Cart).Reactivate, domain.CartStatusActive, domain.ErrInvalidCartTransition},
+		{"abandoned to abandoned", func(c *domain.Cart) { c.Abandon() }, (*domain.Cart).Abandon, domain.CartStatusAbandoned, domain.ErrInvalidCartTransition},
+		{"expired to active", func(c *domain.Cart) { c.Expire() }, (*domain.Cart).Reactivate, domain.CartStatusExpired, domain.ErrInvalidCartTransition},
+		{"checked out to abandoned", func(c *domain.Cart) { c.Checkout() }, (*domain.Cart).Abandon, domain.CartStatusCheckedOut, domain.ErrInvalidCartTransition},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			cartCustomer, _ := domain.NewCustomer("John Mayer")
+			product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("9.80"))
+			cart, _ := domain.NewCart(cartCustomer)
+			cart.AddItem(product, 1)
+			tc.prepare(cart)
+
+			err := tc.transition(cart)
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, cart.GetStatus())
+		})
+	}
+}
+
+type exchangeRatesStub struct {
+	rates map[string]*big.Rat
+}
+
+func (s *exchangeRatesStub) GetRate(from domain.Currency, to domain.Currency) (*big.Rat, error) {
+	if rate, found := s.rates[string(from)+"/"+string(to)]; found {
+		return rate, nil
+	}
+
+	return nil, domain.ErrCurrencyNotConvertible
+}
+
+func Test_GivenACartWithAnItem_WhenRepriceItem_ThenTheLineTakesTheCurrentPriceAndRemembersTheAddedOne(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 2)
+	cart.ClearDomainEvents()
+	product.ChangePrice(testutil.USD("9.50"))
+
+	repriced, err := cart.RepriceItem(product, nil)
+
+	assert.Nil(t, err)
+	assert.True(t, repriced)
+	assert.Equal(t, testutil.USD("19.00"), cart.GetTotal())
+	if assert.Equal(t, 1, len(cart.GetItems())) {
+		item := cart.GetItems()[0]
+		assert.Equal(t, testutil.USD("9.50"), item.GetUnitPrice())
+		assert.Equal(t, testutil.USD("8.00"), item.GetAddedUnitPrice())
+		assert.True(t, item.HasPriceChanged())
+	}
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ItemRepriced{CartId: cart.GetID(), ProductId: product.GetID(), PreviousUnitPrice: testutil.USD("8.00"), UnitPrice: testutil.USD("9.50")},
+	}, cart.GetDomainEvents())
+}
+
+func Test_GivenARepricedItem_WhenAddingMoreOfIt_ThenTheLineKeepsTheCurrentPrice(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 1)
+	product.ChangePrice(testutil.USD("9.00"))
+	cart.RepriceItem(product, nil)
+
+	cart.AddItem(product, 1)
+
+	assert.Equal(t, testutil.USD("18.00"), cart.GetTotal())
+	assert.True(t, cart.GetItems()[0].HasPriceChanged())
+}
+
+func Test_GivenAnUnchangedPrice_WhenRepriceItem_ThenNothingChanges(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 1)
+	cart.ClearDomainEvents()
+
+	repriced, err := cart.RepriceItem(product, nil)
+
+	assert.Nil(t, err)
+	assert.False(t, repriced)
+	assert.False(t, cart.GetItems()[0].HasPriceChanged())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenAProductNotInTheCart_WhenRepriceItem_ThenReturnErrItemNotFound(t *testing.T) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+
+	_, err := cart.RepriceItem(product, nil)
+
+	assert.ErrorIs(t, err, domain.ErrItemNotFound)
+}
+
+func Test_GivenACartTotalThatWouldOverflow_WhenAddItemOrSelectShipping_ThenReturnAmountOutOfRangeAndKeepTheCart(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	large := domain.NewMoney(math.MaxInt64/2+1, domain.DefaultCurrency)
+	yacht, _ := domain.NewProduct("Luxury Sailing Yacht", large)
+	jet, _ := domain.NewProduct("Private Jet Charter", large)
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(yacht, 1)
+	cart.ClearDomainEvents()
+
+	_, err := cart.AddItem(jet, 1)
+
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+	assert.Equal(t, 1, cart.Size())
+	assert.Equal(t, large, cart.GetTotal())
+	assert.Empty(t, cart.GetDomainEvents())
+
+	_, err = cart.UpdateItemQuantity(yacht.GetID(), 2)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	courier, _ := domain.NewFlatRateShipping("courier", "Courier", large)
+	assert.ErrorIs(t, cart.SelectShipping(courier), domain.ErrAmountOutOfRange)
+	assert.False(t, cart.HasShipping())
+	assert.Equal(t, large, cart.GetTotal())
+}
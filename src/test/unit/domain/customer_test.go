@@ -54,3 +54,78 @@ func Test_GivenACustomer_WhenEqualsToAnotherCustomer_ThenReturnsFalse(t *testing
 
 	assert.False(t, customer.EqualsTo(customer2))
 }
+
+func Test_GivenContactDetails_WhenNewEmailPhoneNumberAndAddress_ThenTheyAreNormalized(t *testing.T) {
+	email, err := domain.NewEmail("  John.Mayer@Example.com ")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.Email("john.mayer@example.com"), email)
+
+	phone, err := domain.NewPhoneNumber("+54 (11) 4321-8765")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.PhoneNumber("+541143218765"), phone)
+
+	address, err := domain.NewAddress(" Av. Corrientes 1234 ", "Buenos Aires", "C1043", "ar")
+	assert.Nil(t, err)
+	assert.Equal(t, "Av. Corrientes 1234", address.GetStreet())
+	assert.Equal(t, "AR", address.GetCountry())
+}
+
+func Test_GivenInvalidContactDetails_WhenNewEmailPhoneNumberAndAddress_ThenReturnErrors(t *testing.T) {
+	_, err := domain.NewEmail("John Mayer <john@example.com>")
+	assert.EqualError(t, err, "invalid email")
+
+	_, err = domain.NewPhoneNumber("call me maybe")
+	assert.EqualError(t, err, "invalid phone number")
+
+	_, err = domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "ARG")
+	assert.EqualError(t, err, "invalid address")
+}
+
+func Test_GivenACustomer_WhenRenameAndChangeEmail_ThenRaiseEvents(t *testing.T) {
+	email, _ := domain.NewEmail("john@example.com")
+	customer, _ := domain.NewCustomer("John Mayer", domain.WithEmail(email))
+	customer.ClearDomainEvents()
+	newEmail, _ := domain.NewEmail("john.mayer@example.com")
+
+	assert.Nil(t, customer.Rename("John Clayton Mayer"))
+	assert.Nil(t, customer.ChangeEmail(newEmail))
+	assert.Nil(t, customer.ChangeEmail(newEmail))
+
+	assert.Equal(t, "John Clayton Mayer", customer.GetName())
+	assert.Equal(t, newEmail, customer.GetEmail())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.CustomerRenamed{CustomerId: customer.GetID(), PreviousName: "John Mayer", CustomerName: "John Clayton Mayer"},
+		domain.CustomerEmailChanged{CustomerId: customer.GetID(), PreviousEmail: email, Email: newEmail},
+	}, customer.GetDomainEvents())
+}
+
+func Test_GivenACustomer_WhenChangeContactDetails_ThenRaiseASingleEvent(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	customer.ClearDomainEvents()
+	phone, _ := domain.NewPhoneNumber("+5491143218765")
+	address, _ := domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "AR")
+
+	assert.Nil(t, customer.ChangeContactDetails(phone, address, address))
+	assert.Nil(t, customer.ChangeContactDetails(phone, address, address))
+
+	assert.Equal(t, address, customer.GetShippingAddress())
+	assert.Equal(t, address, customer.GetBillingAddress())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.CustomerContactDetailsChanged{CustomerId: customer.GetID(), Phone: phone, ShippingAddress: address, BillingAddress: address},
+	}, customer.GetDomainEvents())
+}
+
+func Test_GivenADeactivatedCustomer_WhenChangingItOrCreatingACart_ThenReturnErrCustomerDeactivated(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	customer.ClearDomainEvents()
+
+	customer.Deactivate()
+	customer.Deactivate()
+
+	assert.False(t, customer.IsActive())
+	assert.Equal(t, []domain.DomainEvent{domain.CustomerDeactivated{CustomerId: customer.GetID()}}, customer.GetDomainEvents())
+	assert.ErrorIs(t, customer.Rename("John Clayton Mayer"), domain.ErrCustomerDeactivated)
+	cart, err := domain.NewCart(customer)
+	assert.Nil(t, cart)
+	assert.ErrorIs(t, err, domain.ErrCustomerDeactivated)
+}
@@ -0,0 +1,217 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func roundTripCart(t *testing.T, cart *domain.Cart) *domain.Cart {
+	data, err := json.Marshal(cart.ToMemento())
+	assert.Nil(t, err)
+
+	var memento domain.CartMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+
+	restored, err := domain.RestoreCart(memento)
+	assert.Nil(t, err)
+
+	return restored
+}
+
+func Test_GivenAProduct_WhenRestoreItsMemento_ThenTheStateIsEqualAndNoEventsAreRaised(t *testing.T) {
+	sku, _ := domain.NewSKU("foam-pillow")
+	dimensions, _ := domain.NewDimensions(400, 300, 200)
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"), domain.WithSKU(sku), domain.WithTaxCategory("reduced"), domain.WithWeight(300), domain.WithDimensions(dimensions))
+	pillow.Archive()
+	pillow.SetVersion(7)
+
+	data, err := json.Marshal(pillow.ToMemento())
+	assert.Nil(t, err)
+	var memento domain.ProductMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+	restored, err := domain.RestoreProduct(memento)
+
+	assert.Nil(t, err)
+	assert.True(t, pillow.EqualsTo(restored))
+	assert.Equal(t, pillow.ToMemento(), restored.ToMemento())
+	assert.True(t, restored.IsArchived())
+	assert.Equal(t, 7, restored.GetVersion())
+	assert.Empty(t, restored.GetDomainEvents())
+}
+
+func Test_GivenACustomer_WhenRestoreItsMemento_ThenTheStateIsEqualAndNoEventsAreRaised(t *testing.T) {
+	email, _ := domain.NewEmail("john@mayer.com")
+	phone, _ := domain.NewPhoneNumber("+54 11 5555-1234")
+	shipping, _ := domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "AR")
+	customer := mustNewCustomer(t, domain.WithEmail(email), domain.WithPhoneNumber(phone), domain.WithShippingAddress(shipping))
+	customer.Deactivate()
+
+	data, err := json.Marshal(customer.ToMemento())
+	assert.Nil(t, err)
+	var memento domain.CustomerMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+	restored, err := domain.RestoreCustomer(memento)
+
+	assert.Nil(t, err)
+	assert.Equal(t, customer.ToMemento(), restored.ToMemento())
+	assert.Equal(t, domain.Address{}, restored.GetBillingAddress())
+	assert.False(t, restored.IsActive())
+	assert.Empty(t, restored.GetDomainEvents())
+}
+
+func Test_GivenACartWithDiscountsShippingAndTaxes_WhenRestoreItsMemento_ThenTheTotalsAreEqual(t *testing.T) {
+	cart, coffee, _ := newPromotionCart(t)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+	cart.ApplyCoupon(coupon)
+	cart.ApplyPromotions([]domain.Promotion{tenPercentOff})
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+	cart.ApplyTaxes("US", newTaxCalculator(t, domain.RoundTaxPerLine, domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)}))
+
+	restored := roundTripCart(t, cart)
+
+	assert.Equal(t, cart.ToMemento(), restored.ToMemento())
+	assert.Equal(t, testutil.USD("50.00"), restored.GetSubtotal())
+	assert.Equal(t, testutil.USD("9.45"), restored.GetTax())
+	assert.Equal(t, testutil.USD("59.45"), restored.GetTotal())
+	assert.Equal(t, cart.GetTotal(), restored.GetTotal())
+	assert.Equal(t, []domain.CouponCode{"SAVE10"}, restored.GetCoupons())
+	assert.True(t, restored.HasShipping())
+	assert.True(t, restored.IsTaxed())
+	assert.Equal(t, cart.GetLastActivityAt().UTC(), restored.GetLastActivityAt().UTC())
+	assert.Empty(t, restored.GetDomainEvents())
+
+	restoredItems := map[domain.ProductId]int{}
+	for _, cartItem := range restored.GetItems() {
+		restoredItems[cartItem.GetProductId()] = cartItem.GetQuantity()
+	}
+	assert.Equal(t, 3, restoredItems[coffee.GetID()])
+}
+
+func Test_GivenARestoredCartWithShipping_WhenRebindShipping_ThenTheShippingIsQuotedAgain(t *testing.T) {
+	cart, coffee, _ := newShippingCart(t)
+	courier, _ := domain.NewWeightBasedShipping("courier", "Courier", testutil.USD("5.00"), testutil.USD("2.00"), 0)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(courier)
+	restored := roundTripCart(t, cart)
+
+	restored.UpdateItemQuantity(coffee.GetID(), 4)
+	assert.Equal(t, testutil.USD("11.00"), restored.GetShipping().GetCost())
+
+	assert.EqualError(t, restored.RebindShipping(standard), "invalid shipping method")
+	assert.Nil(t, restored.RebindShipping(courier))
+	assert.Equal(t, testutil.USD("17.00"), restored.GetShipping().GetCost())
+
+	restored.Clear()
+	assert.False(t, restored.HasShipping())
+}
+
+func Test_GivenInvalidMementos_WhenRestore_ThenReturnError(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	memento := cart.ToMemento()
+	memento.Status = "lost"
+	_, err := domain.RestoreCart(memento)
+	assert.EqualError(t, err, "invalid cart status")
+
+	memento = cart.ToMemento()
+	memento.Items[0].Quantity = 0
+	_, err = domain.RestoreCart(memento)
+	assert.EqualError(t, err, "invalid cart item memento")
+
+	memento = cart.ToMemento()
+	memento.LastActivityAt = time.Time{}
+	_, err = domain.RestoreCart(memento)
+	assert.EqualError(t, err, "invalid cart memento")
+
+	memento = cart.ToMemento()
+	memento.Version = -1
+	_, err = domain.RestoreCart(memento)
+	assert.EqualError(t, err, "invalid cart memento")
+
+	_, err = domain.RestoreProduct(domain.ProductMemento{Name: "Memory Foam Pillow", UnitPrice: testutil.USD("30.00")})
+	assert.EqualError(t, err, "invalid product memento")
+
+	customer := mustNewCustomer(t)
+	customerMemento := customer.ToMemento()
+	customerMemento.Email = "not an email"
+	_, err = domain.RestoreCustomer(customerMemento)
+	assert.EqualError(t, err, "invalid email")
+}
+
+func Test_GivenAnOrderPlacedFromAQuote_WhenRestoreItsMemento_ThenTheStateIsEqual(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	quote, _ := domain.IssueQuote(cart, "Q-1", issuedAt, time.Hour)
+	quote.Accept(&fixedClock{now: issuedAt})
+	order, err := domain.PlaceOrderFromQuote(cart, quote, issuedAt.Add(time.Minute))
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(order.ToMemento())
+	assert.Nil(t, err)
+	var memento domain.OrderMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+	restored, err := domain.RestoreOrder(memento)
+
+	assert.Nil(t, err)
+	assert.Equal(t, order.ToMemento(), restored.ToMemento())
+	assert.Equal(t, order.GetTotal(), restored.GetTotal())
+	assert.Equal(t, quote.GetID(), restored.GetQuoteID())
+	assert.True(t, restored.HasShipping())
+	assert.Empty(t, restored.GetDomainEvents())
+}
+
+func Test_GivenAnAcceptedQuote_WhenRestoreItsMemento_ThenTheStateIsEqual(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote := newQuote(t, clock.now, time.Hour)
+	quote.Accept(clock)
+
+	data, err := json.Marshal(quote.ToMemento())
+	assert.Nil(t, err)
+	var memento domain.QuoteMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+	restored, err := domain.RestoreQuote(memento)
+
+	assert.Nil(t, err)
+	assert.Equal(t, quote.ToMemento(), restored.ToMemento())
+	assert.Equal(t, domain.QuoteStatusAccepted, restored.GetStatus())
+	assert.Equal(t, testutil.USD("8.10"), restored.GetTotal())
+	assert.Empty(t, restored.GetDomainEvents())
+
+	memento.Status = "lost"
+	_, err = domain.RestoreQuote(memento)
+	assert.EqualError(t, err, "invalid quote status")
+}
+
+func Test_GivenAStockItemWithReservations_WhenRestoreItsMemento_ThenTheReservationsAreEqual(t *testing.T) {
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	firstCart := domain.CartId(uuid.New())
+	secondCart := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(rice.GetID(), 10)
+	stockItem.Reserve(firstCart, 3)
+	stockItem.Reserve(secondCart, 2)
+
+	data, err := json.Marshal(stockItem.ToMemento())
+	assert.Nil(t, err)
+	var memento domain.StockItemMemento
+	assert.Nil(t, json.Unmarshal(data, &memento))
+	restored, err := domain.RestoreStockItem(memento)
+
+	assert.Nil(t, err)
+	assert.Equal(t, stockItem.ToMemento(), restored.ToMemento())
+	assert.Equal(t, 3, restored.GetReservedFor(firstCart))
+	assert.Equal(t, 5, restored.GetAvailable())
+
+	memento.OnHand = 4
+	_, err = domain.RestoreStockItem(memento)
+	assert.Equal(t, domain.ErrOnHandBelowReserved, err)
+}
@@ -0,0 +1,184 @@
+package test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenADecimalAmount_WhenParseMoney_ThenKeepExactMinorUnits(t *testing.T) {
+	money, err := domain.ParseMoney("0.30", domain.DefaultCurrency)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), money.MinorUnits())
+	assert.Equal(t, "0.30", money.Decimal())
+	assert.Equal(t, "0.30 USD", money.String())
+}
+
+func Test_GivenMoreDecimalsThanTheCurrency_WhenParseMoney_ThenRoundHalfToEven(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency domain.Currency
+		expected string
+	}{
+		{amount: "1.005", currency: "USD", expected: "1.00"},
+		{amount: "1.015", currency: "USD", expected: "1.02"},
+		{amount: "1.0151", currency: "USD", expected: "1.02"},
+		{amount: "-1.005", currency: "USD", expected: "-1.00"},
+		{amount: "2.5", currency: "JPY", expected: "2"},
+		{amount: "3.5", currency: "JPY", expected: "4"},
+		{amount: "1.2345", currency: "BHD", expected: "1.234"},
+	}
+
+	for _, test := range tests {
+		money, err := domain.ParseMoney(test.amount, test.currency)
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, money.Decimal(), test.amount+" "+string(test.currency))
+	}
+}
+
+func Test_GivenAnInvalidAmountOrCurrency_WhenParseMoney_ThenReturnError(t *testing.T) {
+	_, err := domain.ParseMoney("ten", domain.DefaultCurrency)
+	assert.Error(t, err)
+
+	_, err = domain.ParseMoney("10", "DOLLAR")
+	assert.EqualError(t, err, "invalid currency")
+}
+
+func Test_GivenMoneyInTheSameCurrency_WhenAdd_ThenReturnExactSum(t *testing.T) {
+	total := testutil.USD("0.00")
+	for i := 0; i < 10; i++ {
+		total, _ = total.Add(testutil.USD("0.10"))
+	}
+
+	assert.Equal(t, testutil.USD("1.00"), total)
+}
+
+func Test_GivenMoneyInDifferentCurrencies_WhenAddOrCompare_ThenReturnCurrencyMismatch(t *testing.T) {
+	euros, _ := domain.ParseMoney("1.00", "EUR")
+
+	_, err := testutil.USD("1.00").Add(euros)
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = testutil.USD("1.00").Subtract(euros)
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+
+	_, err = testutil.USD("1.00").Compare(euros)
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+}
+
+func Test_GivenMoney_WhenMultiplyByRate_ThenRoundHalfToEven(t *testing.T) {
+	half, err := testutil.USD("0.25").MultiplyByRate(big.NewRat(1, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, testutil.USD("0.12"), half)
+
+	half, err = testutil.USD("0.75").MultiplyByRate(big.NewRat(1, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, testutil.USD("0.38"), half)
+}
+
+func Test_GivenMoney_WhenConvert_ThenAdjustForTheTargetCurrencyExponent(t *testing.T) {
+	yen, _ := domain.ParseMoney("1000", "JPY")
+
+	dollars, err := yen.Convert("USD", big.NewRat(67, 10000))
+	assert.NoError(t, err)
+	assert.Equal(t, testutil.USD("6.70"), dollars)
+
+	converted, err := testutil.USD("6.70").Convert("JPY", big.NewRat(10000, 67))
+	assert.NoError(t, err)
+	assert.Equal(t, yen, converted)
+}
+
+func Test_GivenALargeAmount_WhenMultiplyOrAllocate_ThenReturnAmountOutOfRange(t *testing.T) {
+	large := domain.NewMoney(math.MaxInt64/2+1, domain.DefaultCurrency)
+
+	_, err := large.Multiply(2)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = large.MultiplyByRate(big.NewRat(3, 1))
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = large.Convert("KWD", big.NewRat(1, 1))
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = domain.NewMoney(math.MinInt64, domain.DefaultCurrency).Allocate(1, 1)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+}
+
+func Test_GivenALargeAmount_WhenAddOrSubtract_ThenReturnAmountOutOfRange(t *testing.T) {
+	large := domain.NewMoney(math.MaxInt64/2+2, domain.DefaultCurrency)
+	negative := large.Negate()
+
+	_, err := large.Add(large)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = negative.Add(negative)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = large.Subtract(negative)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	_, err = negative.Subtract(large)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	difference, err := large.Subtract(large)
+	assert.Nil(t, err)
+	assert.True(t, difference.IsZero())
+}
+
+func Test_GivenALargeAmount_WhenAllocateWithLargeRatios_ThenSharesAddUpToTheOriginalAmount(t *testing.T) {
+	amount := domain.NewMoney(math.MaxInt64, domain.DefaultCurrency)
+
+	shares, err := amount.Allocate(math.MaxInt64, math.MaxInt64)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Money{
+		domain.NewMoney(math.MaxInt64/2+1, domain.DefaultCurrency),
+		domain.NewMoney(math.MaxInt64/2, domain.DefaultCurrency),
+	}, shares)
+}
+
+func Test_GivenAnUnboundedAmount_WhenParseMoney_ThenReturnError(t *testing.T) {
+	_, err := domain.ParseMoney("1e1000000000", domain.DefaultCurrency)
+	assert.EqualError(t, err, `invalid amount "1e1000000000"`)
+
+	_, err = domain.ParseMoney(strings.Repeat("9", 65), domain.DefaultCurrency)
+	assert.EqualError(t, err, "amount is too long")
+
+	_, err = domain.ParseMoney("1e30", domain.DefaultCurrency)
+	assert.ErrorIs(t, err, domain.ErrAmountOutOfRange)
+
+	money, err := domain.ParseMoney("1.5e2", domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, testutil.USD("150.00"), money)
+}
+
+func Test_GivenMoney_WhenAllocate_ThenSharesAddUpToTheOriginalAmount(t *testing.T) {
+	shares, err := testutil.USD("10.00").Allocate(1, 1, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Money{testutil.USD("3.34"), testutil.USD("3.33"), testutil.USD("3.33")}, shares)
+}
+
+func Test_GivenNoRatios_WhenAllocate_ThenReturnError(t *testing.T) {
+	_, err := testutil.USD("10.00").Allocate()
+
+	assert.EqualError(t, err, "invalid ratio")
+}
+
+func Test_GivenMoney_WhenMarshalAndUnmarshalJSON_ThenRoundTrip(t *testing.T) {
+	data, err := json.Marshal(testutil.USD("10.10"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"amount":"10.10","currency":"USD"}`, string(data))
+
+	var decoded domain.Money
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, testutil.USD("10.10").EqualsTo(decoded))
+}
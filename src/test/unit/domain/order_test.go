@@ -0,0 +1,230 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenACartWithItems_WhenPlaceOrder_ThenTheOrderSnapshotsTheCart(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 3)
+	cart.ClearDomainEvents()
+	placedOn := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	order, err := domain.PlaceOrder(cart, placedOn)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, order) {
+		assert.Equal(t, cart.GetID(), order.GetCartID())
+		assert.Equal(t, customer.GetID(), order.GetCustomerID())
+		assert.Equal(t, testutil.USD("24.30"), order.GetTotal())
+		assert.Equal(t, placedOn, order.GetPlacedOn())
+		if assert.Equal(t, 1, len(order.GetLines())) {
+			assert.Equal(t, rice.GetID(), order.GetLines()[0].GetProductId())
+			assert.Equal(t, testutil.USD("8.10"), order.GetLines()[0].GetUnitPrice())
+			assert.Equal(t, 3, order.GetLines()[0].GetQuantity())
+			assert.Equal(t, testutil.USD("24.30"), order.GetLines()[0].GetTotal())
+		}
+		assert.Empty(t, order.GetDomainEvents())
+	}
+	assert.True(t, cart.IsCheckedOut())
+	if assert.Equal(t, 2, len(cart.GetDomainEvents())) {
+		assert.Equal(t, domain.CartCheckedOut{CartId: cart.GetID(), CustomerId: customer.GetID(), Total: testutil.USD("24.30")}, cart.GetDomainEvents()[0])
+		assert.Equal(t, domain.OrderPlaced{OrderId: order.GetID(), CartId: cart.GetID(), CustomerId: customer.GetID(), Total: testutil.USD("24.30"), PlacedOn: placedOn}, cart.GetDomainEvents()[1])
+	}
+}
+
+func Test_GivenACartWithPromotions_WhenPlaceOrder_ThenTheOrderSnapshotsTheDiscounts(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 3)
+	threeForTwo, _ := domain.NewBuyXGetY("rice 3x2", rice.GetID(), 2, 1)
+	fiveOff, _ := domain.NewFixedAmountOff("FIVE-OFF", testutil.USD("5.00"))
+	cart.ApplyPromotions([]domain.Promotion{threeForTwo, fiveOff})
+
+	order, err := domain.PlaceOrder(cart, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, order) {
+		assert.Equal(t, testutil.USD("24.30"), order.GetSubtotal())
+		assert.Equal(t, []domain.Adjustment{domain.NewLineAdjustment("rice 3x2", rice.GetID(), testutil.USD("8.10"))}, order.GetLineAdjustments())
+		assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("FIVE-OFF", testutil.USD("5.00"))}, order.GetCartAdjustments())
+		assert.Equal(t, testutil.USD("11.20"), order.GetTotal())
+	}
+}
+
+func Test_GivenATaxedCart_WhenPlaceOrder_ThenTheOrderSnapshotsTheTaxLines(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 3)
+	cart.ApplyTaxes("US", newTaxCalculator(t, domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)},
+	))
+
+	order, err := domain.PlaceOrder(cart, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, order) {
+		assert.True(t, order.IsTaxed())
+		assert.Equal(t, []domain.LineTax{domain.NewLineTax(rice.GetID(), big.NewRat(21, 100), false, testutil.USD("5.10"))}, order.GetLineTaxes())
+		assert.Equal(t, testutil.USD("5.10"), order.GetTax())
+		assert.Equal(t, testutil.USD("29.40"), order.GetTotal())
+	}
+}
+
+func Test_GivenACartWithShipping_WhenPlaceOrder_ThenTheOrderSnapshotsTheShippingQuote(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+
+	order, err := domain.PlaceOrder(cart, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, order) {
+		assert.True(t, order.HasShipping())
+		assert.Equal(t, domain.NewShippingQuote("standard", "Standard", testutil.USD("5.00")), order.GetShipping())
+		assert.Equal(t, testutil.USD("50.00"), order.GetSubtotal())
+		assert.Equal(t, testutil.USD("55.00"), order.GetTotal())
+	}
+}
+
+func Test_GivenAnEmptyCart_WhenPlaceOrder_ThenReturnError(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+
+	order, err := domain.PlaceOrder(cart, time.Now())
+
+	assert.Nil(t, order)
+	assert.ErrorIs(t, err, domain.ErrCartEmpty)
+	assert.False(t, cart.IsCheckedOut())
+}
+
+func Test_GivenACheckedOutCart_WhenMutated_ThenReturnCartCheckedOut(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 1)
+	domain.PlaceOrder(cart, time.Now())
+	cart.ClearDomainEvents()
+
+	_, addErr := cart.AddItem(rice, 1)
+	_, updateErr := cart.UpdateItemQuantity(rice.GetID(), 2)
+	removeErr := cart.RemoveItem(rice.GetID())
+	clearErr := cart.Clear()
+	_, placeErr := domain.PlaceOrder(cart, time.Now())
+
+	assert.ErrorIs(t, addErr, domain.ErrCartCheckedOut)
+	assert.ErrorIs(t, updateErr, domain.ErrCartCheckedOut)
+	assert.ErrorIs(t, removeErr, domain.ErrCartCheckedOut)
+	assert.ErrorIs(t, clearErr, domain.ErrCartCheckedOut)
+	assert.ErrorIs(t, placeErr, domain.ErrCartCheckedOut)
+	assert.Equal(t, 1, cart.Size())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenAnAcceptedQuote_WhenPlaceOrderFromQuote_ThenTheOrderKeepsTheQuotedPrices(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 2)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	cart.ApplyPromotions([]domain.Promotion{tenPercentOff})
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote, _ := domain.IssueQuote(cart, "Q-1", clock.now, time.Hour)
+	quote.Accept(clock)
+	cart.ApplyPromotions(nil)
+
+	order, err := domain.PlaceOrderFromQuote(cart, quote, clock.now.Add(59*time.Minute))
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, order) {
+		assert.True(t, order.HasQuote())
+		assert.Equal(t, quote.GetID(), order.GetQuoteID())
+		assert.Equal(t, quote.GetLines(), order.GetLines())
+		assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("SAVE10", testutil.USD("1.62"))}, order.GetCartAdjustments())
+		assert.Equal(t, testutil.USD("14.58"), order.GetTotal())
+		assert.Empty(t, order.GetDomainEvents())
+	}
+	assert.True(t, cart.IsCheckedOut())
+	events := cart.GetDomainEvents()
+	if assert.NotEmpty(t, events) {
+		assert.IsType(t, domain.OrderPlaced{}, events[len(events)-1])
+	}
+}
+
+func Test_GivenAQuoteThatCannotBeOrdered_WhenPlaceOrderFromQuote_ThenReturnErrorAndKeepTheCartActive(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := &fixedClock{now: issuedAt}
+	anotherCart, _ := domain.NewCart(customer)
+	anotherCart.AddItem(rice, 2)
+
+	tests := []struct {
+		testName      string
+		prepare       func(cart *domain.Cart, quote *domain.Quote) *domain.Cart
+		placedOn      time.Time
+		expectedError error
+	}{
+		{
+			testName: "quote is pending",
+			prepare: func(cart *domain.Cart, quote *domain.Quote) *domain.Cart {
+				return cart
+			},
+			placedOn:      issuedAt,
+			expectedError: domain.ErrQuoteNotAccepted,
+		},
+		{
+			testName: "quote expired",
+			prepare: func(cart *domain.Cart, quote *domain.Quote) *domain.Cart {
+				quote.Accept(clock)
+				return cart
+			},
+			placedOn:      issuedAt.Add(time.Hour),
+			expectedError: domain.ErrQuoteExpired,
+		},
+		{
+			testName: "cart items changed",
+			prepare: func(cart *domain.Cart, quote *domain.Quote) *domain.Cart {
+				quote.Accept(clock)
+				cart.AddItem(rice, 1)
+				return cart
+			},
+			placedOn:      issuedAt,
+			expectedError: domain.ErrQuoteCartChanged,
+		},
+		{
+			testName: "quote belongs to another cart",
+			prepare: func(cart *domain.Cart, quote *domain.Quote) *domain.Cart {
+				quote.Accept(clock)
+				return anotherCart
+			},
+			placedOn:      issuedAt,
+			expectedError: domain.ErrQuoteCartMismatch,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			cart, _ := domain.NewCart(customer)
+			cart.AddItem(rice, 2)
+			quote, _ := domain.IssueQuote(cart, "Q-1", issuedAt, time.Hour)
+			cart = tc.prepare(cart, quote)
+
+			order, err := domain.PlaceOrderFromQuote(cart, quote, tc.placedOn)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Nil(t, order)
+			assert.True(t, cart.IsActive())
+		})
+	}
+}
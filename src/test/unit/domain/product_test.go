@@ -4,11 +4,12 @@ import (
 	"testing"
 
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/stretchr/testify/assert"
 )
 
 func Test_GivenAnEmptyName_WhenNewProduct_ThenReturnError(t *testing.T) {
-	product, err := domain.NewProduct("", 10.00)
+	product, err := domain.NewProduct("", testutil.USD("10.00"))
 
 	assert.Nil(t, product)
 	assert.Error(t, err)
@@ -16,7 +17,7 @@ func Test_GivenAnEmptyName_WhenNewProduct_ThenReturnError(t *testing.T) {
 }
 
 func Test_GivenANameWith9Characters_WhenNewProduct_ThenReturnError(t *testing.T) {
-	product, err := domain.NewProduct("123456789", 10.00)
+	product, err := domain.NewProduct("123456789", testutil.USD("10.00"))
 
 	assert.Nil(t, product)
 	assert.Error(t, err)
@@ -24,7 +25,7 @@ func Test_GivenANameWith9Characters_WhenNewProduct_ThenReturnError(t *testing.T)
 }
 
 func Test_GivenANameWithWhitespacesButTrimmedIs9Charactes_WhenNewProduct_ThenReturnError(t *testing.T) {
-	product, err := domain.NewProduct("     123456789        ", 10.00)
+	product, err := domain.NewProduct("     123456789        ", testutil.USD("10.00"))
 
 	assert.Nil(t, product)
 	assert.Error(t, err)
@@ -32,7 +33,7 @@ func Test_GivenANameWithWhitespacesButTrimmedIs9Charactes_WhenNewProduct_ThenRet
 }
 
 func Test_GivenAnInvalidPrice_WhenNewProduct_ThenReturnError(t *testing.T) {
-	product, err := domain.NewProduct("Arroz yamani", 0.00)
+	product, err := domain.NewProduct("Arroz yamani", testutil.USD("0.00"))
 
 	assert.Nil(t, product)
 	assert.Error(t, err)
@@ -40,7 +41,7 @@ func Test_GivenAnInvalidPrice_WhenNewProduct_ThenReturnError(t *testing.T) {
 }
 
 func Test_GivenValidParameters_WhenNewProduct_ThenReturnAProduct(t *testing.T) {
-	product, err := domain.NewProduct("Arroz yamani", 0.01)
+	product, err := domain.NewProduct("Arroz yamani", testutil.USD("0.01"))
 
 	assert.Nil(t, err)
 	assert.NotNil(t, product)
@@ -49,15 +50,113 @@ func Test_GivenValidParameters_WhenNewProduct_ThenReturnAProduct(t *testing.T) {
 	}
 }
 
+func Test_GivenASKU_WhenNewProductWithSKU_ThenTheProductCarriesTheNormalizedSKU(t *testing.T) {
+	sku, err := domain.NewSKU("  arz-yam-1kg ")
+	product, _ := domain.NewProduct("Arroz yamani", testutil.USD("0.01"), domain.WithSKU(sku))
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.SKU("ARZ-YAM-1KG"), product.GetSKU())
+	if assert.Len(t, product.GetDomainEvents(), 1) {
+		assert.Equal(t, sku, product.GetDomainEvents()[0].(domain.ProductCreated).SKU)
+	}
+}
+
+func Test_GivenInvalidCodes_WhenNewSKU_ThenReturnError(t *testing.T) {
+	for _, code := range []string{"", "AB", "ARZ YAM", "-ARZ", "ARZ-", "ARZ--YAM", "ARZ_YAM", "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456"} {
+		sku, err := domain.NewSKU(code)
+
+		assert.Empty(t, sku, code)
+		assert.EqualError(t, err, "invalid sku", code)
+	}
+}
+
 func Test_GivenAProduct_WhenEqualsToItself_ThenReturnsTrue(t *testing.T) {
-	product, _ := domain.NewProduct("Pepsi Ligh", 10.00)
+	product, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
 
 	assert.True(t, product.EqualsTo(product))
 }
 
 func Test_GivenAProduct_WhenEqualsToAnotherProduct_ThenReturnsFalse(t *testing.T) {
-	product, _ := domain.NewProduct("Pepsi Ligh", 10.00)
-	product2, _ := domain.NewProduct("Pepsi Ligh", 10.00)
+	product, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
+	product2, _ := domain.NewProduct("Pepsi Ligh", testutil.USD("10.00"))
 
 	assert.False(t, product.EqualsTo(product2))
 }
+
+func Test_GivenAProduct_WhenRename_ThenTheNameChangesAndAnEventIsRaised(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+
+	err := product.Rename("  Pepsi Light 2.5Lt ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Pepsi Light 2.5Lt", product.GetName())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductRenamed{ProductId: product.GetID(), PreviousName: "Pepsi Light", ProductName: "Pepsi Light 2.5Lt"},
+	}, product.GetDomainEvents())
+}
+
+func Test_GivenAProduct_WhenRenameToAShortName_ThenReturnError(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+
+	err := product.Rename("Pepsi")
+
+	assert.EqualError(t, err, "invalid product name")
+	assert.Equal(t, "Pepsi Light", product.GetName())
+	assert.Empty(t, product.GetDomainEvents())
+}
+
+func Test_GivenAProduct_WhenChangePrice_ThenThePriceChangesAndAnEventIsRaised(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+
+	err := product.ChangePrice(testutil.USD("12.50"))
+	product.ChangePrice(testutil.USD("12.50"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, testutil.USD("12.50"), product.GetPrice())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductPriceChanged{ProductId: product.GetID(), PreviousPrice: testutil.USD("10.00"), ProductUnitPrice: testutil.USD("12.50")},
+	}, product.GetDomainEvents())
+}
+
+func Test_GivenAProduct_WhenChangePriceToZero_ThenReturnError(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+
+	err := product.ChangePrice(testutil.USD("0.00"))
+
+	assert.EqualError(t, err, "invalid product price")
+	assert.Equal(t, testutil.USD("10.00"), product.GetPrice())
+}
+
+func Test_GivenAProduct_WhenArchiveAndRestore_ThenEachTransitionRaisesASingleEvent(t *testing.T) {
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+	product.ClearDomainEvents()
+
+	product.Archive()
+	product.Archive()
+	assert.True(t, product.IsArchived())
+
+	product.Restore()
+	product.Restore()
+	assert.False(t, product.IsArchived())
+
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductArchived{ProductId: product.GetID()},
+		domain.ProductRestored{ProductId: product.GetID()},
+	}, product.GetDomainEvents())
+}
+
+func Test_GivenAnArchivedProduct_WhenAddItemToACart_ThenReturnErrProductArchived(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Pepsi Light", testutil.USD("10.00"))
+	product.Archive()
+
+	item, err := cart.AddItem(product, 1)
+
+	assert.Empty(t, item)
+	assert.ErrorIs(t, err, domain.ErrProductArchived)
+	assert.Equal(t, 0, cart.Size())
+}
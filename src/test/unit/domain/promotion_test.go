@@ -0,0 +1,186 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPromotionCart(t *testing.T) (*domain.Cart, *domain.Product, *domain.Product) {
+	cartCustomer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(cartCustomer)
+	coffee, _ := domain.NewProduct("Cafe La Virginia", testutil.USD("10.00"))
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("4.00"))
+	_, err := cart.AddItem(coffee, 3)
+	assert.Nil(t, err)
+	_, err = cart.AddItem(rice, 5)
+	assert.Nil(t, err)
+	cart.ClearDomainEvents()
+
+	return cart, coffee, rice
+}
+
+func Test_GivenACartWithoutPromotions_WhenGetTotal_ThenItEqualsTheSubtotal(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+
+	assert.Equal(t, testutil.USD("50.00"), cart.GetSubtotal())
+	assert.Equal(t, testutil.USD("50.00"), cart.GetTotal())
+	assert.Empty(t, cart.GetLineAdjustments())
+	assert.Empty(t, cart.GetCartAdjustments())
+}
+
+func Test_GivenABuyXGetYPromotion_WhenApplyPromotions_ThenTheFreeUnitsAreDiscountedFromTheLine(t *testing.T) {
+	cart, coffee, _ := newPromotionCart(t)
+	promotion, err := domain.NewBuyXGetY("coffee 3x2", coffee.GetID(), 2, 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, cart.ApplyPromotions([]domain.Promotion{promotion}))
+
+	assert.Equal(t, []domain.Adjustment{domain.NewLineAdjustment("coffee 3x2", coffee.GetID(), testutil.USD("10.00"))}, cart.GetLineAdjustments())
+	assert.Equal(t, testutil.USD("50.00"), cart.GetSubtotal())
+	assert.Equal(t, testutil.USD("40.00"), cart.GetTotal())
+	assert.Empty(t, cart.GetDomainEvents())
+}
+
+func Test_GivenATieredQuantityDiscount_WhenTheQuantityChanges_ThenTheHighestReachedTierApplies(t *testing.T) {
+	cart, _, rice := newPromotionCart(t)
+	promotion, err := domain.NewTieredQuantityDiscount("rice in bulk", rice.GetID(),
+		domain.QuantityTier{MinQuantity: 10, Percent: 20},
+		domain.QuantityTier{MinQuantity: 5, Percent: 10},
+	)
+	assert.Nil(t, err)
+	cart.ApplyPromotions([]domain.Promotion{promotion})
+
+	assert.Equal(t, []domain.Adjustment{domain.NewLineAdjustment("rice in bulk", rice.GetID(), testutil.USD("2.00"))}, cart.GetLineAdjustments())
+
+	cart.UpdateItemQuantity(rice.GetID(), 10)
+	assert.Equal(t, []domain.Adjustment{domain.NewLineAdjustment("rice in bulk", rice.GetID(), testutil.USD("8.00"))}, cart.GetLineAdjustments())
+	assert.Equal(t, testutil.USD("62.00"), cart.GetTotal())
+
+	cart.UpdateItemQuantity(rice.GetID(), 4)
+	assert.Empty(t, cart.GetLineAdjustments())
+	assert.Equal(t, testutil.USD("46.00"), cart.GetTotal())
+}
+
+func Test_GivenStackedLinePromotions_WhenApplyPromotions_ThenTheLineDiscountNeverExceedsTheLineTotal(t *testing.T) {
+	cart, coffee, _ := newPromotionCart(t)
+	threeForTwo, _ := domain.NewBuyXGetY("coffee 3x2", coffee.GetID(), 2, 1)
+	freeCoffee, _ := domain.NewTieredQuantityDiscount("free coffee", coffee.GetID(), domain.QuantityTier{MinQuantity: 1, Percent: 100})
+
+	cart.ApplyPromotions([]domain.Promotion{threeForTwo, freeCoffee})
+
+	assert.Equal(t, []domain.Adjustment{
+		domain.NewLineAdjustment("coffee 3x2", coffee.GetID(), testutil.USD("10.00")),
+		domain.NewLineAdjustment("free coffee", coffee.GetID(), testutil.USD("20.00")),
+	}, cart.GetLineAdjustments())
+	assert.Equal(t, testutil.USD("20.00"), cart.GetTotal())
+}
+
+func Test_GivenLineAndCartPromotions_WhenApplyPromotions_ThenCartPromotionsApplyToTheDiscountedBalance(t *testing.T) {
+	cart, coffee, _ := newPromotionCart(t)
+	threeForTwo, _ := domain.NewBuyXGetY("coffee 3x2", coffee.GetID(), 2, 1)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	fiveOff, _ := domain.NewFixedAmountOff("FIVE-OFF", testutil.USD("5.00"))
+
+	cart.ApplyPromotions([]domain.Promotion{threeForTwo, tenPercentOff, fiveOff})
+
+	assert.Equal(t, []domain.Adjustment{
+		domain.NewCartAdjustment("SAVE10", testutil.USD("4.00")),
+		domain.NewCartAdjustment("FIVE-OFF", testutil.USD("5.00")),
+	}, cart.GetCartAdjustments())
+	assert.Equal(t, testutil.USD("31.00"), cart.GetTotal())
+}
+
+func Test_GivenAFixedAmountOffGreaterThanTheBalance_WhenApplyPromotions_ThenTheTotalIsNeverNegative(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+	hundredOff, _ := domain.NewFixedAmountOff("HUNDRED-OFF", testutil.USD("100.00"))
+	fiveOff, _ := domain.NewFixedAmountOff("FIVE-OFF", testutil.USD("5.00"))
+
+	cart.ApplyPromotions([]domain.Promotion{hundredOff, fiveOff})
+
+	assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("HUNDRED-OFF", testutil.USD("50.00"))}, cart.GetCartAdjustments())
+	assert.Equal(t, testutil.USD("0.00"), cart.GetTotal())
+}
+
+func Test_GivenAFixedAmountOffInAnotherCurrency_WhenApplyPromotions_ThenItDoesNotApply(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+	euros, _ := domain.ParseMoney("5.00", "EUR")
+	fiveEurosOff, _ := domain.NewFixedAmountOff("FIVE-EUR", euros)
+
+	cart.ApplyPromotions([]domain.Promotion{fiveEurosOff})
+
+	assert.Empty(t, cart.GetCartAdjustments())
+	assert.Equal(t, testutil.USD("50.00"), cart.GetTotal())
+}
+
+func Test_GivenAMinimumSpendRule_WhenTheSubtotalCrossesTheMinimum_ThenTheWrappedPromotionApplies(t *testing.T) {
+	cart, coffee, _ := newPromotionCart(t)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	minimumSpend, err := domain.NewMinimumSpend(testutil.USD("60.00"), tenPercentOff)
+	assert.Nil(t, err)
+	cart.ApplyPromotions([]domain.Promotion{minimumSpend})
+
+	assert.Empty(t, cart.GetCartAdjustments())
+
+	cart.AddItem(coffee, 1)
+	assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("SAVE10", testutil.USD("6.00"))}, cart.GetCartAdjustments())
+	assert.Equal(t, testutil.USD("54.00"), cart.GetTotal())
+}
+
+func Test_GivenInvalidPromotionParameters_WhenNewPromotion_ThenReturnErrors(t *testing.T) {
+	productId := domain.ProductId(uuid.New())
+
+	_, err := domain.NewPercentageOff("SAVE", 101)
+	assert.EqualError(t, err, "invalid percentage off promotion")
+	_, err = domain.NewFixedAmountOff("FREE", testutil.USD("0.00"))
+	assert.EqualError(t, err, "invalid fixed amount off promotion")
+	_, err = domain.NewBuyXGetY("3x2", productId, 2, 0)
+	assert.EqualError(t, err, "invalid buy x get y promotion")
+	_, err = domain.NewTieredQuantityDiscount("bulk", productId, domain.QuantityTier{MinQuantity: 5, Percent: 10}, domain.QuantityTier{MinQuantity: 5, Percent: 20})
+	assert.EqualError(t, err, "invalid tiered quantity discount")
+	_, err = domain.NewMinimumSpend(testutil.USD("10.00"), nil)
+	assert.EqualError(t, err, "invalid minimum spend rule")
+}
+
+func Test_GivenACoupon_WhenApplyAndRemoveCoupon_ThenTheCartTracksTheCodeAndRaisesEvents(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+	code, err := domain.NewCouponCode(" save10 ")
+	assert.Nil(t, err)
+	tenPercentOff, _ := domain.NewPercentageOff(string(code), 10)
+	coupon, _ := domain.NewCoupon(code, tenPercentOff)
+
+	assert.Nil(t, cart.ApplyCoupon(coupon))
+	assert.Nil(t, cart.ApplyCoupon(coupon))
+	assert.Equal(t, []domain.CouponCode{"SAVE10"}, cart.GetCoupons())
+
+	assert.Nil(t, cart.RemoveCoupon(code))
+	assert.ErrorIs(t, cart.RemoveCoupon(code), domain.ErrCouponNotApplied)
+	assert.Empty(t, cart.GetCoupons())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.CouponApplied{CartId: cart.GetID(), Code: "SAVE10"},
+		domain.CouponRemoved{CartId: cart.GetID(), Code: "SAVE10"},
+	}, cart.GetDomainEvents())
+}
+
+func Test_GivenACheckedOutCart_WhenApplyCouponOrPromotions_ThenReturnError(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+	fiveOff, _ := domain.NewFixedAmountOff("FIVE-OFF", testutil.USD("5.00"))
+	cart.ApplyPromotions([]domain.Promotion{fiveOff})
+	coupon, _ := domain.NewCoupon("FIVE-OFF", fiveOff)
+	cart.Checkout()
+
+	assert.ErrorIs(t, cart.ApplyCoupon(coupon), domain.ErrCartCheckedOut)
+	assert.ErrorIs(t, cart.ApplyPromotions(nil), domain.ErrCartCheckedOut)
+	assert.Equal(t, testutil.USD("45.00"), cart.GetTotal())
+}
+
+func Test_GivenInvalidCodes_WhenNewCouponCode_ThenReturnError(t *testing.T) {
+	for _, code := range []string{"", "AB", "SAVE 10", "SAVE--10", "SAVE_10"} {
+		_, err := domain.NewCouponCode(code)
+
+		assert.EqualError(t, err, "invalid coupon code", code)
+	}
+}
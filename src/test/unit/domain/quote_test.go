@@ -0,0 +1,157 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenACartWithItems_WhenIssueQuote_ThenFreezeLinesAndTotal(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 2)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	quote, err := domain.IssueQuote(cart, "Q-1", issuedAt, 24*time.Hour)
+	cart.AddItem(rice, 5)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, quote) {
+		assert.Equal(t, "Q-1", quote.GetNumber())
+		assert.Equal(t, domain.QuoteStatusPending, quote.GetStatus())
+		assert.Equal(t, testutil.USD("16.20"), quote.GetTotal())
+		assert.Equal(t, issuedAt.Add(24*time.Hour), quote.GetExpiresAt())
+		if assert.Equal(t, 1, len(quote.GetLines())) {
+			assert.Equal(t, 2, quote.GetLines()[0].GetQuantity())
+		}
+		if assert.Equal(t, 1, len(quote.GetDomainEvents())) {
+			assert.IsType(t, domain.QuoteIssued{}, quote.GetDomainEvents()[0])
+		}
+	}
+	assert.False(t, cart.IsCheckedOut())
+}
+
+func Test_GivenACartWithPromotions_WhenIssueQuote_ThenFreezeTheDiscounts(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 2)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	cart.ApplyPromotions([]domain.Promotion{tenPercentOff})
+
+	quote, err := domain.IssueQuote(cart, "Q-1", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), 24*time.Hour)
+	cart.ApplyPromotions(nil)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, quote) {
+		assert.Equal(t, testutil.USD("16.20"), quote.GetSubtotal())
+		assert.Empty(t, quote.GetLineAdjustments())
+		assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("SAVE10", testutil.USD("1.62"))}, quote.GetCartAdjustments())
+		assert.Equal(t, testutil.USD("14.58"), quote.GetTotal())
+	}
+}
+
+func Test_GivenACartWithShipping_WhenIssueQuote_ThenFreezeTheShippingQuote(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	express, _ := domain.NewFlatRateShipping("express", "Express", testutil.USD("15.00"))
+	cart.SelectShipping(standard)
+
+	quote, err := domain.IssueQuote(cart, "Q-1", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), 24*time.Hour)
+	cart.SelectShipping(express)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, quote) {
+		assert.Equal(t, domain.NewShippingQuote("standard", "Standard", testutil.USD("5.00")), quote.GetShipping())
+		assert.Equal(t, testutil.USD("55.00"), quote.GetTotal())
+	}
+}
+
+func Test_GivenAnEmptyCart_WhenIssueQuote_ThenReturnError(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+
+	quote, err := domain.IssueQuote(cart, "Q-1", time.Now(), time.Hour)
+
+	assert.Nil(t, quote)
+	assert.ErrorIs(t, err, domain.ErrCartEmpty)
+}
+
+func Test_GivenAPendingQuote_WhenAcceptBeforeExpiry_ThenTheQuoteIsAccepted(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote := newQuote(t, clock.now, time.Hour)
+
+	clock.now = clock.now.Add(59 * time.Minute)
+	err := quote.Accept(clock)
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.QuoteStatusAccepted, quote.GetStatus())
+	if assert.Equal(t, 1, len(quote.GetDomainEvents())) {
+		assert.IsType(t, domain.QuoteAccepted{}, quote.GetDomainEvents()[0])
+	}
+}
+
+func Test_GivenAPendingQuote_WhenAcceptAfterExpiry_ThenTheQuoteExpires(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote := newQuote(t, clock.now, time.Hour)
+
+	clock.now = clock.now.Add(time.Hour)
+	err := quote.Accept(clock)
+
+	assert.ErrorIs(t, err, domain.ErrQuoteExpired)
+	assert.Equal(t, domain.QuoteStatusExpired, quote.GetStatus())
+	if assert.Equal(t, 1, len(quote.GetDomainEvents())) {
+		assert.IsType(t, domain.QuoteExpired{}, quote.GetDomainEvents()[0])
+	}
+
+	quote.ClearDomainEvents()
+	assert.ErrorIs(t, quote.Decline(clock), domain.ErrQuoteExpired)
+	assert.Empty(t, quote.GetDomainEvents())
+}
+
+func Test_GivenADeclinedQuote_WhenAccept_ThenReturnNotPending(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote := newQuote(t, clock.now, time.Hour)
+	quote.Decline(clock)
+
+	err := quote.Accept(clock)
+
+	assert.ErrorIs(t, err, domain.ErrQuoteNotPending)
+	assert.Equal(t, domain.QuoteStatusDeclined, quote.GetStatus())
+}
+
+func Test_GivenAPendingQuote_WhenExpireBeforeExpiry_ThenNothingChanges(t *testing.T) {
+	clock := &fixedClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	quote := newQuote(t, clock.now, time.Hour)
+
+	assert.False(t, quote.Expire(clock))
+	assert.Equal(t, domain.QuoteStatusPending, quote.GetStatus())
+	assert.Empty(t, quote.GetDomainEvents())
+}
+
+func newQuote(t *testing.T, issuedAt time.Time, validFor time.Duration) *domain.Quote {
+	customer, _ := domain.NewCustomer("John Mayer")
+	rice, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.10"))
+	cart, _ := domain.NewCart(customer)
+	cart.AddItem(rice, 1)
+
+	quote, err := domain.IssueQuote(cart, "Q-1", issuedAt, validFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quote.ClearDomainEvents()
+
+	return quote
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
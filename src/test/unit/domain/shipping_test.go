@@ -0,0 +1,128 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newShippingCart(t *testing.T) (*domain.Cart, *domain.Product, *domain.Product) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	dimensions, _ := domain.NewDimensions(400, 300, 200)
+	coffee, _ := domain.NewProduct("Cafe La Virginia", testutil.USD("10.00"), domain.WithWeight(1200))
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"), domain.WithWeight(300), domain.WithDimensions(dimensions))
+	_, err := cart.AddItem(coffee, 2)
+	assert.Nil(t, err)
+	_, err = cart.AddItem(pillow, 1)
+	assert.Nil(t, err)
+	cart.ClearDomainEvents()
+
+	return cart, coffee, pillow
+}
+
+func Test_GivenAFlatRateMethod_WhenSelectShipping_ThenTheCostIsAddedToTheTotal(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	standard, err := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, cart.SelectShipping(standard))
+	assert.Nil(t, cart.SelectShipping(standard))
+
+	assert.True(t, cart.HasShipping())
+	assert.Equal(t, domain.NewShippingQuote("standard", "Standard", testutil.USD("5.00")), cart.GetShipping())
+	assert.Equal(t, testutil.USD("50.00"), cart.GetSubtotal())
+	assert.Equal(t, testutil.USD("55.00"), cart.GetTotal())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ShippingSelected{CartId: cart.GetID(), Method: "standard", Cost: testutil.USD("5.00")},
+	}, cart.GetDomainEvents())
+}
+
+func Test_GivenAWeightBasedMethod_WhenQuoteShipping_ThenChargeTheGreaterOfActualAndVolumetricWeight(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	byWeight, _ := domain.NewWeightBasedShipping("courier", "Courier", testutil.USD("5.00"), testutil.USD("2.00"), 0)
+	byVolume, _ := domain.NewWeightBasedShipping("courier", "Courier", testutil.USD("5.00"), testutil.USD("2.00"), 5000)
+
+	quote, err := cart.QuoteShipping(byWeight)
+	assert.Nil(t, err)
+	assert.Equal(t, testutil.USD("11.00"), quote.GetCost())
+
+	quote, err = cart.QuoteShipping(byVolume)
+	assert.Nil(t, err)
+	assert.Equal(t, testutil.USD("21.00"), quote.GetCost())
+}
+
+func Test_GivenAFreeShippingThreshold_WhenTheDiscountedSubtotalCrossesIt_ThenShippingIsFree(t *testing.T) {
+	cart, coffee, _ := newShippingCart(t)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	freeAboveSixty, err := domain.NewFreeShippingAbove(testutil.USD("60.00"), standard)
+	assert.Nil(t, err)
+	cart.SelectShipping(freeAboveSixty)
+
+	assert.Equal(t, testutil.USD("5.00"), cart.GetShipping().GetCost())
+
+	cart.UpdateItemQuantity(coffee.GetID(), 3)
+	assert.Equal(t, testutil.USD("0.00"), cart.GetShipping().GetCost())
+	assert.Equal(t, testutil.USD("60.00"), cart.GetTotal())
+
+	tenOff, _ := domain.NewFixedAmountOff("TEN-OFF", testutil.USD("10.00"))
+	cart.ApplyPromotions([]domain.Promotion{tenOff})
+	assert.Equal(t, testutil.USD("5.00"), cart.GetShipping().GetCost())
+	assert.Equal(t, testutil.USD("55.00"), cart.GetTotal())
+}
+
+func Test_GivenASelectedMethod_WhenTheCartIsCleared_ThenTheSelectionIsDropped(t *testing.T) {
+	cart, _, _ := newShippingCart(t)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+
+	cart.Clear()
+
+	assert.False(t, cart.HasShipping())
+	assert.Equal(t, testutil.USD("0.00"), cart.GetTotal())
+}
+
+func Test_GivenAnEmptyCartOrAnotherCurrency_WhenSelectShipping_ThenReturnError(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	emptyCart, _ := domain.NewCart(customer)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	euros, _ := domain.ParseMoney("5.00", "EUR")
+	european, _ := domain.NewFlatRateShipping("european", "European", euros)
+	cart, _, _ := newShippingCart(t)
+
+	assert.ErrorIs(t, emptyCart.SelectShipping(standard), domain.ErrCartEmpty)
+	assert.ErrorIs(t, cart.SelectShipping(european), domain.ErrShippingUnavailable)
+	assert.False(t, cart.HasShipping())
+}
+
+func Test_GivenInvalidParameters_WhenNewShippingValues_ThenReturnErrors(t *testing.T) {
+	_, err := domain.NewWeight(-1)
+	assert.EqualError(t, err, "invalid weight")
+	_, err = domain.NewDimensions(10, 0, 10)
+	assert.EqualError(t, err, "invalid dimensions")
+	_, err = domain.NewShippingMethodCode("Next Day!")
+	assert.EqualError(t, err, "invalid shipping method")
+	_, err = domain.NewFlatRateShipping("standard", "Standard", testutil.USD("-1.00"))
+	assert.EqualError(t, err, "invalid flat rate shipping")
+	_, err = domain.NewWeightBasedShipping("courier", "Courier", testutil.USD("5.00"), testutil.USD("0.00"), 0)
+	assert.EqualError(t, err, "invalid weight based shipping")
+	_, err = domain.NewFreeShippingAbove(testutil.USD("50.00"), nil)
+	assert.EqualError(t, err, "invalid free shipping threshold")
+}
+
+func Test_GivenAProduct_WhenChangeShippingDetails_ThenRaiseASingleEvent(t *testing.T) {
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	pillow.ClearDomainEvents()
+	dimensions, _ := domain.NewDimensions(400, 300, 200)
+
+	assert.Nil(t, pillow.ChangeShippingDetails(300, dimensions))
+	assert.Nil(t, pillow.ChangeShippingDetails(300, dimensions))
+
+	assert.Equal(t, domain.Weight(300), pillow.GetWeight())
+	assert.Equal(t, dimensions, pillow.GetDimensions())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductShippingDetailsChanged{ProductId: pillow.GetID(), Weight: 300, Dimensions: dimensions},
+	}, pillow.GetDomainEvents())
+}
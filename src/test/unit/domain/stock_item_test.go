@@ -0,0 +1,90 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANegativeOnHandQuantity_WhenNewStockItem_ThenReturnError(t *testing.T) {
+	stockItem, err := domain.NewStockItem(domain.ProductId(uuid.New()), -1)
+
+	assert.Nil(t, stockItem)
+	assert.EqualError(t, err, "invalid on hand quantity")
+}
+
+func Test_GivenAStockItem_WhenReserve_ThenTheQuantityIsNoLongerAvailable(t *testing.T) {
+	productId := domain.ProductId(uuid.New())
+	cartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(productId, 5)
+	stockItem.ClearDomainEvents()
+
+	err := stockItem.Reserve(cartId, 2)
+	stockItem.Reserve(cartId, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, stockItem.GetOnHand())
+	assert.Equal(t, 3, stockItem.GetReserved())
+	assert.Equal(t, 3, stockItem.GetReservedFor(cartId))
+	assert.Equal(t, 2, stockItem.GetAvailable())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.StockReserved{ProductId: productId, CartId: cartId, Quantity: 2},
+		domain.StockReserved{ProductId: productId, CartId: cartId, Quantity: 1},
+	}, stockItem.GetDomainEvents())
+}
+
+func Test_GivenAStockItemWithReservations_WhenReserveMoreThanAvailable_ThenReturnInsufficientStock(t *testing.T) {
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 3)
+	stockItem.Reserve(domain.CartId(uuid.New()), 2)
+
+	err := stockItem.Reserve(domain.CartId(uuid.New()), 2)
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Equal(t, 2, stockItem.GetReserved())
+}
+
+func Test_GivenAReservation_WhenRelease_ThenTheQuantityIsAvailableAgain(t *testing.T) {
+	cartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	stockItem.Reserve(cartId, 4)
+
+	stockItem.Release(cartId, 1)
+	assert.Equal(t, 3, stockItem.GetReservedFor(cartId))
+
+	stockItem.ReleaseAll(cartId)
+	assert.Equal(t, 0, stockItem.GetReservedFor(cartId))
+	assert.Equal(t, 5, stockItem.GetAvailable())
+}
+
+func Test_GivenAReservation_WhenCommit_ThenTheOnHandQuantityIsReduced(t *testing.T) {
+	productId := domain.ProductId(uuid.New())
+	cartId := domain.CartId(uuid.New())
+	otherCartId := domain.CartId(uuid.New())
+	stockItem, _ := domain.NewStockItem(productId, 5)
+	stockItem.Reserve(cartId, 2)
+	stockItem.Reserve(otherCartId, 1)
+	stockItem.ClearDomainEvents()
+
+	stockItem.Commit(cartId)
+
+	assert.Equal(t, 3, stockItem.GetOnHand())
+	assert.Equal(t, 1, stockItem.GetReserved())
+	assert.Equal(t, 2, stockItem.GetAvailable())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.StockCommitted{ProductId: productId, CartId: cartId, Quantity: 2},
+	}, stockItem.GetDomainEvents())
+}
+
+func Test_GivenAStockItemWithReservations_WhenRestockBelowTheReservedQuantity_ThenReturnError(t *testing.T) {
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	stockItem.Reserve(domain.CartId(uuid.New()), 3)
+
+	err := stockItem.Restock(2)
+
+	assert.ErrorIs(t, err, domain.ErrOnHandBelowReserved)
+	assert.Equal(t, 5, stockItem.GetOnHand())
+	assert.NoError(t, stockItem.Restock(3))
+	assert.Equal(t, 0, stockItem.GetAvailable())
+}
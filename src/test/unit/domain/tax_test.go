@@ -0,0 +1,170 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTaxCalculator(t *testing.T, rounding domain.TaxRounding, rules ...domain.TaxRule) domain.TaxCalculator {
+	calculator, err := domain.NewRuleTableTaxCalculator(rounding, rules...)
+	assert.Nil(t, err)
+
+	return calculator
+}
+
+func Test_GivenValidAndInvalidValues_WhenNewTaxCategoryAndRegion_ThenNormalizeOrReturnError(t *testing.T) {
+	category, err := domain.NewTaxCategory(" Reduced ")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.TaxCategory("reduced"), category)
+
+	region, err := domain.NewTaxRegion("us-ca")
+	assert.Nil(t, err)
+	assert.Equal(t, domain.TaxRegion("US-CA"), region)
+
+	_, err = domain.NewTaxCategory("food & drinks")
+	assert.EqualError(t, err, "invalid tax category")
+	_, err = domain.NewTaxRegion("ARG")
+	assert.EqualError(t, err, "invalid tax region")
+}
+
+func Test_GivenInvalidRules_WhenNewRuleTableTaxCalculator_ThenReturnError(t *testing.T) {
+	_, err := domain.NewRuleTableTaxCalculator("per_cart")
+	assert.EqualError(t, err, "invalid tax rounding")
+
+	_, err = domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine, domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(-1, 10)})
+	assert.EqualError(t, err, "invalid tax rule")
+
+	_, err = domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)},
+		domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(27, 100)},
+	)
+	assert.EqualError(t, err, "duplicated tax rule for AR/standard")
+}
+
+func Test_GivenExclusiveAndInclusiveRules_WhenCalculate_ThenReturnTheTaxOfEachLine(t *testing.T) {
+	calculator := newTaxCalculator(t, domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(8875, 100000)},
+		domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100), Inclusive: true},
+	)
+	productId := domain.ProductId(uuid.New())
+	lines := []domain.TaxableLine{{ProductId: productId, Category: domain.StandardTaxCategory, Amount: testutil.USD("121.00")}}
+
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(productId, big.NewRat(8875, 100000), false, testutil.USD("10.74"))}, calculator.Calculate("US", lines))
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(productId, big.NewRat(21, 100), true, testutil.USD("21.00"))}, calculator.Calculate("AR", lines))
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(productId, new(big.Rat), false, testutil.USD("0.00"))}, calculator.Calculate("UY", lines))
+}
+
+func Test_GivenSeveralSmallLines_WhenCalculatePerLineOrPerTotal_ThenRoundAccordingly(t *testing.T) {
+	rule := domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(1, 10)}
+	lines := []domain.TaxableLine{
+		{ProductId: domain.ProductId(uuid.New()), Category: domain.StandardTaxCategory, Amount: testutil.USD("0.05")},
+		{ProductId: domain.ProductId(uuid.New()), Category: domain.StandardTaxCategory, Amount: testutil.USD("0.05")},
+		{ProductId: domain.ProductId(uuid.New()), Category: domain.StandardTaxCategory, Amount: testutil.USD("0.05")},
+	}
+
+	var perLine []domain.Money
+	for _, lineTax := range newTaxCalculator(t, domain.RoundTaxPerLine, rule).Calculate("US", lines) {
+		perLine = append(perLine, lineTax.GetAmount())
+	}
+	var perTotal []domain.Money
+	for _, lineTax := range newTaxCalculator(t, domain.RoundTaxPerTotal, rule).Calculate("US", lines) {
+		perTotal = append(perTotal, lineTax.GetAmount())
+	}
+
+	assert.Equal(t, []domain.Money{testutil.USD("0.00"), testutil.USD("0.00"), testutil.USD("0.00")}, perLine)
+	assert.Equal(t, []domain.Money{testutil.USD("0.01"), testutil.USD("0.01"), testutil.USD("0.00")}, perTotal)
+}
+
+func Test_GivenACartWithExclusiveTaxes_WhenItemsChange_ThenTaxesAndTotalAreRecalculated(t *testing.T) {
+	cart, coffee, rice := newPromotionCart(t)
+	calculator := newTaxCalculator(t, domain.RoundTaxPerLine, domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)})
+
+	assert.Nil(t, cart.ApplyTaxes("US", calculator))
+
+	assert.Equal(t, testutil.USD("10.50"), cart.GetTax())
+	assert.Equal(t, testutil.USD("60.50"), cart.GetTotal())
+
+	cart.RemoveItem(rice.GetID())
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(coffee.GetID(), big.NewRat(21, 100), false, testutil.USD("6.30"))}, cart.GetLineTaxes())
+	assert.Equal(t, testutil.USD("36.30"), cart.GetTotal())
+}
+
+func Test_GivenACartWithDiscounts_WhenApplyTaxes_ThenTheTaxableAmountIsNetOfDiscounts(t *testing.T) {
+	cart, coffee, rice := newPromotionCart(t)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	cart.ApplyPromotions([]domain.Promotion{tenPercentOff})
+	calculator := newTaxCalculator(t, domain.RoundTaxPerLine, domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)})
+
+	cart.ApplyTaxes("US", calculator)
+
+	lineTaxes := map[domain.ProductId]domain.Money{}
+	for _, lineTax := range cart.GetLineTaxes() {
+		lineTaxes[lineTax.GetProductId()] = lineTax.GetAmount()
+	}
+	assert.Equal(t, map[domain.ProductId]domain.Money{coffee.GetID(): testutil.USD("5.67"), rice.GetID(): testutil.USD("3.78")}, lineTaxes)
+	assert.Equal(t, testutil.USD("9.45"), cart.GetTax())
+	assert.Equal(t, testutil.USD("54.45"), cart.GetTotal())
+}
+
+func Test_GivenACartWithInclusiveTaxes_WhenApplyTaxes_ThenTheTotalIsUnchanged(t *testing.T) {
+	cart, _, _ := newPromotionCart(t)
+	calculator := newTaxCalculator(t, domain.RoundTaxPerTotal, domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100), Inclusive: true})
+
+	cart.ApplyTaxes("AR", calculator)
+
+	assert.Equal(t, testutil.USD("8.68"), cart.GetTax())
+	assert.Equal(t, testutil.USD("50.00"), cart.GetTotal())
+}
+
+func Test_GivenProductsInDifferentTaxCategories_WhenApplyTaxes_ThenEachLineUsesItsCategoryRate(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	book, _ := domain.NewProduct("Domain Driven Design", testutil.USD("50.00"), domain.WithTaxCategory("reduced"))
+	lamp, _ := domain.NewProduct("Desk Lamp Classic", testutil.USD("20.00"))
+	cart.AddItem(book, 1)
+	cart.AddItem(lamp, 1)
+	calculator := newTaxCalculator(t, domain.RoundTaxPerLine,
+		domain.TaxRule{Region: "AR", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)},
+		domain.TaxRule{Region: "AR", Category: "reduced", Rate: big.NewRat(105, 1000)},
+	)
+
+	cart.ApplyTaxes("AR", calculator)
+
+	assert.Equal(t, testutil.USD("9.45"), cart.GetTax())
+	assert.Equal(t, testutil.USD("79.45"), cart.GetTotal())
+}
+
+func Test_GivenAProduct_WhenChangeTaxCategory_ThenRaiseASingleEvent(t *testing.T) {
+	book, _ := domain.NewProduct("Domain Driven Design", testutil.USD("50.00"))
+	book.ClearDomainEvents()
+
+	assert.Equal(t, domain.StandardTaxCategory, book.GetTaxCategory())
+	assert.Nil(t, book.ChangeTaxCategory("reduced"))
+	assert.Nil(t, book.ChangeTaxCategory("reduced"))
+
+	assert.Equal(t, domain.TaxCategory("reduced"), book.GetTaxCategory())
+	assert.Equal(t, []domain.DomainEvent{
+		domain.ProductTaxCategoryChanged{ProductId: book.GetID(), PreviousTaxCategory: domain.StandardTaxCategory, TaxCategory: "reduced"},
+	}, book.GetDomainEvents())
+}
+
+func Test_GivenACustomerWithAddresses_WhenGetTaxRegion_ThenPreferTheShippingCountry(t *testing.T) {
+	shipping, _ := domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "AR")
+	billing, _ := domain.NewAddress("18 de Julio 1234", "Montevideo", "11100", "UY")
+
+	assert.Equal(t, domain.TaxRegion(""), mustNewCustomer(t).GetTaxRegion())
+	assert.Equal(t, domain.TaxRegion("UY"), mustNewCustomer(t, domain.WithBillingAddress(billing)).GetTaxRegion())
+	assert.Equal(t, domain.TaxRegion("AR"), mustNewCustomer(t, domain.WithShippingAddress(shipping), domain.WithBillingAddress(billing)).GetTaxRegion())
+}
+
+func mustNewCustomer(t *testing.T, options ...domain.CustomerOption) *domain.Customer {
+	customer, err := domain.NewCustomer("John Mayer", options...)
+	assert.Nil(t, err)
+
+	return customer
+}
@@ -7,15 +7,20 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 )
 
+var lastActivityAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
 func Test_GivenANilCartService_WhenNewCartController_ThenReturnError(t *testing.T) {
 	controller, err := controllers.NewCartController(nil)
 
@@ -39,9 +44,14 @@ func Test_GivenAValidCreateNewCartRequest_WhenCreateNewCart_ThenReturn201AndACar
 	cartServiceMock := &cartServiceMock{
 		createNewCart: func(_ application.CreateCartCommand) (application.CartDto, error) {
 			return application.CartDto{
-				Id:         newCartId,
-				CustomerId: customerId,
-				Items:      []application.ItemDto{},
+				Id:             newCartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("0.00")),
+				Total:          application.PriceDto(testutil.USD("0.00")),
 			}, nil
 		},
 	}
@@ -56,12 +66,46 @@ func Test_GivenAValidCreateNewCartRequest_WhenCreateNewCart_ThenReturn201AndACar
 
 	if assert.NoError(t, controller.CreateNewCart(c)) {
 		assert.Equal(t, http.StatusCreated, rec.Code)
-		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"items\":[]}\n", newCartId.String(), customerId.String()), rec.Body.String())
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}\n", newCartId.String(), customerId.String()), rec.Body.String())
 	}
 	assert.Equal(t, 1, cartServiceMock.callCount)
 
 }
 
+func Test_GivenACustomerWhoseCartIsReused_WhenCreateNewCart_ThenReturn200AndTheExistingCart(t *testing.T) {
+	existingCartId := uuid.New()
+	customerId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		createNewCart: func(_ application.CreateCartCommand) (application.CartDto, error) {
+			return application.CartDto{
+				Id:             existingCartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("0.00")),
+				Total:          application.PriceDto(testutil.USD("0.00")),
+				Reused:         true,
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(fmt.Sprintf(`{"customer_id":"%s"}`, customerId.String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+
+	if assert.NoError(t, controller.CreateNewCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}\n", existingCartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
 func Test_GivenAValidCreateNewCartRequestButCartServiceFailsToCreateCart_WhenCreateNewCart_ThenReturn500(t *testing.T) {
 	customerId := uuid.New()
 	cartServiceMock := &cartServiceMock{
@@ -230,15 +274,21 @@ func Test_GivenAValidAddItemToCartRequestAndAnExistingEmptyCart_WhenAddItemToCar
 		addItemToCart: func(command application.AddItemToCartCommand) (application.CartDto, error) {
 			if command.CartId == cartId {
 				return application.CartDto{
-					Id:         cartId,
-					CustomerId: customerId,
+					Id:             cartId,
+					CustomerId:     customerId,
+					Currency:       "USD",
+					Status:         "active",
+					LastActivityAt: lastActivityAt,
 					Items: []application.ItemDto{
 						{
 							ProductId: command.ProductId,
-							UnitPrice: 10.10,
+							UnitPrice: application.PriceDto(testutil.USD("10.10")),
+							Currency:  "USD",
 							Quantity:  command.Quantity,
 						},
 					},
+					Subtotal: application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
+					Total:    application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
 				}, nil
 			}
 
@@ -261,7 +311,7 @@ func Test_GivenAValidAddItemToCartRequestAndAnExistingEmptyCart_WhenAddItemToCar
 	if assert.NoError(t, controller.AddItemToCart(c)) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t,
-			fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"items\":[{\"product_id\":\"%s\",\"unit_price\":10.10,\"quantity\":2}]}\n",
+			fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[{\"product_id\":\"%s\",\"unit_price\":10.10,\"currency\":\"USD\",\"quantity\":2,\"price_changed\":false}],\"subtotal\":20.20,\"total\":20.20}\n",
 				cartId.String(), customerId.String(), productId.String()),
 			rec.Body.String())
 	}
@@ -276,15 +326,21 @@ func Test_GivenANonExistantCart_WhenAddItemToCart_ThenReturn404(t *testing.T) {
 		addItemToCart: func(command application.AddItemToCartCommand) (application.CartDto, error) {
 			if command.CartId == cartId {
 				return application.CartDto{
-					Id:         cartId,
-					CustomerId: customerId,
+					Id:             cartId,
+					CustomerId:     customerId,
+					Currency:       "USD",
+					Status:         "active",
+					LastActivityAt: lastActivityAt,
 					Items: []application.ItemDto{
 						{
 							ProductId: command.ProductId,
-							UnitPrice: 10.10,
+							UnitPrice: application.PriceDto(testutil.USD("10.10")),
+							Currency:  "USD",
 							Quantity:  command.Quantity,
 						},
 					},
+					Subtotal: application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
+					Total:    application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
 				}, nil
 			}
 
@@ -343,6 +399,69 @@ func Test_GivenACartServiceFailsToAddItemToCart_WhenAddItemToCart_ThenReturn500(
 	assert.Equal(t, 1, cartServiceMock.callCount)
 }
 
+func Test_GivenNotEnoughStock_WhenAddItemToCart_ThenReturn409(t *testing.T) {
+	cartId := uuid.New()
+	productId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		addItemToCart: func(command application.AddItemToCartCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewInsufficientStockError(productId.String(), 2, 1)
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":2}`, productId.String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	err := controller.AddItemToCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusConflict, err.Code)
+		assert.Equal(t, fmt.Sprintf("insufficient stock for product %s: requested 2, available 1", productId.String()), err.Message)
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
+func Test_GivenAnArchivedProduct_WhenAddItemToCart_ThenReturn422WithProductArchivedCode(t *testing.T) {
+	cartId := uuid.New()
+	productId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		addItemToCart: func(command application.AddItemToCartCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewProductArchivedError(productId.String())
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":1}`, productId.String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	err := controller.AddItemToCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusUnprocessableEntity, err.Code)
+		assert.Equal(t, map[string]string{
+			"code":    "product_archived",
+			"message": fmt.Sprintf("product %s is archived and can no longer be added to carts", productId.String()),
+		}, err.Message)
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
 func Test_GivenANilCartId_WhenAddItemToCart_ThenReturn400(t *testing.T) {
 	productId := uuid.New()
 	cartServiceMock := &cartServiceMock{}
@@ -441,7 +560,7 @@ func Test_GivenAnInvalidGuidFormat_WhenAddItemToCart_ThenReturn400(t *testing.T)
 	assert.Equal(t, 0, cartServiceMock.callCount)
 }
 
-func Test_GivenAnNilProductId_WhenAddItemToCart_ThenReturn400(t *testing.T) {
+func Test_GivenAnNilProductIdAndSKU_WhenAddItemToCart_ThenReturn400(t *testing.T) {
 	cartServiceMock := &cartServiceMock{}
 	controller, _ := controllers.NewCartController(cartServiceMock)
 
@@ -464,7 +583,11 @@ func Test_GivenAnNilProductId_WhenAddItemToCart_ThenReturn400(t *testing.T) {
 			Errors: []config.FieldError{
 				{
 					Field: "ProductId",
-					Error: "ProductId is a required field",
+					Error: "ProductId is required when SKU is not set",
+				},
+				{
+					Field: "SKU",
+					Error: "SKU is required when ProductId is not set",
 				},
 			},
 		}, err.Message)
@@ -634,18 +757,797 @@ func Test_GivenANegativeQuantity_WhenAddItemToCart_ThenReturn400(t *testing.T) {
 	assert.Equal(t, 0, cartServiceMock.callCount)
 }
 
-type cartServiceMock struct {
-	callCount     int
-	createNewCart func(application.CreateCartCommand) (application.CartDto, error)
-	addItemToCart func(application.AddItemToCartCommand) (application.CartDto, error)
+func Test_GivenAValidRemoveItemRequest_WhenRemoveItemFromCart_ThenReturn200AndTheUpdatedCart(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	productId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		removeItemFromCart: func(command application.RemoveItemFromCartCommand) (application.CartDto, error) {
+			assert.Equal(t, cartId, command.CartId)
+			assert.Equal(t, productId, command.ProductId)
+			return application.CartDto{
+				Id:             cartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("0.00")),
+				Total:          application.PriceDto(testutil.USD("0.00")),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items/:productId")
+	c.SetParamNames("cartId", "productId")
+	c.SetParamValues(cartId.String(), productId.String())
+
+	if assert.NoError(t, controller.RemoveItemFromCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
 }
 
-func (c *cartServiceMock) CreateNewCart(command application.CreateCartCommand) (application.CartDto, error) {
-	c.callCount++
-	return c.createNewCart(command)
+func Test_GivenAnItemThatIsNotInTheCart_WhenRemoveItemFromCart_ThenReturn404(t *testing.T) {
+	productId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		removeItemFromCart: func(command application.RemoveItemFromCartCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewNotFoundError(productId.String(), "item")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items/:productId")
+	c.SetParamNames("cartId", "productId")
+	c.SetParamValues(uuid.New().String(), productId.String())
+
+	err := controller.RemoveItemFromCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("item with id %s not found", productId.String()), err.Message)
+	}
 }
 
-func (c *cartServiceMock) AddItemToCart(command application.AddItemToCartCommand) (application.CartDto, error) {
-	c.callCount++
-	return c.addItemToCart(command)
+func Test_GivenAnInvalidProductId_WhenRemoveItemFromCart_ThenReturn400(t *testing.T) {
+	cartServiceMock := &cartServiceMock{}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items/:productId")
+	c.SetParamNames("cartId", "productId")
+	c.SetParamValues(uuid.New().String(), "1234")
+
+	err := controller.RemoveItemFromCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, &config.ValidationErrorsResponse{
+			Message: "there were validation errors",
+			Errors: []config.FieldError{
+				{
+					Field: "ProductId",
+					Error: "ProductId is a required field",
+				},
+			},
+		}, err.Message)
+	}
+	assert.Equal(t, 0, cartServiceMock.callCount)
+}
+
+func Test_GivenAValidUpdateQuantityRequest_WhenUpdateItemQuantity_ThenReturn200AndTheUpdatedCart(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	productId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		updateItemQuantity: func(command application.UpdateItemQuantityCommand) (application.CartDto, error) {
+			return application.CartDto{
+				Id:             command.CartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items: []application.ItemDto{
+					{
+						ProductId: command.ProductId,
+						UnitPrice: application.PriceDto(testutil.USD("10.10")),
+						Currency:  "USD",
+						Quantity:  command.Quantity,
+					},
+				},
+				Subtotal: application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
+				Total:    application.PriceDto(domain.NewMoney(1010*int64(command.Quantity), domain.DefaultCurrency)),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/carts", strings.NewReader(`{"quantity":5}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items/:productId")
+	c.SetParamNames("cartId", "productId")
+	c.SetParamValues(cartId.String(), productId.String())
+
+	if assert.NoError(t, controller.UpdateItemQuantity(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t,
+			fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[{\"product_id\":\"%s\",\"unit_price\":10.10,\"currency\":\"USD\",\"quantity\":5,\"price_changed\":false}],\"subtotal\":50.50,\"total\":50.50}\n",
+				cartId.String(), customerId.String(), productId.String()),
+			rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
+func Test_GivenAZeroQuantity_WhenUpdateItemQuantity_ThenReturn400(t *testing.T) {
+	cartServiceMock := &cartServiceMock{}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/carts", strings.NewReader(`{"quantity":-2}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items/:productId")
+	c.SetParamNames("cartId", "productId")
+	c.SetParamValues(uuid.New().String(), uuid.New().String())
+
+	err := controller.UpdateItemQuantity(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, &config.ValidationErrorsResponse{
+			Message: "there were validation errors",
+			Errors: []config.FieldError{
+				{
+					Field: "Quantity",
+					Error: "Quantity must be greater than 0",
+				},
+			},
+		}, err.Message)
+	}
+	assert.Equal(t, 0, cartServiceMock.callCount)
+}
+
+func Test_GivenAValidClearCartRequest_WhenClearCart_ThenReturn200AndAnEmptyCart(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		clearCart: func(command application.ClearCartCommand) (application.CartDto, error) {
+			return application.CartDto{
+				Id:             command.CartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("0.00")),
+				Total:          application.PriceDto(testutil.USD("0.00")),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/items")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.ClearCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
+func Test_GivenAnExistingCart_WhenGetCart_ThenReturn200AndTheCartDto(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		getCart: func(query application.GetCartQuery) (application.CartDto, error) {
+			return application.CartDto{
+				Id:             query.CartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("0.00")),
+				Total:          application.PriceDto(testutil.USD("0.00")),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.GetCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
+func Test_GivenANonExistantCart_WhenGetCart_ThenReturn404(t *testing.T) {
+	cartId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		getCart: func(query application.GetCartQuery) (application.CartDto, error) {
+			return application.CartDto{}, application.NewNotFoundError(query.CartId.String(), "cart")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	err := controller.GetCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("cart with id %s not found", cartId.String()), err.Message)
+	}
+}
+
+func Test_GivenACustomerWithCarts_WhenGetCustomerCarts_ThenReturn200AndTheCartDtos(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		getCustomerCarts: func(query application.GetCustomerCartsQuery) ([]application.CartDto, error) {
+			return []application.CartDto{
+				{
+					Id:             cartId,
+					CustomerId:     query.CustomerId,
+					Currency:       "USD",
+					Status:         "active",
+					LastActivityAt: lastActivityAt,
+					Items:          []application.ItemDto{},
+					Subtotal:       application.PriceDto(testutil.USD("0.00")),
+					Total:          application.PriceDto(testutil.USD("0.00")),
+				},
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/customers", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId/carts")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	if assert.NoError(t, controller.GetCustomerCarts(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("[{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":0.00,\"total\":0.00}]\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, cartServiceMock.callCount)
+}
+
+func Test_GivenAStatusFilter_WhenGetCustomerCarts_ThenPassTheStatusToTheService(t *testing.T) {
+	customerId := uuid.New()
+	var receivedQuery application.GetCustomerCartsQuery
+	cartServiceMock := &cartServiceMock{
+		getCustomerCarts: func(query application.GetCustomerCartsQuery) ([]application.CartDto, error) {
+			receivedQuery = query
+			return []application.CartDto{}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/customers?status=abandoned", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId/carts")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	if assert.NoError(t, controller.GetCustomerCarts(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "[]\n", rec.Body.String())
+	}
+	assert.Equal(t, customerId, receivedQuery.CustomerId)
+	assert.Equal(t, "abandoned", receivedQuery.Status)
+}
+
+func Test_GivenAnUnknownStatusFilter_WhenGetCustomerCarts_ThenReturnValidationError(t *testing.T) {
+	cartServiceMock := &cartServiceMock{}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/customers?status=forgotten", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId/carts")
+	c.SetParamNames("customerId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.GetCustomerCarts(c)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, cartServiceMock.callCount)
+}
+
+func Test_GivenAValidApplyCouponRequest_WhenApplyCoupon_ThenReturn200AndTheDiscountedCart(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	var receivedCommand application.ApplyCouponCommand
+	cartServiceMock := &cartServiceMock{
+		applyCoupon: func(command application.ApplyCouponCommand) (application.CartDto, error) {
+			receivedCommand = command
+			return application.CartDto{
+				Id:             command.CartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Coupons:        []string{"SAVE10"},
+				Subtotal:       application.PriceDto(testutil.USD("100.00")),
+				CartDiscounts:  []application.DiscountDto{{Promotion: "SAVE10", Amount: application.PriceDto(testutil.USD("10.00"))}},
+				Total:          application.PriceDto(testutil.USD("90.00")),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(`{"code":"SAVE10"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/coupons")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.ApplyCoupon(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"coupons\":[\"SAVE10\"],\"subtotal\":100.00,\"cart_discounts\":[{\"promotion\":\"SAVE10\",\"amount\":10.00}],\"total\":90.00}\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, cartId, receivedCommand.CartId)
+	assert.Equal(t, "SAVE10", receivedCommand.Code)
+}
+
+func Test_GivenAnUnknownCoupon_WhenApplyCoupon_ThenReturn404(t *testing.T) {
+	cartServiceMock := &cartServiceMock{
+		applyCoupon: func(command application.ApplyCouponCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewNotFoundError(command.Code, "coupon")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(`{"code":"SAVE10"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/coupons")
+	c.SetParamNames("cartId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.ApplyCoupon(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, "coupon with id SAVE10 not found", err.Message)
+	}
+}
+
+func Test_GivenAnApplyCouponRequestWithoutCode_WhenApplyCoupon_ThenReturnValidationError(t *testing.T) {
+	cartServiceMock := &cartServiceMock{}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(`{}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/coupons")
+	c.SetParamNames("cartId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.ApplyCoupon(c)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, cartServiceMock.callCount)
+}
+
+func Test_GivenAnAppliedCoupon_WhenRemoveCoupon_ThenReturn200AndPassTheCodeToTheService(t *testing.T) {
+	cartId := uuid.New()
+	var receivedCommand application.RemoveCouponCommand
+	cartServiceMock := &cartServiceMock{
+		removeCoupon: func(command application.RemoveCouponCommand) (application.CartDto, error) {
+			receivedCommand = command
+			return application.CartDto{Id: command.CartId, Items: []application.ItemDto{}}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/coupons/:code")
+	c.SetParamNames("cartId", "code")
+	c.SetParamValues(cartId.String(), "SAVE10")
+
+	if assert.NoError(t, controller.RemoveCoupon(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, cartId, receivedCommand.CartId)
+	assert.Equal(t, "SAVE10", receivedCommand.Code)
+}
+
+func Test_GivenACouponThatIsNotApplied_WhenRemoveCoupon_ThenReturn404(t *testing.T) {
+	cartServiceMock := &cartServiceMock{
+		removeCoupon: func(command application.RemoveCouponCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewNotFoundError(command.Code, "coupon")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/coupons/:code")
+	c.SetParamNames("cartId", "code")
+	c.SetParamValues(uuid.New().String(), "SAVE10")
+
+	err := controller.RemoveCoupon(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+	}
+}
+
+func Test_GivenACartWithItems_WhenGetShippingOptions_ThenReturn200AndTheQuotes(t *testing.T) {
+	cartId := uuid.New()
+	var receivedQuery application.GetShippingOptionsQuery
+	cartServiceMock := &cartServiceMock{
+		getShippingOptions: func(query application.GetShippingOptionsQuery) ([]application.ShippingOptionDto, error) {
+			receivedQuery = query
+			return []application.ShippingOptionDto{
+				{Method: "standard", Name: "Standard", Cost: application.PriceDto(testutil.USD("5.00")), Currency: "USD", Selected: true},
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/shipping-options")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.GetShippingOptions(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "[{\"method\":\"standard\",\"name\":\"Standard\",\"cost\":5.00,\"currency\":\"USD\",\"selected\":true}]\n", rec.Body.String())
+	}
+	assert.Equal(t, cartId, receivedQuery.CartId)
+}
+
+func Test_GivenAnEmptyCart_WhenGetShippingOptions_ThenReturn400(t *testing.T) {
+	cartServiceMock := &cartServiceMock{
+		getShippingOptions: func(query application.GetShippingOptionsQuery) ([]application.ShippingOptionDto, error) {
+			return nil, application.NewInvalidArgumentError("cart", "it is empty")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/shipping-options")
+	c.SetParamNames("cartId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.GetShippingOptions(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+	}
+}
+
+func Test_GivenAValidSelectShippingRequest_WhenSelectShipping_ThenReturn200AndTheCartWithShipping(t *testing.T) {
+	cartId := uuid.New()
+	customerId := uuid.New()
+	var receivedCommand application.SelectShippingCommand
+	cartServiceMock := &cartServiceMock{
+		selectShipping: func(command application.SelectShippingCommand) (application.CartDto, error) {
+			receivedCommand = command
+			return application.CartDto{
+				Id:             command.CartId,
+				CustomerId:     customerId,
+				Currency:       "USD",
+				Status:         "active",
+				LastActivityAt: lastActivityAt,
+				Items:          []application.ItemDto{},
+				Subtotal:       application.PriceDto(testutil.USD("100.00")),
+				Shipping:       &application.ShippingDto{Method: "standard", Name: "Standard", Cost: application.PriceDto(testutil.USD("5.00"))},
+				Total:          application.PriceDto(testutil.USD("105.00")),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/carts", strings.NewReader(`{"method":"standard"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/shipping")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.SelectShipping(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"status\":\"active\",\"last_activity_at\":\"2024-01-02T03:04:05Z\",\"items\":[],\"subtotal\":100.00,\"shipping\":{\"method\":\"standard\",\"name\":\"Standard\",\"cost\":5.00},\"total\":105.00}\n", cartId.String(), customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, cartId, receivedCommand.CartId)
+	assert.Equal(t, "standard", receivedCommand.Method)
+}
+
+func Test_GivenAnUnknownShippingMethod_WhenSelectShipping_ThenReturn404(t *testing.T) {
+	cartServiceMock := &cartServiceMock{
+		selectShipping: func(command application.SelectShippingCommand) (application.CartDto, error) {
+			return application.CartDto{}, application.NewNotFoundError(command.Method, "shipping method")
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/carts", strings.NewReader(`{"method":"overnight"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/shipping")
+	c.SetParamNames("cartId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.SelectShipping(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, "shipping method with id overnight not found", err.Message)
+	}
+}
+
+func Test_GivenAnIfMatchHeader_WhenAddItemToCart_ThenTheExpectedVersionIsPassedAndTheNewETagIsReturned(t *testing.T) {
+	cartId := uuid.New()
+	productId := uuid.New()
+	var expectedVersion *int
+	cartServiceMock := &cartServiceMock{
+		addItemToCart: func(command application.AddItemToCartCommand) (application.CartDto, error) {
+			expectedVersion = command.ExpectedVersion
+			return application.CartDto{Id: cartId, Version: 5}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(
+		fmt.Sprintf(`{"product_id":"%s","quantity":2}`, productId.String())))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Add("If-Match", `"4"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.AddItemToCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+		assert.NotContains(t, rec.Body.String(), "version")
+	}
+	if assert.NotNil(t, expectedVersion) {
+		assert.Equal(t, 4, *expectedVersion)
+	}
+}
+
+func Test_GivenAnInvalidIfMatchHeader_WhenClearCart_ThenReturn400WithoutCallingTheService(t *testing.T) {
+	cartServiceMock := &cartServiceMock{}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	for _, ifMatch := range []string{`W/"4"`, `"four"`, `4`, `"-1"`} {
+		e := echo.New()
+		e.Validator = config.NewRequestValidator()
+		request := httptest.NewRequest(http.MethodDelete, "/carts", nil)
+		request.Header.Add("If-Match", ifMatch)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(request, rec)
+		c.SetPath("/carts/:cartId/items")
+		c.SetParamNames("cartId")
+		c.SetParamValues(uuid.New().String())
+
+		err := controller.ClearCart(c)
+		if assert.Error(t, err, ifMatch) {
+			err := err.(*echo.HTTPError)
+			assert.Equal(t, http.StatusBadRequest, err.Code, ifMatch)
+			assert.Equal(t, "invalid If-Match header", err.Message, ifMatch)
+		}
+	}
+	assert.Equal(t, 0, cartServiceMock.callCount)
+}
+
+func Test_GivenAStaleOrConflictingCart_WhenSelectShipping_ThenReturn412Or409(t *testing.T) {
+	cartId := uuid.New()
+	for expectedCode, serviceErr := range map[int]error{
+		http.StatusPreconditionFailed: application.NewPreconditionFailedError(cartId.String(), "cart", 1, 2),
+		http.StatusConflict:           application.NewConcurrencyConflictError(cartId.String()),
+	} {
+		cartServiceMock := &cartServiceMock{
+			selectShipping: func(application.SelectShippingCommand) (application.CartDto, error) {
+				return application.CartDto{}, serviceErr
+			},
+		}
+		controller, _ := controllers.NewCartController(cartServiceMock)
+
+		e := echo.New()
+		e.Validator = config.NewRequestValidator()
+		request := httptest.NewRequest(http.MethodPut, "/carts", strings.NewReader(`{"method":"standard"}`))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Add("If-Match", `"1"`)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(request, rec)
+		c.SetPath("/carts/:cartId/shipping")
+		c.SetParamNames("cartId")
+		c.SetParamValues(cartId.String())
+
+		err := controller.SelectShipping(c)
+		if assert.Error(t, err) {
+			err := err.(*echo.HTTPError)
+			assert.Equal(t, expectedCode, err.Code)
+			assert.Equal(t, serviceErr.Error(), err.Message)
+		}
+	}
+}
+
+func Test_GivenAnExistingCart_WhenGetCart_ThenReturnItsVersionAsETag(t *testing.T) {
+	cartId := uuid.New()
+	cartServiceMock := &cartServiceMock{
+		getCart: func(application.GetCartQuery) (application.CartDto, error) {
+			return application.CartDto{Id: cartId, Version: 12}, nil
+		},
+	}
+	controller, _ := controllers.NewCartController(cartServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.GetCart(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"12"`, rec.Header().Get("ETag"))
+	}
+}
+
+type cartServiceMock struct {
+	callCount          int
+	createNewCart      func(application.CreateCartCommand) (application.CartDto, error)
+	addItemToCart      func(application.AddItemToCartCommand) (application.CartDto, error)
+	removeItemFromCart func(application.RemoveItemFromCartCommand) (application.CartDto, error)
+	updateItemQuantity func(application.UpdateItemQuantityCommand) (application.CartDto, error)
+	clearCart          func(application.ClearCartCommand) (application.CartDto, error)
+	applyCoupon        func(application.ApplyCouponCommand) (application.CartDto, error)
+	removeCoupon       func(application.RemoveCouponCommand) (application.CartDto, error)
+	getShippingOptions func(application.GetShippingOptionsQuery) ([]application.ShippingOptionDto, error)
+	selectShipping     func(application.SelectShippingCommand) (application.CartDto, error)
+	getCart            func(application.GetCartQuery) (application.CartDto, error)
+	getCustomerCarts   func(application.GetCustomerCartsQuery) ([]application.CartDto, error)
+}
+
+func (c *cartServiceMock) CreateNewCart(command application.CreateCartCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.createNewCart(command)
+}
+
+func (c *cartServiceMock) AddItemToCart(command application.AddItemToCartCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.addItemToCart(command)
+}
+
+func (c *cartServiceMock) RemoveItemFromCart(command application.RemoveItemFromCartCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.removeItemFromCart(command)
+}
+
+func (c *cartServiceMock) UpdateItemQuantity(command application.UpdateItemQuantityCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.updateItemQuantity(command)
+}
+
+func (c *cartServiceMock) ClearCart(command application.ClearCartCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.clearCart(command)
+}
+
+func (c *cartServiceMock) ApplyCoupon(command application.ApplyCouponCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.applyCoupon(command)
+}
+
+func (c *cartServiceMock) RemoveCoupon(command application.RemoveCouponCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.removeCoupon(command)
+}
+
+func (c *cartServiceMock) GetShippingOptions(query application.GetShippingOptionsQuery) ([]application.ShippingOptionDto, error) {
+	c.callCount++
+	return c.getShippingOptions(query)
+}
+
+func (c *cartServiceMock) SelectShipping(command application.SelectShippingCommand) (application.CartDto, error) {
+	c.callCount++
+	return c.selectShipping(command)
+}
+
+func (c *cartServiceMock) GetCart(query application.GetCartQuery) (application.CartDto, error) {
+	c.callCount++
+	return c.getCart(query)
+}
+
+func (c *cartServiceMock) GetCustomerCarts(query application.GetCustomerCartsQuery) ([]application.CartDto, error) {
+	c.callCount++
+	return c.getCustomerCarts(query)
 }
@@ -37,8 +37,9 @@ func Test_GivenACreateCustomerRequest_WhenCreateNewCustomer_ThenReturn200AndCust
 	customerServiceMock := &customerServiceMock{
 		createNewCustomer: func(_ application.CreateCustomerCommand) (application.CustomerDto, error) {
 			return application.CustomerDto{
-				Id:   newCustomerId,
-				Name: "Martin Fowler",
+				Id:     newCustomerId,
+				Name:   "Martin Fowler",
+				Active: true,
 			}, nil
 		},
 	}
@@ -53,7 +54,7 @@ func Test_GivenACreateCustomerRequest_WhenCreateNewCustomer_ThenReturn200AndCust
 
 	if assert.NoError(t, customerController.CreateNewCustomer(c)) {
 		assert.Equal(t, http.StatusCreated, rec.Code)
-		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Martin Fowler\"}\n", newCustomerId.String()), rec.Body.String())
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Martin Fowler\",\"active\":true}\n", newCustomerId.String()), rec.Body.String())
 	}
 	assert.Equal(t, 1, customerServiceMock.callCount)
 }
@@ -162,12 +163,258 @@ func Test_GivenAValidCreateCustomerRequestButCustomerServiceFailsToCreateNewCust
 	assert.Equal(t, 1, customerServiceMock.callCount)
 }
 
+func Test_GivenAnExistingCustomer_WhenGetCustomer_ThenReturn200AndTheCustomerDto(t *testing.T) {
+	customerId := uuid.New()
+	customerServiceMock := &customerServiceMock{
+		getCustomer: func(query application.GetCustomerQuery) (application.CustomerDto, error) {
+			return application.CustomerDto{
+				Id:     query.CustomerId,
+				Name:   "Linus Torvalds",
+				Active: true,
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/customers", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	if assert.NoError(t, controller.GetCustomer(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Linus Torvalds\",\"active\":true}\n", customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, customerServiceMock.callCount)
+}
+
+func Test_GivenANonExistantCustomer_WhenGetCustomer_ThenReturn404(t *testing.T) {
+	customerId := uuid.New()
+	customerServiceMock := &customerServiceMock{
+		getCustomer: func(query application.GetCustomerQuery) (application.CustomerDto, error) {
+			return application.CustomerDto{}, application.NewNotFoundError(query.CustomerId.String(), "customer")
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/customers", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	err := controller.GetCustomer(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("customer with id %s not found", customerId.String()), err.Message)
+	}
+}
+
+func Test_GivenAnUpdateCustomerRequest_WhenUpdateCustomer_ThenReturn200AndTheUpdatedCustomerDto(t *testing.T) {
+	customerId := uuid.New()
+	var receivedCommand application.UpdateCustomerCommand
+	customerServiceMock := &customerServiceMock{
+		updateCustomer: func(command application.UpdateCustomerCommand) (application.CustomerDto, error) {
+			receivedCommand = command
+			return application.CustomerDto{
+				Id:     command.CustomerId,
+				Name:   "Linus Torvalds",
+				Email:  *command.Email,
+				Active: true,
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/customers", strings.NewReader(`{"email":"linus@example.com"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	if assert.NoError(t, controller.UpdateCustomer(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Linus Torvalds\",\"email\":\"linus@example.com\",\"active\":true}\n", customerId.String()), rec.Body.String())
+	}
+	assert.Equal(t, customerId, receivedCommand.CustomerId)
+	assert.Nil(t, receivedCommand.CustomerName)
+}
+
+func Test_GivenAnInvalidEmailOrAddress_WhenUpdateCustomer_ThenReturn400(t *testing.T) {
+	tests := []struct {
+		testName    string
+		requestBody string
+	}{
+		{"invalid email", `{"email":"linus"}`},
+		{"incomplete address", `{"shipping_address":{"street":"1 Kernel Way"}}`},
+		{"invalid country", `{"billing_address":{"street":"1 Kernel Way","city":"Portland","postal_code":"97201","country":"USA"}}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			customerServiceMock := &customerServiceMock{}
+			controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+			e := echo.New()
+			e.Validator = config.NewRequestValidator()
+			request := httptest.NewRequest(http.MethodPatch, "/customers", strings.NewReader(tc.requestBody))
+			request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(request, rec)
+			c.SetPath("/customers/:customerId")
+			c.SetParamNames("customerId")
+			c.SetParamValues(uuid.New().String())
+
+			err := controller.UpdateCustomer(c)
+			if assert.Error(t, err) {
+				err := err.(*echo.HTTPError)
+				assert.Equal(t, http.StatusBadRequest, err.Code)
+			}
+			assert.Equal(t, 0, customerServiceMock.callCount)
+		})
+	}
+}
+
+func Test_GivenAnEmailInUse_WhenUpdateCustomer_ThenReturn409(t *testing.T) {
+	customerServiceMock := &customerServiceMock{
+		updateCustomer: func(command application.UpdateCustomerCommand) (application.CustomerDto, error) {
+			return application.CustomerDto{}, application.NewConflictError("email", "ken@example.com")
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/customers", strings.NewReader(`{"email":"ken@example.com"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.UpdateCustomer(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusConflict, err.Code)
+		assert.Equal(t, application.ConflictDto{Message: `email "ken@example.com" is already in use`, Field: "email"}, err.Message)
+	}
+}
+
+func Test_GivenAnExistingCustomer_WhenDeactivateCustomer_ThenReturn200AndTheDeactivatedCustomerDto(t *testing.T) {
+	customerId := uuid.New()
+	customerServiceMock := &customerServiceMock{
+		deactivateCustomer: func(command application.DeactivateCustomerCommand) (application.CustomerDto, error) {
+			return application.CustomerDto{
+				Id:   command.CustomerId,
+				Name: "Linus Torvalds",
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/customers", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	if assert.NoError(t, controller.DeactivateCustomer(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Linus Torvalds\",\"active\":false}\n", customerId.String()), rec.Body.String())
+	}
+}
+
+func Test_GivenANonExistantCustomer_WhenDeactivateCustomer_ThenReturn404(t *testing.T) {
+	customerServiceMock := &customerServiceMock{
+		deactivateCustomer: func(command application.DeactivateCustomerCommand) (application.CustomerDto, error) {
+			return application.CustomerDto{}, application.NewNotFoundError(command.CustomerId.String(), "customer")
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/customers", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.DeactivateCustomer(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+	}
+}
+
+func Test_GivenACustomerModifiedConcurrently_WhenUpdateCustomer_ThenReturn409(t *testing.T) {
+	customerId := uuid.New()
+	customerServiceMock := &customerServiceMock{
+		updateCustomer: func(command application.UpdateCustomerCommand) (application.CustomerDto, error) {
+			return application.CustomerDto{}, application.NewConcurrencyConflictError(customerId.String())
+		},
+	}
+	controller, _ := controllers.NewCustomerController(customerServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/customers", strings.NewReader(`{"phone":"+5491155555555"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/customers/:customerId")
+	c.SetParamNames("customerId")
+	c.SetParamValues(customerId.String())
+
+	err := controller.UpdateCustomer(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusConflict, err.Code)
+		assert.Equal(t, fmt.Sprintf("%s was modified concurrently, reload it and try again", customerId.String()), err.Message)
+	}
+}
+
 type customerServiceMock struct {
-	callCount         int
-	createNewCustomer func(application.CreateCustomerCommand) (application.CustomerDto, error)
+	callCount          int
+	createNewCustomer  func(application.CreateCustomerCommand) (application.CustomerDto, error)
+	getCustomer        func(application.GetCustomerQuery) (application.CustomerDto, error)
+	updateCustomer     func(application.UpdateCustomerCommand) (application.CustomerDto, error)
+	deactivateCustomer func(application.DeactivateCustomerCommand) (application.CustomerDto, error)
 }
 
 func (c *customerServiceMock) CreateNewCustomer(command application.CreateCustomerCommand) (application.CustomerDto, error) {
 	c.callCount++
 	return c.createNewCustomer(command)
 }
+
+func (c *customerServiceMock) GetCustomer(query application.GetCustomerQuery) (application.CustomerDto, error) {
+	c.callCount++
+	return c.getCustomer(query)
+}
+
+func (c *customerServiceMock) UpdateCustomer(command application.UpdateCustomerCommand) (application.CustomerDto, error) {
+	c.callCount++
+	return c.updateCustomer(command)
+}
+
+func (c *customerServiceMock) DeactivateCustomer(command application.DeactivateCustomerCommand) (application.CustomerDto, error) {
+	c.callCount++
+	return c.deactivateCustomer(command)
+}
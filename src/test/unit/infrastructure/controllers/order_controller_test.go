@@ -0,0 +1,189 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilOrderService_WhenNewOrderController_ThenReturnError(t *testing.T) {
+	controller, err := controllers.NewOrderController(nil)
+
+	assert.Nil(t, controller)
+	if assert.Error(t, err) {
+		assert.Equal(t, "order service was nil", err.Error())
+	}
+}
+
+func Test_GivenACartWithItems_WhenCheckoutCart_ThenReturn201AndTheOrderDto(t *testing.T) {
+	cartId := uuid.New()
+	orderId := uuid.New()
+	customerId := uuid.New()
+	productId := uuid.New()
+	orderServiceMock := &orderServiceMock{
+		checkoutCart: func(command application.CheckoutCartCommand) (application.OrderDto, error) {
+			return application.OrderDto{
+				Id:         orderId,
+				CartId:     command.CartId,
+				CustomerId: customerId,
+				Currency:   "USD",
+				Lines: []application.LineDto{
+					{ProductId: productId, UnitPrice: application.PriceDto(testutil.USD("11.10")), Currency: "USD", Quantity: 2, Total: application.PriceDto(testutil.USD("22.20"))},
+				},
+				Subtotal: application.PriceDto(testutil.USD("22.20")),
+				LineDiscounts: []application.DiscountDto{
+					{Promotion: "launch", ProductId: &productId, Amount: application.PriceDto(testutil.USD("2.00"))},
+				},
+				Total:    application.PriceDto(testutil.USD("20.20")),
+				PlacedOn: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewOrderController(orderServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/checkout")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.CheckoutCart(c)) {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"cart_id\":\"%s\",\"customer_id\":\"%s\",\"currency\":\"USD\",\"lines\":[{\"product_id\":\"%s\",\"unit_price\":11.10,\"currency\":\"USD\",\"quantity\":2,\"total\":22.20}],\"subtotal\":22.20,\"line_discounts\":[{\"promotion\":\"launch\",\"product_id\":\"%s\",\"amount\":2.00}],\"total\":20.20,\"placed_on\":\"2024-01-02T03:04:05Z\"}\n",
+			orderId.String(), cartId.String(), customerId.String(), productId.String(), productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, orderServiceMock.callCount)
+}
+
+func Test_GivenACheckedOutCart_WhenCheckoutCart_ThenReturn400(t *testing.T) {
+	orderServiceMock := &orderServiceMock{
+		checkoutCart: func(command application.CheckoutCartCommand) (application.OrderDto, error) {
+			return application.OrderDto{}, application.NewInvalidArgumentError("cart", "it is already checked out")
+		},
+	}
+	controller, _ := controllers.NewOrderController(orderServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/checkout")
+	c.SetParamNames("cartId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.CheckoutCart(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, "invalid cart: it is already checked out", err.Message)
+	}
+}
+
+func Test_GivenAnInvalidOrderId_WhenGetOrder_ThenReturn400(t *testing.T) {
+	orderServiceMock := &orderServiceMock{}
+	controller, _ := controllers.NewOrderController(orderServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/orders/:orderId")
+	c.SetParamNames("orderId")
+	c.SetParamValues("not-a-uuid")
+
+	err := controller.GetOrder(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+	}
+	assert.Equal(t, 0, orderServiceMock.callCount)
+}
+
+func Test_GivenANonExistantOrder_WhenGetOrder_ThenReturn404(t *testing.T) {
+	orderId := uuid.New()
+	orderServiceMock := &orderServiceMock{
+		getOrder: func(query application.GetOrderQuery) (application.OrderDto, error) {
+			return application.OrderDto{}, application.NewNotFoundError(query.OrderId.String(), "order")
+		},
+	}
+	controller, _ := controllers.NewOrderController(orderServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/orders/:orderId")
+	c.SetParamNames("orderId")
+	c.SetParamValues(orderId.String())
+
+	err := controller.GetOrder(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("order with id %s not found", orderId.String()), err.Message)
+	}
+}
+
+type orderServiceMock struct {
+	callCount    int
+	checkoutCart func(application.CheckoutCartCommand) (application.OrderDto, error)
+	getOrder     func(application.GetOrderQuery) (application.OrderDto, error)
+}
+
+func (s *orderServiceMock) CheckoutCart(command application.CheckoutCartCommand) (application.OrderDto, error) {
+	s.callCount++
+	return s.checkoutCart(command)
+}
+
+func (s *orderServiceMock) GetOrder(query application.GetOrderQuery) (application.OrderDto, error) {
+	s.callCount++
+	return s.getOrder(query)
+}
+
+func Test_GivenAStaleIfMatchHeader_WhenCheckoutCart_ThenPassTheExpectedVersionAndReturn412(t *testing.T) {
+	cartId := uuid.New()
+	var expectedVersion *int
+	orderServiceMock := &orderServiceMock{
+		checkoutCart: func(command application.CheckoutCartCommand) (application.OrderDto, error) {
+			expectedVersion = command.ExpectedVersion
+			return application.OrderDto{}, application.NewPreconditionFailedError(cartId.String(), "cart", 3, 4)
+		},
+	}
+	controller, _ := controllers.NewOrderController(orderServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", nil)
+	request.Header.Add("If-Match", `"3"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/checkout")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	err := controller.CheckoutCart(c)
+
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusPreconditionFailed, err.Code)
+	}
+	if assert.NotNil(t, expectedVersion) {
+		assert.Equal(t, 3, *expectedVersion)
+	}
+}
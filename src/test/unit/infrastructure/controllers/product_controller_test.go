@@ -11,6 +11,7 @@ import (
 	"github.com/bitlogic/go-startup/src/application"
 	"github.com/bitlogic/go-startup/src/infrastructure/config"
 	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -40,7 +41,8 @@ func Test_GivenANewProductRequest_WhenCreateNewProduct_ThenReturn201AndANewProdu
 			return application.ProductDto{
 				Id:        newProductId,
 				Name:      command.ProductName,
-				UnitPrice: application.PriceDto(command.UnitPrice),
+				UnitPrice: application.PriceDto(testutil.USD(string(command.UnitPrice))),
+				Currency:  "USD",
 			}, nil
 		},
 	}
@@ -48,14 +50,14 @@ func Test_GivenANewProductRequest_WhenCreateNewProduct_ThenReturn201AndANewProdu
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":0.01}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":0.01,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
 
 	if assert.NoError(t, controller.CreateNewProduct(c)) {
 		assert.Equal(t, http.StatusCreated, rec.Code)
-		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":0.01}\n", newProductId.String()), rec.Body.String())
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":0.01,\"currency\":\"USD\",\"archived\":false}\n", newProductId.String()), rec.Body.String())
 	}
 	assert.Equal(t, 1, productServiceMock.callCount)
 }
@@ -66,7 +68,7 @@ func Test_GivenANewProductRequestWithNoPrice_WhenCreateNewProduct_ThenReturn400E
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt"}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -94,7 +96,7 @@ func Test_GivenANewProductRequestWithInvalidPriceAndInvalidName_WhenCreateNewPro
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi","unit_price":0}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi","unit_price":0,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -126,7 +128,7 @@ func Test_GivenANewProductRequestWithInvalidPriceAndNoName_WhenCreateNewProduct_
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":0}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":0,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -158,7 +160,7 @@ func Test_GivenANewProductRequestWithNegativePriceAndNoName_WhenCreateNewProduct
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":-1}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":-1,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -191,7 +193,7 @@ func Test_GivenANewProductRequestWithInvalidUnitPriceType_WhenCreateNewProduct_T
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":"123"}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"unit_price":"abc","sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -200,7 +202,7 @@ func Test_GivenANewProductRequestWithInvalidUnitPriceType_WhenCreateNewProduct_T
 	if assert.Error(t, err) {
 		err := err.(*echo.HTTPError)
 		assert.Equal(t, http.StatusBadRequest, err.Code)
-		assert.Equal(t, string("Unmarshal type error: expected=float64, got=string, field=unit_price, offset=19"), err.Message)
+		assert.Equal(t, "amount must be a number or a numeric string", err.Message)
 	}
 	assert.Equal(t, 0, productServiceMock.callCount)
 
@@ -212,7 +214,7 @@ func Test_GivenANewProductRequestWithInvalidNameType_WhenCreateNewProduct_ThenRe
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":123}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":123,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -237,7 +239,7 @@ func Test_GivenProductServiceFailsToCreateNewProduct_WhenCreateNewProduct_ThenRe
 
 	e := echo.New()
 	e.Validator = config.NewRequestValidator()
-	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":0.01}`))
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":0.01,"sku":"PEP-25"}`))
 	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(request, rec)
@@ -252,12 +254,591 @@ func Test_GivenProductServiceFailsToCreateNewProduct_WhenCreateNewProduct_ThenRe
 
 }
 
+func Test_GivenAProductNameInUse_WhenCreateNewProduct_ThenReturn409(t *testing.T) {
+	productServiceMock := &productServiceMock{
+		createNewProduct: func(application.CreateProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewConflictError("product_name", "pepsi light 2.5lt")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{"product_name":"Pepsi Light 2.5Lt","unit_price":0.01,"sku":"PEP-25"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+
+	err := controller.CreateNewProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusConflict, err.Code)
+		assert.Equal(t, application.ConflictDto{Message: `product_name "pepsi light 2.5lt" is already in use`, Field: "product_name"}, err.Message)
+	}
+	assert.Equal(t, 1, productServiceMock.callCount)
+}
+
+func Test_GivenAnExistingProduct_WhenGetProduct_ThenReturn200AndTheProductDto(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		getProduct: func(query application.GetProductQuery) (application.ProductDto, error) {
+			return application.ProductDto{
+				Id:        query.ProductId,
+				Name:      "Pepsi Light 2.5Lt",
+				UnitPrice: application.PriceDto(testutil.USD("1.10")),
+				Currency:  "USD",
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.GetProduct(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":1.10,\"currency\":\"USD\",\"archived\":false}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, productServiceMock.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenGetProduct_ThenReturn404(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		getProduct: func(query application.GetProductQuery) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewNotFoundError(query.ProductId.String(), "product")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	err := controller.GetProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", productId.String()), err.Message)
+	}
+}
+
+func Test_GivenAnExistingSKU_WhenGetProductBySKU_ThenReturn200AndTheProductDto(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		getProductBySKU: func(query application.GetProductBySKUQuery) (application.ProductDto, error) {
+			return application.ProductDto{
+				Id:        productId,
+				SKU:       query.SKU,
+				Name:      "Pepsi Light 2.5Lt",
+				UnitPrice: application.PriceDto(testutil.USD("1.10")),
+				Currency:  "USD",
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/by-sku/:sku")
+	c.SetParamNames("sku")
+	c.SetParamValues("PEP-25")
+
+	if assert.NoError(t, controller.GetProductBySKU(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"sku\":\"PEP-25\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":1.10,\"currency\":\"USD\",\"archived\":false}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, productServiceMock.callCount)
+}
+
+func Test_GivenAnUnknownOrInvalidSKU_WhenGetProductBySKU_ThenReturn404Or400(t *testing.T) {
+	tests := []struct {
+		testName        string
+		serviceError    error
+		expectedCode    int
+		expectedMessage string
+	}{
+		{
+			testName:        "unknown sku",
+			serviceError:    application.NewNotFoundError("PEP-25", "product"),
+			expectedCode:    http.StatusNotFound,
+			expectedMessage: "product with id PEP-25 not found",
+		},
+		{
+			testName:        "invalid sku",
+			serviceError:    application.NewInvalidArgumentError("sku", "invalid sku"),
+			expectedCode:    http.StatusBadRequest,
+			expectedMessage: "invalid sku: invalid sku",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			productServiceMock := &productServiceMock{
+				getProductBySKU: func(query application.GetProductBySKUQuery) (application.ProductDto, error) {
+					return application.ProductDto{}, tc.serviceError
+				},
+			}
+			controller, _ := controllers.NewProductController(productServiceMock)
+
+			e := echo.New()
+			e.Validator = config.NewRequestValidator()
+			request := httptest.NewRequest(http.MethodGet, "/products", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(request, rec)
+			c.SetPath("/products/by-sku/:sku")
+			c.SetParamNames("sku")
+			c.SetParamValues("PEP-25")
+
+			err := controller.GetProductBySKU(c)
+			if assert.Error(t, err) {
+				err := err.(*echo.HTTPError)
+				assert.Equal(t, tc.expectedCode, err.Code)
+				assert.Equal(t, tc.expectedMessage, err.Message)
+			}
+		})
+	}
+}
+
+func Test_GivenAnInvalidProductId_WhenGetProduct_ThenReturn400(t *testing.T) {
+	productServiceMock := &productServiceMock{}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues("not-a-uuid")
+
+	err := controller.GetProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+	}
+	assert.Equal(t, 0, productServiceMock.callCount)
+}
+
+func Test_GivenQueryParameters_WhenListProducts_ThenReturn200AndAProductPage(t *testing.T) {
+	productId := uuid.New()
+	nextCursor := "abc"
+	var receivedQuery application.ListProductsQuery
+	productServiceMock := &productServiceMock{
+		listProducts: func(query application.ListProductsQuery) (application.ProductPageDto, error) {
+			receivedQuery = query
+			return application.ProductPageDto{
+				Items: []application.ProductDto{
+					{Id: productId, Name: "Pepsi Light 2.5Lt", UnitPrice: application.PriceDto(testutil.USD("1.10")), Currency: "USD"},
+				},
+				NextCursor: &nextCursor,
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products?q=pepsi&min_price=1&max_price=2.5&sort=-price&cursor=xyz&limit=1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+
+	if assert.NoError(t, controller.ListProducts(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"items\":[{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":1.10,\"currency\":\"USD\",\"archived\":false}],\"next_cursor\":\"abc\"}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, application.ListProductsQuery{
+		Search:   "pepsi",
+		MinPrice: "1",
+		MaxPrice: "2.5",
+		Sort:     "-price",
+		Cursor:   "xyz",
+		Limit:    1,
+	}, receivedQuery)
+}
+
+func Test_GivenAnUnknownSort_WhenListProducts_ThenReturn400(t *testing.T) {
+	productServiceMock := &productServiceMock{}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products?sort=weight", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+
+	err := controller.ListProducts(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, &config.ValidationErrorsResponse{
+			Message: "there were validation errors",
+			Errors: []config.FieldError{
+				{
+					Field: "Sort",
+					Error: "Sort must be one of [name -name price -price]",
+				},
+			},
+		}, err.Message)
+	}
+	assert.Equal(t, 0, productServiceMock.callCount)
+}
+
+func Test_GivenAnInvalidCursor_WhenListProducts_ThenReturn400(t *testing.T) {
+	productServiceMock := &productServiceMock{
+		listProducts: func(query application.ListProductsQuery) (application.ProductPageDto, error) {
+			return application.ProductPageDto{}, application.NewInvalidArgumentError("cursor", "it does not belong to this listing")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products?cursor=garbage", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+
+	err := controller.ListProducts(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, "invalid cursor: it does not belong to this listing", err.Message)
+	}
+}
+
+func Test_GivenAnUpdateProductRequest_WhenUpdateProduct_ThenReturn200AndTheUpdatedProductDto(t *testing.T) {
+	productId := uuid.New()
+	var receivedCommand application.UpdateProductCommand
+	productServiceMock := &productServiceMock{
+		updateProduct: func(command application.UpdateProductCommand) (application.ProductDto, error) {
+			receivedCommand = command
+			return application.ProductDto{
+				Id:        command.ProductId,
+				Name:      *command.ProductName,
+				UnitPrice: application.PriceDto(testutil.USD(string(command.UnitPrice))),
+				Currency:  "USD",
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/products", strings.NewReader(`{"product_name":"Pepsi Light 3Lt","unit_price":1.25}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.UpdateProduct(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 3Lt\",\"unit_price\":1.25,\"currency\":\"USD\",\"archived\":false}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, productId, receivedCommand.ProductId)
+	assert.Equal(t, application.AmountDto("1.25"), receivedCommand.UnitPrice)
+}
+
+func Test_GivenAShortProductName_WhenUpdateProduct_ThenReturn400(t *testing.T) {
+	productServiceMock := &productServiceMock{}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/products", strings.NewReader(`{"product_name":"Pepsi"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.UpdateProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+	}
+	assert.Equal(t, 0, productServiceMock.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenUpdateProduct_ThenReturn404(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		updateProduct: func(command application.UpdateProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewNotFoundError(command.ProductId.String(), "product")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/products", strings.NewReader(`{"unit_price":2}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	err := controller.UpdateProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+		assert.Equal(t, fmt.Sprintf("product with id %s not found", productId.String()), err.Message)
+	}
+}
+
+func Test_GivenAProductNameInUse_WhenUpdateProduct_ThenReturn409(t *testing.T) {
+	productServiceMock := &productServiceMock{
+		updateProduct: func(command application.UpdateProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewConflictError("product_name", "coca cola 1.5lt")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/products", strings.NewReader(`{"product_name":"Coca Cola 1.5Lt"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.UpdateProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusConflict, err.Code)
+		assert.Equal(t, application.ConflictDto{Message: `product_name "coca cola 1.5lt" is already in use`, Field: "product_name"}, err.Message)
+	}
+}
+
+func Test_GivenACurrencyWithoutPrice_WhenUpdateProduct_ThenReturn400(t *testing.T) {
+	productServiceMock := &productServiceMock{
+		updateProduct: func(command application.UpdateProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewInvalidArgumentError("currency", "it can only be changed together with unit_price")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPatch, "/products", strings.NewReader(`{"currency":"EUR"}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.UpdateProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, "invalid currency: it can only be changed together with unit_price", err.Message)
+	}
+}
+
+func Test_GivenAnExistingProduct_WhenArchiveProduct_ThenReturn200AndTheArchivedProductDto(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		archiveProduct: func(command application.ArchiveProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{
+				Id:        command.ProductId,
+				Name:      "Pepsi Light 2.5Lt",
+				UnitPrice: application.PriceDto(testutil.USD("1.10")),
+				Currency:  "USD",
+				Archived:  true,
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.ArchiveProduct(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":1.10,\"currency\":\"USD\",\"archived\":true}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, productServiceMock.callCount)
+}
+
+func Test_GivenANonExistantProduct_WhenArchiveProduct_ThenReturn404(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		archiveProduct: func(command application.ArchiveProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{}, application.NewNotFoundError(command.ProductId.String(), "product")
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	err := controller.ArchiveProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+	}
+}
+
+func Test_GivenAnArchivedProduct_WhenRestoreProduct_ThenReturn200AndTheRestoredProductDto(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		restoreProduct: func(command application.RestoreProductCommand) (application.ProductDto, error) {
+			return application.ProductDto{
+				Id:        command.ProductId,
+				Name:      "Pepsi Light 2.5Lt",
+				UnitPrice: application.PriceDto(testutil.USD("1.10")),
+				Currency:  "USD",
+			}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId/restore")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.RestoreProduct(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"name\":\"Pepsi Light 2.5Lt\",\"unit_price\":1.10,\"currency\":\"USD\",\"archived\":false}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, 1, productServiceMock.callCount)
+}
+
+func Test_GivenAStaleIfMatchHeader_WhenArchiveProduct_ThenReturn412(t *testing.T) {
+	productId := uuid.New()
+	var expectedVersion *int
+	productServiceMock := &productServiceMock{
+		archiveProduct: func(command application.ArchiveProductCommand) (application.ProductDto, error) {
+			expectedVersion = command.ExpectedVersion
+			return application.ProductDto{}, application.NewPreconditionFailedError(productId.String(), "product", 1, 3)
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodDelete, "/products", nil)
+	request.Header.Add("If-Match", `"1"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	err := controller.ArchiveProduct(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusPreconditionFailed, err.Code)
+		assert.Equal(t, fmt.Sprintf("product with id %s is at version 3, not 1", productId.String()), err.Message)
+	}
+	if assert.NotNil(t, expectedVersion) {
+		assert.Equal(t, 1, *expectedVersion)
+	}
+}
+
+func Test_GivenAnExistingProduct_WhenGetProduct_ThenReturnItsVersionAsETag(t *testing.T) {
+	productId := uuid.New()
+	productServiceMock := &productServiceMock{
+		getProduct: func(query application.GetProductQuery) (application.ProductDto, error) {
+			return application.ProductDto{Id: query.ProductId, Version: 3}, nil
+		},
+	}
+	controller, _ := controllers.NewProductController(productServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/products", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.GetProduct(c)) {
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+	}
+}
+
 type productServiceMock struct {
 	callCount        int
 	createNewProduct func(application.CreateProductCommand) (application.ProductDto, error)
+	getProduct       func(application.GetProductQuery) (application.ProductDto, error)
+	getProductBySKU  func(application.GetProductBySKUQuery) (application.ProductDto, error)
+	listProducts     func(application.ListProductsQuery) (application.ProductPageDto, error)
+	updateProduct    func(application.UpdateProductCommand) (application.ProductDto, error)
+	archiveProduct   func(application.ArchiveProductCommand) (application.ProductDto, error)
+	restoreProduct   func(application.RestoreProductCommand) (application.ProductDto, error)
 }
 
 func (s *productServiceMock) CreateNewProduct(command application.CreateProductCommand) (application.ProductDto, error) {
 	s.callCount++
 	return s.createNewProduct(command)
 }
+
+func (s *productServiceMock) GetProduct(query application.GetProductQuery) (application.ProductDto, error) {
+	s.callCount++
+	return s.getProduct(query)
+}
+
+func (s *productServiceMock) GetProductBySKU(query application.GetProductBySKUQuery) (application.ProductDto, error) {
+	s.callCount++
+	return s.getProductBySKU(query)
+}
+
+func (s *productServiceMock) ListProducts(query application.ListProductsQuery) (application.ProductPageDto, error) {
+	s.callCount++
+	return s.listProducts(query)
+}
+
+func (s *productServiceMock) UpdateProduct(command application.UpdateProductCommand) (application.ProductDto, error) {
+	s.callCount++
+	return s.updateProduct(command)
+}
+
+func (s *productServiceMock) ArchiveProduct(command application.ArchiveProductCommand) (application.ProductDto, error) {
+	s.callCount++
+	return s.archiveProduct(command)
+}
+
+func (s *productServiceMock) RestoreProduct(command application.RestoreProductCommand) (application.ProductDto, error) {
+	s.callCount++
+	return s.restoreProduct(command)
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilQuoteService_WhenNewQuoteController_ThenReturnError(t *testing.T) {
+	controller, err := controllers.NewQuoteController(nil)
+
+	assert.Nil(t, controller)
+	if assert.Error(t, err) {
+		assert.Equal(t, "quote service was nil", err.Error())
+	}
+}
+
+func Test_GivenAValidCreateQuoteRequest_WhenCreateQuote_ThenReturn201(t *testing.T) {
+	cartId := uuid.New()
+	var receivedCommand application.CreateQuoteCommand
+	quoteServiceMock := &quoteServiceMock{
+		createQuote: func(command application.CreateQuoteCommand) (application.QuoteDto, error) {
+			receivedCommand = command
+			return application.QuoteDto{Id: uuid.New(), Number: "Q-20240102-000001", CartId: command.CartId, Status: "pending"}, nil
+		},
+	}
+	controller, _ := controllers.NewQuoteController(quoteServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/carts", strings.NewReader(`{"valid_for_hours":24}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/carts/:cartId/quotes")
+	c.SetParamNames("cartId")
+	c.SetParamValues(cartId.String())
+
+	if assert.NoError(t, controller.CreateQuote(c)) {
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"number":"Q-20240102-000001"`)
+	}
+	assert.Equal(t, application.CreateQuoteCommand{CartId: cartId, ValidForHours: 24}, receivedCommand)
+}
+
+func Test_GivenAnExpiredQuote_WhenAcceptQuote_ThenReturn400(t *testing.T) {
+	quoteServiceMock := &quoteServiceMock{
+		acceptQuote: func(command application.AcceptQuoteCommand) (application.QuoteDto, error) {
+			return application.QuoteDto{}, application.NewInvalidArgumentError("quote", "it expired at 2024-01-02T04:04:05Z")
+		},
+	}
+	controller, _ := controllers.NewQuoteController(quoteServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPost, "/quotes", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/quotes/:quoteId/accept")
+	c.SetParamNames("quoteId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.AcceptQuote(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusBadRequest, err.Code)
+		assert.Equal(t, "invalid quote: it expired at 2024-01-02T04:04:05Z", err.Message)
+	}
+}
+
+func Test_GivenANonExistantQuote_WhenGetQuote_ThenReturn404(t *testing.T) {
+	quoteServiceMock := &quoteServiceMock{
+		getQuote: func(query application.GetQuoteQuery) (application.QuoteDto, error) {
+			return application.QuoteDto{}, application.NewNotFoundError(query.QuoteId.String(), "quote")
+		},
+	}
+	controller, _ := controllers.NewQuoteController(quoteServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodGet, "/quotes", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/quotes/:quoteId")
+	c.SetParamNames("quoteId")
+	c.SetParamValues(uuid.New().String())
+
+	err := controller.GetQuote(c)
+	if assert.Error(t, err) {
+		err := err.(*echo.HTTPError)
+		assert.Equal(t, http.StatusNotFound, err.Code)
+	}
+}
+
+type quoteServiceMock struct {
+	callCount    int
+	createQuote  func(application.CreateQuoteCommand) (application.QuoteDto, error)
+	getQuote     func(application.GetQuoteQuery) (application.QuoteDto, error)
+	acceptQuote  func(application.AcceptQuoteCommand) (application.QuoteDto, error)
+	declineQuote func(application.DeclineQuoteCommand) (application.QuoteDto, error)
+}
+
+func (s *quoteServiceMock) CreateQuote(command application.CreateQuoteCommand) (application.QuoteDto, error) {
+	s.callCount++
+	return s.createQuote(command)
+}
+
+func (s *quoteServiceMock) GetQuote(query application.GetQuoteQuery) (application.QuoteDto, error) {
+	s.callCount++
+	return s.getQuote(query)
+}
+
+func (s *quoteServiceMock) AcceptQuote(command application.AcceptQuoteCommand) (application.QuoteDto, error) {
+	s.callCount++
+	return s.acceptQuote(command)
+}
+
+func (s *quoteServiceMock) DeclineQuote(command application.DeclineQuoteCommand) (application.QuoteDto, error) {
+	s.callCount++
+	return s.declineQuote(command)
+}
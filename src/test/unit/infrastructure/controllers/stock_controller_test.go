@@ -0,0 +1,204 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/application"
+	"github.com/bitlogic/go-startup/src/infrastructure/config"
+	"github.com/bitlogic/go-startup/src/infrastructure/controllers"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilStockService_WhenNewStockController_ThenReturnError(t *testing.T) {
+	controller, err := controllers.NewStockController(nil)
+
+	assert.Nil(t, controller)
+	if assert.Error(t, err) {
+		assert.Equal(t, "stock service was nil", err.Error())
+	}
+}
+
+func Test_GivenAValidUpdateStockRequest_WhenUpdateStock_ThenReturn200AndTheStockDto(t *testing.T) {
+	productId := uuid.New()
+	var receivedCommand application.UpdateStockCommand
+	stockServiceMock := &stockServiceMock{
+		updateStock: func(command application.UpdateStockCommand) (application.StockDto, error) {
+			receivedCommand = command
+			return application.StockDto{ProductId: command.ProductId, OnHand: *command.OnHand, Reserved: 2, Available: *command.OnHand - 2}, nil
+		},
+	}
+	controller, _ := controllers.NewStockController(stockServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/products", strings.NewReader(`{"on_hand":10}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId/stock")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.UpdateStock(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, fmt.Sprintf("{\"product_id\":\"%s\",\"on_hand\":10,\"reserved\":2,\"available\":8}\n", productId.String()), rec.Body.String())
+	}
+	assert.Equal(t, productId, receivedCommand.ProductId)
+	assert.Equal(t, 1, stockServiceMock.callCount)
+}
+
+func Test_GivenAnInvalidUpdateStockRequest_WhenUpdateStock_ThenReturn400(t *testing.T) {
+	tests := []struct {
+		testName    string
+		requestBody string
+	}{
+		{testName: "missing on hand", requestBody: `{}`},
+		{testName: "negative on hand", requestBody: `{"on_hand":-1}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			stockServiceMock := &stockServiceMock{}
+			controller, _ := controllers.NewStockController(stockServiceMock)
+
+			e := echo.New()
+			e.Validator = config.NewRequestValidator()
+			request := httptest.NewRequest(http.MethodPut, "/products", strings.NewReader(tc.requestBody))
+			request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(request, rec)
+			c.SetPath("/products/:productId/stock")
+			c.SetParamNames("productId")
+			c.SetParamValues(uuid.New().String())
+
+			err := controller.UpdateStock(c)
+			if assert.Error(t, err) {
+				assert.Equal(t, http.StatusBadRequest, err.(*echo.HTTPError).Code)
+			}
+			assert.Equal(t, 0, stockServiceMock.callCount)
+		})
+	}
+}
+
+func Test_GivenStockServiceErrors_WhenUpdateStock_ThenMapThemToStatusCodes(t *testing.T) {
+	productId := uuid.New()
+	tests := []struct {
+		testName     string
+		err          error
+		expectedCode int
+	}{
+		{testName: "unknown product", err: application.NewNotFoundError(productId.String(), "product"), expectedCode: http.StatusNotFound},
+		{testName: "below reserved", err: application.NewInvalidArgumentError("on_hand", "it is below the 4 units reserved in carts"), expectedCode: http.StatusBadRequest},
+		{testName: "unexpected", err: errors.New("failed to save entity"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			stockServiceMock := &stockServiceMock{
+				updateStock: func(application.UpdateStockCommand) (application.StockDto, error) {
+					return application.StockDto{}, tc.err
+				},
+			}
+			controller, _ := controllers.NewStockController(stockServiceMock)
+
+			e := echo.New()
+			e.Validator = config.NewRequestValidator()
+			request := httptest.NewRequest(http.MethodPut, "/products", strings.NewReader(`{"on_hand":1}`))
+			request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(request, rec)
+			c.SetPath("/products/:productId/stock")
+			c.SetParamNames("productId")
+			c.SetParamValues(productId.String())
+
+			err := controller.UpdateStock(c)
+			if assert.Error(t, err) {
+				err := err.(*echo.HTTPError)
+				assert.Equal(t, tc.expectedCode, err.Code)
+				assert.Equal(t, tc.err.Error(), err.Message)
+			}
+		})
+	}
+}
+
+type stockServiceMock struct {
+	callCount   int
+	updateStock func(application.UpdateStockCommand) (application.StockDto, error)
+}
+
+func (s *stockServiceMock) UpdateStock(command application.UpdateStockCommand) (application.StockDto, error) {
+	s.callCount++
+	return s.updateStock(command)
+}
+
+func Test_GivenAnIfMatchHeader_WhenUpdateStock_ThenTheExpectedVersionIsPassedAndTheNewETagIsReturned(t *testing.T) {
+	productId := uuid.New()
+	var expectedVersion *int
+	stockServiceMock := &stockServiceMock{
+		updateStock: func(command application.UpdateStockCommand) (application.StockDto, error) {
+			expectedVersion = command.ExpectedVersion
+			return application.StockDto{ProductId: command.ProductId, OnHand: *command.OnHand, Available: *command.OnHand, Version: 3}, nil
+		},
+	}
+	controller, _ := controllers.NewStockController(stockServiceMock)
+
+	e := echo.New()
+	e.Validator = config.NewRequestValidator()
+	request := httptest.NewRequest(http.MethodPut, "/products", strings.NewReader(`{"on_hand":10}`))
+	request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Add("If-Match", `"2"`)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(request, rec)
+	c.SetPath("/products/:productId/stock")
+	c.SetParamNames("productId")
+	c.SetParamValues(productId.String())
+
+	if assert.NoError(t, controller.UpdateStock(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+		assert.NotContains(t, rec.Body.String(), "version")
+	}
+	if assert.NotNil(t, expectedVersion) {
+		assert.Equal(t, 2, *expectedVersion)
+	}
+}
+
+func Test_GivenAStaleOrConflictingStockItem_WhenUpdateStock_ThenReturn412Or409(t *testing.T) {
+	productId := uuid.New()
+	for expectedCode, serviceErr := range map[int]error{
+		http.StatusPreconditionFailed: application.NewPreconditionFailedError(productId.String(), "stock", 1, 2),
+		http.StatusConflict:           application.NewConcurrencyConflictError(productId.String()),
+	} {
+		stockServiceMock := &stockServiceMock{
+			updateStock: func(application.UpdateStockCommand) (application.StockDto, error) {
+				return application.StockDto{}, serviceErr
+			},
+		}
+		controller, _ := controllers.NewStockController(stockServiceMock)
+
+		e := echo.New()
+		e.Validator = config.NewRequestValidator()
+		request := httptest.NewRequest(http.MethodPut, "/products", strings.NewReader(`{"on_hand":10}`))
+		request.Header.Add(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Add("If-Match", `"1"`)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(request, rec)
+		c.SetPath("/products/:productId/stock")
+		c.SetParamNames("productId")
+		c.SetParamValues(productId.String())
+
+		err := controller.UpdateStock(c)
+		if assert.Error(t, err) {
+			err := err.(*echo.HTTPError)
+			assert.Equal(t, expectedCode, err.Code)
+			assert.Equal(t, serviceErr.Error(), err.Message)
+		}
+	}
+}
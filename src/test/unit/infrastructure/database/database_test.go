@@ -0,0 +1,30 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenADriverOtherThanSQLite_WhenOpen_ThenReturnUnsupportedDriverError(t *testing.T) {
+	db, err := database.Open("postgres", "postgres://localhost/go-startup")
+
+	assert.Nil(t, db)
+	assert.EqualError(t, err, `unsupported database driver "postgres"`)
+}
+
+func Test_GivenASQLiteDatabase_WhenOpen_ThenForeignKeysAreEnforced(t *testing.T) {
+	db, err := database.Open(database.SQLiteDriver, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	var enabled int
+	assert.Nil(t, db.QueryRow(`PRAGMA foreign_keys`).Scan(&enabled))
+	assert.Equal(t, 1, enabled)
+	_, err = db.Exec(`INSERT INTO cart_coupons (cart_id, position, code) VALUES ('missing', 0, 'WELCOME')`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func openDatabase(t *testing.T) *sql.DB {
+	db, err := sql.Open(database.SQLiteDriver, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func Test_GivenAnEmptyDatabase_WhenMigrate_ThenEveryEmbeddedMigrationIsAppliedAndRecorded(t *testing.T) {
+	db := openDatabase(t)
+
+	applied, err := database.Migrate(db)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, applied)
+	versions, err := database.AppliedVersions(db)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, versions)
+	for _, table := range []string{"products", "customers", "carts", "cart_items", "cart_coupons", "outbox_records"} {
+		var name string
+		assert.Nil(t, db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&name), table)
+	}
+}
+
+func Test_GivenAMigratedDatabase_WhenMigrateAgain_ThenNothingIsApplied(t *testing.T) {
+	db := openDatabase(t)
+	database.Migrate(db)
+
+	applied, err := database.Migrate(db)
+
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+}
+
+func Test_GivenANewMigration_WhenApplyMigrations_ThenOnlyThePendingOnesAreAppliedInVersionOrder(t *testing.T) {
+	db := openDatabase(t)
+	migrations := fstest.MapFS{
+		"0001_create_notes.sql": {Data: []byte(`CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL);`)},
+	}
+	database.ApplyMigrations(db, migrations)
+	migrations["0010_add_note_author.sql"] = &fstest.MapFile{Data: []byte(`ALTER TABLE notes ADD COLUMN author TEXT NOT NULL DEFAULT '';`)}
+	migrations["0002_seed_notes.sql"] = &fstest.MapFile{Data: []byte(`INSERT INTO notes (id, body) VALUES (1, 'hello');`)}
+
+	applied, err := database.ApplyMigrations(db, migrations)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 10}, applied)
+	var author string
+	assert.Nil(t, db.QueryRow(`SELECT author FROM notes WHERE id = 1`).Scan(&author))
+}
+
+func Test_GivenAFailingMigration_WhenApplyMigrations_ThenItIsRolledBackAndNotRecorded(t *testing.T) {
+	db := openDatabase(t)
+	migrations := fstest.MapFS{
+		"0001_create_notes.sql": {Data: []byte(`CREATE TABLE notes (id INTEGER PRIMARY KEY);`)},
+		"0002_broken.sql":       {Data: []byte(`CREATE TABLE authors (id INTEGER PRIMARY KEY); INSERT INTO missing_table VALUES (1);`)},
+	}
+
+	applied, err := database.ApplyMigrations(db, migrations)
+
+	assert.Equal(t, []int{1}, applied)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "migration 2_broken")
+	}
+	versions, _ := database.AppliedVersions(db)
+	assert.Equal(t, []int{1}, versions)
+	_, err = db.Exec(`INSERT INTO authors (id) VALUES (1)`)
+	assert.Error(t, err)
+}
+
+func Test_GivenAMigrationWithAnInvalidName_WhenApplyMigrations_ThenReturnError(t *testing.T) {
+	db := openDatabase(t)
+
+	_, err := database.ApplyMigrations(db, fstest.MapFS{"create_notes.sql": {Data: []byte(`SELECT 1;`)}})
+	assert.EqualError(t, err, `invalid migration file name "create_notes.sql"`)
+
+	_, err = database.ApplyMigrations(db, fstest.MapFS{
+		"0001_create_notes.sql": {Data: []byte(`SELECT 1;`)},
+		"1_other_notes.sql":     {Data: []byte(`SELECT 1;`)},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/events"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenNothing_WhenNewSynchronousEventDispatcher_ThenReturnAnEventDispatcher(t *testing.T) {
+	dispatcher := events.NewSynchronousEventDispatcher()
+
+	assert.NotNil(t, dispatcher)
+	assert.IsType(t, &events.SynchronousEventDispatcher{}, dispatcher)
+}
+
+func Test_GivenATypedHandler_WhenDispatch_ThenTheHandlerReceivesOnlyItsEventType(t *testing.T) {
+	dispatcher := events.NewSynchronousEventDispatcher()
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	cart.AddItem(product, 2)
+
+	var handledEvents []domain.ItemAddedToCart
+	domain.RegisterEventHandler(dispatcher, func(event domain.ItemAddedToCart) error {
+		handledEvents = append(handledEvents, event)
+		return nil
+	})
+
+	err := dispatcher.Dispatch(cart.GetDomainEvents()...)
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(handledEvents)) {
+		assert.Equal(t, cart.GetID(), handledEvents[0].CartId)
+		assert.Equal(t, product.GetID(), handledEvents[0].ProductId)
+		assert.Equal(t, 2, handledEvents[0].Quantity)
+	}
+}
+
+func Test_GivenSeveralHandlersForTheSameEvent_WhenDispatch_ThenAllOfThemAreCalledInRegistrationOrder(t *testing.T) {
+	dispatcher := events.NewSynchronousEventDispatcher()
+	customer, _ := domain.NewCustomer("John Mayer")
+
+	var calls []string
+	domain.RegisterEventHandler(dispatcher, func(event domain.CustomerCreated) error {
+		calls = append(calls, "analytics")
+		return nil
+	})
+	domain.RegisterEventHandler(dispatcher, func(event domain.CustomerCreated) error {
+		calls = append(calls, "notifications")
+		return nil
+	})
+
+	err := dispatcher.Dispatch(customer.GetDomainEvents()...)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"analytics", "notifications"}, calls)
+}
+
+func Test_GivenAFailingHandler_WhenDispatch_ThenTheRemainingHandlersRunAndTheErrorIsReturned(t *testing.T) {
+	dispatcher := events.NewSynchronousEventDispatcher()
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+
+	var called bool
+	domain.RegisterEventHandler(dispatcher, func(event domain.ProductCreated) error {
+		return errors.New("failed to handle event")
+	})
+	domain.RegisterEventHandler(dispatcher, func(event domain.ProductCreated) error {
+		called = true
+		return nil
+	})
+
+	err := dispatcher.Dispatch(product.GetDomainEvents()...)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "failed to handle event", err.Error())
+	}
+	assert.True(t, called)
+}
+
+func Test_GivenNoHandlers_WhenDispatch_ThenReturnNoError(t *testing.T) {
+	dispatcher := events.NewSynchronousEventDispatcher()
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+
+	err := dispatcher.Dispatch(product.GetDomainEvents()...)
+
+	assert.Nil(t, err)
+}
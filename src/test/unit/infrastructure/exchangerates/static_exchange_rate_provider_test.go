@@ -0,0 +1,67 @@
+package test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/exchangerates"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenStaticRates_WhenGetRate_ThenReturnDirectInverseAndIdentityRates(t *testing.T) {
+	provider, err := exchangerates.NewStaticExchangeRateProvider(map[string]map[string]string{
+		"usd": {"EUR": "0.8"},
+	})
+	assert.NoError(t, err)
+
+	rate, err := provider.GetRate("USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(4, 5), rate)
+
+	rate, err = provider.GetRate("EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(5, 4), rate)
+
+	rate, err = provider.GetRate("ARS", "ARS")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(1, 1), rate)
+}
+
+func Test_GivenAnUnknownPair_WhenGetRate_ThenReturnCurrencyNotConvertible(t *testing.T) {
+	provider, _ := exchangerates.NewStaticExchangeRateProvider(nil)
+
+	_, err := provider.GetRate("USD", "EUR")
+
+	assert.ErrorIs(t, err, domain.ErrCurrencyNotConvertible)
+}
+
+func Test_GivenAnInvalidRate_WhenNewStaticExchangeRateProvider_ThenReturnError(t *testing.T) {
+	provider, err := exchangerates.NewStaticExchangeRateProvider(map[string]map[string]string{
+		"USD": {"EUR": "-1"},
+	})
+
+	assert.Nil(t, provider)
+	assert.Error(t, err)
+}
+
+func Test_GivenARatesFile_WhenNewFileExchangeRateProvider_ThenLoadTheRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	os.WriteFile(path, []byte(`{"EUR":{"USD":"1.08"}}`), 0o644)
+
+	provider, err := exchangerates.NewFileExchangeRateProvider(path)
+	assert.NoError(t, err)
+
+	rate, err := provider.GetRate("EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewRat(108, 100), rate)
+}
+
+func Test_GivenAMissingRatesFile_WhenNewFileExchangeRateProvider_ThenReturnError(t *testing.T) {
+	provider, err := exchangerates.NewFileExchangeRateProvider(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.Nil(t, provider)
+	assert.Error(t, err)
+}
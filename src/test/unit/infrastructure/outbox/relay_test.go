@@ -0,0 +1,117 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilStore_WhenNewRelay_ThenReturnError(t *testing.T) {
+	relay, err := outbox.NewRelay(nil, outbox.NewLogPublisher(nil), outbox.RelayConfig{})
+
+	assert.Nil(t, relay)
+	if assert.Error(t, err) {
+		assert.Equal(t, "outbox store was nil", err.Error())
+	}
+}
+
+func Test_GivenANilPublisher_WhenNewRelay_ThenReturnError(t *testing.T) {
+	relay, err := outbox.NewRelay(outbox.NewInMemoryStore(), nil, outbox.RelayConfig{})
+
+	assert.Nil(t, relay)
+	if assert.Error(t, err) {
+		assert.Equal(t, "outbox publisher was nil", err.Error())
+	}
+}
+
+func Test_GivenPendingRecords_WhenProcessPending_ThenPublishAndMarkThemDelivered(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := outbox.NewInMemoryStore()
+	customer, _ := domain.NewCustomer("John Mayer")
+	records, _ := outbox.NewRecords(customer.GetID().String(), customer.GetDomainEvents(), now)
+	store.Append(records...)
+
+	var published []outbox.Record
+	relay, _ := outbox.NewRelay(store, outbox.PublisherFunc(func(record outbox.Record) error {
+		published = append(published, record)
+		return nil
+	}), outbox.RelayConfig{Now: func() time.Time { return now }})
+
+	err := relay.ProcessPending()
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(published)) {
+		assert.Equal(t, "CustomerCreated", published[0].EventType)
+	}
+	if assert.Equal(t, 1, len(store.All())) {
+		assert.True(t, store.All()[0].IsDelivered())
+	}
+}
+
+func Test_GivenAFailingPublisher_WhenProcessPending_ThenBackOffExponentiallyUntilDelivered(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	store := outbox.NewInMemoryStore()
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	records, _ := outbox.NewRecords(product.GetID().String(), product.GetDomainEvents(), now)
+	store.Append(records...)
+
+	failures := 2
+	var attempts int
+	relay, _ := outbox.NewRelay(store, outbox.PublisherFunc(func(record outbox.Record) error {
+		attempts++
+		if attempts <= failures {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}), outbox.RelayConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Now:            func() time.Time { return now },
+	})
+
+	relay.ProcessPending()
+	assert.Equal(t, now.Add(time.Second), store.All()[0].NextAttemptAt)
+
+	relay.ProcessPending()
+	assert.Equal(t, 1, attempts)
+
+	now = now.Add(time.Second)
+	relay.ProcessPending()
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, now.Add(2*time.Second), store.All()[0].NextAttemptAt)
+
+	now = now.Add(2 * time.Second)
+	relay.ProcessPending()
+	assert.Equal(t, 3, attempts)
+	assert.True(t, store.All()[0].IsDelivered())
+	assert.Equal(t, 3, store.All()[0].Attempts)
+}
+
+func Test_GivenAStartedRelay_WhenStop_ThenPendingRecordsWerePublished(t *testing.T) {
+	store := outbox.NewInMemoryStore()
+	customer, _ := domain.NewCustomer("John Mayer")
+	records, _ := outbox.NewRecords(customer.GetID().String(), customer.GetDomainEvents(), time.Now())
+	store.Append(records...)
+
+	published := make(chan outbox.Record, 1)
+	relay, _ := outbox.NewRelay(store, outbox.PublisherFunc(func(record outbox.Record) error {
+		published <- record
+		return nil
+	}), outbox.RelayConfig{PollInterval: time.Millisecond})
+
+	relay.Start()
+	select {
+	case record := <-published:
+		assert.Equal(t, records[0].Id, record.Id)
+	case <-time.After(time.Second):
+		t.Fatal("record was not published")
+	}
+	relay.Stop()
+
+	assert.True(t, store.All()[0].IsDelivered())
+}
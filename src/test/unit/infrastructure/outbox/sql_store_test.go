@@ -0,0 +1,113 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLStore(t *testing.T) *outbox.SQLStore {
+	db, err := database.Open(database.SQLiteDriver, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := outbox.NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func Test_GivenANilDB_WhenNewSQLStore_ThenReturnError(t *testing.T) {
+	store, err := outbox.NewSQLStore(nil)
+
+	assert.Nil(t, store)
+	assert.EqualError(t, err, "db was nil")
+}
+
+func Test_GivenASQLStore_WhenAppendAndMarkDelivered_ThenTheRecordIsNoLongerPending(t *testing.T) {
+	store := newSQLStore(t)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	records, _ := outbox.NewRecords(product.GetID().String(), product.GetDomainEvents(), now)
+
+	assert.Nil(t, store.Append(records...))
+	pending, err := store.Pending(now, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, records, pending)
+
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+	pending, _ = store.Pending(now, 10)
+	assert.Empty(t, pending)
+	all, _ := store.All()
+	if assert.Len(t, all, 1) {
+		assert.True(t, all[0].IsDelivered())
+		assert.Equal(t, 1, all[0].Attempts)
+	}
+}
+
+func Test_GivenASQLStore_WhenMarkFailed_ThenTheRecordIsPendingOnlyAfterTheNextAttempt(t *testing.T) {
+	store := newSQLStore(t)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	records, _ := outbox.NewRecords(product.GetID().String(), product.GetDomainEvents(), now)
+	store.Append(records...)
+
+	assert.Nil(t, store.MarkFailed(records[0].Id, "broker unavailable", now.Add(time.Minute)))
+
+	pending, _ := store.Pending(now, 10)
+	assert.Empty(t, pending)
+	pending, _ = store.Pending(now.Add(time.Minute), 10)
+	if assert.Equal(t, 1, len(pending)) {
+		assert.Equal(t, 1, pending[0].Attempts)
+		assert.Equal(t, "broker unavailable", pending[0].LastError)
+	}
+}
+
+func Test_GivenASQLStore_WhenMarkDeliveredAnUnknownRecord_ThenReturnError(t *testing.T) {
+	store := newSQLStore(t)
+	records, _ := outbox.NewRecords("aggregate", []domain.DomainEvent{domain.CustomerCreated{}}, time.Now())
+
+	err := store.MarkDelivered(records[0].Id, time.Now())
+
+	assert.EqualError(t, err, "outbox record "+records[0].Id.String()+" not found")
+}
+
+func Test_GivenASQLStore_WhenPendingWithALimit_ThenReturnTheOldestRecordsFirst(t *testing.T) {
+	store := newSQLStore(t)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	older, _ := outbox.NewRecords("older", []domain.DomainEvent{domain.CustomerCreated{}}, now.Add(-time.Minute))
+	newer, _ := outbox.NewRecords("newer", []domain.DomainEvent{domain.CustomerCreated{}}, now)
+	store.Append(newer...)
+	store.Append(older...)
+
+	pending, err := store.Pending(now, 1)
+
+	assert.Nil(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "older", pending[0].AggregateId)
+	}
+}
+
+func Test_GivenASQLStore_WhenDiscard_ThenTheRecordsAreRemoved(t *testing.T) {
+	store := newSQLStore(t)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	kept, _ := outbox.NewRecords("kept", []domain.DomainEvent{domain.CustomerCreated{}}, now)
+	discarded, _ := outbox.NewRecords("discarded", []domain.DomainEvent{domain.CustomerCreated{}}, now)
+	store.Append(kept...)
+	store.Append(discarded...)
+
+	assert.Nil(t, store.Discard(discarded[0].Id))
+
+	all, _ := store.All()
+	assert.Equal(t, kept, all)
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenACartWithEvents_WhenNewRecords_ThenReturnOneRecordPerEvent(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	occurredOn := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	records, err := outbox.NewRecords(cart.GetID().String(), cart.GetDomainEvents(), occurredOn)
+
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(records)) {
+		assert.Equal(t, "CartCreated", records[0].EventType)
+		assert.Equal(t, cart.GetID().String(), records[0].AggregateId)
+		assert.Equal(t, occurredOn, records[0].OccurredOn)
+		assert.Contains(t, string(records[0].Payload), customer.GetID().String())
+		assert.False(t, records[0].IsDelivered())
+	}
+}
+
+func Test_GivenAnInMemoryStore_WhenAppendAndMarkDelivered_ThenTheRecordIsNoLongerPending(t *testing.T) {
+	store := outbox.NewInMemoryStore()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	records, _ := outbox.NewRecords(product.GetID().String(), product.GetDomainEvents(), now)
+
+	assert.Nil(t, store.Append(records...))
+	pending, _ := store.Pending(now, 10)
+	assert.Equal(t, 1, len(pending))
+
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+	pending, _ = store.Pending(now, 10)
+	assert.Empty(t, pending)
+}
+
+func Test_GivenAnInMemoryStore_WhenMarkFailed_ThenTheRecordIsPendingOnlyAfterTheNextAttempt(t *testing.T) {
+	store := outbox.NewInMemoryStore()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	records, _ := outbox.NewRecords(product.GetID().String(), product.GetDomainEvents(), now)
+	store.Append(records...)
+
+	assert.Nil(t, store.MarkFailed(records[0].Id, "broker unavailable", now.Add(time.Minute)))
+
+	pending, _ := store.Pending(now, 10)
+	assert.Empty(t, pending)
+	pending, _ = store.Pending(now.Add(time.Minute), 10)
+	if assert.Equal(t, 1, len(pending)) {
+		assert.Equal(t, 1, pending[0].Attempts)
+		assert.Equal(t, "broker unavailable", pending[0].LastError)
+	}
+}
+
+func Test_GivenAnInMemoryStore_WhenMarkDeliveredAnUnknownRecord_ThenReturnError(t *testing.T) {
+	store := outbox.NewInMemoryStore()
+	records, _ := outbox.NewRecords("aggregate", []domain.DomainEvent{domain.CustomerCreated{}}, time.Now())
+
+	err := store.MarkDelivered(records[0].Id, time.Now())
+
+	assert.Error(t, err)
+}
+
+func Test_GivenAFileStore_WhenReopened_ThenPendingAndDeliveredRecordsAreRestored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	events := append(customer.GetDomainEvents(), cart.GetDomainEvents()...)
+	records, _ := outbox.NewRecords("aggregate", events, now)
+
+	store, err := outbox.NewFileStore(path)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Append(records...))
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+
+	reopened, err := outbox.NewFileStore(path)
+
+	assert.Nil(t, err)
+	pending, _ := reopened.Pending(now, 10)
+	if assert.Equal(t, 1, len(pending)) {
+		assert.Equal(t, records[1].Id, pending[0].Id)
+		assert.Equal(t, "CartCreated", pending[0].EventType)
+	}
+	assert.Equal(t, 2, len(reopened.All()))
+}
+
+func Test_GivenAnEmptyPath_WhenNewFileStore_ThenReturnError(t *testing.T) {
+	store, err := outbox.NewFileStore("")
+
+	assert.Nil(t, store)
+	if assert.Error(t, err) {
+		assert.Equal(t, "outbox file path was empty", err.Error())
+	}
+}
+
+func Test_GivenAFileStore_WhenDiscard_ThenTheRecordsAreRemovedAndStayRemovedAfterReopening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, _ := outbox.NewFileStore(path)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	kept, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	discarded, _ := domain.NewProduct("Arroz Integral Gallo", testutil.USD("9.00"))
+	keptRecords, _ := outbox.NewRecords(kept.GetID().String(), kept.GetDomainEvents(), now)
+	discardedRecords, _ := outbox.NewRecords(discarded.GetID().String(), discarded.GetDomainEvents(), now)
+	store.Append(keptRecords...)
+	store.Append(discardedRecords...)
+
+	assert.Nil(t, store.Discard(discardedRecords[0].Id))
+
+	reopened, err := outbox.NewFileStore(path)
+	assert.Nil(t, err)
+	if assert.Len(t, reopened.All(), 1) {
+		assert.Equal(t, keptRecords[0].Id, reopened.All()[0].Id)
+	}
+	assert.Nil(t, reopened.MarkDelivered(keptRecords[0].Id, now))
+}
+
+func Test_GivenAFileStore_WhenMarkDelivered_ThenOnlyTheChangedRecordIsAppendedToTheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, _ := outbox.NewFileStore(path)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	records, _ := outbox.NewRecords("aggregate", append(customer.GetDomainEvents(), cart.GetDomainEvents()...), now)
+	store.Append(records...)
+	before, _ := os.ReadFile(path)
+
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+
+	after, _ := os.ReadFile(path)
+	assert.True(t, bytes.HasPrefix(after, before))
+	assert.Equal(t, 3, bytes.Count(after, []byte("\n")))
+}
+
+func Test_GivenACompactionInterval_WhenWriteMoreTimesThanTheInterval_ThenTheDeliveredRecordsArePruned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, _ := outbox.NewFileStore(path, outbox.WithCompactEvery(4))
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	records, _ := outbox.NewRecords("aggregate", append(customer.GetDomainEvents(), cart.GetDomainEvents()...), now)
+	store.Append(records...)
+	store.MarkFailed(records[1].Id, "broker unavailable", now)
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, 1, bytes.Count(data, []byte("\n")))
+	if assert.Len(t, store.All(), 1) {
+		assert.Equal(t, records[1].Id, store.All()[0].Id)
+	}
+
+	reopened, err := outbox.NewFileStore(path)
+	assert.Nil(t, err)
+	pending, _ := reopened.Pending(now, 10)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "broker unavailable", pending[0].LastError)
+		assert.Equal(t, 1, pending[0].Attempts)
+	}
+}
+
+func Test_GivenATornWriteAtTheEndOfTheOutboxFile_WhenReopened_ThenItIsDiscardedAndTheStoreKeepsWorking(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, _ := outbox.NewFileStore(path)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	customer, _ := domain.NewCustomer("John Mayer")
+	records, _ := outbox.NewRecords("aggregate", customer.GetDomainEvents(), now)
+	store.Append(records...)
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	file.WriteString(`{"id":"2b5e`)
+	file.Close()
+
+	reopened, err := outbox.NewFileStore(path)
+	assert.Nil(t, err)
+	assert.Nil(t, reopened.MarkDelivered(records[0].Id, now))
+
+	reopened, err = outbox.NewFileStore(path)
+	assert.Nil(t, err)
+	if assert.Len(t, reopened.All(), 1) {
+		assert.True(t, reopened.All()[0].IsDelivered())
+	}
+}
+
+func Test_GivenAPruneInterval_WhenMarkDeliveredMoreTimesThanTheInterval_ThenTheDeliveredRecordsAreRemoved(t *testing.T) {
+	store := outbox.NewInMemoryStore(outbox.WithPruneEvery(2))
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	product, _ := domain.NewProduct("Arroz Blanco Gallo", testutil.USD("8.00"))
+	events := append(append(customer.GetDomainEvents(), cart.GetDomainEvents()...), product.GetDomainEvents()...)
+	records, _ := outbox.NewRecords("aggregate", events, now)
+	store.Append(records...)
+
+	assert.Nil(t, store.MarkDelivered(records[0].Id, now))
+	assert.Len(t, store.All(), 3)
+	assert.Nil(t, store.MarkDelivered(records[1].Id, now))
+
+	if assert.Len(t, store.All(), 1) {
+		assert.Equal(t, records[2].Id, store.All()[0].Id)
+	}
+	pending, _ := store.Pending(now, 10)
+	assert.Len(t, pending, 1)
+	assert.Nil(t, store.MarkFailed(records[2].Id, "broker unavailable", now))
+}
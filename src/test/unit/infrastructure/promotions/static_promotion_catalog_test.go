@@ -0,0 +1,88 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/promotions"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAStaticCatalog_WhenFindCoupon_ThenReturnTheCouponOrAnError(t *testing.T) {
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+	catalog, err := promotions.NewStaticPromotionCatalog([]domain.Coupon{coupon}, nil)
+	assert.NoError(t, err)
+
+	found, err := catalog.FindCoupon("SAVE10")
+	assert.NoError(t, err)
+	assert.Equal(t, coupon, found)
+
+	_, err = catalog.FindCoupon("SAVE20")
+	assert.EqualError(t, err, "coupon not found")
+	assert.Empty(t, catalog.GetAutomaticPromotions())
+}
+
+func Test_GivenDuplicatedCouponCodes_WhenNewStaticPromotionCatalog_ThenReturnError(t *testing.T) {
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+
+	catalog, err := promotions.NewStaticPromotionCatalog([]domain.Coupon{coupon, coupon}, nil)
+
+	assert.Nil(t, catalog)
+	assert.EqualError(t, err, "duplicated coupon SAVE10")
+}
+
+func Test_GivenAPromotionsFile_WhenNewFilePromotionCatalog_ThenLoadCouponsAndAutomaticPromotions(t *testing.T) {
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	coffee, _ := domain.NewProduct("Cafe La Virginia", testutil.USD("10.00"))
+	cart.AddItem(coffee, 3)
+
+	path := filepath.Join(t.TempDir(), "promotions.json")
+	os.WriteFile(path, []byte(fmt.Sprintf(`{
+		"coupons": [
+			{"code": "save10", "type": "percentage_off", "percent": 10},
+			{"code": "FIVE-OFF", "type": "fixed_amount_off", "amount": "5.00", "minimum_spend": "100.00"}
+		],
+		"automatic": [
+			{"name": "coffee 3x2", "type": "buy_x_get_y", "product_id": "%s", "buy": 2, "free": 1}
+		]
+	}`, uuid.UUID(coffee.GetID()).String())), 0o644)
+
+	catalog, err := promotions.NewFilePromotionCatalog(path)
+	assert.NoError(t, err)
+
+	tenPercentOff, err := catalog.FindCoupon("SAVE10")
+	assert.NoError(t, err)
+	fiveOff, err := catalog.FindCoupon("FIVE-OFF")
+	assert.NoError(t, err)
+
+	cart.ApplyPromotions(append(catalog.GetAutomaticPromotions(), tenPercentOff.GetPromotion(), fiveOff.GetPromotion()))
+
+	assert.Equal(t, []domain.Adjustment{domain.NewLineAdjustment("coffee 3x2", coffee.GetID(), testutil.USD("10.00"))}, cart.GetLineAdjustments())
+	assert.Equal(t, []domain.Adjustment{domain.NewCartAdjustment("SAVE10", testutil.USD("2.00"))}, cart.GetCartAdjustments())
+	assert.Equal(t, testutil.USD("18.00"), cart.GetTotal())
+}
+
+func Test_GivenAnUnknownPromotionType_WhenNewFilePromotionCatalog_ThenReturnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "promotions.json")
+	os.WriteFile(path, []byte(`{"automatic":[{"name":"mystery","type":"mystery_box"}]}`), 0o644)
+
+	catalog, err := promotions.NewFilePromotionCatalog(path)
+
+	assert.Nil(t, catalog)
+	assert.EqualError(t, err, `promotion "mystery": unknown promotion type "mystery_box"`)
+}
+
+func Test_GivenAMissingPromotionsFile_WhenNewFilePromotionCatalog_ThenReturnError(t *testing.T) {
+	catalog, err := promotions.NewFilePromotionCatalog(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.Nil(t, catalog)
+	assert.Error(t, err)
+}
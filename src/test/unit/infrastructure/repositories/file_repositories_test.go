@@ -0,0 +1,310 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAFileCartRepository_WhenReopened_ThenTheSavedCartsAreRestored(t *testing.T) {
+	directory := t.TempDir()
+	repo, err := repositories.NewFileCartRepository(directory)
+	assert.Nil(t, err)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	cartToSave.AddItem(aProduct, 1)
+	assert.Nil(t, repo.Save(cartToSave))
+	cartToSave.UpdateItemQuantity(aProduct.GetID(), 3)
+	assert.Nil(t, repo.Save(cartToSave))
+
+	reopened, err := repositories.NewFileCartRepository(directory)
+	assert.Nil(t, err)
+	cartSaved, err := reopened.FindByID(cartToSave.GetID())
+
+	assert.Nil(t, err)
+	assert.Equal(t, cartToSave.ToMemento(), cartSaved.ToMemento())
+	assert.Equal(t, testutil.USD("30.00"), cartSaved.GetTotal())
+	reopenedCarts, err := reopened.GetCustomerCarts(aCustomer.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reopenedCarts))
+}
+
+func Test_GivenAFileProductRepository_WhenReopened_ThenTheUniqueIndexesAreRebuilt(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileProductRepository(directory)
+	sku, _ := domain.NewSKU("ARROZ-1KG")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"), domain.WithSKU(sku))
+	assert.Nil(t, repo.Save(aProduct))
+
+	reopened, err := repositories.NewFileProductRepository(directory)
+	assert.Nil(t, err)
+	productSaved, err := reopened.FindBySKU(sku)
+	assert.Nil(t, err)
+	assert.Equal(t, aProduct.ToMemento(), productSaved.ToMemento())
+
+	anotherProduct, _ := domain.NewProduct("Arroz integral", testutil.USD("12.00"), domain.WithSKU(sku))
+	err = reopened.Save(anotherProduct)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "sku", Value: "ARROZ-1KG"}, err)
+}
+
+func Test_GivenAFileCustomerRepository_WhenReopened_ThenTheLatestStateOfEachCustomerIsRestored(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileCustomerRepository(directory)
+	previousEmail, _ := domain.NewEmail("john@mayer.com")
+	email, _ := domain.NewEmail("john.mayer@gmail.com")
+	aCustomer, _ := domain.NewCustomer("John Mayer", domain.WithEmail(previousEmail))
+	repo.Save(aCustomer)
+	aCustomer.ChangeEmail(email)
+	repo.Save(aCustomer)
+
+	reopened, err := repositories.NewFileCustomerRepository(directory)
+	assert.Nil(t, err)
+
+	customerSaved, err := reopened.FindByID(aCustomer.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, email, customerSaved.GetEmail())
+	anotherCustomer, _ := domain.NewCustomer("Vaughn Vernon", domain.WithEmail(previousEmail))
+	assert.Nil(t, reopened.Save(anotherCustomer))
+}
+
+func Test_GivenATornWriteAtTheEndOfTheLog_WhenReopened_ThenItIsDiscardedAndTheLogKeepsWorking(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileCustomerRepository(directory)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	repo.Save(aCustomer)
+	logFile, _ := os.OpenFile(filepath.Join(directory, "customers.wal"), os.O_WRONLY|os.O_APPEND, 0o644)
+	logFile.WriteString(`{"id":"2b5e`)
+	logFile.Close()
+
+	reopened, err := repositories.NewFileCustomerRepository(directory)
+	assert.Nil(t, err)
+	anotherCustomer, _ := domain.NewCustomer("Vaughn Vernon")
+	assert.Nil(t, reopened.Save(anotherCustomer))
+
+	reopened, err = repositories.NewFileCustomerRepository(directory)
+	assert.Nil(t, err)
+	_, err = reopened.FindByID(aCustomer.GetID())
+	assert.Nil(t, err)
+	_, err = reopened.FindByID(anotherCustomer.GetID())
+	assert.Nil(t, err)
+}
+
+func Test_GivenACorruptRecordInTheMiddleOfTheLog_WhenReopened_ThenReturnError(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileCustomerRepository(directory)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	repo.Save(aCustomer)
+	logPath := filepath.Join(directory, "customers.wal")
+	data, _ := os.ReadFile(logPath)
+	os.WriteFile(logPath, append([]byte("{not json}\n"), data...), 0o644)
+
+	reopened, err := repositories.NewFileCustomerRepository(directory)
+
+	assert.Nil(t, reopened)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "corrupt write-ahead log at line 1")
+	}
+}
+
+func Test_GivenASnapshotInterval_WhenSaveMoreTimesThanTheInterval_ThenTheLogIsCompactedIntoASnapshot(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileProductRepository(directory, repositories.WithSnapshotEvery(2))
+	first, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	second, _ := domain.NewProduct("Dulce de leche", testutil.USD("12.00"))
+	third, _ := domain.NewProduct("Yerba mate 1 Kg", testutil.USD("8.00"))
+	repo.Save(first)
+	repo.Save(second)
+	repo.Save(third)
+
+	snapshot, _ := os.ReadFile(filepath.Join(directory, "products.snapshot"))
+	log, _ := os.ReadFile(filepath.Join(directory, "products.wal"))
+	assert.Equal(t, 2, bytes.Count(snapshot, []byte("\n")))
+	assert.Equal(t, 1, bytes.Count(log, []byte("\n")))
+
+	reopened, err := repositories.NewFileProductRepository(directory, repositories.WithSnapshotEvery(2))
+	assert.Nil(t, err)
+	page, _ := reopened.List(domain.ProductListQuery{})
+	assert.Equal(t, 3, len(page.Products))
+}
+
+func Test_GivenAFileCartRepositoryWithAnOutbox_WhenReopened_ThenRestoredCartsAreNotAppendedToTheOutboxAgain(t *testing.T) {
+	directory := t.TempDir()
+	outboxStore := outbox.NewInMemoryStore()
+	repo, _ := repositories.NewFileCartRepository(directory, repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	cartToSave, _ := domain.NewCart(aCustomer)
+	repo.Save(cartToSave)
+
+	_, err := repositories.NewFileCartRepository(directory, repositories.WithOutbox(outboxStore))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(outboxStore.All()))
+}
+
+func Test_GivenACrashAfterTheWriteAheadLogCommit_WhenReopened_ThenTheOutboxRecordsOfTheLastEntryAreAppended(t *testing.T) {
+	directory := t.TempDir()
+	outboxStore := outbox.NewInMemoryStore()
+	repo, _ := repositories.NewFileCartRepository(directory, repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	repo.Save(cartToSave)
+	cartToSave.AddItem(aProduct, 1)
+	repo.Save(cartToSave)
+	lastRecords := outboxStore.All()[1:]
+
+	crashedOutbox := outbox.NewInMemoryStore()
+	_, err := repositories.NewFileCartRepository(directory, repositories.WithOutbox(crashedOutbox))
+
+	assert.Nil(t, err)
+	assert.Equal(t, lastRecords, crashedOutbox.All())
+}
+
+func Test_GivenAFailingOutbox_WhenSave_ThenTheWriteAheadLogEntryIsRemovedAndTheCartIsNotSaved(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileCartRepository(directory, repositories.WithOutbox(&failingOutbox{Store: outbox.NewInMemoryStore()}))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	cartToSave, _ := domain.NewCart(aCustomer)
+
+	err := repo.Save(cartToSave)
+
+	assert.EqualError(t, err, "outbox unavailable")
+	assert.Equal(t, 0, cartToSave.GetVersion())
+	_, err = repo.FindByID(cartToSave.GetID())
+	assert.EqualError(t, err, "entity not found")
+	log, _ := os.ReadFile(filepath.Join(directory, "carts.wal"))
+	assert.Empty(t, log)
+	reopened, err := repositories.NewFileCartRepository(directory)
+	assert.Nil(t, err)
+	_, err = reopened.FindByID(cartToSave.GetID())
+	assert.EqualError(t, err, "entity not found")
+}
+
+type failingOutbox struct {
+	outbox.Store
+}
+
+func (o *failingOutbox) Append(...outbox.Record) error {
+	return errors.New("outbox unavailable")
+}
+
+func Test_GivenAFailingWriteAheadLog_WhenSave_ThenTheOutboxRecordsAreDiscardedAndTheCartIsNotSaved(t *testing.T) {
+	directory := t.TempDir()
+	outboxStore := outbox.NewInMemoryStore()
+	repo, _ := repositories.NewFileCartRepository(directory, repositories.WithOutbox(outboxStore))
+	assert.Nil(t, os.Mkdir(filepath.Join(directory, "carts.wal"), 0o755))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	cartToSave, _ := domain.NewCart(aCustomer)
+
+	err := repo.Save(cartToSave)
+
+	assert.Error(t, err)
+	assert.Empty(t, outboxStore.All())
+	assert.Equal(t, 0, cartToSave.GetVersion())
+	_, err = repo.FindByID(cartToSave.GetID())
+	assert.EqualError(t, err, "entity not found")
+}
+
+func Test_GivenAFileCartRepository_WhenReopened_ThenTheVersionIsRestoredAndStaleSavesAreRejected(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileCartRepository(directory)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(cartToSave))
+	staleCart := cartToSave.Clone()
+	cartToSave.AddItem(aProduct, 1)
+	assert.Nil(t, repo.Save(cartToSave))
+
+	reopened, err := repositories.NewFileCartRepository(directory)
+	assert.Nil(t, err)
+	cartSaved, _ := reopened.FindByID(cartToSave.GetID())
+	assert.Equal(t, 2, cartSaved.GetVersion())
+
+	staleCart.AddItem(aProduct, 5)
+	assert.IsType(t, &domain.ConcurrencyConflictError{}, reopened.Save(staleCart))
+	cartSaved.AddItem(aProduct, 1)
+	assert.Nil(t, reopened.Save(cartSaved))
+	assert.Equal(t, 3, cartSaved.GetVersion())
+}
+
+func Test_GivenAFileCartRepository_WhenDelete_ThenReturnErrorAndKeepTheCart(t *testing.T) {
+	repo, _ := repositories.NewFileCartRepository(t.TempDir())
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	cartToSave, _ := domain.NewCart(aCustomer)
+	repo.Save(cartToSave)
+
+	err := repo.(interface{ Delete(domain.CartId) error }).Delete(cartToSave.GetID())
+
+	assert.EqualError(t, err, "delete is not supported by file repositories")
+	cartsSaved, _ := repo.GetCustomerCarts(aCustomer.GetID())
+	assert.Len(t, cartsSaved, 1)
+}
+
+func Test_GivenAFileQuoteRepository_WhenReopened_ThenTheQuotesAreRestoredAndNextQuoteNumberContinuesTheSequence(t *testing.T) {
+	directory := t.TempDir()
+	repo, _ := repositories.NewFileQuoteRepository(directory)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo.NextQuoteNumber(issuedAt)
+	number, _ := repo.NextQuoteNumber(issuedAt)
+	assert.Equal(t, "Q-20240102-000001", number)
+	quote, _ := domain.IssueQuote(cart, number, issuedAt, time.Hour)
+	assert.Nil(t, repo.Save(quote))
+
+	reopened, err := repositories.NewFileQuoteRepository(directory)
+	assert.Nil(t, err)
+	quoteSaved, err := reopened.FindByID(quote.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, quote.ToMemento(), quoteSaved.ToMemento())
+
+	next, err := reopened.NextQuoteNumber(issuedAt)
+	assert.Nil(t, err)
+	assert.Equal(t, "Q-20240102-000002", next)
+	duplicated, _ := domain.IssueQuote(cart, number, issuedAt, time.Hour)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "number", Value: number}, reopened.Save(duplicated))
+}
+
+func Test_GivenAFileOrderAndStockRepository_WhenReopened_ThenTheOrdersAndReservationsAreRestored(t *testing.T) {
+	directory := t.TempDir()
+	orderRepository, _ := repositories.NewFileOrderRepository(directory)
+	stockRepository, _ := repositories.NewFileStockRepository(directory)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	stockItem, _ := domain.NewStockItem(aProduct.GetID(), 10)
+	stockItem.Reserve(cart.GetID(), 2)
+	assert.Nil(t, stockRepository.Save(stockItem))
+	order, _ := domain.PlaceOrder(cart, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	assert.Nil(t, orderRepository.Save(order))
+
+	reopenedOrders, err := repositories.NewFileOrderRepository(directory)
+	assert.Nil(t, err)
+	reopenedStock, err := repositories.NewFileStockRepository(directory)
+	assert.Nil(t, err)
+
+	orderSaved, err := reopenedOrders.FindByID(order.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, order.ToMemento(), orderSaved.ToMemento())
+	reservations, err := reopenedStock.GetCartReservations(cart.GetID())
+	assert.Nil(t, err)
+	assert.Len(t, reservations, 1)
+	assert.Equal(t, 2, reservations[0].GetReservedFor(cart.GetID()))
+	_, err = reopenedStock.GetCartReservations(domain.CartId(uuid.New()))
+	assert.Nil(t, err)
+}
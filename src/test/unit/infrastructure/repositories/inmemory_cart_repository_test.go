@@ -1,10 +1,15 @@
 package test
 
 import (
+	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,7 +24,7 @@ func Test_GivenNothing_WhenNewInMemoryCartRepository_ThenReturnACartRepository(t
 func Test_GivenACartRepository_WhenSave_ThenSaves(t *testing.T) {
 	repo := repositories.NewInMemoryCartRepository()
 	aCustomer, _ := domain.NewCustomer("John Mayer")
-	aProduct, _ := domain.NewProduct("Arroz con leche", 10.00)
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
 	cartToSave, _ := domain.NewCart(aCustomer)
 	cartToSave.AddItem(aProduct, 1)
 
@@ -29,29 +34,29 @@ func Test_GivenACartRepository_WhenSave_ThenSaves(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotEmpty(t, cartSaved)
 	assert.Equal(t, 1, cartSaved.Size())
-	assert.Equal(t, 10.00, cartSaved.GetTotal())
+	assert.Equal(t, testutil.USD("10.00"), cartSaved.GetTotal())
 }
 
 func Test_GivenACartRepository_WhenGetByCustomer_ThenReturnsTheCustomersCart(t *testing.T) {
 	repo := repositories.NewInMemoryCartRepository()
 	aCustomer, _ := domain.NewCustomer("John Mayer")
-	aProduct, _ := domain.NewProduct("Arroz con leche", 10.00)
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
 	cartToSave, _ := domain.NewCart(aCustomer)
 	cartToSave.AddItem(aProduct, 1)
 
 	repo.Save(cartToSave)
-	cartsSaved := repo.GetCustomerCarts(aCustomer.GetID())
+	cartsSaved, _ := repo.GetCustomerCarts(aCustomer.GetID())
 
 	assert.NotEmpty(t, cartsSaved)
 	assert.Equal(t, 1, len(cartsSaved))
 	assert.Equal(t, 1, cartsSaved[0].Size())
-	assert.Equal(t, 10.00, cartsSaved[0].GetTotal())
+	assert.Equal(t, testutil.USD("10.00"), cartsSaved[0].GetTotal())
 }
 
 func Test_GivenACartRepositoryWithOneCart_WhenFindByIDWithUnexistingID_ThenReturnsError(t *testing.T) {
 	repo := repositories.NewInMemoryCartRepository()
 	aCustomer, _ := domain.NewCustomer("John Mayer")
-	aProduct, _ := domain.NewProduct("Arroz con leche", 10.00)
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
 	cartToSave, _ := domain.NewCart(aCustomer)
 	cartToSave.AddItem(aProduct, 1)
 
@@ -64,3 +69,211 @@ func Test_GivenACartRepositoryWithOneCart_WhenFindByIDWithUnexistingID_ThenRetur
 	assert.Nil(t, cartSaved)
 
 }
+
+func Test_GivenACartRepositoryWithAnOutbox_WhenSave_ThenThePendingDomainEventsAreStoredInTheOutbox(t *testing.T) {
+	outboxStore := outbox.NewInMemoryStore()
+	repo := repositories.NewInMemoryCartRepository(repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	cartToSave.AddItem(aProduct, 1)
+
+	err := repo.Save(cartToSave)
+
+	assert.Nil(t, err)
+	records := outboxStore.All()
+	if assert.Equal(t, 2, len(records)) {
+		assert.Equal(t, "CartCreated", records[0].EventType)
+		assert.Equal(t, "ItemAddedToCart", records[1].EventType)
+		assert.Equal(t, cartToSave.GetID().String(), records[1].AggregateId)
+	}
+}
+
+func Test_GivenTheOutboxFailsToAppend_WhenSave_ThenTheCartIsNotSaved(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository(repositories.WithOutbox(&failingOutboxStore{}))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	cartToSave, _ := domain.NewCart(aCustomer)
+
+	err := repo.Save(cartToSave)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, "failed to append to outbox", err.Error())
+	}
+	cartSaved, findErr := repo.FindByID(cartToSave.GetID())
+	assert.Error(t, findErr)
+	assert.Nil(t, cartSaved)
+}
+
+type failingOutboxStore struct {
+	outbox.Store
+}
+
+func (s *failingOutboxStore) Append(records ...outbox.Record) error {
+	return errors.New("failed to append to outbox")
+}
+
+func Test_GivenCartsWithDifferentActivity_WhenFindIdleCarts_ThenReturnOnlyIdleActiveOrAbandonedCarts(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository()
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newCartAt := func(lastActivityAt time.Time) *domain.Cart {
+		aCustomer, _ := domain.NewCustomer("John Mayer")
+		cart, _ := domain.NewCart(aCustomer, domain.WithCartClock(&fixedClock{now: lastActivityAt}))
+		return cart
+	}
+	recentCart := newCartAt(now)
+	idleCart := newCartAt(now.Add(-2 * time.Hour))
+	olderIdleCart := newCartAt(now.Add(-3 * time.Hour))
+	abandonedCart := newCartAt(now.Add(-4 * time.Hour))
+	abandonedCart.Abandon()
+	checkedOutCart := newCartAt(now.Add(-5 * time.Hour))
+	checkedOutCart.AddItem(aProduct, 1)
+	checkedOutCart.Checkout()
+	for _, cart := range []*domain.Cart{recentCart, idleCart, olderIdleCart, abandonedCart, checkedOutCart} {
+		repo.Save(cart)
+	}
+
+	var idleCartIds []domain.CartId
+	idleCarts, _ := repo.FindIdleCarts(now.Add(-time.Hour))
+	for _, cart := range idleCarts {
+		idleCartIds = append(idleCartIds, cart.GetID())
+	}
+
+	assert.Equal(t, []domain.CartId{abandonedCart.GetID(), olderIdleCart.GetID(), idleCart.GetID()}, idleCartIds)
+}
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+func Test_GivenASavedCart_WhenTheFoundCartIsModifiedWithoutSaving_ThenTheRepositoryKeepsTheSavedState(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository()
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	cartToSave.AddItem(aProduct, 1)
+	repo.Save(cartToSave)
+
+	cartToSave.AddItem(aProduct, 5)
+	foundCart, _ := repo.FindByID(cartToSave.GetID())
+	foundCart.AddItem(aProduct, 2)
+	cartSaved, _ := repo.FindByID(cartToSave.GetID())
+
+	assert.Equal(t, 1, cartSaved.Size())
+	assert.Empty(t, cartSaved.GetDomainEvents())
+	cartsSaved, _ := repo.GetCustomerCarts(aCustomer.GetID())
+	assert.Equal(t, 1, cartsSaved[0].Size())
+
+	assert.Nil(t, repo.Save(foundCart))
+	cartSaved, _ = repo.FindByID(cartToSave.GetID())
+	assert.Equal(t, 3, cartSaved.Size())
+}
+
+func Test_GivenManyGoroutines_WhenSavingAndReadingCartsConcurrently_ThenEveryCartIsStoredWithoutDataRaces(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository()
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	const workers = 16
+	const saves = 25
+
+	var wg sync.WaitGroup
+	cartIds := make([]domain.CartId, workers)
+	for worker := 0; worker < workers; worker++ {
+		aCustomer, _ := domain.NewCustomer("John Mayer")
+		cart, _ := domain.NewCart(aCustomer)
+		cartIds[worker] = cart.GetID()
+
+		wg.Add(1)
+		go func(customerId domain.CustomerId, cart *domain.Cart) {
+			defer wg.Done()
+			for save := 0; save < saves; save++ {
+				cart.AddItem(aProduct, 1)
+				assert.Nil(t, repo.Save(cart))
+
+				foundCart, err := repo.FindByID(cart.GetID())
+				if assert.Nil(t, err) {
+					foundCart.AddItem(aProduct, 1)
+				}
+				repo.GetCustomerCarts(customerId)
+				repo.FindIdleCarts(time.Now().Add(time.Hour))
+			}
+		}(aCustomer.GetID(), cart)
+	}
+	wg.Wait()
+
+	for _, cartId := range cartIds {
+		cartSaved, err := repo.FindByID(cartId)
+		if assert.Nil(t, err) {
+			assert.Equal(t, saves, cartSaved.Size())
+		}
+	}
+}
+
+func Test_GivenACartSavedAfterEachAddItem_WhenGetCustomerCarts_ThenTheCartIsReturnedOnce(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository()
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	anotherCustomer, _ := domain.NewCustomer("Tom Misch")
+	firstCart, _ := domain.NewCart(aCustomer)
+	secondCart, _ := domain.NewCart(aCustomer)
+	secondCart.Abandon()
+	anotherCart, _ := domain.NewCart(anotherCustomer)
+	assert.Nil(t, repo.Save(firstCart))
+	assert.Nil(t, repo.Save(secondCart))
+	assert.Nil(t, repo.Save(anotherCart))
+	for _, name := range []string{"Arroz con leche", "Dulce de leche", "Flan casero"} {
+		aProduct, _ := domain.NewProduct(name, testutil.USD("10.00"))
+		firstCart.AddItem(aProduct, 1)
+		assert.Nil(t, repo.Save(firstCart))
+	}
+
+	cartsSaved, _ := repo.GetCustomerCarts(aCustomer.GetID())
+
+	if assert.Len(t, cartsSaved, 2) {
+		assert.Equal(t, firstCart.GetID(), cartsSaved[0].GetID())
+		assert.Equal(t, 3, cartsSaved[0].Size())
+		assert.Equal(t, secondCart.GetID(), cartsSaved[1].GetID())
+	}
+	anotherCustomerCarts, _ := repo.GetCustomerCarts(anotherCustomer.GetID())
+	assert.Len(t, anotherCustomerCarts, 1)
+	unknownCustomerCarts, _ := repo.GetCustomerCarts(domain.CustomerId(uuid.New()))
+	assert.Empty(t, unknownCustomerCarts)
+}
+
+func Test_GivenASavedCart_WhenDelete_ThenItIsRemovedFromTheCustomerIndex(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository().(*repositories.InMemoryCartRepository)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	firstCart, _ := domain.NewCart(aCustomer)
+	secondCart, _ := domain.NewCart(aCustomer)
+	repo.Save(firstCart)
+
+	assert.Nil(t, repo.Delete(firstCart.GetID()))
+	assert.Nil(t, repo.Save(secondCart))
+
+	cartsSaved, _ := repo.GetCustomerCarts(aCustomer.GetID())
+	if assert.Len(t, cartsSaved, 1) {
+		assert.Equal(t, secondCart.GetID(), cartsSaved[0].GetID())
+	}
+	_, err := repo.FindByID(firstCart.GetID())
+	assert.EqualError(t, err, "entity not found")
+	assert.EqualError(t, repo.Delete(firstCart.GetID()), "entity not found")
+}
+
+func Test_GivenACustomerWithAnActiveCart_WhenSaveAnotherActiveCart_ThenReturnUniqueConstraintError(t *testing.T) {
+	repo := repositories.NewInMemoryCartRepository()
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	activeCart, _ := domain.NewCart(aCustomer)
+	activeCart.AddItem(aProduct, 1)
+	secondCart, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(activeCart))
+
+	err := repo.Save(secondCart)
+
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "active_cart", Value: aCustomer.GetID().String()}, err)
+	activeCart.Checkout()
+	assert.Nil(t, repo.Save(activeCart))
+	assert.Nil(t, repo.Save(secondCart))
+}
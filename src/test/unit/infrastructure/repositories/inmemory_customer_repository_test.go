@@ -41,3 +41,46 @@ func Test_GivenACustomerRepositoryWithOneItem_WhenFinByIDWithUnexistingID_ThenRe
 	assert.Nil(t, customerSaved)
 
 }
+
+func Test_GivenACustomerWithAnEmail_WhenSaveAnotherCustomerWithTheSameEmail_ThenReturnUniqueConstraintError(t *testing.T) {
+	repo := repositories.NewInMemoryCustomerRepository()
+	email, _ := domain.NewEmail("john@example.com")
+	john, _ := domain.NewCustomer("John Mayer", domain.WithEmail(email))
+	impostor, _ := domain.NewCustomer("John Impostor", domain.WithEmail(email))
+	repo.Save(john)
+
+	err := repo.Save(impostor)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, &domain.UniqueConstraintError{Field: "email", Value: "john@example.com"}, err)
+	}
+	_, err = repo.FindByID(impostor.GetID())
+	assert.EqualError(t, err, "entity not found")
+	assert.Nil(t, repo.Save(john))
+}
+
+func Test_GivenACustomerThatChangedItsEmail_WhenSaveAnotherCustomerWithThePreviousEmail_ThenSaves(t *testing.T) {
+	repo := repositories.NewInMemoryCustomerRepository()
+	previousEmail, _ := domain.NewEmail("john@example.com")
+	newEmail, _ := domain.NewEmail("mayer@example.com")
+	john, _ := domain.NewCustomer("John Mayer", domain.WithEmail(previousEmail))
+	repo.Save(john)
+	john.ChangeEmail(newEmail)
+	repo.Save(john)
+	another, _ := domain.NewCustomer("John Another", domain.WithEmail(previousEmail))
+
+	err := repo.Save(another)
+
+	assert.Nil(t, err)
+	customerFound, _ := repo.FindByID(another.GetID())
+	assert.Equal(t, previousEmail, customerFound.GetEmail())
+}
+
+func Test_GivenCustomersWithoutEmail_WhenSave_ThenSaves(t *testing.T) {
+	repo := repositories.NewInMemoryCustomerRepository()
+	john, _ := domain.NewCustomer("John Mayer")
+	another, _ := domain.NewCustomer("John Another")
+
+	assert.Nil(t, repo.Save(john))
+	assert.Nil(t, repo.Save(another))
+}
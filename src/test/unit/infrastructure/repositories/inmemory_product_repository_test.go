@@ -5,6 +5,7 @@ import (
 
 	"github.com/bitlogic/go-startup/src/domain"
 	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,7 +19,7 @@ func Test_GivenNothing_WhenNewInMemoryProductRepository_ThenReturnAProductReposi
 
 func Test_GivenAProductRepository_WhenSave_ThenSaves(t *testing.T) {
 	repo := repositories.NewInMemoryProductRepository()
-	productToSave, _ := domain.NewProduct("Arroz con mani", 10.00)
+	productToSave, _ := domain.NewProduct("Arroz con mani", testutil.USD("10.00"))
 
 	repo.Save(productToSave)
 	productSaved, err := repo.FindByID(productToSave.GetID())
@@ -30,7 +31,7 @@ func Test_GivenAProductRepository_WhenSave_ThenSaves(t *testing.T) {
 
 func Test_GivenAProductRepositoryWithItems_WhenFindByIDWithUnexistingID_ThenReturnesError(t *testing.T) {
 	repo := repositories.NewInMemoryProductRepository()
-	productToSave, _ := domain.NewProduct("Arroz con mani", 10.00)
+	productToSave, _ := domain.NewProduct("Arroz con mani", testutil.USD("10.00"))
 	repo.Save(productToSave)
 
 	productSaved, err := repo.FindByID(domain.ProductId(uuid.New()))
@@ -39,3 +40,280 @@ func Test_GivenAProductRepositoryWithItems_WhenFindByIDWithUnexistingID_ThenRetu
 	assert.Equal(t, "entity not found", err.Error())
 	assert.Nil(t, productSaved)
 }
+
+func newCatalog(t *testing.T) (domain.ProductRepository, map[string]*domain.Product) {
+	return fillCatalog(t, repositories.NewInMemoryProductRepository())
+}
+
+func fillCatalog(t *testing.T, repo domain.ProductRepository) (domain.ProductRepository, map[string]*domain.Product) {
+	products := map[string]*domain.Product{}
+	for name, price := range map[string]domain.Money{
+		"Arroz con leche":    testutil.USD("3.50"),
+		"Arroz con mani":     testutil.USD("2.00"),
+		"Pepsi Light 2.5Lt":  testutil.USD("2.75"),
+		"Pepsi Regular 1Lt":  testutil.USD("1.20"),
+		"Mortadela 1 Kg":     testutil.USD("10.00"),
+		"Salame Milan 1 Kg":  testutil.USD("15.00"),
+		"Queso Cremoso 1 Kg": testutil.USD("9.00"),
+	} {
+		product, err := domain.NewProduct(name, price)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo.Save(product)
+		products[name] = product
+	}
+
+	return repo, products
+}
+
+func productNames(page domain.ProductPage) []string {
+	var names []string
+	for _, product := range page.Products {
+		names = append(names, product.GetName())
+	}
+	return names
+}
+
+func Test_GivenAProductCatalog_WhenListWithoutFilters_ThenReturnAllProductsSortedByName(t *testing.T) {
+	repo, _ := newCatalog(t)
+
+	page, err := repo.List(domain.ProductListQuery{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"Arroz con leche",
+		"Arroz con mani",
+		"Mortadela 1 Kg",
+		"Pepsi Light 2.5Lt",
+		"Pepsi Regular 1Lt",
+		"Queso Cremoso 1 Kg",
+		"Salame Milan 1 Kg",
+	}, productNames(page))
+	assert.Empty(t, page.NextCursor)
+}
+
+func Test_GivenAProductCatalog_WhenListWithSearchAndPriceRange_ThenReturnOnlyMatchingProducts(t *testing.T) {
+	repo, _ := newCatalog(t)
+
+	page, err := repo.List(domain.ProductListQuery{
+		NameContains: "PEPSI",
+		MinPrice:     moneyRef(testutil.USD("2.00")),
+		MaxPrice:     moneyRef(testutil.USD("5.00")),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Pepsi Light 2.5Lt"}, productNames(page))
+}
+
+func Test_GivenAProductCatalog_WhenListSortedByPriceDescending_ThenReturnTheMostExpensiveFirst(t *testing.T) {
+	repo, _ := newCatalog(t)
+
+	page, err := repo.List(domain.ProductListQuery{
+		SortBy:     domain.SortProductsByPrice,
+		Descending: true,
+		Limit:      3,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Salame Milan 1 Kg", "Mortadela 1 Kg", "Queso Cremoso 1 Kg"}, productNames(page))
+	assert.NotEmpty(t, page.NextCursor)
+}
+
+func Test_GivenAProductCatalog_WhenFollowingTheNextCursor_ThenEveryProductIsReturnedExactlyOnce(t *testing.T) {
+	repo, _ := newCatalog(t)
+	query := domain.ProductListQuery{
+		SortBy: domain.SortProductsByPrice,
+		Limit:  2,
+	}
+
+	var names []string
+	pages := 0
+	for {
+		page, err := repo.List(query)
+		if !assert.Nil(t, err) {
+			return
+		}
+		names = append(names, productNames(page)...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, 4, pages)
+	assert.Equal(t, []string{
+		"Pepsi Regular 1Lt",
+		"Arroz con mani",
+		"Pepsi Light 2.5Lt",
+		"Arroz con leche",
+		"Queso Cremoso 1 Kg",
+		"Mortadela 1 Kg",
+		"Salame Milan 1 Kg",
+	}, names)
+}
+
+func Test_GivenACursorFromAnotherSort_WhenList_ThenReturnInvalidCursorError(t *testing.T) {
+	repo, _ := newCatalog(t)
+	firstPage, _ := repo.List(domain.ProductListQuery{Limit: 2})
+
+	page, err := repo.List(domain.ProductListQuery{
+		SortBy: domain.SortProductsByPrice,
+		Cursor: firstPage.NextCursor,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	assert.Empty(t, page.Products)
+}
+
+func Test_GivenAMalformedCursor_WhenList_ThenReturnInvalidCursorError(t *testing.T) {
+	repo, _ := newCatalog(t)
+
+	_, err := repo.List(domain.ProductListQuery{Cursor: "%%%"})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func Test_GivenAnArchivedProduct_WhenList_ThenItIsOnlyReturnedWhenArchivedProductsAreIncluded(t *testing.T) {
+	repo, products := newCatalog(t)
+	products["Mortadela 1 Kg"].Archive()
+	repo.Save(products["Mortadela 1 Kg"])
+
+	activePage, err := repo.List(domain.ProductListQuery{NameContains: "1 Kg"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Queso Cremoso 1 Kg", "Salame Milan 1 Kg"}, productNames(activePage))
+
+	allPage, err := repo.List(domain.ProductListQuery{NameContains: "1 Kg", IncludeArchived: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Mortadela 1 Kg", "Queso Cremoso 1 Kg", "Salame Milan 1 Kg"}, productNames(allPage))
+}
+
+func moneyRef(money domain.Money) *domain.Money {
+	return &money
+}
+
+func Test_GivenAProduct_WhenSaveAnotherProductWithTheSameNameInAnotherCase_ThenReturnUniqueConstraintError(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	product, _ := domain.NewProduct("Arroz con mani", testutil.USD("10.00"))
+	duplicate, _ := domain.NewProduct("ARROZ CON MANI", testutil.USD("12.00"))
+	repo.Save(product)
+
+	err := repo.Save(duplicate)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, &domain.UniqueConstraintError{Field: "product_name", Value: "arroz con mani"}, err)
+	}
+	product.Rename("Arroz con leche")
+	assert.Nil(t, repo.Save(product))
+	assert.Nil(t, repo.Save(duplicate))
+}
+
+func Test_GivenAProductWithASKU_WhenFindBySKU_ThenReturnTheProduct(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	sku, _ := domain.NewSKU("ARZ-MANI-1KG")
+	productToSave, _ := domain.NewProduct("Arroz con mani", testutil.USD("10.00"), domain.WithSKU(sku))
+	repo.Save(productToSave)
+
+	productFound, err := repo.FindBySKU(sku)
+	assert.Nil(t, err)
+	assert.Equal(t, productToSave.ToMemento(), productFound.ToMemento())
+
+	_, err = repo.FindBySKU("ARZ-LECHE-1KG")
+	assert.EqualError(t, err, "entity not found")
+}
+
+func Test_GivenAProduct_WhenFindByName_ThenItIsFoundByItsCurrentNameIgnoringCase(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	repo.Save(pillow)
+
+	productFound, err := repo.FindByName("memory foam PILLOW")
+	assert.Nil(t, err)
+	assert.Equal(t, pillow.ToMemento(), productFound.ToMemento())
+
+	pillow.Rename("Memory Foam Pillow XL")
+	repo.Save(pillow)
+
+	_, err = repo.FindByName("Memory Foam Pillow")
+	assert.EqualError(t, err, "entity not found")
+	productFound, err = repo.FindByName("Memory Foam Pillow XL")
+	assert.Nil(t, err)
+	assert.Equal(t, pillow.GetID(), productFound.GetID())
+}
+
+func Test_GivenAProductWithASKU_WhenSaveAnotherProductWithTheSameSKU_ThenReturnUniqueConstraintError(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	sku, _ := domain.NewSKU("ARZ-MANI-1KG")
+	product, _ := domain.NewProduct("Arroz con mani", testutil.USD("10.00"), domain.WithSKU(sku))
+	duplicate, _ := domain.NewProduct("Arroz con leche", testutil.USD("12.00"), domain.WithSKU(sku))
+	repo.Save(product)
+
+	err := repo.Save(duplicate)
+
+	if assert.Error(t, err) {
+		assert.Equal(t, &domain.UniqueConstraintError{Field: "sku", Value: "ARZ-MANI-1KG"}, err)
+	}
+}
+
+func Test_GivenAProduct_WhenSaveTwice_ThenTheVersionIsIncrementedOnEachSave(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+
+	assert.Nil(t, repo.Save(pillow))
+	assert.Equal(t, 1, pillow.GetVersion())
+	assert.Nil(t, repo.Save(pillow))
+	assert.Equal(t, 2, pillow.GetVersion())
+
+	productSaved, _ := repo.FindByID(pillow.GetID())
+	assert.Equal(t, 2, productSaved.GetVersion())
+}
+
+func Test_GivenTwoCopiesOfAProduct_WhenSaveTheStaleOne_ThenReturnConcurrencyConflictError(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	repo.Save(pillow)
+	firstCopy, _ := repo.FindByID(pillow.GetID())
+	secondCopy, _ := repo.FindByID(pillow.GetID())
+	firstCopy.Rename("Memory Foam Pillow XL")
+	assert.Nil(t, repo.Save(firstCopy))
+
+	secondCopy.Archive()
+	err := repo.Save(secondCopy)
+
+	assert.Equal(t, &domain.ConcurrencyConflictError{Id: pillow.GetID().String(), ExpectedVersion: 1, ActualVersion: 2}, err)
+	assert.Equal(t, 1, secondCopy.GetVersion())
+	productSaved, _ := repo.FindByID(pillow.GetID())
+	assert.Equal(t, "Memory Foam Pillow XL", productSaved.GetName())
+	assert.False(t, productSaved.IsArchived())
+}
+
+func Test_GivenARenamedProduct_WhenSaveAnotherProductWithItsPreviousName_ThenTheNameIsFree(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository()
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	repo.Save(pillow)
+
+	pillow.Rename("Memory Foam Pillow XL")
+	assert.Nil(t, repo.Save(pillow))
+
+	anotherPillow, _ := domain.NewProduct("memory foam PILLOW", testutil.USD("35.00"))
+	assert.Nil(t, repo.Save(anotherPillow))
+	duplicatedPillow, _ := domain.NewProduct("Memory Foam Pillow xl", testutil.USD("40.00"))
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "product_name", Value: "memory foam pillow xl"}, repo.Save(duplicatedPillow))
+}
+
+func Test_GivenADeletedProduct_WhenSaveAnotherProductWithItsName_ThenTheNameIsFree(t *testing.T) {
+	repo := repositories.NewInMemoryProductRepository().(*repositories.InMemoryProductRepository)
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	repo.Save(pillow)
+
+	assert.Nil(t, repo.Delete(pillow.GetID()))
+
+	_, err := repo.FindByID(pillow.GetID())
+	assert.EqualError(t, err, "entity not found")
+	anotherPillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("35.00"))
+	assert.Nil(t, repo.Save(anotherPillow))
+	productFound, err := repo.FindByID(anotherPillow.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, "Memory Foam Pillow", productFound.GetName())
+}
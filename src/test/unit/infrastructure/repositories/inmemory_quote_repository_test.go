@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAQuoteRepository_WhenNextQuoteNumber_ThenTheSequenceOnlyAdvancesWhenAQuoteIsSaved(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	issuedAt := time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC)
+
+	first, err := repo.NextQuoteNumber(issuedAt)
+	assert.NoError(t, err)
+	unsaved, err := repo.NextQuoteNumber(issuedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, "Q-20240102-000001", first)
+	assert.Equal(t, first, unsaved)
+
+	quote, _ := domain.IssueQuote(cart, first, issuedAt, time.Hour)
+	assert.Nil(t, repo.Save(quote))
+	duplicated, _ := domain.IssueQuote(cart, first, issuedAt, time.Hour)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "number", Value: first}, repo.Save(duplicated))
+
+	second, err := repo.NextQuoteNumber(issuedAt.Add(2 * time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "Q-20240103-000002", second)
+}
+
+func Test_GivenPendingAndDecidedQuotes_WhenFindExpiredQuotes_ThenReturnOnlyThePendingOnesPastTheirExpiry(t *testing.T) {
+	repo := repositories.NewInMemoryQuoteRepository()
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	later, _ := domain.IssueQuote(cart, "Q-20240102-000001", issuedAt, 2*time.Hour)
+	sooner, _ := domain.IssueQuote(cart, "Q-20240102-000002", issuedAt, time.Hour)
+	stillValid, _ := domain.IssueQuote(cart, "Q-20240102-000003", issuedAt, 4*time.Hour)
+	accepted, _ := domain.IssueQuote(cart, "Q-20240102-000004", issuedAt, time.Hour)
+	accepted.Accept(&fixedClock{now: issuedAt})
+	for _, quote := range []*domain.Quote{later, sooner, stillValid, accepted} {
+		repo.Save(quote)
+	}
+
+	expired, err := repo.FindExpiredQuotes(issuedAt.Add(2 * time.Hour))
+
+	assert.Nil(t, err)
+	if assert.Len(t, expired, 2) {
+		assert.Equal(t, sooner.GetID(), expired[0].GetID())
+		assert.Equal(t, later.GetID(), expired[1].GetID())
+	}
+}
@@ -0,0 +1,40 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAStockRepository_WhenSave_ThenFindByProductId(t *testing.T) {
+	repo := repositories.NewInMemoryStockRepository()
+	stockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 3)
+
+	repo.Save(stockItem)
+	savedStockItem, err := repo.FindByID(stockItem.GetID())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, savedStockItem.GetOnHand())
+}
+
+func Test_GivenStockReservedByDifferentCarts_WhenGetCartReservations_ThenReturnOnlyTheCartsStockItems(t *testing.T) {
+	repo := repositories.NewInMemoryStockRepository()
+	cartId := domain.CartId(uuid.New())
+	reservedStockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 3)
+	reservedStockItem.Reserve(cartId, 1)
+	otherStockItem, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 3)
+	otherStockItem.Reserve(domain.CartId(uuid.New()), 1)
+	repo.Save(reservedStockItem)
+	repo.Save(otherStockItem)
+
+	stockItems, err := repo.GetCartReservations(cartId)
+
+	assert.Nil(t, err)
+	if assert.Len(t, stockItems, 1) {
+		assert.Equal(t, reservedStockItem.GetID(), stockItems[0].GetID())
+		assert.Equal(t, 1, stockItems[0].GetReservedFor(cartId))
+	}
+}
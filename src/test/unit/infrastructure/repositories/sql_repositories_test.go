@@ -0,0 +1,592 @@
+package test
+
+import (
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/database"
+	"github.com/bitlogic/go-startup/src/infrastructure/outbox"
+	"github.com/bitlogic/go-startup/src/infrastructure/repositories"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDatabase(t *testing.T) *sql.DB {
+	db, err := database.Open(database.SQLiteDriver, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func Test_GivenNoDatabase_WhenNewSQLRepositories_ThenReturnError(t *testing.T) {
+	productRepository, err := repositories.NewSQLProductRepository(nil)
+	assert.Nil(t, productRepository)
+	assert.EqualError(t, err, "db was nil")
+
+	_, err = repositories.NewSQLCustomerRepository(nil)
+	assert.EqualError(t, err, "db was nil")
+
+	_, err = repositories.NewSQLCartRepository(nil)
+	assert.EqualError(t, err, "db was nil")
+
+	_, err = repositories.NewSQLOrderRepository(nil)
+	assert.EqualError(t, err, "db was nil")
+
+	_, err = repositories.NewSQLQuoteRepository(nil)
+	assert.EqualError(t, err, "db was nil")
+
+	_, err = repositories.NewSQLStockRepository(nil)
+	assert.EqualError(t, err, "db was nil")
+}
+
+func Test_GivenASQLProductRepository_WhenSaveAndFind_ThenTheProductIsRestored(t *testing.T) {
+	repo, _ := repositories.NewSQLProductRepository(newTestDatabase(t))
+	sku, _ := domain.NewSKU("FOAM-PILLOW")
+	dimensions, _ := domain.NewDimensions(400, 300, 200)
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"), domain.WithSKU(sku), domain.WithTaxCategory("reduced"), domain.WithWeight(300), domain.WithDimensions(dimensions))
+	assert.Nil(t, repo.Save(pillow))
+	pillow.Archive()
+	assert.Nil(t, repo.Save(pillow))
+
+	productSaved, err := repo.FindByID(pillow.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, pillow.ToMemento(), productSaved.ToMemento())
+
+	productSaved, err = repo.FindBySKU(sku)
+	assert.Nil(t, err)
+	assert.Equal(t, pillow.GetID(), productSaved.GetID())
+
+	productSaved, err = repo.FindByName("MEMORY foam pillow")
+	assert.Nil(t, err)
+	assert.Equal(t, pillow.GetID(), productSaved.GetID())
+
+	_, err = repo.FindByName("Latex Pillow Standard")
+	assert.EqualError(t, err, "entity not found")
+
+	productSaved, err = repo.FindByID(domain.ProductId(uuid.New()))
+	assert.EqualError(t, err, "entity not found")
+	assert.Nil(t, productSaved)
+}
+
+func Test_GivenASQLProductRepository_WhenSaveADuplicatedNameOrSKU_ThenReturnUniqueConstraintError(t *testing.T) {
+	repo, _ := repositories.NewSQLProductRepository(newTestDatabase(t))
+	sku, _ := domain.NewSKU("ARROZ-1KG")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"), domain.WithSKU(sku))
+	repo.Save(aProduct)
+
+	sameName, _ := domain.NewProduct("ARROZ CON LECHE", testutil.USD("12.00"))
+	err := repo.Save(sameName)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "product_name", Value: "arroz con leche"}, err)
+
+	sameSKU, _ := domain.NewProduct("Arroz integral", testutil.USD("12.00"), domain.WithSKU(sku))
+	err = repo.Save(sameSKU)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "sku", Value: "ARROZ-1KG"}, err)
+
+	aProduct.Rename("Arroz con leche y canela")
+	assert.Nil(t, repo.Save(aProduct))
+	assert.Nil(t, repo.Save(sameName))
+}
+
+func Test_GivenASQLProductCatalog_WhenFollowingTheNextCursor_ThenEveryProductIsReturnedExactlyOnceInPriceOrder(t *testing.T) {
+	sqlRepo, _ := repositories.NewSQLProductRepository(newTestDatabase(t))
+	repo, _ := fillCatalog(t, sqlRepo)
+	query := domain.ProductListQuery{
+		SortBy:     domain.SortProductsByPrice,
+		Descending: true,
+		Limit:      2,
+	}
+
+	var names []string
+	pages := 0
+	for {
+		page, err := repo.List(query)
+		if !assert.Nil(t, err) {
+			return
+		}
+		names = append(names, productNames(page)...)
+		pages++
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+	}
+
+	assert.Equal(t, 4, pages)
+	assert.Equal(t, []string{
+		"Salame Milan 1 Kg",
+		"Mortadela 1 Kg",
+		"Queso Cremoso 1 Kg",
+		"Arroz con leche",
+		"Pepsi Light 2.5Lt",
+		"Arroz con mani",
+		"Pepsi Regular 1Lt",
+	}, names)
+}
+
+func Test_GivenASQLProductCatalog_WhenListWithFilters_ThenReturnTheSameProductsAsInMemory(t *testing.T) {
+	sqlRepo, _ := repositories.NewSQLProductRepository(newTestDatabase(t))
+	sqlCatalog, sqlProducts := fillCatalog(t, sqlRepo)
+	memoryCatalog, memoryProducts := newCatalog(t)
+	sqlProducts["Mortadela 1 Kg"].Archive()
+	sqlCatalog.Save(sqlProducts["Mortadela 1 Kg"])
+	memoryProducts["Mortadela 1 Kg"].Archive()
+	memoryCatalog.Save(memoryProducts["Mortadela 1 Kg"])
+
+	for _, query := range []domain.ProductListQuery{
+		{},
+		{NameContains: "1 Kg"},
+		{NameContains: "1 Kg", IncludeArchived: true},
+		{NameContains: "PEPSI", MinPrice: moneyRef(testutil.USD("2.00")), MaxPrice: moneyRef(testutil.USD("5.00"))},
+		{NameContains: "%"},
+		{MinPrice: moneyRef(domain.NewMoney(100, "EUR"))},
+		{SortBy: domain.SortProductsByName, Descending: true, Limit: 3},
+	} {
+		sqlPage, sqlErr := sqlCatalog.List(query)
+		memoryPage, memoryErr := memoryCatalog.List(query)
+
+		assert.Nil(t, sqlErr)
+		assert.Nil(t, memoryErr)
+		assert.Equal(t, productNames(memoryPage), productNames(sqlPage), "%+v", query)
+		assert.Equal(t, memoryPage.NextCursor == "", sqlPage.NextCursor == "", "%+v", query)
+	}
+
+	_, err := sqlCatalog.List(domain.ProductListQuery{Cursor: "%%%"})
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func Test_GivenASQLCustomerRepository_WhenChangeEmail_ThenOnlyTheNewEmailIsTaken(t *testing.T) {
+	repo, _ := repositories.NewSQLCustomerRepository(newTestDatabase(t))
+	previousEmail, _ := domain.NewEmail("john@mayer.com")
+	email, _ := domain.NewEmail("john.mayer@gmail.com")
+	address, _ := domain.NewAddress("Av. Corrientes 1234", "Buenos Aires", "C1043", "AR")
+	aCustomer, _ := domain.NewCustomer("John Mayer", domain.WithEmail(previousEmail), domain.WithShippingAddress(address))
+	assert.Nil(t, repo.Save(aCustomer))
+	aCustomer.ChangeEmail(email)
+	assert.Nil(t, repo.Save(aCustomer))
+
+	customerSaved, err := repo.FindByID(aCustomer.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, aCustomer.ToMemento(), customerSaved.ToMemento())
+
+	anotherCustomer, _ := domain.NewCustomer("Vaughn Vernon", domain.WithEmail(email))
+	err = repo.Save(anotherCustomer)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "email", Value: "john.mayer@gmail.com"}, err)
+	anotherCustomer.ChangeEmail(previousEmail)
+	assert.Nil(t, repo.Save(anotherCustomer))
+}
+
+func Test_GivenASQLCartRepository_WhenSaveACartWithCouponsShippingAndTaxes_ThenItIsRestoredWithTheSameTotals(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	coffee, _ := domain.NewProduct("Coffee Beans 1 Kg", testutil.USD("10.00"), domain.WithWeight(1200))
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"), domain.WithTaxCategory("reduced"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(coffee, 2)
+	cart.AddItem(pillow, 1)
+	tenPercentOff, _ := domain.NewPercentageOff("SAVE10", 10)
+	coupon, _ := domain.NewCoupon("SAVE10", tenPercentOff)
+	cart.ApplyCoupon(coupon)
+	cart.ApplyPromotions([]domain.Promotion{tenPercentOff})
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+	taxCalculator, _ := domain.NewRuleTableTaxCalculator(domain.RoundTaxPerLine, domain.TaxRule{Region: "US", Category: domain.StandardTaxCategory, Rate: big.NewRat(21, 100)})
+	cart.ApplyTaxes("US", taxCalculator)
+	assert.Nil(t, repo.Save(cart))
+
+	cartSaved, err := repo.FindByID(cart.GetID())
+
+	assert.Nil(t, err)
+	assert.Equal(t, cart.ToMemento(), cartSaved.ToMemento())
+	assert.Equal(t, cart.GetTotal(), cartSaved.GetTotal())
+	assert.False(t, cartSaved.GetTax().IsZero())
+	assert.True(t, cartSaved.IsTaxed())
+	assert.True(t, cartSaved.HasShipping())
+
+	cart.RemoveItem(pillow.GetID())
+	assert.Nil(t, repo.Save(cart))
+	cartSaved, _ = repo.FindByID(cart.GetID())
+	assert.Equal(t, 1, len(cartSaved.GetItems()))
+
+	_, err = repo.FindByID(domain.CartId(uuid.New()))
+	assert.EqualError(t, err, "entity not found")
+}
+
+func Test_GivenASQLCartRepository_WhenSaveTheSameCartTwice_ThenGetCustomerCartsReturnsItOnce(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	anotherCustomer, _ := domain.NewCustomer("Vaughn Vernon")
+	firstCart, _ := domain.NewCart(aCustomer)
+	secondCart, _ := domain.NewCart(aCustomer)
+	secondCart.Abandon()
+	otherCart, _ := domain.NewCart(anotherCustomer)
+	for _, cart := range []*domain.Cart{firstCart, secondCart, firstCart, otherCart} {
+		assert.Nil(t, repo.Save(cart))
+	}
+
+	var ids []domain.CartId
+	cartsSaved, err := repo.GetCustomerCarts(aCustomer.GetID())
+	assert.Nil(t, err)
+	for _, cart := range cartsSaved {
+		ids = append(ids, cart.GetID())
+	}
+
+	assert.Equal(t, []domain.CartId{firstCart.GetID(), secondCart.GetID()}, ids)
+}
+
+func Test_GivenSQLCartsWithDifferentActivity_WhenFindIdleCarts_ThenReturnOnlyIdleActiveOrAbandonedCarts(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t))
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newCartAt := func(lastActivityAt time.Time) *domain.Cart {
+		aCustomer, _ := domain.NewCustomer("John Mayer")
+		cart, _ := domain.NewCart(aCustomer, domain.WithCartClock(&fixedClock{now: lastActivityAt}))
+		return cart
+	}
+	recentCart := newCartAt(now)
+	idleCart := newCartAt(now.Add(-2 * time.Hour))
+	abandonedCart := newCartAt(now.Add(-4 * time.Hour))
+	abandonedCart.Abandon()
+	checkedOutCart := newCartAt(now.Add(-5 * time.Hour))
+	checkedOutCart.AddItem(aProduct, 1)
+	checkedOutCart.Checkout()
+	for _, cart := range []*domain.Cart{recentCart, idleCart, abandonedCart, checkedOutCart} {
+		repo.Save(cart)
+	}
+
+	var ids []domain.CartId
+	idleCarts, err := repo.FindIdleCarts(now.Add(-time.Hour))
+	assert.Nil(t, err)
+	for _, cart := range idleCarts {
+		ids = append(ids, cart.GetID())
+	}
+
+	assert.Equal(t, []domain.CartId{abandonedCart.GetID(), idleCart.GetID()}, ids)
+}
+
+func Test_GivenACustomerWithAnActiveSQLCart_WhenSaveAnotherActiveCart_ThenReturnUniqueConstraintError(t *testing.T) {
+	db := newTestDatabase(t)
+	repo, _ := repositories.NewSQLCartRepository(db)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	activeCart, _ := domain.NewCart(aCustomer)
+	activeCart.AddItem(aProduct, 1)
+	secondCart, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(activeCart))
+
+	err := repo.Save(secondCart)
+
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "active_cart", Value: aCustomer.GetID().String()}, err)
+	_, err = db.Exec(`INSERT INTO carts (id, customer_id, currency, status, last_activity_at) VALUES (?, ?, 'USD', 'active', 0)`, uuid.New().String(), aCustomer.GetID().String())
+	assert.Error(t, err)
+	activeCart.Checkout()
+	assert.Nil(t, repo.Save(activeCart))
+	assert.Nil(t, repo.Save(secondCart))
+}
+
+func Test_GivenASQLRepositoryWithAnOutbox_WhenSave_ThenTheDomainEventsAreAppendedToTheOutbox(t *testing.T) {
+	outboxStore := outbox.NewInMemoryStore()
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t), repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 1)
+
+	assert.Nil(t, repo.Save(cart))
+
+	assert.Equal(t, len(cart.GetDomainEvents()), len(outboxStore.All()))
+	assert.NotEmpty(t, outboxStore.All())
+}
+
+func Test_GivenASQLOutbox_WhenSaveToASQLRepository_ThenTheDomainEventsAreStoredInTheSameDatabase(t *testing.T) {
+	db := newTestDatabase(t)
+	outboxStore, _ := outbox.NewSQLStore(db)
+	repo, _ := repositories.NewSQLCartRepository(db, repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 1)
+
+	assert.Nil(t, repo.Save(cart))
+
+	records, err := outboxStore.All()
+	assert.Nil(t, err)
+	if assert.Len(t, records, len(cart.GetDomainEvents())) {
+		assert.Equal(t, cart.GetID().String(), records[0].AggregateId)
+	}
+}
+
+func Test_GivenAFailingSQLOutbox_WhenSaveToASQLRepository_ThenTheCartIsNotSaved(t *testing.T) {
+	db := newTestDatabase(t)
+	outboxStore, _ := outbox.NewSQLStore(db)
+	repo, _ := repositories.NewSQLCartRepository(db, repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 1)
+	_, err := db.Exec(`DROP TABLE outbox_records`)
+	assert.Nil(t, err)
+
+	err = repo.Save(cart)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, cart.GetVersion())
+	_, err = repo.FindByID(cart.GetID())
+	assert.Error(t, err)
+}
+
+func Test_GivenASQLOutbox_WhenSaveAStaleCart_ThenNoDomainEventsAreStored(t *testing.T) {
+	db := newTestDatabase(t)
+	outboxStore, _ := outbox.NewSQLStore(db)
+	repo, _ := repositories.NewSQLCartRepository(db, repositories.WithOutbox(outboxStore))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(cart))
+	staleCopy, _ := repo.FindByID(cart.GetID())
+	freshCopy, _ := repo.FindByID(cart.GetID())
+	freshCopy.AddItem(aProduct, 1)
+	assert.Nil(t, repo.Save(freshCopy))
+	recordsBefore, _ := outboxStore.All()
+
+	staleCopy.AddItem(aProduct, 2)
+	err := repo.Save(staleCopy)
+
+	assert.Error(t, err)
+	recordsAfter, _ := outboxStore.All()
+	assert.Equal(t, recordsBefore, recordsAfter)
+}
+
+func Test_GivenANonTransactionalOutbox_WhenItFailsAfterTheSQLCommit_ThenReturnTheErrorAndKeepTheCart(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t), repositories.WithOutbox(&failingOutboxStore{}))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 1)
+
+	err := repo.Save(cart)
+
+	assert.EqualError(t, err, "failed to append to outbox")
+	cartSaved, err := repo.FindByID(cart.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cartSaved.GetVersion())
+}
+
+func Test_GivenAClosedDatabase_WhenGetCustomerCartsOrFindIdleCarts_ThenReturnTheError(t *testing.T) {
+	db := newTestDatabase(t)
+	repo, _ := repositories.NewSQLCartRepository(db)
+	db.Close()
+
+	carts, err := repo.GetCustomerCarts(domain.CustomerId(uuid.New()))
+	assert.Error(t, err)
+	assert.Nil(t, carts)
+
+	carts, err = repo.FindIdleCarts(time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, carts)
+}
+
+func Test_GivenTwoCopiesOfASQLCart_WhenSaveTheStaleOne_ThenReturnConcurrencyConflictErrorAndKeepTheStoredCart(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(cartToSave))
+	firstCopy, _ := repo.FindByID(cartToSave.GetID())
+	secondCopy, _ := repo.FindByID(cartToSave.GetID())
+	assert.Equal(t, 1, firstCopy.GetVersion())
+
+	firstCopy.AddItem(aProduct, 1)
+	assert.Nil(t, repo.Save(firstCopy))
+	assert.Equal(t, 2, firstCopy.GetVersion())
+	secondCopy.AddItem(aProduct, 5)
+	err := repo.Save(secondCopy)
+
+	assert.Equal(t, &domain.ConcurrencyConflictError{Id: cartToSave.GetID().String(), ExpectedVersion: 1, ActualVersion: 2}, err)
+	assert.Equal(t, 1, secondCopy.GetVersion())
+	cartSaved, _ := repo.FindByID(cartToSave.GetID())
+	assert.Equal(t, firstCopy.ToMemento(), cartSaved.ToMemento())
+}
+
+func Test_GivenASQLProductAndCustomer_WhenSaveAStaleCopy_ThenReturnConcurrencyConflictError(t *testing.T) {
+	db := newTestDatabase(t)
+	productRepo, _ := repositories.NewSQLProductRepository(db)
+	customerRepo, _ := repositories.NewSQLCustomerRepository(db)
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"))
+	johnMayer, _ := domain.NewCustomer("John Mayer")
+	assert.Nil(t, productRepo.Save(pillow))
+	assert.Nil(t, customerRepo.Save(johnMayer))
+	stalePillow := pillow.Clone()
+	staleJohnMayer := johnMayer.Clone()
+	assert.Nil(t, productRepo.Save(pillow))
+	assert.Nil(t, customerRepo.Save(johnMayer))
+
+	assert.IsType(t, &domain.ConcurrencyConflictError{}, productRepo.Save(stalePillow))
+	assert.IsType(t, &domain.ConcurrencyConflictError{}, customerRepo.Save(staleJohnMayer))
+	productSaved, _ := productRepo.FindByID(pillow.GetID())
+	customerSaved, _ := customerRepo.FindByID(johnMayer.GetID())
+	assert.Equal(t, 2, productSaved.GetVersion())
+	assert.Equal(t, 2, customerSaved.GetVersion())
+}
+
+func Test_GivenCopiesOfTheSameSQLCartSavedConcurrently_WhenSave_ThenOnlyOneSucceedsAndTheOthersConflict(t *testing.T) {
+	repo, _ := repositories.NewSQLCartRepository(newTestDatabase(t))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cartToSave, _ := domain.NewCart(aCustomer)
+	assert.Nil(t, repo.Save(cartToSave))
+
+	var copies []*domain.Cart
+	for i := 0; i < 8; i++ {
+		cartCopy, _ := repo.FindByID(cartToSave.GetID())
+		cartCopy.AddItem(aProduct, i+1)
+		copies = append(copies, cartCopy)
+	}
+
+	errs := make([]error, len(copies))
+	var wg sync.WaitGroup
+	for i, cartCopy := range copies {
+		wg.Add(1)
+		go func(i int, cartCopy *domain.Cart) {
+			defer wg.Done()
+			errs[i] = repo.Save(cartCopy)
+		}(i, cartCopy)
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		assert.IsType(t, &domain.ConcurrencyConflictError{}, err)
+	}
+	assert.Equal(t, 1, saved)
+	cartSaved, _ := repo.FindByID(cartToSave.GetID())
+	assert.Equal(t, 2, cartSaved.GetVersion())
+}
+
+func Test_GivenASQLQuoteAndOrderRepository_WhenSaveAndFind_ThenTheQuoteAndOrderAreRestored(t *testing.T) {
+	db := newTestDatabase(t)
+	quoteRepository, _ := repositories.NewSQLQuoteRepository(db)
+	orderRepository, _ := repositories.NewSQLOrderRepository(db)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	coffee, _ := domain.NewProduct("Coffee Beans 1 Kg", testutil.USD("10.00"), domain.WithWeight(1200))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(coffee, 2)
+	standard, _ := domain.NewFlatRateShipping("standard", "Standard", testutil.USD("5.00"))
+	cart.SelectShipping(standard)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	number, err := quoteRepository.NextQuoteNumber(issuedAt)
+	assert.Nil(t, err)
+	quote, _ := domain.IssueQuote(cart, number, issuedAt, time.Hour)
+	duplicated, _ := domain.IssueQuote(cart, number, issuedAt, time.Hour)
+	assert.Nil(t, quoteRepository.Save(quote))
+	quote.Accept(&fixedClock{now: issuedAt})
+	assert.Nil(t, quoteRepository.Save(quote))
+	order, _ := domain.PlaceOrderFromQuote(cart, quote, issuedAt.Add(time.Minute))
+	assert.Nil(t, orderRepository.Save(order))
+
+	quoteSaved, err := quoteRepository.FindByID(quote.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, quote.ToMemento(), quoteSaved.ToMemento())
+	orderSaved, err := orderRepository.FindByID(order.GetID())
+	assert.Nil(t, err)
+	assert.Equal(t, order.ToMemento(), orderSaved.ToMemento())
+	assert.Equal(t, testutil.USD("25.00"), orderSaved.GetTotal())
+
+	err = quoteRepository.Save(duplicated)
+	assert.Equal(t, &domain.UniqueConstraintError{Field: "number", Value: number}, err)
+
+	_, err = quoteRepository.FindByID(domain.QuoteId(uuid.New()))
+	assert.EqualError(t, err, "entity not found")
+	_, err = orderRepository.FindByID(domain.OrderId(uuid.New()))
+	assert.EqualError(t, err, "entity not found")
+}
+
+func Test_GivenASQLQuoteRepository_WhenReopened_ThenNextQuoteNumberContinuesFromTheSavedQuotesOnly(t *testing.T) {
+	db := newTestDatabase(t)
+	repo, _ := repositories.NewSQLQuoteRepository(db)
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, err := repo.NextQuoteNumber(issuedAt)
+	assert.Nil(t, err)
+	unsaved, _ := repo.NextQuoteNumber(issuedAt)
+	assert.Equal(t, first, unsaved)
+	quote, _ := domain.IssueQuote(cart, first, issuedAt, time.Hour)
+	assert.Nil(t, repo.Save(quote))
+	duplicated, _ := domain.IssueQuote(cart, first, issuedAt, time.Hour)
+	assert.Error(t, repo.Save(duplicated))
+
+	reopened, _ := repositories.NewSQLQuoteRepository(db)
+	second, err := reopened.NextQuoteNumber(issuedAt)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Q-20240102-000001", first)
+	assert.Equal(t, "Q-20240102-000002", second)
+}
+
+func Test_GivenSQLQuotes_WhenFindExpiredQuotes_ThenReturnOnlyThePendingOnesPastTheirExpiry(t *testing.T) {
+	repo, _ := repositories.NewSQLQuoteRepository(newTestDatabase(t))
+	aCustomer, _ := domain.NewCustomer("John Mayer")
+	aProduct, _ := domain.NewProduct("Arroz con leche", testutil.USD("10.00"))
+	cart, _ := domain.NewCart(aCustomer)
+	cart.AddItem(aProduct, 2)
+	issuedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	later, _ := domain.IssueQuote(cart, "Q-20240102-000001", issuedAt, 2*time.Hour)
+	sooner, _ := domain.IssueQuote(cart, "Q-20240102-000002", issuedAt, time.Hour)
+	stillValid, _ := domain.IssueQuote(cart, "Q-20240102-000003", issuedAt, 4*time.Hour)
+	accepted, _ := domain.IssueQuote(cart, "Q-20240102-000004", issuedAt, time.Hour)
+	accepted.Accept(&fixedClock{now: issuedAt})
+	for _, quote := range []*domain.Quote{later, sooner, stillValid, accepted} {
+		assert.Nil(t, repo.Save(quote))
+	}
+
+	expired, err := repo.FindExpiredQuotes(issuedAt.Add(2 * time.Hour))
+
+	assert.Nil(t, err)
+	if assert.Len(t, expired, 2) {
+		assert.Equal(t, sooner.ToMemento(), expired[0].ToMemento())
+		assert.Equal(t, later.GetID(), expired[1].GetID())
+	}
+}
+
+func Test_GivenSQLStockItemsReservedByACart_WhenGetCartReservations_ThenReturnOnlyTheItemsReservedByThatCart(t *testing.T) {
+	repo, _ := repositories.NewSQLStockRepository(newTestDatabase(t))
+	aCart := domain.CartId(uuid.New())
+	anotherCart := domain.CartId(uuid.New())
+	coffee, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 10)
+	pillow, _ := domain.NewStockItem(domain.ProductId(uuid.New()), 5)
+	coffee.Reserve(aCart, 3)
+	coffee.Reserve(anotherCart, 1)
+	pillow.Reserve(anotherCart, 2)
+	assert.Nil(t, repo.Save(coffee))
+	assert.Nil(t, repo.Save(pillow))
+	coffee.Release(anotherCart, 1)
+	assert.Nil(t, repo.Save(coffee))
+
+	reservations, err := repo.GetCartReservations(aCart)
+	assert.Nil(t, err)
+	assert.Len(t, reservations, 1)
+	assert.Equal(t, coffee.ToMemento(), reservations[0].ToMemento())
+
+	reservations, err = repo.GetCartReservations(anotherCart)
+	assert.Nil(t, err)
+	assert.Len(t, reservations, 1)
+	assert.Equal(t, 2, reservations[0].GetReservedFor(anotherCart))
+
+	_, err = repo.FindByID(domain.ProductId(uuid.New()))
+	assert.EqualError(t, err, "entity not found")
+}
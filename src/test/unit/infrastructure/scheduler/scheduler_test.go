@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/scheduler"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenANilClock_WhenNewScheduler_ThenReturnError(t *testing.T) {
+	jobScheduler, err := scheduler.NewScheduler(nil, time.Second)
+
+	assert.Nil(t, jobScheduler)
+	if assert.Error(t, err) {
+		assert.Equal(t, "clock was nil", err.Error())
+	}
+}
+
+func Test_GivenAnInvalidJob_WhenEvery_ThenReturnError(t *testing.T) {
+	jobScheduler, _ := scheduler.NewScheduler(&manualClock{}, time.Second)
+	noop := scheduler.JobFunc(func() error { return nil })
+
+	assert.EqualError(t, jobScheduler.Every("", time.Minute, noop), "invalid job name")
+	assert.EqualError(t, jobScheduler.Every("noop", 0, noop), "invalid job interval")
+	assert.EqualError(t, jobScheduler.Every("noop", time.Minute, nil), "job was nil")
+}
+
+func Test_GivenAScheduledJob_WhenTheClockAdvances_ThenRunItOncePerInterval(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	jobScheduler, _ := scheduler.NewScheduler(clock, time.Second)
+	runs := 0
+	jobScheduler.Every("count", time.Minute, scheduler.JobFunc(func() error {
+		runs++
+		return nil
+	}))
+
+	jobScheduler.RunDue()
+	assert.Equal(t, 1, runs)
+
+	clock.now = clock.now.Add(59 * time.Second)
+	jobScheduler.RunDue()
+	assert.Equal(t, 1, runs)
+
+	clock.now = clock.now.Add(time.Second)
+	jobScheduler.RunDue()
+	assert.Equal(t, 2, runs)
+}
+
+func Test_GivenAFailingJob_WhenRunDue_ThenTheOtherJobsStillRun(t *testing.T) {
+	clock := &manualClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	jobScheduler, _ := scheduler.NewScheduler(clock, time.Second)
+	ran := false
+	jobScheduler.Every("failing", time.Minute, scheduler.JobFunc(func() error {
+		return errors.New("boom")
+	}))
+	jobScheduler.Every("healthy", time.Minute, scheduler.JobFunc(func() error {
+		ran = true
+		return nil
+	}))
+
+	jobScheduler.RunDue()
+
+	assert.True(t, ran)
+}
+
+func Test_GivenAStartedScheduler_WhenStop_ThenTheJobHasRunAndNoLongerRuns(t *testing.T) {
+	jobScheduler, _ := scheduler.NewScheduler(domain.SystemClock(), time.Millisecond)
+	runs := make(chan struct{}, 1)
+	jobScheduler.Every("signal", time.Hour, scheduler.JobFunc(func() error {
+		runs <- struct{}{}
+		return nil
+	}))
+
+	jobScheduler.Start()
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		t.Fatal("job did not run")
+	}
+	jobScheduler.Stop()
+	jobScheduler.Stop()
+
+	select {
+	case <-runs:
+		t.Fatal("job ran after the scheduler was stopped")
+	default:
+	}
+}
+
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/shipping"
+	"github.com/bitlogic/go-startup/src/test/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenAShippingMethodsFile_WhenNewFileShippingCatalog_ThenLoadEveryMethod(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shipping.json")
+	os.WriteFile(path, []byte(`{
+		"methods": [
+			{"code": "standard", "name": "Standard", "type": "flat_rate", "cost": "5.00", "free_above": "60.00"},
+			{"code": "Express", "type": "weight_based", "base": "10.00", "per_kg": "3.00", "volumetric_divisor": 5000}
+		]
+	}`), 0o644)
+
+	catalog, err := shipping.NewFileShippingCatalog(path)
+	assert.NoError(t, err)
+
+	customer, _ := domain.NewCustomer("John Mayer")
+	cart, _ := domain.NewCart(customer)
+	pillow, _ := domain.NewProduct("Memory Foam Pillow", testutil.USD("30.00"), domain.WithWeight(1500))
+	cart.AddItem(pillow, 1)
+
+	var quotes []domain.ShippingQuote
+	for _, method := range catalog.GetShippingMethods() {
+		quote, err := cart.QuoteShipping(method)
+		assert.NoError(t, err)
+		quotes = append(quotes, quote)
+	}
+	assert.Equal(t, []domain.ShippingQuote{
+		domain.NewShippingQuote("standard", "Standard", testutil.USD("5.00")),
+		domain.NewShippingQuote("express", "express", testutil.USD("16.00")),
+	}, quotes)
+
+	cart.UpdateItemQuantity(pillow.GetID(), 2)
+	standard, err := catalog.FindShippingMethod("standard")
+	assert.NoError(t, err)
+	quote, _ := cart.QuoteShipping(standard)
+	assert.Equal(t, testutil.USD("0.00"), quote.GetCost())
+}
+
+func Test_GivenAnInvalidShippingMethodsFile_WhenNewFileShippingCatalog_ThenReturnError(t *testing.T) {
+	for name, content := range map[string]string{
+		"invalid code":     `{"methods":[{"code":"next day","type":"flat_rate","cost":"5.00"}]}`,
+		"unknown type":     `{"methods":[{"code":"pigeon","type":"carrier_pigeon","cost":"5.00"}]}`,
+		"invalid cost":     `{"methods":[{"code":"standard","type":"flat_rate","cost":"five"}]}`,
+		"missing per kg":   `{"methods":[{"code":"express","type":"weight_based","base":"10.00"}]}`,
+		"duplicated codes": `{"methods":[{"code":"standard","type":"flat_rate","cost":"5.00"},{"code":"STANDARD","type":"flat_rate","cost":"6.00"}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), "shipping.json")
+		os.WriteFile(path, []byte(content), 0o644)
+
+		catalog, err := shipping.NewFileShippingCatalog(path)
+
+		assert.Nil(t, catalog, name)
+		assert.Error(t, err, name)
+	}
+}
+
+func Test_GivenAStaticShippingCatalog_WhenFindAnUnknownMethod_ThenReturnError(t *testing.T) {
+	catalog, err := shipping.NewStaticShippingCatalog(nil)
+	assert.NoError(t, err)
+
+	method, err := catalog.FindShippingMethod("standard")
+
+	assert.Nil(t, method)
+	assert.EqualError(t, err, "shipping method not found")
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitlogic/go-startup/src/domain"
+	"github.com/bitlogic/go-startup/src/infrastructure/taxes"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GivenATaxRulesFile_WhenNewFileTaxCalculator_ThenLoadTheRulesAndRounding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "taxes.json")
+	os.WriteFile(path, []byte(`{
+		"rounding": "per_total",
+		"rules": [
+			{"region": "ar", "category": "standard", "rate": "0.21", "inclusive": true},
+			{"region": "US", "category": "Reduced", "rate": "0.05"}
+		]
+	}`), 0o644)
+
+	calculator, err := taxes.NewFileTaxCalculator(path)
+	assert.NoError(t, err)
+
+	productId := domain.ProductId(uuid.New())
+	amount, _ := domain.ParseMoney("121.00", domain.DefaultCurrency)
+	tax, _ := domain.ParseMoney("21.00", domain.DefaultCurrency)
+	reducedTax, _ := domain.ParseMoney("6.05", domain.DefaultCurrency)
+
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(productId, big.NewRat(21, 100), true, tax)},
+		calculator.Calculate("AR", []domain.TaxableLine{{ProductId: productId, Category: domain.StandardTaxCategory, Amount: amount}}))
+	assert.Equal(t, []domain.LineTax{domain.NewLineTax(productId, big.NewRat(5, 100), false, reducedTax)},
+		calculator.Calculate("US", []domain.TaxableLine{{ProductId: productId, Category: "reduced", Amount: amount}}))
+}
+
+func Test_GivenAnInvalidTaxRulesFile_WhenNewFileTaxCalculator_ThenReturnError(t *testing.T) {
+	for name, content := range map[string]string{
+		"invalid region":   `{"rules":[{"region":"ARG","category":"standard","rate":"0.21"}]}`,
+		"invalid rate":     `{"rules":[{"region":"AR","category":"standard","rate":"21%"}]}`,
+		"invalid rounding": `{"rounding":"per_cart","rules":[]}`,
+	} {
+		path := filepath.Join(t.TempDir(), "taxes.json")
+		os.WriteFile(path, []byte(content), 0o644)
+
+		calculator, err := taxes.NewFileTaxCalculator(path)
+
+		assert.Nil(t, calculator, name)
+		assert.Error(t, err, name)
+	}
+}
+
+func Test_GivenAMissingTaxRulesFile_WhenNewFileTaxCalculator_ThenReturnError(t *testing.T) {
+	calculator, err := taxes.NewFileTaxCalculator(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.Nil(t, calculator)
+	assert.Error(t, err)
+}